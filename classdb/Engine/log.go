@@ -0,0 +1,140 @@
+package Engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+
+	gd "graphics.gd/internal"
+)
+
+// NewLogHandler returns a [slog.Handler] that writes records to the engine's output, such that
+// they show up in the editor's Output panel and debugger. Debug records are printed with
+// print_verbose, Info records with print_rich (with the attributes rendered as rich text) and
+// warnings and errors are pushed to the debugger, along with the source file and line that they
+// were logged from. If opts is nil, the default options are used.
+//
+//	slog.SetDefault(slog.New(Engine.NewLogHandler(nil)))
+func NewLogHandler(opts *slog.HandlerOptions) slog.Handler {
+	var handler logHandler
+	if opts != nil {
+		handler.opts = *opts
+	}
+	return handler
+}
+
+type logHandler struct {
+	opts   slog.HandlerOptions
+	prefix string      // qualifies the keys of attributes inside of a group.
+	attrs  []slog.Attr // qualified attributes added with WithAttrs.
+}
+
+func (h logHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minimum := slog.LevelInfo
+	if h.opts.Level != nil {
+		minimum = h.opts.Level.Level()
+	}
+	return level >= minimum
+}
+
+func (h logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h.attrs = append(h.attrs[:len(h.attrs):len(h.attrs)], h.qualify(h.prefix, attrs)...)
+	return h
+}
+
+func (h logHandler) WithGroup(name string) slog.Handler {
+	if name != "" {
+		h.prefix += name + "."
+	}
+	return h
+}
+
+// qualify resolves the attributes, flattening any groups into dotted keys.
+func (h logHandler) qualify(prefix string, attrs []slog.Attr) []slog.Attr {
+	var qualified []slog.Attr
+	for _, attr := range attrs {
+		attr.Value = attr.Value.Resolve()
+		if attr.Value.Kind() == slog.KindGroup {
+			group := prefix
+			if attr.Key != "" {
+				group += attr.Key + "."
+			}
+			qualified = append(qualified, h.qualify(group, attr.Value.Group())...)
+			continue
+		}
+		if h.opts.ReplaceAttr != nil {
+			var groups []string
+			if prefix != "" {
+				groups = strings.Split(strings.TrimSuffix(prefix, "."), ".")
+			}
+			attr = h.opts.ReplaceAttr(groups, attr)
+			attr.Value = attr.Value.Resolve()
+		}
+		if attr.Equal(slog.Attr{}) {
+			continue
+		}
+		attr.Key = prefix + attr.Key
+		qualified = append(qualified, attr)
+	}
+	return qualified
+}
+
+func (h logHandler) Handle(_ context.Context, record slog.Record) error {
+	attrs := h.attrs
+	if record.NumAttrs() > 0 {
+		var added []slog.Attr
+		record.Attrs(func(attr slog.Attr) bool {
+			added = append(added, attr)
+			return true
+		})
+		attrs = append(attrs[:len(attrs):len(attrs)], h.qualify(h.prefix, added)...)
+	}
+	switch {
+	case record.Level < slog.LevelInfo:
+		gd.PrintVerbose(gd.NewVariant(h.format(record.Message, attrs, false)))
+	case record.Level < slog.LevelWarn:
+		gd.PrintRich(gd.NewVariant(h.format(record.Message, attrs, true)))
+	default:
+		var source runtime.Frame
+		if record.PC != 0 {
+			source, _ = runtime.CallersFrames([]uintptr{record.PC}).Next()
+		}
+		message := h.format(record.Message, attrs, false)
+		if record.Level < slog.LevelError {
+			gd.Global.PrintWarningMessage(record.Message, message, source.Function, source.File, int32(source.Line), true)
+		} else {
+			gd.Global.PrintErrorMessage(record.Message, message, source.Function, source.File, int32(source.Line), true)
+		}
+	}
+	return nil
+}
+
+// format the message followed by key=value pairs for each attribute, when
+// rich is true, the output is formatted as BBCode for print_rich.
+func (h logHandler) format(message string, attrs []slog.Attr, rich bool) string {
+	var buf strings.Builder
+	if rich {
+		message = escapeBBCode(message)
+	}
+	buf.WriteString(message)
+	for _, attr := range attrs {
+		value := attr.Value.String()
+		if strings.ContainsAny(value, " \t\n\"=") || value == "" {
+			value = fmt.Sprintf("%q", value)
+		}
+		buf.WriteByte(' ')
+		if rich {
+			fmt.Fprintf(&buf, "[color=gray]%s=[/color]%s", escapeBBCode(attr.Key), escapeBBCode(value))
+		} else {
+			fmt.Fprintf(&buf, "%s=%s", attr.Key, value)
+		}
+	}
+	return buf.String()
+}
+
+// escapeBBCode prevents print_rich from interpreting any square brackets in s as tags.
+func escapeBBCode(s string) string {
+	return strings.ReplaceAll(s, "[", "[lb]")
+}
@@ -0,0 +1,39 @@
+package Engine_test
+
+import (
+	"log/slog"
+	"strings"
+	"testing"
+
+	"graphics.gd/classdb/Engine"
+	"graphics.gd/startup/enginetest"
+)
+
+func TestMain(m *testing.M) {
+	enginetest.Main(m)
+}
+
+func TestLogHandler(t *testing.T) {
+	output := enginetest.Capture(t)
+	logger := slog.New(Engine.NewLogHandler(nil)).With("player", "[b]one[/b]")
+	logger.Debug("hidden")
+	logger.Info("spawned", slog.Group("at", "x", 1, "y", 2))
+	logger.Warn("low health", "health", 3)
+	logger.Error("died")
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	expected := []string{
+		"spawned [color=gray]player=[/color][lb]b]one[lb]/b] [color=gray]at.x=[/color]1 [color=gray]at.y=[/color]2",
+		"WARNING: low health player=[b]one[/b] health=3",
+		"   at: graphics.gd/classdb/Engine_test.TestLogHandler (",
+		"ERROR: died player=[b]one[/b]",
+		"   at: graphics.gd/classdb/Engine_test.TestLogHandler (",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("unexpected output:\n%s", output.String())
+	}
+	for i := range expected {
+		if !strings.HasPrefix(lines[i], expected[i]) {
+			t.Fatalf("expected %q, got %q", expected[i], lines[i])
+		}
+	}
+}
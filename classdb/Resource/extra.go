@@ -1,6 +1,7 @@
 package Resource
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
 	"unsafe"
@@ -8,6 +9,8 @@ import (
 	"graphics.gd/classdb/ResourceLoader"
 	gd "graphics.gd/internal"
 	"graphics.gd/internal/pointers"
+	"graphics.gd/variant/Error"
+	"graphics.gd/variant/String"
 )
 
 // ID that uniquely identifies a resource.
@@ -24,14 +27,30 @@ func Int64(id int64) ID { //gd:rid_from_int64
 	return gd.RidFromInt64(gd.Int(id))
 }
 
-// Load behaves like the builtin "load" function in GDScript.
+// Load behaves like the builtin "load" function in GDScript, it panics if the resource cannot be
+// loaded as a T.
 func Load[T Any](path string) T {
-	resource := Instance(ResourceLoader.Load(string(path)))
+	result, err := Open[T](path)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// Open loads the resource at the given path as a T, which is passed to the engine as the type
+// hint, so that the loader only accepts resources of the expected type. For a Go resource, the
+// result is the Go value that the engine created while loading it.
+func Open[T Any](path string) (T, error) {
+	var zero T
+	resource := Instance(ResourceLoader.Advanced().Load(String.New(path), String.New(nameOf(reflect.TypeFor[T]())), ResourceLoader.CacheModeReuse))
+	if pointers.Get(resource.AsObject()[0]) == ([3]uint64{}) {
+		return zero, fmt.Errorf("cannot load %s: %w", path, Error.FileCantOpen)
+	}
 	result, ok := as[T](resource)
 	if !ok {
-		panic("Resource is " + resource.AsObject()[0].GetClass().String() + " not " + reflect.TypeFor[T]().String())
+		return zero, fmt.Errorf("cannot load %s: resource is %s not %s: %w", path, resource.AsObject()[0].GetClass().String(), reflect.TypeFor[T](), Error.FileUnrecognized)
 	}
-	return result
+	return result, nil
 }
 
 // As attempts to cast the given class to T, returning true
@@ -0,0 +1,162 @@
+package Resource_test
+
+import (
+	"errors"
+	"testing"
+
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Resource"
+	"graphics.gd/classdb/ResourceSaver"
+	gd "graphics.gd/internal"
+	"graphics.gd/startup/enginetest"
+	"graphics.gd/variant/Error"
+	"graphics.gd/variant/String"
+)
+
+func TestMain(m *testing.M) {
+	classdb.Register[Ability]()
+	classdb.Register[Item]()
+	enginetest.Main(m)
+}
+
+type Ability struct {
+	classdb.Extension[Ability, Resource.Instance]
+
+	Name string
+}
+
+func (a *Ability) AsResource() Resource.Instance { return a.Super() }
+
+type Item struct {
+	classdb.Extension[Item, Resource.Instance]
+
+	Title     string
+	Price     int `default:"10"`
+	Upgrade   *Ability
+	Abilities []*Ability
+}
+
+func (item *Item) AsResource() Resource.Instance { return item.Super() }
+
+var itemVersion = 1
+
+func (item *Item) Version() int { return itemVersion }
+
+func (item *Item) Migrate(version int, name string, value any) (string, any, bool) {
+	if version < 2 && name == "Price" {
+		return name, value.(int64) / 100, true // prices used to be in cents.
+	}
+	return name, value, true
+}
+
+func TestResources(t *testing.T) {
+	enginetest.Capture(t)
+	save := func(path string, item *Item) {
+		t.Helper()
+		if code := ResourceSaver.Advanced().Save(item.AsResource(), String.New(path), 0); code != 0 {
+			t.Fatalf("cannot save %s: %v", path, code)
+		}
+	}
+	sword := &Item{
+		Title:     "Sword",
+		Price:     250,
+		Upgrade:   &Ability{Name: "Sharpen"},
+		Abilities: []*Ability{{Name: "Slash"}, {Name: "Parry"}},
+	}
+	save("res://sword.tres", sword)
+	loaded, err := Resource.Open[*Item]("res://sword.tres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded == sword || loaded.Title != "Sword" || loaded.Price != 250 {
+		t.Fatalf("unexpected item %q with price %d", loaded.Title, loaded.Price)
+	}
+	if loaded.Upgrade == nil || loaded.Upgrade == sword.Upgrade || loaded.Upgrade.Name != "Sharpen" {
+		t.Fatalf("expected a new Sharpen upgrade, got %v", loaded.Upgrade)
+	}
+	if len(loaded.Abilities) != 2 || loaded.Abilities[0].Name != "Slash" || loaded.Abilities[1].Name != "Parry" {
+		t.Fatalf("expected Slash and Parry, got %v", loaded.Abilities)
+	}
+	created := gd.Global.ClassDB.ConstructObject(gd.NewStringName("Item"))
+	if price := created[0].Get(gd.NewStringName("Price")).Interface(); price != int64(10) {
+		t.Fatalf("expected the engine to create items with the default price, got %v", price)
+	}
+	save("res://stick.tres", &Item{Title: "Stick", Price: 10})
+	if stick := Resource.Load[*Item]("res://stick.tres"); stick.Price != 10 || stick.Upgrade != nil || len(stick.Abilities) != 0 {
+		t.Fatalf("expected the default price, got %d", stick.Price)
+	}
+	itemVersion = 2
+	defer func() { itemVersion = 1 }()
+	if migrated := Resource.Load[*Item]("res://sword.tres"); migrated.Price != 2 || migrated.Title != "Sword" {
+		t.Fatalf("expected the price to be migrated from cents, got %d", migrated.Price)
+	}
+	save("res://axe.tres", &Item{Title: "Axe", Price: 3})
+	if axe := Resource.Load[*Item]("res://axe.tres"); axe.Price != 3 {
+		t.Fatalf("expected the current version not to be migrated, got %d", axe.Price)
+	}
+	if _, err := Resource.Open[*Ability]("res://sword.tres"); !errors.Is(err, Error.FileCantOpen) {
+		t.Fatalf("expected an error when loading an item as an ability, got %v", err)
+	}
+}
+
+func TestCreatedItems(t *testing.T) {
+	enginetest.Capture(t)
+	itemVersion = 2
+	defer func() { itemVersion = 1 }()
+	created := gd.Global.ClassDB.ConstructObject(gd.NewStringName("Item"))
+	if version := created[0].Get(gd.NewStringName("version")).Interface(); version == int64(0) {
+		t.Fatal("expected a new item not to report version 0")
+	}
+	untouched, ok := classdb.As[*Item](created[0])
+	if !ok {
+		t.Fatal("expected the engine to create an Item")
+	}
+	if code := ResourceSaver.Advanced().Save(untouched.AsResource(), String.New("res://new.tres"), 0); code != 0 {
+		t.Fatalf("cannot save res://new.tres: %v", code)
+	}
+	if loaded := Resource.Load[*Item]("res://new.tres"); loaded.Price != 10 {
+		t.Fatalf("expected a new item to be saved with the current version, got price %d", loaded.Price)
+	}
+	created[0].Set(gd.NewStringName("Price"), gd.NewVariant(5))
+	if price := created[0].Get(gd.NewStringName("Price")).Interface(); price != int64(5) {
+		t.Fatalf("expected setting the price of a new item not to migrate it, got %v", price)
+	}
+	if version := created[0].Get(gd.NewStringName("version")).Interface(); version != int64(2) {
+		t.Fatalf("expected the item to report the current version, got %v", version)
+	}
+}
+
+func TestMigrateAfterEngineProperties(t *testing.T) {
+	enginetest.Capture(t)
+	itemVersion = 2
+	defer func() { itemVersion = 1 }()
+	created := gd.Global.ClassDB.ConstructObject(gd.NewStringName("Item"))
+	// a .tres file sets the properties of the Resource class before those of the Item.
+	created[0].Set(gd.NewStringName("resource_name"), gd.NewVariant(gd.NewString("Sword")))
+	created[0].Set(gd.NewStringName("version"), gd.NewVariant(1))
+	created[0].Set(gd.NewStringName("Price"), gd.NewVariant(250))
+	if price := created[0].Get(gd.NewStringName("Price")).Interface(); price != int64(2) {
+		t.Fatalf("expected the price to be migrated from cents, got %v", price)
+	}
+}
+
+type versioned struct {
+	classdb.Extension[versioned, Resource.Instance]
+
+	Revision int `gd:"version"`
+}
+
+func (v *versioned) Version() int { return 1 }
+
+func (v *versioned) Migrate(version int, name string, value any) (string, any, bool) {
+	return name, value, true
+}
+
+func TestMigratorVersionField(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a Migrator with a version field not to register")
+		}
+	}()
+	classdb.Register[versioned]()
+}
@@ -129,6 +129,13 @@ used as the main loop for the application.
 
 If the Struct implements an OnRegister(Lifetime) method, it will
 be called on a temporary instance when the class is registered.
+
+Exported fields are saved along with the object, such that a Struct
+extending Resource can be saved to and loaded from resource files,
+including fields that point to other Go resources or hold slices of
+them. Fields with a `default:"value"` tag are set to that value when
+the engine creates the object. Implement [Migrator] to load resource
+files that were saved before the fields of the Struct changed.
 */
 func Register[T Class]() {
 	register := func() {
@@ -240,6 +247,11 @@ func registerClassInformation(className string, inherits string, rtype reflect.T
 		class.BriefDescription = brief
 		class.Description = whole
 	}
+	migrator := reflect.PointerTo(rtype).Implements(reflect.TypeFor[Migrator]())
+	if migrator {
+		// registered first, so that the version is loaded before the properties that it applies to.
+		gd.Global.ClassDB.RegisterClassProperty(gd.Global.ExtensionToken, gd.NewStringName(className), versionOf(), gd.NewStringName(""), gd.NewStringName(""))
+	}
 	for i := 1; i < rtype.NumField(); i++ {
 		field := rtype.Field(i)
 		if !field.IsExported() || field.Anonymous || field.Name == "Object" {
@@ -252,6 +264,9 @@ func registerClassInformation(className string, inherits string, rtype reflect.T
 		if field.Tag.Get("gd") != "" {
 			name = field.Tag.Get("gd")
 		}
+		if migrator && name == versionProperty {
+			panic(fmt.Sprintf("gdextension.RegisterClass: %s.%s cannot be named %q, as %s implements Migrator", rtype.Name(), field.Name, versionProperty, rtype.Name()))
+		}
 		if reflect.PointerTo(field.Type).Implements(reflect.TypeOf([0]gd.IsSignal{}).Elem()) {
 			var signal xmlSignal
 			name, _, _ = strings.Cut(name, "(")
@@ -318,8 +333,10 @@ func (class classImplementation) createInstance(reuse reflect.Value) [1]gd.Objec
 }
 
 func (class classImplementation) reloadInstance(value reflect.Value, super [1]gd.Object) gd.ObjectInterface {
-	if !value.IsValid() {
+	created := !value.IsValid()
+	if created {
 		value = reflect.New(class.Type)
+		setDefaults(value.Elem())
 	}
 	extensionClass := value.Interface().(isClass)
 	extensionClass.setObject(super)
@@ -369,11 +386,29 @@ func (class classImplementation) reloadInstance(value reflect.Value, super [1]gd
 	if len(signals) > 0 {
 		go manageSignals(super[0].AsObject()[0].GetInstanceId(), chSignals)
 	}
-	return &instanceImplementation{
+	instance := &instanceImplementation{
 		object:   pointers.Get(super[0])[0],
 		Value:    value.Addr().Interface().(isClass),
 		signals:  signals,
 		isEditor: !class.Tool && EngineClass.IsEditorHint(),
+		pristine: created,
+	}
+	if migrator, ok := instance.Value.(Migrator); ok {
+		instance.version = migrator.Version()
+	}
+	return instance
+}
+
+// setDefaults sets each exported field with a default tag to its default value.
+func setDefaults(value reflect.Value) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if _, ok := field.Tag.Lookup("default"); !ok || !field.IsExported() {
+			continue
+		}
+		if def, ok := defaultOf(field); ok {
+			value.Field(i).Set(def)
+		}
 	}
 }
 
@@ -417,6 +452,9 @@ type instanceImplementation struct {
 
 	// FIXME use a bitfield for these booleans.
 	isEditor bool
+	pristine bool // created by the engine, with no properties set yet.
+
+	version int // of the properties being set, for a [Migrator].
 }
 
 var lastGC int
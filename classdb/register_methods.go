@@ -7,6 +7,7 @@ import (
 	gd "graphics.gd/internal"
 	"graphics.gd/internal/pointers"
 	"graphics.gd/variant/Array"
+	ErrorType "graphics.gd/variant/Error"
 	"graphics.gd/variant/Packed"
 	"graphics.gd/variant/String"
 
@@ -34,6 +35,9 @@ func registerMethods(class gd.StringName, rtype reflect.Type) {
 		if method.Name == "OnRegister" {
 			continue
 		}
+		if _, ok := reflect.TypeFor[Migrator]().MethodByName(method.Name); ok && classTypePtr.Implements(reflect.TypeFor[Migrator]()) {
+			continue
+		}
 		var offset = 0
 		var arguments = make([]gd.PropertyInfo, 0, method.Type.NumIn()-1-offset)
 		var metadatas = make([]gd.ClassMethodArgumentMetadata, 0, method.Type.NumIn()-1-offset)
@@ -46,20 +50,30 @@ func registerMethods(class gd.StringName, rtype reflect.Type) {
 		}
 		var returns *gd.PropertyInfo
 		var returnMetadata gd.ClassMethodArgumentMetadata
-		if method.Type.NumOut() > 0 {
+		switch {
+		case method.Type.NumOut() == 1 && returnsError(method.Type):
+			returns = &gd.PropertyInfo{
+				Type:       gd.TypeInt,
+				Name:       gd.NewStringName("result"),
+				ClassName:  gd.NewStringName("Error"),
+				HintString: gd.NewString(""),
+				Usage:      int64(PropertyUsageDefault | PropertyUsageClassIsEnum),
+			}
+		case method.Type.NumOut() > 0:
 			property, ok := propertyOf(reflect.StructField{Name: "result", Type: method.Type.Out(0)})
 			if ok {
 				returns = &property
 				returnMetadata = 0
 			}
 		}
+		name := nameOf(rtype) + "." + method.Name
 
 		gd.Global.ClassDB.RegisterClassMethod(gd.Global.ExtensionToken, class, gd.Method{
 			Name: gd.NewStringName(method.Name),
-			Call: variantCall(method),
+			Call: variantCall(name, method),
 			PointerCall: func(instance any, args gd.Address, ret gd.Address) {
 				extensionInstance := instance.(*instanceImplementation).Value
-				slowCall(hasContext, reflect.ValueOf(extensionInstance).Method(i), args, ret)
+				slowCall(name, hasContext, reflect.ValueOf(extensionInstance).Method(i), args, ret)
 			},
 			Arguments:           arguments,
 			ArgumentsMetadata:   metadatas,
@@ -69,26 +83,58 @@ func registerMethods(class gd.StringName, rtype reflect.Type) {
 	}
 }
 
-func variantCall(method reflect.Method) func(instance any, v ...gd.Variant) (gd.Variant, error) {
+var errorType = reflect.TypeFor[error]()
+
+// returnsError reports whether the last result of the method is an error.
+func returnsError(method reflect.Type) bool {
+	return method.NumOut() > 0 && method.Out(method.NumOut()-1) == errorType
+}
+
+// resultOf returns the value to return to the engine, given the results of a call to the named method.
+// A method that returns an error pushes any non-nil error to the engine, the error is returned to the
+// engine as an [ErrorType.Code] if it is the only result.
+func resultOf(name string, rets []reflect.Value) (reflect.Value, bool) {
+	if len(rets) == 0 {
+		return reflect.Value{}, false
+	}
+	if last := rets[len(rets)-1]; last.Type() == errorType {
+		err, _ := last.Interface().(error)
+		if err != nil {
+			EngineClass.Raise(fmt.Errorf("%s: %w", name, err))
+		}
+		if len(rets) == 1 {
+			return reflect.ValueOf(int64(ErrorType.New(err))), true
+		}
+	}
+	return rets[0], true
+}
+
+func variantCall(name string, method reflect.Method) func(instance any, v ...gd.Variant) (gd.Variant, error) {
 	return func(instance any, v ...gd.Variant) (result gd.Variant, err error) {
+		switch expected := method.Type.NumIn() - 1; {
+		case len(v) < expected:
+			return gd.Variant{}, &gd.CallError{ErrorType: gd.ErrTooFewArguments, Expected: int32(expected), Method: name}
+		case len(v) > expected:
+			return gd.Variant{}, &gd.CallError{ErrorType: gd.ErrTooManyArguments, Expected: int32(expected), Method: name}
+		}
 		var args = make([]reflect.Value, len(v))
 		for i := 0; i < method.Type.NumIn()-1; i++ {
 			args[i], err = gd.ConvertToDesiredGoType(v[i], method.Type.In(i+1))
 			if err != nil {
-				EngineClass.Raise(err)
-				return gd.Variant{}, err
+				vtype, _ := gd.VariantTypeOf(method.Type.In(i + 1))
+				return gd.Variant{}, &gd.CallError{ErrorType: gd.ErrInvalidArgument, Argument: int32(i), Expected: int32(vtype), Method: name}
 			}
 		}
 		extensionInstance := instance.(*instanceImplementation).Value
 		rets := reflect.ValueOf(extensionInstance).Method(method.Index).Call(args)
-		if len(rets) > 0 {
-			return gd.NewVariant(rets[0].Interface()), nil
+		if result, ok := resultOf(name, rets); ok {
+			return gd.NewVariant(result.Interface()), nil
 		}
 		return gd.Variant{}, nil
 	}
 }
 
-func slowCall(hasContext bool, method reflect.Value, p_args gd.Address, p_ret gd.Address) {
+func slowCall(name string, hasContext bool, method reflect.Value, p_args gd.Address, p_ret gd.Address) {
 
 	var (
 		args = make([]reflect.Value, method.Type().NumIn())
@@ -227,9 +273,7 @@ func slowCall(hasContext bool, method reflect.Value, p_args gd.Address, p_ret gd
 			}
 		}
 	}
-	rrets := method.Call(args)
-	if len(rrets) > 0 {
-		result := rrets[0]
+	if result, ok := resultOf(name, method.Call(args)); ok {
 		vtype, ok := gd.VariantTypeOf(result.Type())
 		if !ok {
 			panic(fmt.Sprintf("gdextension: unsupported Go -> Godot type %v", result.Type()))
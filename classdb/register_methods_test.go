@@ -0,0 +1,63 @@
+package classdb_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Node"
+	gd "graphics.gd/internal"
+	"graphics.gd/startup/enginetest"
+	"graphics.gd/variant/Error"
+)
+
+func TestMain(m *testing.M) {
+	classdb.Register[Player]()
+	classdb.Register[Door]()
+	enginetest.Main(m)
+}
+
+type Player struct {
+	classdb.Extension[Player, Node.Instance]
+
+	Health int
+}
+
+func (p *Player) Heal(amount int) error {
+	if amount < 0 {
+		return fmt.Errorf("cannot heal by %d: %w", amount, Error.InvalidParameter)
+	}
+	p.Health += amount
+	return nil
+}
+
+func TestErrors(t *testing.T) {
+	output := enginetest.Capture(t)
+	player := new(Player)
+	defer player.Super().QueueFree()
+	self := gd.NewVariant(player.AsObject()[0])
+	result, err := self.Call(gd.NewStringName("Heal"), gd.NewVariant(2))
+	if err != nil || result.Interface() != int64(Error.Code(0)) || player.Health != 2 {
+		t.Fatalf("unexpected result %v (%v)", result.Interface(), err)
+	}
+	result, err = self.Call(gd.NewStringName("Heal"), gd.NewVariant(-1))
+	if err != nil || result.Interface() != int64(Error.InvalidParameter) {
+		t.Fatalf("expected %d, got %v (%v)", Error.InvalidParameter, result.Interface(), err)
+	}
+	if expected := "ERROR: Player.Heal: cannot heal by -1: Invalid parameter"; !strings.HasPrefix(output.String(), expected) {
+		t.Fatalf("expected %q, got %q", expected, output.String())
+	}
+	_, err = self.Call(gd.NewStringName("Heal"))
+	if !errors.Is(err, Error.InvalidParameter) {
+		t.Fatalf("expected an invalid parameter error, got %v", err)
+	}
+	if err.Error() != "Player.Heal: too few arguments, expected 1" {
+		t.Fatal(err)
+	}
+	_, err = self.Call(gd.NewStringName("Missing"))
+	if !errors.Is(err, Error.MethodNotFound) || err.Error() != "Missing: invalid method" {
+		t.Fatalf("expected a method not found error, got %v", err)
+	}
+}
@@ -9,6 +9,37 @@ import (
 	"graphics.gd/internal/pointers"
 )
 
+// Migrator can be implemented by a registered class to upgrade the properties that were saved by
+// an older version of it, such as a Go resource whose fields have been renamed or changed type
+// since it was saved to a resource file. The version is saved with the other properties as
+// "version" (so no field of a Migrator can use that name), it should start at 1 and be increased
+// whenever the fields change in an incompatible way. The version is only known when a resource
+// is loaded, properties set in any other way (and those loaded from a resource saved before the
+// class implemented Migrator) are never migrated, nor are the properties of the engine class.
+type Migrator interface {
+	Version() int
+
+	// Migrate is called for each property loaded from a resource saved by an older version,
+	// returning the name and value of the property to set instead, or false to discard it.
+	// Properties that have not changed should be returned as they are.
+	Migrate(version int, name string, value any) (string, any, bool)
+}
+
+// versionProperty is the name of the property that stores the version of a [Migrator].
+const versionProperty = "version"
+
+// versionOf returns the property that stores the version of a [Migrator], it is only saved, so
+// that it is not shown in the inspector.
+func versionOf() gd.PropertyInfo {
+	return gd.PropertyInfo{
+		Type:       gd.TypeInt,
+		Name:       gd.NewStringName(versionProperty),
+		ClassName:  gd.NewStringName(""),
+		HintString: gd.NewString(""),
+		Usage:      int64(PropertyUsageStorage),
+	}
+}
+
 func propertyOf(field reflect.StructField) (gd.PropertyInfo, bool) {
 	var name = field.Name
 	tag, ok := field.Tag.Lookup("gd")
@@ -34,7 +65,7 @@ func propertyOf(field reflect.StructField) (gd.PropertyInfo, bool) {
 			if !ok {
 				return gd.PropertyInfo{}, false
 			}
-			if elem.Implements(reflect.TypeFor[ResourceClass.Any]()) {
+			if elem.Implements(reflect.TypeFor[ResourceClass.Any]()) || elem.Implements(reflect.TypeOf([0]interface{ Super() ResourceClass.Instance }{}).Elem()) {
 				hintString = fmt.Sprintf("%d/%d:%s", gd.TypeObject, PropertyHintResourceType, nameOf(elem)) // MAKE_RESOURCE_TYPE_HINT
 			} else {
 				hint |= PropertyHintArrayType
@@ -65,6 +96,13 @@ func propertyOf(field reflect.StructField) (gd.PropertyInfo, bool) {
 
 // Set needs to reference++ any resources that are sucessfully set.
 func (instance *instanceImplementation) Set(name gd.StringName, value gd.Variant) bool {
+	if migrator, ok := instance.Value.(Migrator); ok {
+		var migrated bool
+		name, value, migrated = instance.migrate(migrator, name, value)
+		if !migrated {
+			return true
+		}
+	}
 	if impl, ok := instance.Value.(interface {
 		Set(string, any) bool
 	}); ok {
@@ -107,6 +145,9 @@ func (instance *instanceImplementation) Set(name gd.StringName, value gd.Variant
 		if ok {
 			converted = reflect.ValueOf(ext)
 			isExtensionClass = true
+			if !converted.Type().AssignableTo(field.Type()) {
+				return false
+			}
 		}
 	}
 	if !converted.IsValid() {
@@ -129,11 +170,14 @@ func (instance *instanceImplementation) Set(name gd.StringName, value gd.Variant
 		if !ok {
 			return false
 		}
-		ref, ok := gd.As[gd.RefCounted](obj.AsObject()[0])
-		if ok {
-			ref.Reference()
+		keep(obj.AsObject())
+	}
+	if converted.Kind() == reflect.Slice && converted.Type().Elem().Implements(reflect.TypeOf([0]gd.IsClass{}).Elem()) {
+		for i := 0; i < converted.Len(); i++ {
+			if elem := converted.Index(i); !elem.IsZero() {
+				keep(elem.Interface().(gd.IsClass).AsObject())
+			}
 		}
-		pointers.Pin(obj.AsObject()[0])
 	}
 	field.Set(converted)
 	if impl, ok := instance.Value.(interface {
@@ -144,7 +188,72 @@ func (instance *instanceImplementation) Set(name gd.StringName, value gd.Variant
 	return true
 }
 
+// keep the object alive for as long as it is referred to by a field.
+func keep(obj [1]gd.Object) {
+	ref, ok := gd.As[gd.RefCounted](obj[0])
+	if ok {
+		ref.Reference()
+	}
+	pointers.Pin(obj[0])
+}
+
+// migrate the property being set, if the properties being set were saved by an older version of
+// the [Migrator], it reports false when the property should be discarded.
+func (instance *instanceImplementation) migrate(migrator Migrator, name gd.StringName, value gd.Variant) (gd.StringName, gd.Variant, bool) {
+	sname := name.String()
+	if sname == versionProperty {
+		// the loader sets the version before the properties of the class, as it is registered
+		// first, it is null when the resource was saved before any of its properties were set.
+		if version, ok := value.Interface().(gd.Int); ok && instance.pristine {
+			instance.version = int(version)
+		}
+		instance.pristine = false
+		return name, value, false
+	}
+	if !ownProperty(reflect.TypeOf(instance.Value).Elem(), sname) {
+		// properties of the engine class, such as resource_name, are loaded before the version
+		// and are never migrated.
+		return name, value, true
+	}
+	// the first property of the class was not the version, so the object is being edited (or
+	// loaded from a resource without a version) and the properties are already of the current
+	// version.
+	instance.pristine = false
+	if instance.version >= migrator.Version() {
+		return name, value, true
+	}
+	sname, migrated, ok := migrator.Migrate(instance.version, sname, value.Interface())
+	if !ok {
+		return name, value, false
+	}
+	return gd.NewStringName(sname), gd.NewVariant(migrated), true
+}
+
+// ownProperty reports whether the named property is registered for one of the fields of the
+// class, rather than inherited from the engine class that it extends.
+func ownProperty(rtype reflect.Type, name string) bool {
+	for i := 1; i < rtype.NumField(); i++ {
+		field := rtype.Field(i)
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		if tag := field.Tag.Get("gd"); tag == name || (tag == "" && field.Name == name) {
+			return true
+		}
+	}
+	return false
+}
+
 func (instance *instanceImplementation) Get(name gd.StringName) (gd.Variant, bool) {
+	if migrator, ok := instance.Value.(Migrator); ok && name.String() == versionProperty {
+		if instance.pristine {
+			// the engine samples the default value of each property from a new object and
+			// does not save values equal to it, so the version of a new object is null,
+			// rather than the current version, to make sure that the version is always saved.
+			return gd.Variant{}, true
+		}
+		return gd.NewVariant(migrator.Version()), true
+	}
 	if impl, ok := instance.Value.(interface {
 		Get(string) any
 	}); ok {
@@ -234,14 +343,28 @@ func (instance *instanceImplementation) PropertyGetRevert(name gd.StringName) (g
 	if !ok {
 		return gd.Variant{}, false
 	}
+	value, ok := defaultOf(field)
+	if !ok {
+		return gd.Variant{}, false
+	}
+	return gd.NewVariant(value.Interface()), true
+}
+
+// defaultOf returns the value of the field's default tag, or the zero value when the tag is
+// empty. Strings are used as they are, other values are scanned with [fmt.Sscanf].
+func defaultOf(field reflect.StructField) (reflect.Value, bool) {
 	var value = reflect.New(field.Type)
-	if tag := field.Tag.Get("default"); tag != "" {
-		_, err := fmt.Sscanf(tag, "%v", value.Interface())
-		if err != nil {
-			return gd.Variant{}, false
+	tag := field.Tag.Get("default")
+	switch {
+	case tag == "":
+	case field.Type.Kind() == reflect.String:
+		value.Elem().SetString(tag)
+	default:
+		if _, err := fmt.Sscanf(tag, "%v", value.Interface()); err != nil {
+			return reflect.Value{}, false
 		}
 	}
-	return gd.NewVariant(value.Elem().Interface()), true
+	return value.Elem(), true
 }
 
 func (instance *instanceImplementation) ValidateProperty(info *gd.PropertyInfo) bool {
@@ -25,6 +25,9 @@ func registerSignals(class gd.StringName, rtype reflect.Type) {
 		name = strings.TrimSuffix(name, ")")
 		name, args, _ := strings.Cut(name, "(")
 		argNames := strings.Split(args, ",")
+		for i := range argNames {
+			argNames[i] = strings.TrimSpace(argNames[i])
+		}
 		if reflect.PointerTo(field.Type).Implements(reflect.TypeOf([0]gd.IsSignal{}).Elem()) {
 			var signalName = gd.NewStringName(name)
 			var emit, ok = field.Type.MethodByName("Emit")
@@ -44,7 +47,7 @@ func registerSignals(class gd.StringName, rtype reflect.Type) {
 				vtype, ok := gd.VariantTypeOf(ftype.In(i))
 				if ok {
 					name := fmt.Sprintf("arg%d", i)
-					if i-1 < len(argNames) {
+					if i-1 < len(argNames) && argNames[i-1] != "" {
 						name = argNames[i-1]
 					}
 					args = append(args, gd.PropertyInfo{
@@ -66,7 +69,7 @@ func registerSignals(class gd.StringName, rtype reflect.Type) {
 					vtype, ok := gd.VariantTypeOf(arg)
 					if ok {
 						name := fmt.Sprintf("arg%d", i)
-						if i < len(argNames) {
+						if i < len(argNames) && argNames[i] != "" {
 							name = argNames[i]
 						}
 						args = append(args, gd.PropertyInfo{
@@ -79,9 +82,9 @@ func registerSignals(class gd.StringName, rtype reflect.Type) {
 			} else if !(etype.Kind() == reflect.Struct && etype.NumField() == 0) {
 				vtype, ok := gd.VariantTypeOf(etype)
 				if ok {
-					name := fmt.Sprintf("event", i)
-					if i-1 < len(argNames) {
-						name = argNames[i-1]
+					name := "event"
+					if argNames[0] != "" {
+						name = argNames[0]
 					}
 					args = append(args, gd.PropertyInfo{
 						Type:      vtype,
@@ -0,0 +1,42 @@
+package classdb_test
+
+import (
+	"slices"
+	"testing"
+
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Node"
+	"graphics.gd/startup/enginetest"
+)
+
+type Door struct {
+	classdb.Extension[Door, Node.Instance]
+
+	Opened  chan<- struct{}
+	Knocked chan<- int
+	Locked  chan<- string `gd:"locked(by)"`
+	Moved   chan<- func() (from, to float64)
+	Damaged chan<- func() (amount int, source string) `gd:"damaged(amount, source)"`
+}
+
+func TestSignalArguments(t *testing.T) {
+	for _, test := range []struct {
+		signal string
+		args   []string
+	}{
+		{"Opened", []string{}},
+		{"Knocked", []string{"event"}},
+		{"locked", []string{"by"}},
+		{"Moved", []string{"arg0", "arg1"}},
+		{"damaged", []string{"amount", "source"}},
+	} {
+		args, ok := enginetest.SignalArguments("Door", test.signal)
+		if !ok {
+			t.Errorf("%s: signal was not registered", test.signal)
+			continue
+		}
+		if !slices.Equal(args, test.args) {
+			t.Errorf("%s: expected arguments %q, got %q", test.signal, test.args, args)
+		}
+	}
+}
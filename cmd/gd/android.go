@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"graphics.gd/format/cfg"
+	"runtime.link/api/xray"
+)
+
+// androidAPI is the minimum Android API level that Godot supports, it can be overridden with
+// $ANDROID_API to target a newer NDK platform.
+const androidAPI = "21"
+
+// androidActivity is the activity that the engine's export templates launch the game from.
+const androidActivity = "com.godot.game.GodotApp"
+
+// androidPreset is the export preset that gd adds to export_presets.cfg when the project does
+// not have one for Android, the %d is the preset number and the %s the package name.
+const androidPreset = `[preset.%[1]d]
+
+name="Android"
+platform="Android"
+runnable=true
+dedicated_server=false
+custom_features=""
+export_filter="all_resources"
+include_filter=""
+exclude_filter=""
+export_path=".godot/android/debug.apk"
+encryption_include_filters=""
+encryption_exclude_filters=""
+encrypt_pck=false
+encrypt_directory=false
+script_export_mode=2
+
+[preset.%[1]d.options]
+
+gradle_build/use_gradle_build=false
+architectures/armeabi-v7a=false
+architectures/arm64-v8a=true
+architectures/x86=false
+architectures/x86_64=false
+package/unique_name="%[2]s"
+package/signed=true
+`
+
+// androidToolchain returns the NDK clang compiler to cross-compile the library with, the NDK is
+// located with $ANDROID_NDK_HOME, $ANDROID_NDK_ROOT or $ANDROID_HOME/ndk-bundle.
+func androidToolchain(getenv func(string) string, GOOS, GOARCH string) (string, error) {
+	ndk := getenv("ANDROID_NDK_HOME")
+	if ndk == "" {
+		ndk = getenv("ANDROID_NDK_ROOT")
+	}
+	if ndk == "" && getenv("ANDROID_HOME") != "" {
+		ndk = filepath.Join(getenv("ANDROID_HOME"), "ndk-bundle")
+	}
+	if ndk == "" {
+		return "", errors.New("gd: -target android requires $ANDROID_NDK_HOME to be set to the location of the Android NDK")
+	}
+	prebuilt := filepath.Join(ndk, "toolchains", "llvm", "prebuilt")
+	host, err := androidHost(prebuilt, GOOS, GOARCH)
+	if err != nil {
+		return "", err
+	}
+	api := getenv("ANDROID_API")
+	if api == "" {
+		api = androidAPI
+	}
+	clang := filepath.Join(prebuilt, host, "bin", "aarch64-linux-android"+api+"-clang")
+	if GOOS == "windows" {
+		clang += ".cmd"
+	}
+	if _, err := os.Stat(clang); err != nil {
+		return "", fmt.Errorf("gd: cannot find the NDK compiler for Android API %v: %w", api, err)
+	}
+	return clang, nil
+}
+
+// androidHost returns the directory of the NDK's prebuilt toolchain that runs on the given host,
+// named after its GOOS and CPU. The NDK only ships x86_64 toolchains for macOS (as universal
+// binaries) and Windows, so these are used when there isn't one for the host's CPU.
+func androidHost(prebuilt, GOOS, GOARCH string) (string, error) {
+	var arch string
+	switch GOARCH {
+	case "amd64":
+		arch = "x86_64"
+	case "arm64":
+		arch = "aarch64"
+		if GOOS == "darwin" {
+			arch = "arm64"
+		}
+	case "386":
+		arch = "x86"
+	default:
+		arch = GOARCH
+	}
+	for _, host := range []string{GOOS + "-" + arch, GOOS + "-x86_64"} {
+		if info, err := os.Stat(filepath.Join(prebuilt, host)); err == nil && info.IsDir() {
+			return host, nil
+		}
+	}
+	return "", fmt.Errorf("gd: the Android NDK in %v does not have a toolchain for %v/%v", prebuilt, GOOS, GOARCH)
+}
+
+// androidPackage returns the Android package name to use for the project.
+func androidPackage(project string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return -1
+		}
+	}, project)
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "game" + name
+	}
+	return "gd." + name
+}
+
+// setupAndroid adds an Android export preset to the project, unless it already has one, and
+// returns the package name of the preset.
+func setupAndroid(graphics, project string) (string, error) {
+	path := filepath.Join(graphics, "export_presets.cfg")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", xray.New(err)
+	}
+	presets, err := cfg.Parse(data)
+	if err != nil {
+		return "", fmt.Errorf("gd: %s: %w", path, err)
+	}
+	n := 0
+	for ; presets.Section(fmt.Sprintf("preset.%d", n)) != nil; n++ {
+		section := fmt.Sprintf("preset.%d", n)
+		if platform, _ := presets.Get(section, "platform"); platform == "Android" {
+			name, _ := presets.Get(section+".options", "package/unique_name")
+			if name, ok := name.(string); ok && name != "" && !strings.Contains(name, "$genname") {
+				return name, nil
+			}
+			return androidPackage(project), nil
+		}
+	}
+	name := androidPackage(project)
+	preset, err := cfg.Parse([]byte(fmt.Sprintf(androidPreset, n, name)))
+	if err != nil {
+		return "", xray.New(err)
+	}
+	if len(presets.Sections) > 0 {
+		preset.Sections[0].Comments = []string{""}
+	}
+	presets.Sections = append(presets.Sections, preset.Sections...)
+	updated, err := presets.Bytes()
+	if err != nil {
+		return "", xray.New(err)
+	}
+	if err := os.WriteFile(path, updated, 0o644); err != nil {
+		return "", xray.New(err)
+	}
+	return name, nil
+}
+
+// runAndroid exports a debug APK for the project, then (if a device is connected) installs it
+// with adb, launches it and streams the engine and Go logs until the user interrupts gd.
+func runAndroid(godot, graphics, project string) error {
+	pkg, err := setupAndroid(graphics, project)
+	if err != nil {
+		return xray.New(err)
+	}
+	apk, err := filepath.Abs(filepath.Join(graphics, ".godot", "android", "debug.apk"))
+	if err != nil {
+		return xray.New(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(apk), 0o755); err != nil {
+		return xray.New(err)
+	}
+	export := exec.Command(godot, "--headless", "--export-debug", "Android", apk)
+	export.Dir = graphics
+	export.Stderr = os.Stderr
+	export.Stdout = os.Stdout
+	if err := export.Run(); err != nil {
+		return fmt.Errorf("gd: failed to export the Android APK (are the Android export templates and SDK configured?): %w", err)
+	}
+	fmt.Println("gd: exported", apk)
+	adb, err := exec.LookPath("adb")
+	if err != nil {
+		fmt.Println("gd: adb not found, skipping deployment")
+		return nil
+	}
+	state, err := exec.Command(adb, "get-state").Output()
+	if err != nil || string(bytes.TrimSpace(state)) != "device" {
+		fmt.Println("gd: no Android device connected, skipping deployment")
+		return nil
+	}
+	for _, args := range [][]string{
+		{"install", "-r", apk},
+		{"logcat", "-c"},
+		{"shell", "am", "start", "-n", pkg + "/" + androidActivity},
+	} {
+		cmd := exec.Command(adb, args...)
+		cmd.Stderr = os.Stderr
+		cmd.Stdout = os.Stdout
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("gd: adb %s: %w", strings.Join(args, " "), err)
+		}
+	}
+	logcat := exec.Command(adb, "logcat", "-v", "brief", "godot:V", "Go:V", "GoLog:V", "*:S")
+	logcat.Stderr = os.Stderr
+	logcat.Stdout = os.Stdout
+	return xray.New(logcat.Run())
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeNDK creates the prebuilt toolchain directories of an NDK for each of the hosts, with a
+// clang for the default Android API.
+func fakeNDK(t *testing.T, hosts ...string) string {
+	t.Helper()
+	ndk := t.TempDir()
+	for _, host := range hosts {
+		clang := "aarch64-linux-android" + androidAPI + "-clang"
+		if strings.HasPrefix(host, "windows") {
+			clang += ".cmd"
+		}
+		writeFile(t, filepath.Join(ndk, "toolchains", "llvm", "prebuilt", host, "bin", clang), "")
+	}
+	return ndk
+}
+
+func TestAndroidHost(t *testing.T) {
+	ndk := fakeNDK(t, "linux-x86_64", "linux-aarch64", "darwin-x86_64", "windows-x86_64")
+	prebuilt := filepath.Join(ndk, "toolchains", "llvm", "prebuilt")
+	for _, test := range []struct {
+		GOOS, GOARCH, host string
+	}{
+		{"linux", "amd64", "linux-x86_64"},
+		{"linux", "arm64", "linux-aarch64"},
+		{"darwin", "arm64", "darwin-x86_64"},
+		{"darwin", "amd64", "darwin-x86_64"},
+		{"windows", "arm64", "windows-x86_64"},
+	} {
+		host, err := androidHost(prebuilt, test.GOOS, test.GOARCH)
+		if err != nil || host != test.host {
+			t.Errorf("%s/%s: expected %s, got %q (%v)", test.GOOS, test.GOARCH, test.host, host, err)
+		}
+	}
+	if host, err := androidHost(filepath.Join(fakeNDK(t, "darwin-arm64", "darwin-x86_64"), "toolchains", "llvm", "prebuilt"), "darwin", "arm64"); err != nil || host != "darwin-arm64" {
+		t.Errorf("expected the native darwin-arm64 toolchain, got %q (%v)", host, err)
+	}
+	if _, err := androidHost(prebuilt, "freebsd", "amd64"); err == nil {
+		t.Error("expected an error for a host without a toolchain")
+	}
+}
+
+func TestAndroidToolchain(t *testing.T) {
+	ndk := fakeNDK(t, "linux-x86_64", "windows-x86_64")
+	env := func(vars map[string]string) func(string) string {
+		return func(key string) string { return vars[key] }
+	}
+	clang, err := androidToolchain(env(map[string]string{"ANDROID_NDK_HOME": ndk}), "linux", "amd64")
+	if expected := filepath.Join(ndk, "toolchains", "llvm", "prebuilt", "linux-x86_64", "bin", "aarch64-linux-android21-clang"); err != nil || clang != expected {
+		t.Fatalf("expected %s, got %q (%v)", expected, clang, err)
+	}
+	clang, err = androidToolchain(env(map[string]string{"ANDROID_NDK_ROOT": ndk}), "windows", "amd64")
+	if err != nil || !strings.HasSuffix(clang, filepath.Join("windows-x86_64", "bin", "aarch64-linux-android21-clang.cmd")) {
+		t.Fatalf("expected the windows toolchain from $ANDROID_NDK_ROOT, got %q (%v)", clang, err)
+	}
+	sdk := t.TempDir()
+	if err := os.Rename(ndk, filepath.Join(sdk, "ndk-bundle")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := androidToolchain(env(map[string]string{"ANDROID_HOME": sdk}), "linux", "arm64"); err != nil {
+		t.Fatalf("expected the ndk-bundle of $ANDROID_HOME to be used, got %v", err)
+	}
+	if _, err := androidToolchain(env(map[string]string{"ANDROID_HOME": sdk, "ANDROID_API": "34"}), "linux", "amd64"); err == nil || !strings.Contains(err.Error(), "API 34") {
+		t.Fatalf("expected an error for a missing API level, got %v", err)
+	}
+	if _, err := androidToolchain(env(nil), "linux", "amd64"); err == nil || !strings.Contains(err.Error(), "ANDROID_NDK_HOME") {
+		t.Fatalf("expected an error without an NDK, got %v", err)
+	}
+}
@@ -1,17 +1,23 @@
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
 	"go/ast"
+	"go/format"
 	"go/parser"
 	"go/token"
 	"go/types"
 	"os"
+	"path/filepath"
 	"runtime"
 	"slices"
 	"strings"
 
+	"golang.org/x/mod/semver"
 	"golang.org/x/tools/go/packages"
+	udiff "graphics.gd/cmd/gd/internal/diff"
 	"graphics.gd/cmd/gd/internal/refactor/eg"
 	"graphics.gd/variant/String"
 	"runtime.link/api/xray"
@@ -43,21 +49,137 @@ func checkForFixes(undefined []string) {
 	}
 }
 
-func fix() error {
+// migration is a before/after example, used to rewrite deprecated code.
+type migration struct {
+	module  string // path of the module that provides the migration.
+	version string // version of the module that the migration applies to, empty applies to all versions.
+	source  string // Go source with 'before' and 'after' functions (see [eg.Help]).
+}
+
+// migrationFile is the name of the file that modules which build on graphics.gd can place at
+// their module root, in order to provide 'gd fix' migrations for their own deprecations. It
+// uses the same format as deprecated.txt
+const migrationFile = "gdfix.txt"
+
+// parseMigrations splits a migration file into its examples, each example may be annotated
+// with a //gd:version directive, indicating the version of the module that introduced the
+// change.
+func parseMigrations(module, file string) []migration {
+	var migrations []migration
+	for example := range String.Splits(file, "\n\n") {
+		if strings.TrimSpace(example) == "" {
+			continue
+		}
+		var version string
+		for _, line := range strings.Split(example, "\n") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(line), "//gd:version "); ok {
+				version = strings.TrimSpace(v)
+			}
+		}
+		migrations = append(migrations, migration{module: module, version: version, source: example})
+	}
+	return migrations
+}
+
+// moduleMigrations returns the migrations that the module provides in its [migrationFile] and
+// records the version of the module in to, unless it was already given.
+func moduleMigrations(mod *packages.Module, to versions) []migration {
+	version := mod.Version
+	if mod.Replace != nil {
+		version = mod.Replace.Version
+	}
+	if _, ok := to[mod.Path]; !ok && semver.IsValid(version) {
+		to[mod.Path] = version
+	}
+	if mod.Path == "graphics.gd" || mod.Dir == "" {
+		return nil
+	}
+	file, err := os.ReadFile(filepath.Join(mod.Dir, migrationFile))
+	if err != nil {
+		return nil
+	}
+	return parseMigrations(mod.Path, string(file))
+}
+
+// versions maps module paths to versions, [flag.Value] accepts either a version of
+// graphics.gd or module@version.
+type versions map[string]string
+
+func (v versions) String() string { return fmt.Sprint(map[string]string(v)) }
+
+func (v versions) Set(s string) error {
+	module, version, ok := strings.Cut(s, "@")
+	if !ok {
+		module, version = "graphics.gd", s
+	}
+	if !semver.IsValid(version) {
+		return fmt.Errorf("invalid version %q", version)
+	}
+	v[module] = version
+	return nil
+}
+
+// applies reports whether the migration falls within the version range being upgraded across.
+func (m migration) applies(from, to versions) bool {
+	if m.version == "" {
+		return true
+	}
+	if v, ok := from[m.module]; ok && semver.Compare(m.version, v) <= 0 {
+		return false // already upgraded past this migration.
+	}
+	if v, ok := to[m.module]; ok && semver.Compare(m.version, v) > 0 {
+		return false // not upgrading this far yet.
+	}
+	return true
+}
+
+func fix(args []string) error {
+	var (
+		from = versions{}
+		to   = versions{}
+	)
+	flags := flag.NewFlagSet("gd fix", flag.ContinueOnError)
+	diff := flags.Bool("diff", false, "print a unified diff of the changes, instead of rewriting files")
+	flags.Var(from, "from", "only apply migrations newer than this `[module@]version`")
+	flags.Var(to, "to", "only apply migrations up to this `[module@]version` (defaults to the required version)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: gd fix [-diff] [-from [module@]version] [-to [module@]version] [packages]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	patterns := flags.Args()
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
 	cfg := &packages.Config{
 		Fset:  token.NewFileSet(),
-		Mode:  packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps | packages.NeedCompiledGoFiles,
+		Mode:  packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedImports | packages.NeedDeps | packages.NeedCompiledGoFiles | packages.NeedModule,
 		Tests: true,
 	}
-	pkgs, err := packages.Load(cfg, "./...")
+	pkgs, err := packages.Load(cfg, patterns...)
 	if err != nil {
 		return xray.New(err)
 	}
+	migrations := parseMigrations("graphics.gd", fixes)
+	modules := make(map[string]bool)
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		mod := pkg.Module
+		if mod == nil || modules[mod.Path] {
+			return
+		}
+		modules[mod.Path] = true
+		migrations = append(migrations, moduleMigrations(mod, to)...)
+	})
 	var transformers []*eg.Transformer
-	for example := range String.Splits(fixes, "\n\n") {
-		f, err := parser.ParseFile(cfg.Fset, "/tmp/fixes.go", strings.NewReader(example), parser.ParseComments)
+	for _, migration := range migrations {
+		if !migration.applies(from, to) {
+			continue
+		}
+		f, err := parser.ParseFile(cfg.Fset, "/tmp/fixes.go", strings.NewReader(migration.source), parser.ParseComments)
 		if err != nil {
-			return xray.New(err)
+			return xray.New(fmt.Errorf("%s migration: %w", migration.module, err))
 		}
 		tInfo := types.Info{
 			Types:      make(map[ast.Expr]types.TypeAndValue),
@@ -71,16 +193,21 @@ func fix() error {
 		tPkg, _ := conf.Check("egtemplate", cfg.Fset, []*ast.File{f}, &tInfo)
 		xform, err := eg.NewTransformer(cfg.Fset, tPkg, f, &tInfo, false)
 		if err != nil {
-			return xray.New(err)
+			return xray.New(fmt.Errorf("%s migration: %w", migration.module, err))
 		}
 		transformers = append(transformers, xform)
 	}
 	var hadErrors bool
+	handled := make(map[string]bool)
 	for _, pkg := range pkgs {
 		for i, filename := range pkg.CompiledGoFiles {
 			if filename == "/tmp/fixes.go" {
 				continue // Don't rewrite the template file.
 			}
+			if handled[filename] {
+				continue // the package's files are loaded again, for its test variant.
+			}
+			handled[filename] = true
 			file := pkg.Syntax[i]
 			var n int
 			for _, xform := range transformers {
@@ -89,6 +216,20 @@ func fix() error {
 			if n == 0 {
 				continue
 			}
+			if *diff {
+				original, err := os.ReadFile(filename)
+				if err != nil {
+					return xray.New(err)
+				}
+				var rewritten bytes.Buffer
+				if err := format.Node(&rewritten, cfg.Fset, file); err != nil {
+					fmt.Fprintf(os.Stderr, "eg: %s\n", err)
+					hadErrors = true
+					continue
+				}
+				fmt.Print(udiff.Unified(filename, filename, string(original), rewritten.String()))
+				continue
+			}
 			fmt.Fprintf(os.Stderr, "=== %s (%d matches)\n", filename, n)
 			if err := eg.WriteAST(cfg.Fset, filename, file); err != nil {
 				fmt.Fprintf(os.Stderr, "eg: %s\n", err)
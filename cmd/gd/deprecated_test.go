@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const testMigrations = `package fixes
+
+func before() { Old() }
+func after()  { New() }
+
+//gd:version v1.2.0
+func before() { Older() }
+func after()  { Newer() }
+
+`
+
+func TestParseMigrations(t *testing.T) {
+	migrations := parseMigrations("example.com/lib", testMigrations)
+	if len(migrations) != 3 {
+		t.Fatalf("expected 3 examples, got %d", len(migrations))
+	}
+	for i, version := range []string{"", "", "v1.2.0"} {
+		if migrations[i].module != "example.com/lib" || migrations[i].version != version {
+			t.Fatalf("migration %d: unexpected %s@%q", i, migrations[i].module, migrations[i].version)
+		}
+	}
+	if len(parseMigrations("graphics.gd", fixes)) == 0 {
+		t.Fatal("expected deprecated.txt to have migrations")
+	}
+}
+
+func TestMigrationApplies(t *testing.T) {
+	m := migration{module: "example.com/lib", version: "v1.2.0"}
+	for _, test := range []struct {
+		from, to string
+		applies  bool
+	}{
+		{"", "", true},
+		{"v1.1.0", "v1.2.0", true},
+		{"v1.1.0", "v1.3.0", true},
+		{"v1.2.0", "v1.3.0", false},
+		{"v1.0.0", "v1.1.0", false},
+	} {
+		from, to := versions{}, versions{}
+		if test.from != "" {
+			if err := from.Set("example.com/lib@" + test.from); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if test.to != "" {
+			if err := to.Set("example.com/lib@" + test.to); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if applies := m.applies(from, to); applies != test.applies {
+			t.Errorf("from %q to %q: expected %v, got %v", test.from, test.to, test.applies, applies)
+		}
+	}
+	if !(migration{module: "example.com/lib"}).applies(versions{"example.com/lib": "v9.0.0"}, versions{}) {
+		t.Fatal("expected a migration without a version to always apply")
+	}
+	if (migration{module: "graphics.gd", version: "v0.2.0"}).applies(versions{}, versions{"graphics.gd": "v0.1.0"}) {
+		t.Fatal("expected a newer graphics.gd migration not to apply")
+	}
+	v := versions{}
+	if err := v.Set("v0.3.0"); err != nil || v["graphics.gd"] != "v0.3.0" {
+		t.Fatalf("expected a bare version to refer to graphics.gd, got %v (%v)", v, err)
+	}
+	if err := v.Set("example.com/lib@latest"); err == nil {
+		t.Fatal("expected an invalid version to be rejected")
+	}
+}
+
+func TestModuleMigrations(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, migrationFile), []byte(testMigrations), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	to := versions{}
+	migrations := moduleMigrations(&packages.Module{Path: "example.com/lib", Version: "v1.3.0", Dir: dir}, to)
+	if len(migrations) != 3 || migrations[2].version != "v1.2.0" {
+		t.Fatalf("expected the migrations of %s, got %v", migrationFile, migrations)
+	}
+	if to["example.com/lib"] != "v1.3.0" {
+		t.Fatalf("expected the required version to be the upper bound, got %v", to)
+	}
+	to = versions{"example.com/lib": "v1.1.0"}
+	replaced := &packages.Module{Path: "example.com/lib", Version: "v1.3.0", Replace: &packages.Module{Version: "v1.4.0"}, Dir: dir}
+	if moduleMigrations(replaced, to); to["example.com/lib"] != "v1.1.0" {
+		t.Fatalf("expected -to to take precedence over the required version, got %v", to)
+	}
+	to = versions{}
+	if moduleMigrations(replaced, to); to["example.com/lib"] != "v1.4.0" {
+		t.Fatalf("expected the version of the replacement, got %v", to)
+	}
+	if migrations := moduleMigrations(&packages.Module{Path: "example.com/other", Dir: t.TempDir()}, versions{}); len(migrations) != 0 {
+		t.Fatalf("expected no migrations without a %s, got %v", migrationFile, migrations)
+	}
+}
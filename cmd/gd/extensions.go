@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+
+	"graphics.gd/format/cfg"
+	"runtime.link/api/xray"
+)
+
+// extension is a Go library that is built as a GDExtension, each extension is described by
+// a .gdextension file in the graphics directory. The project's main package is always built
+// into the 'library' extension, any other .gdextension file that names a Go package under a
+// [gd] section is managed by gd too:
+//
+//	[gd]
+//	package = "./tools/editor"
+//
+// Each extension has its own entry_symbol, which defaults to "load" followed by the name of
+// the extension in camel case (so editor_tools.gdextension is loaded with loadEditorTools).
+// The package exports it with [graphics.gd/startup.LoadExtension]:
+//
+//	//export loadEditorTools
+//	func loadEditorTools(lookupFunc uintptr, classes, configuration unsafe.Pointer) uint8 {
+//		return startup.LoadExtension(lookupFunc, classes, configuration)
+//	}
+//
+// gd only adds the entries that are missing from these files, so the entry_symbol,
+// compatibility_maximum, [dependencies], [icons] and any other user edits are preserved.
+type extension struct {
+	name   string // name of the .gdextension file, without the file extension.
+	pkg    string // Go package to build, empty for the project's main package.
+	symbol string // entry_symbol that the engine calls to load the library.
+}
+
+// extensionPlatforms are the Godot feature tags that gd builds libraries for.
+var extensionPlatforms = []struct {
+	feature, GOOS, GOARCH string
+}{
+	{"linux.x86_64", "linux", "amd64"},
+	{"linux.arm64", "linux", "arm64"},
+	{"windows.x86_64", "windows", "amd64"},
+	{"android.arm64", "android", "arm64"},
+	{"macos.release", "darwin", "universal"},
+	{"macos.debug", "darwin", "universal"},
+	{"macos.arm64", "darwin", "arm64"},
+	{"macos.amd64", "darwin", "amd64"},
+}
+
+// library returns the file name of the extension's shared library, relative to the graphics
+// directory.
+func (ext extension) library(GOOS, GOARCH string) string {
+	name := GOOS + "_" + GOARCH
+	if ext.name != "library" {
+		name = ext.name + "_" + name
+	}
+	switch GOOS {
+	case "windows":
+		return name + ".dll"
+	case "darwin":
+		return name + ".dylib"
+	default:
+		return name + ".so"
+	}
+}
+
+// entrySymbol returns the default entry_symbol for the extension with the given name.
+func entrySymbol(name string) string {
+	if name == "library" {
+		return "loadExtension"
+	}
+	symbol := "load"
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		symbol += strings.ToUpper(word[:1]) + word[1:]
+	}
+	return symbol
+}
+
+// extensions returns the library extension, followed by any other extensions in the graphics
+// directory that are managed by gd.
+func extensions(graphics string) ([]extension, error) {
+	exts := []extension{{name: "library", symbol: entrySymbol("library")}}
+	files, err := filepath.Glob(filepath.Join(graphics, "*.gdextension"))
+	if err != nil {
+		return nil, xray.New(err)
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".gdextension")
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, xray.New(err)
+		}
+		config, err := cfg.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("gd: %s: %w", file, err)
+		}
+		symbol, ok := config.Get("configuration", "entry_symbol")
+		if !ok {
+			symbol = entrySymbol(name)
+		}
+		if name == "library" {
+			exts[0].symbol = fmt.Sprint(symbol)
+			continue
+		}
+		pkg, ok := config.Get("gd", "package")
+		if !ok {
+			continue // not managed by gd.
+		}
+		exts = append(exts, extension{name: name, pkg: fmt.Sprint(pkg), symbol: fmt.Sprint(symbol)})
+	}
+	return exts, nil
+}
+
+// setupExtension creates or updates the extension's .gdextension file, so that it includes the
+// required configuration and an entry for each supported platform.
+func setupExtension(graphics string, ext extension) error {
+	path := filepath.Join(graphics, ext.name+".gdextension")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return xray.New(err)
+	}
+	missing := os.IsNotExist(err)
+	if missing && ext.name == "library" {
+		data = []byte(library_gdextension)
+	}
+	config, err := cfg.Parse(data)
+	if err != nil {
+		return fmt.Errorf("gd: %s: %w", path, err)
+	}
+	changed := missing
+	ensure := func(section, key string, value any) {
+		if _, ok := config.Get(section, key); !ok {
+			config.Set(section, key, value)
+			changed = true
+		}
+	}
+	ensure("configuration", "entry_symbol", ext.symbol)
+	ensure("configuration", "compatibility_minimum", version)
+	for _, platform := range extensionPlatforms {
+		ensure("libraries", platform.feature, ext.library(platform.GOOS, platform.GOARCH))
+	}
+	if !changed {
+		return nil
+	}
+	updated, err := config.Bytes()
+	if err != nil {
+		return xray.New(err)
+	}
+	if err := os.WriteFile(path, updated, 0o644); err != nil {
+		return xray.New(err)
+	}
+	return nil
+}
+
+// setupExtensionList ensures that each of the extensions is listed in the engine's
+// extension_list.cfg, so that they are loaded without having to restart the editor.
+func setupExtensionList(graphics string, exts []extension) error {
+	path := filepath.Join(graphics, ".godot", "extension_list.cfg")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return xray.New(err)
+	}
+	lines := strings.Fields(string(data))
+	var missing bool
+	for _, ext := range exts {
+		if res := "res://" + ext.name + ".gdextension"; !slices.Contains(lines, res) {
+			lines = append(lines, res)
+			missing = true
+		}
+	}
+	if !missing {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		return xray.New(err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEntrySymbol(t *testing.T) {
+	for name, symbol := range map[string]string{
+		"library":      "loadExtension",
+		"editor_tools": "loadEditorTools",
+		"ai-2d":        "loadAi2d",
+	} {
+		if got := entrySymbol(name); got != symbol {
+			t.Errorf("entrySymbol(%q) = %q, expected %q", name, got, symbol)
+		}
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	graphics := t.TempDir()
+	exts, err := extensions(graphics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exts) != 1 || exts[0] != (extension{name: "library", symbol: "loadExtension"}) {
+		t.Fatalf("expected only the library extension, got %v", exts)
+	}
+	writeFile(t, filepath.Join(graphics, "library.gdextension"), "[configuration]\nentry_symbol = \"loadGame\"\n")
+	writeFile(t, filepath.Join(graphics, "editor_tools.gdextension"), "[gd]\npackage = \"./tools/editor\"\n")
+	writeFile(t, filepath.Join(graphics, "custom.gdextension"), "[gd]\npackage = \"./custom\"\n\n[configuration]\nentry_symbol = \"custom_init\"\n")
+	writeFile(t, filepath.Join(graphics, "thirdparty.gdextension"), "[configuration]\nentry_symbol = \"thirdparty_init\"\n")
+	exts, err = extensions(graphics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []extension{
+		{name: "library", symbol: "loadGame"},
+		{name: "custom", pkg: "./custom", symbol: "custom_init"},
+		{name: "editor_tools", pkg: "./tools/editor", symbol: "loadEditorTools"},
+	}
+	if !slices.Equal(exts, expected) {
+		t.Fatalf("expected %v, got %v", expected, exts)
+	}
+	writeFile(t, filepath.Join(graphics, "broken.gdextension"), "[gd\n")
+	if _, err := extensions(graphics); err == nil || !strings.Contains(err.Error(), "broken.gdextension") {
+		t.Fatalf("expected an error naming the invalid file, got %v", err)
+	}
+}
+
+func TestSetupExtension(t *testing.T) {
+	graphics := t.TempDir()
+	library := extension{name: "library", symbol: "loadExtension"}
+	if err := setupExtension(graphics, library); err != nil {
+		t.Fatal(err)
+	}
+	written := readProject(t, filepath.Join(graphics, "library.gdextension"))
+	template := readProject(t, "graphics/library.gdextension")
+	for _, section := range template.Sections {
+		for _, key := range section.Keys {
+			if value, _ := written.Get(section.Name, key.Name); value != key.Value {
+				t.Errorf("%s/%s: expected %v from the template, got %v", section.Name, key.Name, key.Value, value)
+			}
+		}
+	}
+	tools := extension{name: "editor_tools", pkg: "./tools/editor", symbol: "loadEditorTools"}
+	path := filepath.Join(graphics, "editor_tools.gdextension")
+	edited := `; edited by hand
+[gd]
+package = "./tools/editor"
+
+[configuration]
+entry_symbol = "loadMyTools"
+compatibility_maximum = "4.4"
+
+[libraries]
+linux.x86_64 = "custom/tools.so"
+
+[icons]
+EditorTools = "res://icon.svg"
+`
+	writeFile(t, path, edited)
+	if err := setupExtension(graphics, tools); err != nil {
+		t.Fatal(err)
+	}
+	project := readProject(t, path)
+	for _, setting := range []struct {
+		section, key string
+		value        any
+	}{
+		{"gd", "package", "./tools/editor"},
+		{"configuration", "entry_symbol", "loadMyTools"},
+		{"configuration", "compatibility_minimum", version},
+		{"configuration", "compatibility_maximum", "4.4"},
+		{"libraries", "linux.x86_64", "custom/tools.so"},
+		{"libraries", "windows.x86_64", "editor_tools_windows_amd64.dll"},
+		{"libraries", "macos.release", "editor_tools_darwin_universal.dylib"},
+		{"icons", "EditorTools", "res://icon.svg"},
+	} {
+		if value, _ := project.Get(setting.section, setting.key); value != setting.value {
+			t.Errorf("%s/%s: expected %v, got %v", setting.section, setting.key, setting.value, value)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "; edited by hand") {
+		t.Fatalf("expected the comment to be kept, got %q", data)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := setupExtension(graphics, tools); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := os.ReadFile(path); err != nil || string(again) != string(data) {
+		t.Fatalf("expected a complete file to be left alone, got %q (%v)", again, err)
+	}
+	if after, _ := os.Stat(path); !after.ModTime().Equal(info.ModTime()) {
+		t.Fatal("expected a complete file not to be rewritten")
+	}
+}
+
+func TestSetupExtensionList(t *testing.T) {
+	graphics := t.TempDir()
+	path := filepath.Join(graphics, ".godot", "extension_list.cfg")
+	writeFile(t, path, "res://library.gdextension\nres://addons/thirdparty.gdextension\n")
+	exts := []extension{{name: "library"}, {name: "editor_tools"}}
+	if err := setupExtensionList(graphics, exts); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "res://library.gdextension\nres://addons/thirdparty.gdextension\nres://editor_tools.gdextension"
+	if string(data) != expected {
+		t.Fatalf("expected %q, got %q", expected, data)
+	}
+	if err := setupExtensionList(graphics, exts); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := os.ReadFile(path); string(again) != expected {
+		t.Fatalf("expected the list to be unchanged, got %q", again)
+	}
+}
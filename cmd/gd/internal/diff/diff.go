@@ -0,0 +1,152 @@
+// Package diff produces unified diffs between two versions of a text file.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines to show around each change.
+const context = 3
+
+type op struct {
+	kind byte // ' ', '-' or '+'
+	line string
+}
+
+// Unified returns a unified diff of the two texts, labelled with the given names.
+// Returns an empty string if the texts are identical.
+func Unified(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	ops := lines(splitLines(oldText), splitLines(newText))
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+	var (
+		oldLine, newLine = 1, 1
+	)
+	for i := 0; i < len(ops); {
+		if ops[i].kind == ' ' {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+		// expand the hunk to cover every change within 2*context lines of each other.
+		start := max(i-context, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*context {
+				end = min(end+context, len(ops))
+				break
+			}
+			end = next
+		}
+		hunkOld, hunkNew := oldLine-(i-start), newLine-(i-start)
+		var oldCount, newCount int
+		for _, o := range ops[start:end] {
+			if o.kind != '+' {
+				oldCount++
+			}
+			if o.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(hunkOld, oldCount), hunkRange(hunkNew, newCount))
+		for _, o := range ops[start:end] {
+			out.WriteByte(o.kind)
+			out.WriteString(o.line)
+			if !strings.HasSuffix(o.line, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		for _, o := range ops[i:end] {
+			if o.kind != '+' {
+				oldLine++
+			}
+			if o.kind != '-' {
+				newLine++
+			}
+		}
+		i = end
+	}
+	return out.String()
+}
+
+func hunkRange(line, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", line-1)
+	case 1:
+		return fmt.Sprint(line)
+	default:
+		return fmt.Sprintf("%d,%d", line, count)
+	}
+}
+
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lines computes the edit script between a and b, common prefixes and suffixes are
+// trimmed before the longest common subsequence is computed on what remains (which
+// for refactorings, is usually small).
+func lines(a, b []string) []op {
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	var ops []op
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{' ', line})
+	}
+	x, y := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			ops = append(ops, op{' ', x[i]})
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{'-', x[i]})
+			i++
+		default:
+			ops = append(ops, op{'+', y[j]})
+			j++
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{' ', line})
+	}
+	return ops
+}
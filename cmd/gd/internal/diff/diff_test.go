@@ -0,0 +1,54 @@
+package diff_test
+
+import (
+	"testing"
+
+	"graphics.gd/cmd/gd/internal/diff"
+)
+
+func TestUnified(t *testing.T) {
+	var (
+		before = "package main\n\nimport \"graphics.gd/startup\"\n\nfunc main() {\n\tstartup.Loader()\n\tstartup.Engine()\n}\n"
+		after  = "package main\n\nimport \"graphics.gd/startup\"\n\nfunc main() {\n\tstartup.LoadingScene()\n\tstartup.Scene()\n}\n"
+	)
+	const expected = `--- a/main.go
++++ b/main.go
+@@ -3,6 +3,6 @@
+ import "graphics.gd/startup"
+ 
+ func main() {
+-	startup.Loader()
+-	startup.Engine()
++	startup.LoadingScene()
++	startup.Scene()
+ }
+`
+	if got := diff.Unified("a/main.go", "b/main.go", before, after); got != expected {
+		t.Fatalf("unexpected diff:\n%s", got)
+	}
+	const separate = `--- a
++++ b
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -9,5 +9,6 @@
+ i
+ j
+ k
+-l
++L
+ m
++n
+\ No newline at end of file
+`
+	if got := diff.Unified("a", "b", "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm\n", "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nL\nm\nn"); got != separate {
+		t.Fatalf("unexpected diff:\n%s", got)
+	}
+	if got := diff.Unified("a", "b", before, before); got != "" {
+		t.Fatalf("expected no diff, got:\n%s", got)
+	}
+}
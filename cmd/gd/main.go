@@ -7,6 +7,32 @@
 // keep the graphical representation of their project and manage their assets. Running the
 // command without any command line arguments will launch the Godot editor for managing
 // the assets in this directory.
+//
+// The project's main package is built into the library described by 'library.gdextension', a
+// project can be split into several Go extensions by adding more .gdextension files that name
+// the Go package to build under a [gd] section (see extension). gd only adds missing entries to
+// these files, so that user edits are preserved.
+//
+// An existing project.godot is parsed (see [graphics.gd/format/cfg]) and any settings that gd
+// requires, such as run/main_loop_type, are merged into it without disturbing other settings.
+//
+// 'gd run -target android' cross-compiles the project with the NDK toolchain located by
+// $ANDROID_NDK_HOME, exports a debug APK with the headless editor and, when a device is
+// connected, installs it with adb and streams its logs.
+//
+// 'gd run -target server' launches the engine with --headless, without a window or rendering, for
+// dedicated servers (see [graphics.gd/startup.Server]).
+//
+// 'gd vet' runs the standard 'go vet' checks, along with graphics.gd specific checks (see
+// [graphics.gd/cmd/gd/vet]) that catch mistakes which would otherwise panic at startup.
+//
+// 'gd fix' rewrites uses of deprecated APIs, with '-diff' the changes are printed as a unified
+// diff instead of being written. Migrations can be annotated with a '//gd:version' directive and
+// filtered with '-from' and '-to', modules that build on graphics.gd can ship their own migrations
+// in a 'gdfix.txt' file at their module root.
+//
+// 'gd pack' lists, extracts, creates and compares .pck archives (see [graphics.gd/format/pck])
+// without launching the engine, so that patches and DLC can be built as part of a Go pipeline.
 package main
 
 import (
@@ -14,6 +40,7 @@ import (
 	"bufio"
 	"bytes"
 	"errors"
+	"flag"
 	"fmt"
 	"go/build"
 	"io"
@@ -23,6 +50,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -68,6 +96,34 @@ func setupFile(force bool, name, embed string, args ...any) error {
 }
 
 func main() {
+	if os.Getenv(vetToolEnv) != "" {
+		vetTool()
+	}
+	// these commands don't need the engine.
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "vet":
+			if err := runVet(os.Args[2:]); err != nil {
+				os.Exit(1)
+			}
+			return
+		case "fix":
+			if err := fix(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			return
+		case "pack":
+			if err := pack(os.Args[2:]); err != nil {
+				if errors.Is(err, flag.ErrHelp) {
+					os.Exit(2)
+				}
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
 	if err := wrap(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
@@ -88,14 +144,24 @@ func installGodot(gobin string) (string, error) {
 		}
 		return "godot", nil
 	case "linux":
-		fmt.Println("gd: downloading Godot v" + version + " stable for linux")
-		resp, err := http.Get("https://github.com/godotengine/godot-builds/releases/download/" + version + "-stable/Godot_v" + version + "-stable_linux.x86_64.zip")
+		var arch string
+		switch runtime.GOARCH {
+		case "amd64":
+			arch = "x86_64"
+		case "arm64":
+			arch = "arm64"
+		default:
+			return "", fmt.Errorf("gd: installing godot for GOARCH %v is not supported", runtime.GOARCH)
+		}
+		release := "Godot_v" + version + "-stable_linux." + arch
+		fmt.Println("gd: downloading Godot v" + version + " stable for linux/" + arch)
+		resp, err := http.Get("https://github.com/godotengine/godot-builds/releases/download/" + version + "-stable/" + release + ".zip")
 		if err != nil {
 			return "", xray.New(err)
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != 200 {
-			return "", xray.New(err)
+			return "", fmt.Errorf("gd: failed to download %s.zip: %v", release, resp.Status)
 		}
 		data, err := io.ReadAll(resp.Body)
 		if err != nil {
@@ -105,7 +171,7 @@ func installGodot(gobin string) (string, error) {
 		if err != nil {
 			return "", xray.New(err)
 		}
-		inZip, err := archive.Open("Godot_v" + version + "-stable_linux.x86_64")
+		inZip, err := archive.Open(release)
 		if err != nil {
 			return "", xray.New(err)
 		}
@@ -157,7 +223,7 @@ func useGodot() (string, error) {
 	if binary, err := exec.LookPath("godot-" + version); err == nil {
 		return binary, nil
 	}
-	godotBin := filepath.Join(gobin + "godot-" + version)
+	godotBin := filepath.Join(gobin, "godot-"+version)
 	info, err := os.Stat(godotBin)
 	if err != nil {
 		if !errors.Is(err, fs.ErrNotExist) {
@@ -177,6 +243,24 @@ func useGodot() (string, error) {
 	return filepath.Join(gobin, "godot-"+version), nil
 }
 
+// targetFlag returns the value of the -target flag of 'gd run' or 'gd build', along with the
+// arguments without the flag, so that the rest can be passed on to the go command.
+func targetFlag(args []string) (string, []string, error) {
+	if len(args) < 3 || (args[1] != "run" && args[1] != "build") {
+		return "", args, nil
+	}
+	if value, ok := strings.CutPrefix(args[2], "-target="); ok {
+		return value, slices.Delete(slices.Clone(args), 2, 3), nil
+	}
+	if args[2] != "-target" {
+		return "", args, nil
+	}
+	if len(args) < 4 {
+		return "", nil, errors.New("gd: flag needs an argument: -target")
+	}
+	return args[3], slices.Delete(slices.Clone(args), 2, 4), nil
+}
+
 func wrap() error {
 	GOOS, GOARCH := runtime.GOOS, runtime.GOARCH
 	if os.Getenv("GOOS") != "" {
@@ -185,6 +269,24 @@ func wrap() error {
 	if os.Getenv("GOARCH") != "" {
 		GOARCH = os.Getenv("GOARCH")
 	}
+	target, remaining, err := targetFlag(os.Args)
+	if err != nil {
+		return err
+	}
+	os.Args = remaining
+	var buildEnv []string
+	switch target {
+	case "", "server":
+	case "android":
+		cc, err := androidToolchain(os.Getenv, runtime.GOOS, runtime.GOARCH)
+		if err != nil {
+			return err
+		}
+		GOOS, GOARCH = "android", "arm64"
+		buildEnv = []string{"GOOS=android", "CC=" + cc}
+	default:
+		return fmt.Errorf("gd: unsupported -target %q (supported targets: android, server)", target)
+	}
 	if GOARCH != "amd64" && GOARCH != "arm64" && GOARCH != "wasm" {
 		return errors.New("gd requires an amd64, wasm, or arm64 system")
 	}
@@ -211,9 +313,13 @@ func wrap() error {
 		}
 	}
 	graphics := "./graphics"
-	if GOOS == "android" {
+	if runtime.GOOS == "android" {
 		graphics = "/sdcard/gd/" + filepath.Base(wd)
 	}
+	exts, err := extensions(graphics)
+	if err != nil {
+		return xray.New(err)
+	}
 	setup := func() error {
 		if GOOS == "js" {
 			if err := os.MkdirAll(graphics+"/.godot/public", 0o755); err != nil {
@@ -271,7 +377,7 @@ func wrap() error {
 		if err := setupFile(false, graphics+"/main.tscn", main_tscn); err != nil {
 			return xray.New(err)
 		}
-		if err := setupFile(false, graphics+"/project.godot", project_godot, filepath.Base(wd)); err != nil {
+		if err := setupProject(os.Stderr, graphics+"/project.godot", filepath.Base(wd)); err != nil {
 			return xray.New(err)
 		}
 		if err := setupFile(false, graphics+"/export_presets.cfg", export_presets_cfg, filepath.Join(
@@ -279,8 +385,10 @@ func wrap() error {
 		)); err != nil {
 			return xray.New(err)
 		}
-		if err := setupFile(true, graphics+"/library.gdextension", library_gdextension); err != nil {
-			return xray.New(err)
+		for _, ext := range exts {
+			if err := setupExtension(graphics, ext); err != nil {
+				return xray.New(err)
+			}
 		}
 		if _, err := os.Stat(graphics + "/.godot"); os.IsNotExist(err) {
 			godot := exec.Command(godot, "--import", "--headless")
@@ -293,30 +401,24 @@ func wrap() error {
 		if err := setupFile(false, graphics+"/.godot/extension_list.cfg", extension_list_cfg); err != nil {
 			return xray.New(err)
 		}
-		return nil
+		return setupExtensionList(graphics, exts)
 	}
 	var runGodotArgs []string
-	var libraryPath = graphics + "/" + fmt.Sprintf("%v_%v", GOOS, GOARCH)
-	switch GOOS {
-	case "windows":
-		libraryPath += ".dll"
-	case "darwin":
-		libraryPath += ".dylib"
-	case "js":
+	var libraryPath = graphics + "/" + exts[0].library(GOOS, GOARCH)
+	if GOOS == "js" {
 		libraryPath = filepath.Join(graphics, ".godot", "public", "library.wasm")
 		runGodotArgs = []string{"--headless", "--export-debug", "Web"}
-	default:
-		libraryPath += ".so"
 	}
 	if len(os.Args) == 1 {
 		os.Args = append(os.Args, "run")
 		runGodotArgs = []string{"-e"}
 	}
+	if target == "server" {
+		runGodotArgs = append(runGodotArgs, "--headless")
+	}
 	args := make([]string, len(os.Args)-1)
 	builds := [][]string{}
 	switch os.Args[1] {
-	case "fix":
-		return fix()
 	case "run", "build":
 		copy(args, os.Args[1:])
 		args[0] = "build"
@@ -336,18 +438,27 @@ func wrap() error {
 	}
 	builds = append(builds, args)
 	arches := []string{GOARCH}
-	if runtime.GOOS == "darwin" && (GOARCH == "amd64" || GOARCH == "arm64") {
+	universal := runtime.GOOS == "darwin" && GOOS == "darwin" && (GOARCH == "amd64" || GOARCH == "arm64")
+	missingArch := "arm64"
+	if GOARCH == "arm64" {
+		missingArch = "amd64"
+	}
+	if universal {
 		// GOARCH possible values = "amd64", "arm64"
-		missingArch := "arm64"
-		if GOARCH == "arm64" {
-			missingArch = "amd64"
-		}
-		missingArgs := make([]string, len(os.Args)-1)
-		missingLibraryName := fmt.Sprintf("%v_%v.dylib", GOOS, missingArch)
-		missingArgs = append(args, "-buildmode=c-shared", "-o", graphics+"/"+missingLibraryName)
+		missingArgs := append(slices.Clone(args), "-buildmode=c-shared", "-o", graphics+"/"+exts[0].library(GOOS, missingArch))
 		builds = append(builds, missingArgs)
 		arches = append(arches, missingArch)
 	}
+	if GOOS != "js" && (os.Args[1] == "run" || os.Args[1] == "build") {
+		for _, ext := range exts[1:] {
+			builds = append(builds, []string{"build", "-buildmode=c-shared", "-o", graphics + "/" + ext.library(GOOS, GOARCH), ext.pkg})
+			arches = append(arches, GOARCH)
+			if universal {
+				builds = append(builds, []string{"build", "-buildmode=c-shared", "-o", graphics + "/" + ext.library(GOOS, missingArch), ext.pkg})
+				arches = append(arches, missingArch)
+			}
+		}
+	}
 	for i, commandArgs := range builds {
 		var undefinedSymbols []string
 		var parsingDone = make(chan struct{})
@@ -374,6 +485,7 @@ func wrap() error {
 			golang.Env = append(os.Environ(), "CGO_ENABLED=1")
 		}
 		golang.Env = append(golang.Env, "GOARCH="+arches[i])
+		golang.Env = append(golang.Env, buildEnv...)
 		golang.Stderr = capture
 		golang.Stdout = os.Stdout
 		golang.Stdin = os.Stdin
@@ -384,18 +496,26 @@ func wrap() error {
 			return err
 		}
 	}
-	if runtime.GOOS == "darwin" && (GOARCH == "amd64" || GOARCH == "arm64") {
+	if universal {
 		// check if command is available in the system
 		_, err := exec.LookPath("lipo")
 		if err != nil {
 			return fmt.Errorf("gd: lipo command not found in the system, please install it!")
 		}
-		lipoCommand := exec.Command("lipo", "-create", graphics+"/darwin_amd64.dylib", graphics+"/darwin_arm64.dylib", "-output", graphics+"/darwin_universal.dylib")
-		lipoCommand.Stderr = os.Stderr
-		lipoCommand.Stdout = os.Stdout
-		lipoCommand.Stdin = os.Stdin
-		if err := lipoCommand.Run(); err != nil {
-			return err
+		for _, ext := range exts {
+			if ext.pkg != "" && os.Args[1] != "run" && os.Args[1] != "build" {
+				continue
+			}
+			lipoCommand := exec.Command("lipo", "-create",
+				graphics+"/"+ext.library(GOOS, "amd64"),
+				graphics+"/"+ext.library(GOOS, "arm64"),
+				"-output", graphics+"/"+ext.library(GOOS, "universal"))
+			lipoCommand.Stderr = os.Stderr
+			lipoCommand.Stdout = os.Stdout
+			lipoCommand.Stdin = os.Stdin
+			if err := lipoCommand.Run(); err != nil {
+				return err
+			}
 		}
 	}
 	if err := setup(); err != nil {
@@ -403,6 +523,9 @@ func wrap() error {
 	}
 	switch os.Args[1] {
 	case "run":
+		if target == "android" {
+			return runAndroid(godot, graphics, filepath.Base(wd))
+		}
 		godot := exec.Command(godot, runGodotArgs...)
 		godot.Dir = graphics
 		godot.Stderr = os.Stderr
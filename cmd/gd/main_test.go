@@ -0,0 +1,38 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTargetFlag(t *testing.T) {
+	for _, test := range []struct {
+		args   []string
+		target string
+		rest   []string
+	}{
+		{[]string{"gd"}, "", []string{"gd"}},
+		{[]string{"gd", "run"}, "", []string{"gd", "run"}},
+		{[]string{"gd", "run", "-target", "android", "./cmd"}, "android", []string{"gd", "run", "./cmd"}},
+		{[]string{"gd", "build", "-target=server"}, "server", []string{"gd", "build"}},
+		{[]string{"gd", "build", "-target="}, "", []string{"gd", "build"}},
+		{[]string{"gd", "run", "-race", "-target", "android"}, "", []string{"gd", "run", "-race", "-target", "android"}},
+		{[]string{"gd", "test", "-target", "android"}, "", []string{"gd", "test", "-target", "android"}},
+	} {
+		args := slices.Clone(test.args)
+		target, rest, err := targetFlag(args)
+		if err != nil {
+			t.Errorf("%q: %v", test.args, err)
+			continue
+		}
+		if target != test.target || !slices.Equal(rest, test.rest) {
+			t.Errorf("%q: expected %q %q, got %q %q", test.args, test.target, test.rest, target, rest)
+		}
+		if !slices.Equal(args, test.args) {
+			t.Errorf("%q: the arguments were modified to %q", test.args, args)
+		}
+	}
+	if _, _, err := targetFlag([]string{"gd", "run", "-target"}); err == nil {
+		t.Fatal("expected an error for a missing target")
+	}
+}
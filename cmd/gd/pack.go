@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"graphics.gd/format/pck"
+)
+
+const packUsage = `usage: gd pack [-key hex] <command> [arguments]
+
+	gd pack list    archive.pck               list the files in an archive
+	gd pack extract archive.pck dir           extract all files into dir
+	gd pack create  [-encrypt] archive.pck dir  create an archive from the files in dir
+	gd pack diff    old.pck new.pck           compare the files in two archives
+
+The encryption key is read from -key or $GODOT_SCRIPT_ENCRYPTION_KEY (as 64 hex digits).
+`
+
+// pack implements the 'gd pack' subcommand, for working with .pck archives without
+// launching the engine.
+func pack(args []string) error {
+	flags := flag.NewFlagSet("gd pack", flag.ContinueOnError)
+	keyHex := flags.String("key", os.Getenv("GODOT_SCRIPT_ENCRYPTION_KEY"), "`hex` encoded AES-256 key for encrypted archives")
+	encrypt := flags.Bool("encrypt", false, "encrypt the files and directory of a created archive")
+	flags.Usage = func() { fmt.Fprint(flags.Output(), packUsage) }
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	args = flags.Args()
+	if len(args) > 0 {
+		// allow flags after the command name.
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		args = append(args[:1:1], flags.Args()...)
+	}
+	var key []byte
+	if *keyHex != "" {
+		var err error
+		if key, err = hex.DecodeString(*keyHex); err != nil || len(key) != pck.KeySize {
+			return fmt.Errorf("gd pack: -key must be %d hex digits", pck.KeySize*2)
+		}
+	}
+	if len(args) == 0 {
+		flags.Usage()
+		return flag.ErrHelp
+	}
+	switch {
+	case args[0] == "list" && len(args) == 2:
+		archive, err := pck.OpenReader(args[1], key)
+		if err != nil {
+			return err
+		}
+		defer archive.Close()
+		fmt.Println(archive.Header)
+		for _, file := range archive.Files {
+			var encrypted string
+			if file.Encrypted {
+				encrypted = " (encrypted)"
+			}
+			fmt.Printf("%x %10d %s%s\n", file.MD5, file.Size, file.Path, encrypted)
+		}
+		return nil
+	case args[0] == "extract" && len(args) == 3:
+		archive, err := pck.OpenReader(args[1], key)
+		if err != nil {
+			return err
+		}
+		defer archive.Close()
+		for _, file := range archive.Files {
+			data, err := archive.Read(file)
+			if err != nil {
+				return fmt.Errorf("gd pack: %s: %w", file.Path, err)
+			}
+			rel := filepath.FromSlash(strings.TrimPrefix(file.Path, "res://"))
+			if !filepath.IsLocal(rel) {
+				return fmt.Errorf("gd pack: refusing to extract %s outside of %s", file.Path, args[2])
+			}
+			path := filepath.Join(args[2], rel)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				return err
+			}
+		}
+		return nil
+	case args[0] == "create" && len(args) == 3:
+		if *encrypt && key == nil {
+			return errors.New("gd pack: -encrypt requires a -key")
+		}
+		out, err := os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		archive := pck.NewWriter(out)
+		archive.Key = key
+		archive.EncryptDirectory = *encrypt
+		err = filepath.WalkDir(args[2], func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			rel, err := filepath.Rel(args[2], path)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if *encrypt {
+				return archive.AddEncrypted(filepath.ToSlash(rel), data)
+			}
+			return archive.Add(filepath.ToSlash(rel), data)
+		})
+		if err != nil {
+			return err
+		}
+		if err := archive.Close(); err != nil {
+			return err
+		}
+		return out.Close()
+	case args[0] == "diff" && len(args) == 3:
+		before, err := pck.OpenReader(args[1], key)
+		if err != nil {
+			return err
+		}
+		defer before.Close()
+		after, err := pck.OpenReader(args[2], key)
+		if err != nil {
+			return err
+		}
+		defer after.Close()
+		var paths []string
+		for _, file := range before.Files {
+			paths = append(paths, file.Path)
+		}
+		for _, file := range after.Files {
+			if _, ok := before.Lookup(file.Path); !ok {
+				paths = append(paths, file.Path)
+			}
+		}
+		slices.Sort(paths)
+		var changed bool
+		for _, path := range paths {
+			a, inBefore := before.Lookup(path)
+			b, inAfter := after.Lookup(path)
+			switch {
+			case !inAfter:
+				fmt.Println("-", path)
+			case !inBefore:
+				fmt.Println("+", path)
+			case a.MD5 != b.MD5 || a.Size != b.Size:
+				fmt.Println("M", path)
+			default:
+				continue
+			}
+			changed = true
+		}
+		if changed {
+			os.Exit(1)
+		}
+		return nil
+	}
+	flags.Usage()
+	return flag.ErrHelp
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"graphics.gd/format/cfg"
+	"runtime.link/api/xray"
+)
+
+// projectSettings that gd requires in project.godot, so that the Go library runs on startup.
+var projectSettings = []struct {
+	section, key string
+	value        any
+}{
+	{"application", "run/main_loop_type", "GoMainLoop"},
+}
+
+// setupProject creates project.godot from the template, or when the project already exists,
+// merges the projectSettings into it, leaving everything else as it is. Settings that were
+// changed to a different value are overwritten, with a warning written to w, as the project
+// would not run without them.
+func setupProject(w io.Writer, path, name string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return setupFile(false, path, project_godot, name)
+	}
+	if err != nil {
+		return xray.New(err)
+	}
+	project, err := cfg.Parse(data)
+	if err != nil {
+		return fmt.Errorf("gd: %s: %w", path, err)
+	}
+	var changed bool
+	for _, setting := range projectSettings {
+		value, ok := project.Get(setting.section, setting.key)
+		if ok && reflect.DeepEqual(value, setting.value) {
+			continue
+		}
+		if ok {
+			fmt.Fprintf(w, "gd: %s: replacing %s/%s = %v with %v, as it is required by graphics.gd\n",
+				path, setting.section, setting.key, value, setting.value)
+		}
+		project.Set(setting.section, setting.key, setting.value)
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	updated, err := project.Bytes()
+	if err != nil {
+		return xray.New(err)
+	}
+	return xray.New(os.WriteFile(path, updated, 0o644))
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"graphics.gd/format/cfg"
+)
+
+func TestSetupProject(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "project.godot")
+	var warnings strings.Builder
+	if err := setupProject(&warnings, path, "Example"); err != nil {
+		t.Fatal(err)
+	}
+	project := readProject(t, path)
+	if name, _ := project.Get("application", "config/name"); name != "Example" {
+		t.Fatalf("expected the template to be written, got name %v", name)
+	}
+	edited := `; kept by gd
+config_version=5
+
+[application]
+config/name="Edited"
+run/main_loop_type="SceneTree"
+
+[display]
+window/size/viewport_width=320
+`
+	if err := os.WriteFile(path, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := setupProject(&warnings, path, "Example"); err != nil {
+		t.Fatal(err)
+	}
+	project = readProject(t, path)
+	if loop, _ := project.Get("application", "run/main_loop_type"); loop != "GoMainLoop" {
+		t.Fatalf("expected the main loop type to be replaced, got %v", loop)
+	}
+	if name, _ := project.Get("application", "config/name"); name != "Edited" {
+		t.Fatalf("expected the name to be kept, got %v", name)
+	}
+	if _, ok := project.Get("display", "window/size/viewport_width"); !ok {
+		t.Fatal("expected the display settings to be kept")
+	}
+	if !strings.Contains(warnings.String(), `application/run/main_loop_type = SceneTree`) {
+		t.Fatalf("expected a warning about the main loop type, got %q", warnings.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "; kept by gd") {
+		t.Fatalf("expected the comment to be kept, got %q", data)
+	}
+	warnings.Reset()
+	if err := setupProject(&warnings, path, "Example"); err != nil {
+		t.Fatal(err)
+	}
+	if warnings.Len() != 0 {
+		t.Fatalf("expected no warnings for a set up project, got %q", warnings.String())
+	}
+}
+
+func readProject(t *testing.T, path string) *cfg.File {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	project, err := cfg.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
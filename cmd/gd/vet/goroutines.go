@@ -0,0 +1,57 @@
+package vet
+
+import (
+	"go/ast"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Goroutines checks for engine calls made from inside of a go statement, the engine is not
+// thread-safe, so these calls need to be deferred back onto the main thread (ie. with
+// Callable.Defer).
+var Goroutines = &analysis.Analyzer{
+	Name:     "gdgoroutines",
+	Doc:      "check for engine calls inside go statements",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runGoroutines,
+}
+
+func runGoroutines(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.GoStmt)(nil)}, func(node ast.Node) {
+		stmt := node.(*ast.GoStmt)
+		if fn, ok := isEngineFunc(pass.TypesInfo, stmt.Call); ok {
+			pass.Reportf(stmt.Call.Pos(), "engine call %s.%s in go statement, engine calls must happen on the main thread", fn.Pkg().Name(), fn.Name())
+			return
+		}
+		if lit, ok := ast.Unparen(stmt.Call.Fun).(*ast.FuncLit); ok {
+			reportEngineCalls(pass, lit.Body)
+		}
+	})
+	return nil, nil
+}
+
+// reportEngineCalls reports all engine calls within the given node. Function literals that are
+// passed as arguments are not followed, as these are callbacks which may well be deferred back
+// onto the main thread.
+func reportEngineCalls(pass *analysis.Pass, root ast.Node) {
+	ast.Inspect(root, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		if fn, ok := isEngineFunc(pass.TypesInfo, call); ok {
+			pass.Reportf(call.Pos(), "engine call %s.%s in go statement, engine calls must happen on the main thread (use Callable.Defer)", fn.Pkg().Name(), fn.Name())
+		}
+		reportEngineCalls(pass, call.Fun)
+		for _, arg := range call.Args {
+			if _, ok := ast.Unparen(arg).(*ast.FuncLit); ok {
+				continue
+			}
+			reportEngineCalls(pass, arg)
+		}
+		return false
+	})
+}
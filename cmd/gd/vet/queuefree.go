@@ -0,0 +1,136 @@
+package vet
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// QueueFree checks for uses of an engine object, after QueueFree (or Free) has been called on it
+// within the same block.
+var QueueFree = &analysis.Analyzer{
+	Name:     "gdqueuefree",
+	Doc:      "check for uses of an engine object after QueueFree or Free has been called on it",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runQueueFree,
+}
+
+func runQueueFree(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{
+		(*ast.BlockStmt)(nil),
+		(*ast.CaseClause)(nil),
+		(*ast.CommClause)(nil),
+	}, func(node ast.Node) {
+		switch block := node.(type) {
+		case *ast.BlockStmt:
+			checkFreedUses(pass, block.List)
+		case *ast.CaseClause:
+			checkFreedUses(pass, block.Body)
+		case *ast.CommClause:
+			checkFreedUses(pass, block.Body)
+		}
+	})
+	return nil, nil
+}
+
+func checkFreedUses(pass *analysis.Pass, stmts []ast.Stmt) {
+	for i, stmt := range stmts {
+		expr, ok := stmt.(*ast.ExprStmt)
+		if !ok {
+			continue
+		}
+		call, ok := ast.Unparen(expr.X).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		fn, ok := isEngineFunc(pass.TypesInfo, call)
+		if !ok || (fn.Name() != "QueueFree" && fn.Name() != "Free") {
+			continue
+		}
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		root := rootIdent(sel.X)
+		if root == nil {
+			continue
+		}
+		obj, ok := pass.TypesInfo.Uses[root].(*types.Var)
+		if !ok {
+			continue
+		}
+		if use := firstUseAfter(pass.TypesInfo, obj, stmts[i+1:]); use != nil {
+			pass.Reportf(use.Pos(), "%s used after %s was called on it", root.Name, fn.Name())
+		}
+	}
+}
+
+// rootIdent returns the variable that the expression refers to, following any
+// argument-less method calls (such as AsNode()), but not field selections.
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch e := ast.Unparen(expr).(type) {
+		case *ast.Ident:
+			return e
+		case *ast.CallExpr:
+			sel, ok := ast.Unparen(e.Fun).(*ast.SelectorExpr)
+			if !ok || len(e.Args) > 0 {
+				return nil
+			}
+			expr = sel.X
+		default:
+			return nil
+		}
+	}
+}
+
+// firstUseAfter returns the first use of obj in the given statements, stopping early
+// if obj is reassigned.
+func firstUseAfter(info *types.Info, obj *types.Var, stmts []ast.Stmt) *ast.Ident {
+	for _, stmt := range stmts {
+		if assign, ok := stmt.(*ast.AssignStmt); ok {
+			for _, lhs := range assign.Lhs {
+				if ident, ok := ast.Unparen(lhs).(*ast.Ident); ok && info.ObjectOf(ident) == obj {
+					return firstUseIn(info, obj, assign.Rhs...)
+				}
+			}
+		}
+		var found *ast.Ident
+		ast.Inspect(stmt, func(node ast.Node) bool {
+			if found != nil {
+				return false
+			}
+			if _, ok := node.(*ast.FuncLit); ok {
+				return false // closures are not followed, as we can't tell when they will run.
+			}
+			if ident, ok := node.(*ast.Ident); ok && info.Uses[ident] == obj {
+				found = ident
+			}
+			return true
+		})
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func firstUseIn(info *types.Info, obj *types.Var, exprs ...ast.Expr) *ast.Ident {
+	var found *ast.Ident
+	for _, expr := range exprs {
+		ast.Inspect(expr, func(node ast.Node) bool {
+			if found != nil {
+				return false
+			}
+			if ident, ok := node.(*ast.Ident); ok && info.Uses[ident] == obj {
+				found = ident
+			}
+			return true
+		})
+	}
+	return found
+}
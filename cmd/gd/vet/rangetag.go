@@ -0,0 +1,90 @@
+package vet
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// RangeTag checks that `range:"min,max[,step][,extra...]"` struct tags on extension classes are
+// only used on numeric fields, and that they are well-formed.
+var RangeTag = &analysis.Analyzer{
+	Name:     "gdrangetag",
+	Doc:      "check that range struct tags are well-formed and only applied to numeric fields",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runRangeTag,
+}
+
+func runRangeTag(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(node ast.Node) {
+		expr := node.(*ast.StructType)
+		st, ok := pass.TypesInfo.TypeOf(expr).(*types.Struct)
+		if !ok || !isClassStruct(st) {
+			return
+		}
+		for _, field := range expr.Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+			tag, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				continue
+			}
+			hint, ok := reflect.StructTag(tag).Lookup("range")
+			if !ok {
+				continue
+			}
+			basic, ok := pass.TypesInfo.TypeOf(field.Type).Underlying().(*types.Basic)
+			if !ok || basic.Info()&(types.IsInteger|types.IsFloat) == 0 {
+				pass.Reportf(field.Tag.Pos(), "range tag on non-numeric field of type %s", pass.TypesInfo.TypeOf(field.Type))
+				continue
+			}
+			if err := validateRange(hint); err != "" {
+				pass.Reportf(field.Tag.Pos(), "malformed range tag %q: %s", hint, err)
+			}
+		}
+	})
+	return nil, nil
+}
+
+// validateRange returns a description of the problem with the given range hint string, or
+// an empty string if it is valid.
+func validateRange(hint string) string {
+	parts := strings.Split(hint, ",")
+	if len(parts) < 2 {
+		return "expected at least min,max"
+	}
+	var numbers []float64
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if i >= 2 {
+			switch {
+			case part == "or_greater", part == "or_less", part == "exp", part == "radians_as_degrees",
+				part == "degrees", part == "hide_slider", strings.HasPrefix(part, "suffix:"):
+				continue
+			}
+			if i > 2 {
+				return "unknown option " + strconv.Quote(part)
+			}
+		}
+		f, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return strconv.Quote(part) + " is not a number"
+		}
+		numbers = append(numbers, f)
+	}
+	if numbers[0] > numbers[1] {
+		return "min is greater than max"
+	}
+	if len(numbers) > 2 && numbers[2] <= 0 {
+		return "step must be positive"
+	}
+	return ""
+}
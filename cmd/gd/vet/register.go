@@ -0,0 +1,93 @@
+package vet
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Register checks that the type passed to classdb.Register is a named struct, that embeds a
+// classdb.Extension as its first field, parameterised by the struct itself.
+var Register = &analysis.Analyzer{
+	Name:     "gdregister",
+	Doc:      "check that types passed to classdb.Register embed classdb.Extension as their first field",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runRegister,
+}
+
+func runRegister(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(node ast.Node) {
+		call := node.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != pkgClassDB || fn.Name() != "Register" {
+			return
+		}
+		ident := instantiatedIdent(call.Fun)
+		if ident == nil {
+			return
+		}
+		instance, ok := pass.TypesInfo.Instances[ident]
+		if !ok || instance.TypeArgs.Len() != 1 {
+			return
+		}
+		class := instance.TypeArgs.At(0)
+		named, ok := types.Unalias(class).(*types.Named)
+		if !ok {
+			pass.Reportf(call.Pos(), "classdb.Register: %s must be a named struct type", class)
+			return
+		}
+		st, ok := named.Underlying().(*types.Struct)
+		if !ok {
+			pass.Reportf(call.Pos(), "classdb.Register: %s must be a named struct type", class)
+			return
+		}
+		if st.NumFields() == 0 || !st.Field(0).Embedded() || !embedsExtension(st.Field(0).Type()) {
+			pass.Reportf(call.Pos(), "classdb.Register: the first field of %s must embed classdb.Extension", named.Obj().Name())
+			return
+		}
+		// a directly embedded classdb.Extension[T, S] must refer back to the struct being registered.
+		if ftype := st.Field(0).Type(); isExtension(ftype) && types.Unalias(ftype).(*types.Named).TypeArgs().Len() > 0 {
+			self := types.Unalias(ftype).(*types.Named).TypeArgs().At(0)
+			if !types.Identical(self, named) && !types.Identical(self, types.NewPointer(named)) {
+				pass.Reportf(call.Pos(), "classdb.Register: %s embeds classdb.Extension[%s, ...], expected classdb.Extension[%s, ...]",
+					named.Obj().Name(), self, named.Obj().Name())
+			}
+		}
+	})
+	return nil, nil
+}
+
+// instantiatedIdent returns the identifier of an explicitly instantiated generic function.
+func instantiatedIdent(fun ast.Expr) *ast.Ident {
+	switch expr := ast.Unparen(fun).(type) {
+	case *ast.IndexExpr:
+		return instantiatedIdent(expr.X)
+	case *ast.IndexListExpr:
+		return instantiatedIdent(expr.X)
+	case *ast.SelectorExpr:
+		return expr.Sel
+	case *ast.Ident:
+		return expr
+	}
+	return nil
+}
+
+// extensionOf returns the classdb.Extension instantiation embedded (through first fields) by t.
+func extensionOf(t types.Type) *types.Named {
+	for range 32 {
+		if isExtension(t) {
+			return types.Unalias(t).(*types.Named)
+		}
+		st, ok := t.Underlying().(*types.Struct)
+		if !ok || st.NumFields() == 0 || !st.Field(0).Embedded() {
+			return nil
+		}
+		t = st.Field(0).Type()
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package vet
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// Signals checks the signal fields of extension classes, the Emit method of a signal field must
+// not return any values and a channel signal must be send-only.
+var Signals = &analysis.Analyzer{
+	Name:     "gdsignals",
+	Doc:      "check that signal fields on extension classes have an Emit method without results",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      runSignals,
+}
+
+func runSignals(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(node ast.Node) {
+		expr := node.(*ast.StructType)
+		st, ok := pass.TypesInfo.TypeOf(expr).(*types.Struct)
+		if !ok || !isClassStruct(st) {
+			return
+		}
+		for _, field := range expr.Fields.List {
+			if len(field.Names) == 0 {
+				continue
+			}
+			ftype := pass.TypesInfo.TypeOf(field.Type)
+			if ftype == nil {
+				continue
+			}
+			for _, name := range field.Names {
+				if !name.IsExported() {
+					continue
+				}
+				if isSignal(ftype) {
+					emit, _, _ := types.LookupFieldOrMethod(ftype, true, nil, "Emit")
+					fn, ok := emit.(*types.Func)
+					if !ok {
+						pass.Reportf(name.Pos(), "signal field %s has no Emit method", name.Name)
+						continue
+					}
+					if results := fn.Type().(*types.Signature).Results(); results.Len() != 0 {
+						pass.Reportf(name.Pos(), "signal field %s: Emit must not return any values", name.Name)
+					}
+				}
+				if ch, ok := ftype.Underlying().(*types.Chan); ok && ch.Dir() == types.RecvOnly {
+					pass.Reportf(name.Pos(), "signal field %s must be a send-only channel (chan<-), not receive-only", name.Name)
+				}
+			}
+		}
+	})
+	return nil, nil
+}
+
+// isSignal reports whether *t implements the internal signal injection interface, such
+// that classdb.Register treats it as a signal field.
+func isSignal(t types.Type) bool {
+	mset := types.NewMethodSet(types.NewPointer(t))
+	for i := range mset.Len() {
+		obj := mset.At(i).Obj()
+		if obj.Name() == "setSignal" && obj.Pkg() != nil && obj.Pkg().Path() == pkgInternal {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package gdgoroutines
+
+import "graphics.gd/classdb/Node"
+
+func goroutines() {
+	node := Node.New()
+	go node.QueueFree() // want `engine call Node.QueueFree in go statement`
+	go func() {
+		_ = node.Name() // want `engine call Node.Name in go statement`
+		later(func() {
+			node.SetName("deferred")
+		})
+	}()
+}
+
+func later(func()) {}
@@ -0,0 +1,18 @@
+package gdqueuefree
+
+import "graphics.gd/classdb/Node"
+
+func freed() {
+	node := Node.New()
+	node.QueueFree()
+	node.SetName("freed") // want `node used after QueueFree was called on it`
+
+	other := Node.New()
+	other.AsNode().QueueFree()
+	other = Node.New()
+	other.SetName("new")
+
+	closure := Node.New()
+	closure.QueueFree()
+	_ = func() { closure.SetName("later") }
+}
@@ -0,0 +1,23 @@
+package gdrangetag
+
+import (
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Node"
+)
+
+type Good struct {
+	classdb.Extension[Good, Node.Instance]
+
+	Speed  float32 `range:"0,100,0.5"`
+	Health int     `range:"0,10,1,or_greater,suffix:hp"`
+}
+
+type Bad struct {
+	classdb.Extension[Bad, Node.Instance]
+
+	Label string  `range:"0,10"`     // want `range tag on non-numeric field of type string`
+	Flip  float64 `range:"10,0"`     // want `malformed range tag "10,0": min is greater than max`
+	Step  int     `range:"0,10,-1"`  // want `malformed range tag "0,10,-1": step must be positive`
+	Min   int     `range:"0"`        // want `malformed range tag "0": expected at least min,max`
+	Opt   int     `range:"0,1,1,up"` // want `malformed range tag "0,1,1,up": unknown option "up"`
+}
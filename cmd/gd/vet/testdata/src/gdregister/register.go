@@ -0,0 +1,34 @@
+package gdregister
+
+import (
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Node"
+)
+
+type Good struct {
+	classdb.Extension[Good, Node.Instance]
+}
+
+type Embedded struct {
+	Good
+}
+
+type WrongSelf struct {
+	classdb.Extension[Good, Node.Instance]
+}
+
+type NotFirst struct {
+	Name string
+	classdb.Extension[NotFirst, Node.Instance]
+}
+
+func (Embedded) class()  {}
+func (WrongSelf) class() {}
+func (NotFirst) class()  {}
+
+func register() {
+	classdb.Register[Good]()
+	classdb.Register[Embedded]()
+	classdb.Register[WrongSelf]() // want `classdb.Register: WrongSelf embeds classdb.Extension\[gdregister.Good, ...\], expected classdb.Extension\[WrongSelf, ...\]`
+	classdb.Register[NotFirst]()  // want `classdb.Register: the first field of NotFirst must embed classdb.Extension`
+}
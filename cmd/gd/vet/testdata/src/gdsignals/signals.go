@@ -0,0 +1,24 @@
+package gdsignals
+
+import (
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Node"
+)
+
+type Good struct {
+	classdb.Extension[Good, Node.Instance]
+
+	Changed classdb.SignalOf
+	Events  chan<- int
+}
+
+type Bad struct {
+	classdb.Extension[Bad, Node.Instance]
+
+	Broken classdb.BadSignal // want `signal field Broken: Emit must not return any values`
+	Wrong  <-chan int        // want `signal field Wrong must be a send-only channel`
+}
+
+type NotAClass struct {
+	Broken classdb.BadSignal
+}
@@ -0,0 +1,10 @@
+package Node
+
+type Instance struct{ ptr uintptr }
+
+func New() Instance { return Instance{} }
+
+func (self Instance) AsNode() Instance { return self }
+func (self Instance) QueueFree()       {}
+func (self Instance) SetName(string)   {}
+func (self Instance) Name() string     { return "" }
@@ -0,0 +1,20 @@
+package classdb
+
+import gd "graphics.gd/internal"
+
+type Class interface{ class() }
+
+type Extension[T Class, S any] struct{ super S }
+
+func (Extension[T, S]) class() {}
+
+func Register[T Class]() {}
+
+// SignalOf is a signal field whose Emit method has the given signature.
+type SignalOf struct{ gd.Signal }
+
+func (SignalOf) Emit(int) {}
+
+type BadSignal struct{ gd.Signal }
+
+func (BadSignal) Emit() bool { return false }
@@ -0,0 +1,7 @@
+package gd
+
+type Signal struct{ raw uintptr }
+
+func (s *Signal) setSignal(val Signal) { *s = val }
+
+type IsSignal interface{ setSignal(Signal) }
@@ -0,0 +1,65 @@
+// Package vet provides static analyzers that catch graphics.gd specific mistakes at build time,
+// that would otherwise only be caught at runtime (usually as a panic inside [classdb.Register]).
+//
+// The analyzers are run by the 'gd vet' command, alongside the standard 'go vet' checks. They are
+// regular [analysis.Analyzer] values, so they can also be plugged into any other go/analysis driver
+// (such as gopls, golangci-lint or a custom multichecker).
+package vet
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Analyzers that are specific to graphics.gd.
+var Analyzers = []*analysis.Analyzer{
+	Register,
+	Signals,
+	QueueFree,
+	Goroutines,
+	RangeTag,
+}
+
+const (
+	pkgClassDB  = "graphics.gd/classdb"
+	pkgInternal = "graphics.gd/internal"
+)
+
+// isExtension reports whether the given type is an instantiation of classdb.Extension.
+func isExtension(t types.Type) bool {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Origin().Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == pkgClassDB && obj.Name() == "Extension"
+}
+
+// embedsExtension reports whether the struct type embeds a classdb.Extension as its first field
+// (either directly, or through a chain of first-field embeddings).
+func embedsExtension(t types.Type) bool {
+	return extensionOf(t) != nil
+}
+
+// isClassStruct reports whether the given struct embeds classdb.Extension, such that it can be registered.
+func isClassStruct(st *types.Struct) bool {
+	return st.NumFields() > 0 && st.Field(0).Embedded() && embedsExtension(st.Field(0).Type())
+}
+
+// isEngineFunc reports whether the called function or method belongs to one of the engine
+// class packages (graphics.gd/classdb/...), these must only be called on the main thread.
+func isEngineFunc(info *types.Info, call *ast.CallExpr) (*types.Func, bool) {
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return nil, false
+	}
+	path := fn.Pkg().Path()
+	if !strings.HasPrefix(path, pkgClassDB+"/") {
+		return nil, false
+	}
+	return fn, true
+}
@@ -0,0 +1,16 @@
+package vet_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+	"graphics.gd/cmd/gd/vet"
+)
+
+func TestAnalyzers(t *testing.T) {
+	for _, analyzer := range vet.Analyzers {
+		t.Run(analyzer.Name, func(t *testing.T) {
+			analysistest.Run(t, analysistest.TestData(), analyzer, analyzer.Name)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Package editor provides helpers for extending the editor from Go: custom inspectors for Go types,
+// docks built from Go controls and import plugins written as Go functions.
+//
+// Everything registered with this package is added to the editor by a single tool EditorPlugin, which
+// is only added when the engine is running the editor, such that the helpers have no effect while the
+// game is running. The helpers should be called from main, before the engine starts.
+//
+//	func main() {
+//		editor.Inspect(editor.Inspector[*Item]{
+//			Property: func(item *Item, name string) (EditorProperty.Instance, bool) {
+//				if name == "Icon" {
+//					return NewIconPicker(item), true
+//				}
+//				return EditorProperty.Nil, false
+//			},
+//		})
+//		editor.Dock(EditorPlugin.DockSlotRightUl, "Items", NewItemList)
+//		startup.Scene()
+//	}
+package editor
+
+import (
+	"sync"
+
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Control"
+	EditorPluginClass "graphics.gd/classdb/EditorPlugin"
+	"graphics.gd/classdb/Engine"
+	gd "graphics.gd/internal"
+)
+
+// plugin adds everything registered with this package to the editor, when it enters the tree.
+type plugin struct {
+	classdb.Extension[plugin, EditorPluginClass.Instance] `gd:"GoEditorPlugin"`
+
+	inspectors []*inspectorPlugin
+	importers  []*importPlugin
+	docks      []Control.Instance
+}
+
+// registry of the helpers to add to the editor.
+var registry struct {
+	sync.Mutex
+	once       sync.Once
+	inspectors []inspector
+	importers  []importer
+	docks      []dock
+}
+
+type dock struct {
+	slot  EditorPluginClass.DockSlot
+	title string
+	new   func() Control.Instance
+}
+
+// Dock adds a dock to the given slot of the editor, with the given title. The dock's control is
+// created by calling new when the editor starts, and it is freed when the editor exits.
+func Dock(slot EditorPluginClass.DockSlot, title string, new func() Control.Instance) {
+	registry.Lock()
+	registry.docks = append(registry.docks, dock{slot: slot, title: title, new: new})
+	registry.Unlock()
+	register()
+}
+
+// register the plugin classes, along with the editor plugin, the first time that a helper is used.
+func register() {
+	registry.once.Do(func() {
+		classdb.Register[plugin]()
+		classdb.Register[inspectorPlugin]()
+		classdb.Register[importPlugin]()
+		add := func() {
+			if !Engine.IsEditorHint() {
+				return
+			}
+			name := gd.NewStringName(classdb.NameFor[plugin]())
+			gd.Global.EditorPlugins.Add(name)
+			gd.RegisterCleanup(func() { gd.Global.EditorPlugins.Remove(name) })
+		}
+		if gd.Linked {
+			add()
+		} else {
+			gd.PostStartupFunctions = append(gd.PostStartupFunctions, add)
+		}
+	})
+}
+
+func (p *plugin) EnterTree() {
+	registry.Lock()
+	defer registry.Unlock()
+	editor := p.Super()
+	for _, inspector := range registry.inspectors {
+		ip := &inspectorPlugin{inspector: inspector}
+		editor.AddInspectorPlugin(ip.Super())
+		p.inspectors = append(p.inspectors, ip)
+	}
+	for _, importer := range registry.importers {
+		ip := &importPlugin{importer: importer}
+		editor.AddImportPlugin(ip.Super())
+		p.importers = append(p.importers, ip)
+	}
+	for _, dock := range registry.docks {
+		control := dock.new()
+		control.AsNode().SetName(dock.title)
+		editor.AddControlToDock(dock.slot, control)
+		p.docks = append(p.docks, control)
+	}
+}
+
+func (p *plugin) ExitTree() {
+	editor := p.Super()
+	for _, ip := range p.inspectors {
+		editor.RemoveInspectorPlugin(ip.Super())
+	}
+	for _, ip := range p.importers {
+		editor.RemoveImportPlugin(ip.Super())
+	}
+	for _, control := range p.docks {
+		editor.RemoveControlFromDocks(control)
+		control.AsNode().QueueFree()
+	}
+	p.inspectors, p.importers, p.docks = nil, nil, nil
+}
@@ -0,0 +1,113 @@
+package editor_test
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Control"
+	"graphics.gd/classdb/EditorPlugin"
+	"graphics.gd/classdb/EditorProperty"
+	"graphics.gd/classdb/Node"
+	"graphics.gd/classdb/Resource"
+	"graphics.gd/editor"
+	"graphics.gd/startup/enginetest"
+	"graphics.gd/variant/Error"
+	"graphics.gd/variant/Object"
+)
+
+type Item struct {
+	classdb.Extension[Item, Resource.Instance]
+
+	Title string
+	Price int
+}
+
+func (item *Item) AsResource() Resource.Instance { return item.Super() }
+
+func TestMain(m *testing.M) {
+	classdb.Register[Item]()
+	editor.Inspect(editor.Inspector[*Item]{
+		Begin: func(item *Item) Control.Instance {
+			return Control.New()
+		},
+		Property: func(item *Item, name string) (EditorProperty.Instance, bool) {
+			if name == "Price" {
+				return EditorProperty.New(), true
+			}
+			return EditorProperty.Nil, false
+		},
+	})
+	editor.Dock(EditorPlugin.DockSlotRightUl, "Items", func() Control.Instance {
+		return Control.New()
+	})
+	editor.Import(editor.Importer[*Item]{
+		Name:       "item",
+		Extensions: []string{"item"},
+		Import: func(source []byte) (*Item, error) {
+			title, price, ok := strings.Cut(string(source), ",")
+			if !ok {
+				return nil, errors.ErrUnsupported
+			}
+			item := &Item{Title: title}
+			var err error
+			item.Price, err = strconv.Atoi(price)
+			return item, err
+		},
+	})
+	enginetest.Editor = true
+	enginetest.Main(m)
+}
+
+func TestInspect(t *testing.T) {
+	item := new(Item)
+	inspection := enginetest.Inspect(Object.Instance(item.AsObject()))
+	if len(inspection.Controls) != 1 {
+		t.Fatalf("expected 1 custom control, got %d", len(inspection.Controls))
+	}
+	if _, ok := inspection.Properties["Price"]; !ok || len(inspection.Properties) != 1 {
+		t.Fatalf("expected a property editor for Price, got %v", inspection.Properties)
+	}
+	if !slices.Equal(inspection.Replaced, []string{"Price"}) {
+		t.Fatalf("expected the built-in Price editor to be replaced, got %v", inspection.Replaced)
+	}
+	node := Node.New()
+	defer node.QueueFree()
+	if inspection := enginetest.Inspect(Object.Instance(node.AsObject())); len(inspection.Controls) != 0 {
+		t.Fatal("expected the inspector to only handle items")
+	}
+}
+
+func TestDock(t *testing.T) {
+	docks := enginetest.Docks()
+	if len(docks) != 1 {
+		t.Fatalf("expected 1 dock, got %d", len(docks))
+	}
+	if name := docks[0].AsNode().Name(); name != "Items" {
+		t.Fatalf("expected the dock to be named Items, got %q", name)
+	}
+}
+
+func TestImport(t *testing.T) {
+	enginetest.WriteFile("res://sword.item", []byte("Sword,250"))
+	path, err := enginetest.Import("res://sword.item")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, ".res") {
+		t.Fatalf("expected the item to be imported as a binary resource, got %s", path)
+	}
+	if item := Resource.Load[*Item](path); item.Title != "Sword" || item.Price != 250 {
+		t.Fatalf("expected the imported Sword to cost 250, got %q at %d", item.Title, item.Price)
+	}
+	enginetest.WriteFile("res://broken.item", []byte("Sword"))
+	if _, err := enginetest.Import("res://broken.item"); !errors.Is(err, Error.Unavailable) {
+		t.Fatalf("expected the importer's error, got %v", err)
+	}
+	if _, err := enginetest.Import("res://missing.item"); !errors.Is(err, Error.FileNotFound) {
+		t.Fatalf("expected a missing source file to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package editor
+
+import (
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/EditorImportPlugin"
+	"graphics.gd/classdb/FileAccess"
+	"graphics.gd/classdb/Resource"
+	"graphics.gd/classdb/ResourceSaver"
+	"graphics.gd/variant/Float"
+	"graphics.gd/variant/String"
+)
+
+// Importer imports source files into resources of type T, a registered Go resource class, the
+// imported resources can then be loaded from the path of the source file, like any other resource.
+type Importer[T interface {
+	classdb.Class
+	Resource.Any
+}] struct {
+	Name        string   // unique name of the importer, such as "items.csv".
+	VisibleName string   // shown in the Import dock, after "Import as", defaults to the Name.
+	Extensions  []string // file extensions recognized by the importer, without the leading dot.
+
+	// Import converts the contents of a source file into a resource.
+	Import func(source []byte) (T, error)
+}
+
+// Import adds the importer to the editor.
+func Import[T interface {
+	classdb.Class
+	Resource.Any
+}](imp Importer[T]) {
+	visible := imp.VisibleName
+	if visible == "" {
+		visible = imp.Name
+	}
+	registry.Lock()
+	registry.importers = append(registry.importers, importer{
+		name:       imp.Name,
+		visible:    visible,
+		resource:   classdb.NameFor[T](),
+		extensions: imp.Extensions,
+		convert: func(source []byte) (Resource.Instance, error) {
+			resource, err := imp.Import(source)
+			if err != nil {
+				return Resource.Nil, err
+			}
+			return resource.AsResource(), nil
+		},
+	})
+	registry.Unlock()
+	register()
+}
+
+// importer is the type-erased form of an [Importer].
+type importer struct {
+	name       string
+	visible    string
+	resource   string
+	extensions []string
+	convert    func(source []byte) (Resource.Instance, error)
+}
+
+// importPlugin imports files with an [Importer], the imported resources are saved in the
+// engine's binary resource format.
+type importPlugin struct {
+	classdb.Extension[importPlugin, EditorImportPlugin.Instance] `gd:"GoEditorImportPlugin"`
+	classdb.Tool
+
+	importer importer
+}
+
+func (p *importPlugin) GetImporterName() string           { return p.importer.name }
+func (p *importPlugin) GetVisibleName() string            { return p.importer.visible }
+func (p *importPlugin) GetRecognizedExtensions() []string { return p.importer.extensions }
+func (p *importPlugin) GetSaveExtension() string          { return "res" }
+func (p *importPlugin) GetResourceType() string           { return p.importer.resource }
+func (p *importPlugin) GetPresetCount() int               { return 0 }
+func (p *importPlugin) GetPresetName(preset int) string   { return "" }
+func (p *importPlugin) GetPriority() Float.X              { return 1 }
+func (p *importPlugin) GetImportOrder() int               { return 0 }
+func (p *importPlugin) CanImportThreaded() bool           { return false }
+func (p *importPlugin) GetImportOptions(path string, preset int) []map[any]any {
+	return nil
+}
+func (p *importPlugin) GetOptionVisibility(path string, option string, options map[any]any) bool {
+	return true
+}
+
+func (p *importPlugin) Import(source, save string, options map[any]any, variants, generated []string) error {
+	if p.importer.convert == nil {
+		return nil
+	}
+	data := FileAccess.GetFileAsBytes(source)
+	if len(data) == 0 {
+		if err := FileAccess.GetOpenError(); err != nil {
+			return err
+		}
+	}
+	resource, err := p.importer.convert(data)
+	if err != nil {
+		return err
+	}
+	if code := ResourceSaver.Advanced().Save(resource, String.New(save+"."+p.GetSaveExtension()), 0); code != 0 {
+		return code
+	}
+	return nil
+}
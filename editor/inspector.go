@@ -0,0 +1,103 @@
+package editor
+
+import (
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Control"
+	"graphics.gd/classdb/EditorInspectorPlugin"
+	"graphics.gd/classdb/EditorProperty"
+	gd "graphics.gd/internal"
+	"graphics.gd/variant"
+	"graphics.gd/variant/Object"
+)
+
+// Inspector customizes how objects of type T are shown in the editor's inspector, where T is usually
+// a pointer to a Go struct registered with [classdb.Register]. Each function is optional.
+type Inspector[T gd.IsClass] struct {
+	// Begin returns a control to add above the properties of the object, or [Control.Nil].
+	Begin func(object T) Control.Instance
+
+	// Property returns a custom editor for the named property, or [EditorProperty.Nil] to keep the
+	// built-in editor. When replace is true, the built-in editor is removed, otherwise the custom
+	// editor is added before it.
+	Property func(object T, name string) (editor EditorProperty.Instance, replace bool)
+
+	// End returns a control to add below the properties of the object, or [Control.Nil].
+	End func(object T) Control.Instance
+}
+
+// Inspect adds the inspector to the editor, it is used for every object of type T that is
+// selected in the editor.
+func Inspect[T gd.IsClass](inspector Inspector[T]) {
+	registry.Lock()
+	registry.inspectors = append(registry.inspectors, inspector)
+	registry.Unlock()
+	register()
+}
+
+// inspector is implemented by each [Inspector], the object has already been checked to be
+// handled by the inspector.
+type inspector interface {
+	handles(object Object.Instance) bool
+	begin(object Object.Instance) Control.Instance
+	property(object Object.Instance, name string) (EditorProperty.Instance, bool)
+	end(object Object.Instance) Control.Instance
+}
+
+func (i Inspector[T]) handles(object Object.Instance) bool {
+	_, ok := Object.Is[T](object)
+	return ok
+}
+
+func (i Inspector[T]) begin(object Object.Instance) Control.Instance {
+	if i.Begin == nil {
+		return Control.Nil
+	}
+	return i.Begin(Object.As[T](object))
+}
+
+func (i Inspector[T]) property(object Object.Instance, name string) (EditorProperty.Instance, bool) {
+	if i.Property == nil {
+		return EditorProperty.Nil, false
+	}
+	return i.Property(Object.As[T](object), name)
+}
+
+func (i Inspector[T]) end(object Object.Instance) Control.Instance {
+	if i.End == nil {
+		return Control.Nil
+	}
+	return i.End(Object.As[T](object))
+}
+
+// inspectorPlugin adds the controls of an [Inspector] to the editor's inspector.
+type inspectorPlugin struct {
+	classdb.Extension[inspectorPlugin, EditorInspectorPlugin.Instance] `gd:"GoEditorInspectorPlugin"`
+	classdb.Tool
+
+	inspector inspector
+}
+
+func (p *inspectorPlugin) CanHandle(object Object.Instance) bool {
+	return p.inspector != nil && p.inspector.handles(object)
+}
+
+func (p *inspectorPlugin) ParseBegin(object Object.Instance) {
+	if control := p.inspector.begin(object); control != Control.Nil {
+		p.Super().AddCustomControl(control)
+	}
+}
+
+func (p *inspectorPlugin) ParseProperty(object Object.Instance, vtype variant.Type, name string, hint EditorInspectorPlugin.PropertyHint, hintString string, usage EditorInspectorPlugin.PropertyUsageFlags, wide bool) bool {
+	editor, replace := p.inspector.property(object, name)
+	if editor == EditorProperty.Nil {
+		return false
+	}
+	p.Super().AddPropertyEditor(name, editor.AsControl())
+	return replace
+}
+
+func (p *inspectorPlugin) ParseEnd(object Object.Instance) {
+	if control := p.inspector.end(object); control != Control.Nil {
+		p.Super().AddCustomControl(control)
+	}
+}
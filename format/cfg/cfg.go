@@ -0,0 +1,129 @@
+// Package cfg reads and writes Godot's ConfigFile format, as used by project.godot,
+// export_presets.cfg, .gdextension and .import files, without the engine.
+//
+// A [File] is a sequence of [section] headers, each followed by key=value lines. Values use the
+// same syntax as text resources and are decoded by [graphics.gd/format/tres], so an input map
+// event is a tres.Object and a dictionary is a tres.Dictionary. Comments and blank lines are kept
+// with the section or key that follows them, so that a file can be parsed, edited and written
+// back out without losing anything that a person (or the editor) wrote into it.
+//
+// Files saved with ConfigFile.save_encrypted or ConfigFile.save_encrypted_pass can be read with
+// [ParseEncrypted] and [ParseEncryptedPass] and written with [File.Encrypted] and
+// [File.EncryptedPass].
+package cfg
+
+import "slices"
+
+// File is a ConfigFile.
+type File struct {
+	Sections []Section
+	Comments []string // comment and blank lines after the last key.
+}
+
+// Section of the file, such as [application].
+type Section struct {
+	Name     string   // empty for the keys before the first section header.
+	Comments []string // comment and blank lines before the header.
+	Keys     []Key
+}
+
+// Key within a section, such as config/name="Game".
+type Key struct {
+	Name     string
+	Value    any      // see [graphics.gd/format/tres] for the Go types used for each value.
+	Comments []string // comment and blank lines before the key.
+	Comment  string   // comment at the end of the line, including the leading ';'.
+}
+
+// Section returns the section with the given name, or nil if there isn't one.
+func (f *File) Section(name string) *Section {
+	for i := range f.Sections {
+		if f.Sections[i].Name == name {
+			return &f.Sections[i]
+		}
+	}
+	return nil
+}
+
+// Get returns the value of the key within the given section.
+func (f *File) Get(section, key string) (any, bool) {
+	if s := f.Section(section); s != nil {
+		return s.Get(key)
+	}
+	return nil, false
+}
+
+// Set the value of the key within the given section, adding the section and key with the same
+// layout as the engine, if they are missing. As with ConfigFile.set_value, a nil value deletes
+// the key (and the section, if it becomes empty).
+func (f *File) Set(section, key string, value any) {
+	if value == nil {
+		f.Delete(section, key)
+		return
+	}
+	s := f.Section(section)
+	if s == nil {
+		switch {
+		case section == "":
+			if len(f.Sections) > 0 && len(f.Sections[0].Comments) == 0 {
+				f.Sections[0].Comments = []string{""}
+			}
+			f.Sections = slices.Insert(f.Sections, 0, Section{})
+			s = &f.Sections[0]
+		case len(f.Sections) > 0:
+			f.Sections = append(f.Sections, Section{Name: section, Comments: []string{""}})
+			s = &f.Sections[len(f.Sections)-1]
+		default:
+			f.Sections = append(f.Sections, Section{Name: section})
+			s = &f.Sections[len(f.Sections)-1]
+		}
+	}
+	s.Set(key, value)
+}
+
+// Delete the key within the given section, along with the section, if it becomes empty.
+func (f *File) Delete(section, key string) {
+	s := f.Section(section)
+	if s == nil {
+		return
+	}
+	s.Delete(key)
+	if len(s.Keys) == 0 {
+		f.DeleteSection(section)
+	}
+}
+
+// DeleteSection deletes the section with the given name and all of its keys.
+func (f *File) DeleteSection(name string) {
+	f.Sections = slices.DeleteFunc(f.Sections, func(s Section) bool { return s.Name == name })
+}
+
+// Get returns the value of the key.
+func (s *Section) Get(name string) (any, bool) {
+	for _, key := range s.Keys {
+		if key.Name == name {
+			return key.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Set the value of the key, adding it to the end of the section if it is missing.
+func (s *Section) Set(name string, value any) {
+	for i, key := range s.Keys {
+		if key.Name == name {
+			s.Keys[i].Value = value
+			return
+		}
+	}
+	key := Key{Name: name, Value: value}
+	if len(s.Keys) == 0 && s.Name != "" {
+		key.Comments = []string{""}
+	}
+	s.Keys = append(s.Keys, key)
+}
+
+// Delete the key.
+func (s *Section) Delete(name string) {
+	s.Keys = slices.DeleteFunc(s.Keys, func(key Key) bool { return key.Name == name })
+}
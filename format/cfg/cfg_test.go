@@ -0,0 +1,178 @@
+package cfg_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"graphics.gd/format/cfg"
+	"graphics.gd/format/tres"
+	"graphics.gd/variant/Vector2i"
+)
+
+const project = `; Engine configuration file.
+; It's best edited using the editor UI and not directly,
+; since the parameters that go here are not all obvious.
+;
+; Format:
+;   [section] ; section goes between []
+;   param=value ; assign values to parameters
+
+config_version=5
+
+[application]
+
+config/name="Game"
+run/main_scene="res://main.tscn"
+config/features=PackedStringArray("4.3", "Forward Plus")
+
+[autoload]
+
+Events="*res://events.gd" ; signal bus
+
+[display]
+
+window/size/viewport_width=1280
+window/stretch/scale=1.5
+
+[input]
+
+jump={
+"deadzone": 0.5,
+"events": [Object(InputEventKey,"resource_local_to_scene":false,"keycode":0,"physical_keycode":32)
+]
+}
+`
+
+func TestRoundTrip(t *testing.T) {
+	file, err := cfg.Parse([]byte(project))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := file.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != project {
+		t.Fatalf("round trip mismatch:\n%s", data)
+	}
+	if version, _ := file.Get("", "config_version"); version != 5 {
+		t.Fatalf("unexpected config_version %v", version)
+	}
+	if scale, _ := file.Get("display", "window/stretch/scale"); scale != 1.5 {
+		t.Fatalf("unexpected scale %v", scale)
+	}
+	jump, _ := file.Get("input", "jump")
+	dict, ok := jump.(tres.Dictionary)
+	if !ok || len(dict) != 2 {
+		t.Fatalf("unexpected jump %#v", jump)
+	}
+	if events := dict[1].Value.([]any); events[0].(tres.Object).Class != "InputEventKey" {
+		t.Fatalf("unexpected events %#v", events)
+	}
+}
+
+func TestSet(t *testing.T) {
+	file, err := cfg.Parse([]byte(project))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Set("application", "run/main_loop_type", "GoMainLoop")
+	file.Set("display", "window/size/viewport_width", 640)
+	file.Set("autoload", "Events", nil)
+	file.Set("rendering", "textures/canvas_textures/default_texture_filter", 0)
+	file.Set("editor_plugins", "enabled", tres.Raw(`PackedStringArray("res://addons/a/plugin.cfg")`))
+	data, err := file.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `[application]
+
+config/name="Game"
+run/main_scene="res://main.tscn"
+config/features=PackedStringArray("4.3", "Forward Plus")
+run/main_loop_type="GoMainLoop"
+
+[display]
+
+window/size/viewport_width=640
+window/stretch/scale=1.5
+`
+	if !bytes.Contains(data, []byte(expected)) {
+		t.Fatalf("unexpected output:\n%s", data)
+	}
+	const added = `
+[rendering]
+
+textures/canvas_textures/default_texture_filter=0
+
+[editor_plugins]
+
+enabled=PackedStringArray("res://addons/a/plugin.cfg")
+`
+	if !bytes.HasSuffix(data, []byte(added)) {
+		t.Fatalf("unexpected output:\n%s", data)
+	}
+	if file.Section("autoload") != nil {
+		t.Fatal("empty section was not deleted")
+	}
+	reparsed, err := cfg.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := reparsed.Bytes(); !bytes.Equal(again, data) {
+		t.Fatalf("unstable output:\n%s", again)
+	}
+}
+
+func TestNew(t *testing.T) {
+	var file cfg.File
+	file.Set("player", "position", Vector2i.XY{1, 2})
+	file.Set("player", "name", "Gopher")
+	file.Set("", "version", 1)
+	data, err := file.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = "version=1\n\n[player]\n\nposition=Vector2i(1, 2)\nname=\"Gopher\"\n"
+	if string(data) != expected {
+		t.Fatalf("unexpected output:\n%q", data)
+	}
+}
+
+func TestEncrypted(t *testing.T) {
+	file, err := cfg.Parse([]byte(project))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := bytes.Repeat([]byte{7}, cfg.KeySize)
+	data, err := file.Encrypted(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted, err := cfg.ParseEncrypted(data, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, _ := decrypted.Get("application", "config/name"); name != "Game" {
+		t.Fatalf("unexpected name %v", name)
+	}
+	data, err = file.EncryptedPass("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.ParseEncryptedPass(data, "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.ParseEncryptedPass(data, "guess"); !errors.Is(err, cfg.ErrChecksum) {
+		t.Fatalf("expected a checksum error, got %v", err)
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	_, err := cfg.Parse([]byte("[a]\nb=1\nc=Vector2(1, x)\nd=2\n"))
+	var syntax *cfg.SyntaxError
+	if !errors.As(err, &syntax) || syntax.Line != 3 {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
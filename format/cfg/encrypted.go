@@ -0,0 +1,118 @@
+package cfg
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+)
+
+// Magic number at the start of encrypted files ("GDEC").
+const Magic uint32 = 0x43454447
+
+// KeySize is the size of the AES-256 key used for encrypted files.
+const KeySize = 32
+
+var (
+	ErrFormat   = errors.New("cfg: not an encrypted file")
+	ErrKey      = errors.New("cfg: encrypted files require a 32 byte key")
+	ErrChecksum = errors.New("cfg: checksum mismatch (invalid key or password?)")
+)
+
+// ParseEncrypted parses a file saved with ConfigFile.save_encrypted.
+func ParseEncrypted(data, key []byte) (*File, error) {
+	plain, err := decrypt(data, key)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(plain)
+}
+
+// ParseEncryptedPass parses a file saved with ConfigFile.save_encrypted_pass.
+func ParseEncryptedPass(data []byte, password string) (*File, error) {
+	return ParseEncrypted(data, passwordKey(password))
+}
+
+// Encrypted returns the file in the same format as ConfigFile.save_encrypted.
+func (f *File) Encrypted(key []byte) ([]byte, error) {
+	plain, err := f.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return encrypt(plain, key)
+}
+
+// EncryptedPass returns the file in the same format as ConfigFile.save_encrypted_pass.
+func (f *File) EncryptedPass(password string) ([]byte, error) {
+	return f.Encrypted(passwordKey(password))
+}
+
+// passwordKey returns the key that the engine derives from a password, which is the hex
+// encoded MD5 of the password.
+func passwordKey(password string) []byte {
+	sum := md5.Sum([]byte(password))
+	return []byte(hex.EncodeToString(sum[:]))
+}
+
+// encrypt data in the same format as the engine's FileAccessEncrypted, using AES-256 in CFB
+// mode.
+func encrypt(data, key []byte) ([]byte, error) {
+	if len(key) != KeySize {
+		return nil, ErrKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	sum := md5.Sum(data)
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, Magic)
+	out.Write(sum[:])
+	binary.Write(&out, binary.LittleEndian, uint64(len(data)))
+	out.Write(iv)
+	padded := make([]byte, (len(data)+aes.BlockSize-1)/aes.BlockSize*aes.BlockSize)
+	copy(padded, data)
+	cipher.NewCFBEncrypter(block, iv).XORKeyStream(padded, padded)
+	out.Write(padded)
+	return out.Bytes(), nil
+}
+
+// decrypt data written by encrypt.
+func decrypt(data, key []byte) ([]byte, error) {
+	if len(key) != KeySize {
+		return nil, ErrKey
+	}
+	var header struct {
+		Magic  uint32
+		MD5    [16]byte
+		Length uint64
+		IV     [16]byte
+	}
+	r := bytes.NewReader(data)
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil || header.Magic != Magic {
+		return nil, ErrFormat
+	}
+	padded := data[len(data)-r.Len():]
+	if header.Length > uint64(len(padded)) {
+		return nil, ErrFormat
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(padded))
+	cipher.NewCFBDecrypter(block, header.IV[:]).XORKeyStream(plain, padded)
+	plain = plain[:header.Length]
+	if md5.Sum(plain) != header.MD5 {
+		return nil, ErrChecksum
+	}
+	return plain, nil
+}
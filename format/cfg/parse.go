@@ -0,0 +1,104 @@
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"graphics.gd/format/tres"
+)
+
+// SyntaxError describes a problem with the text being parsed.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string { return fmt.Sprintf("cfg: line %d: %s", e.Line, e.Msg) }
+
+// Parse a ConfigFile.
+func Parse(data []byte) (*File, error) {
+	var (
+		file    File
+		pending []string // comment and blank lines, waiting for the next section or key.
+		line    = 1
+		src     = string(data)
+	)
+	errorf := func(format string, args ...any) error {
+		return &SyntaxError{Line: line, Msg: fmt.Sprintf(format, args...)}
+	}
+	for len(src) > 0 {
+		text, _, _ := strings.Cut(src, "\n")
+		trimmed := strings.TrimSpace(text)
+		switch {
+		case trimmed == "" || trimmed[0] == ';' || trimmed[0] == '#':
+			pending = append(pending, trimmed)
+			src = src[min(len(text)+1, len(src)):]
+			line++
+			continue
+		case trimmed[0] == '[':
+			name, comment, ok := strings.Cut(trimmed[1:], "]")
+			if !ok {
+				return nil, errorf("unterminated section header %q", trimmed)
+			}
+			if comment = strings.TrimSpace(comment); comment != "" && comment[0] != ';' {
+				return nil, errorf("unexpected %q after section header", comment)
+			}
+			file.Sections = append(file.Sections, Section{Name: name, Comments: pending})
+			pending = nil
+			src = src[min(len(text)+1, len(src)):]
+			line++
+			continue
+		}
+		if len(file.Sections) == 0 {
+			file.Sections = append(file.Sections, Section{})
+		}
+		section := &file.Sections[len(file.Sections)-1]
+		src = strings.TrimLeft(src, " \t")
+		key := Key{Comments: pending}
+		pending = nil
+		if src[0] == '"' {
+			value, rest, err := tres.CutValue(src)
+			if err != nil {
+				return nil, errorf("invalid key: %v", err)
+			}
+			name, ok := value.(string)
+			if !ok {
+				return nil, errorf("invalid key %q", text)
+			}
+			key.Name, src = name, rest
+		} else {
+			name, rest, ok := strings.Cut(src, "=")
+			if !ok || strings.Contains(name, "\n") {
+				return nil, errorf("expected key=value, found %q", trimmed)
+			}
+			key.Name, src = strings.TrimSpace(name), "="+rest
+		}
+		src = strings.TrimLeft(src, " \t")
+		if !strings.HasPrefix(src, "=") {
+			return nil, errorf("expected '=' after %q", key.Name)
+		}
+		value, rest, err := tres.CutValue(src[1:])
+		if err != nil {
+			var syntax *tres.SyntaxError
+			if errors.As(err, &syntax) {
+				return nil, &SyntaxError{Line: line + syntax.Line - 1, Msg: syntax.Msg}
+			}
+			return nil, errorf("%v", err)
+		}
+		line += strings.Count(src[:len(src)-len(rest)], "\n")
+		key.Value = value
+		text, _, _ = strings.Cut(rest, "\n")
+		if trimmed := strings.TrimSpace(text); trimmed != "" {
+			if trimmed[0] != ';' && trimmed[0] != '#' {
+				return nil, errorf("unexpected %q after value", trimmed)
+			}
+			key.Comment = trimmed
+		}
+		src = rest[min(len(text)+1, len(rest)):]
+		line++
+		section.Keys = append(section.Keys, key)
+	}
+	file.Comments = pending
+	return &file, nil
+}
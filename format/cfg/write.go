@@ -0,0 +1,62 @@
+package cfg
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"graphics.gd/format/tres"
+)
+
+// Bytes returns the file in the ConfigFile format, laid out in the same way as the engine.
+func (f *File) Bytes() ([]byte, error) {
+	var buf []byte
+	comments := func(lines []string) {
+		for _, line := range lines {
+			buf = append(buf, line...)
+			buf = append(buf, '\n')
+		}
+	}
+	for _, section := range f.Sections {
+		comments(section.Comments)
+		if section.Name != "" {
+			buf = append(buf, '[')
+			buf = append(buf, section.Name...)
+			buf = append(buf, "]\n"...)
+		}
+		for _, key := range section.Keys {
+			comments(key.Comments)
+			if key.Name == "" || strings.ContainsAny(key.Name, " \t\r\n=\"[]{}:;#,") {
+				name, _ := tres.FormatValue(key.Name)
+				buf = append(buf, name...)
+			} else {
+				buf = append(buf, key.Name...)
+			}
+			buf = append(buf, '=')
+			value, err := tres.FormatValue(key.Value)
+			if err != nil {
+				return nil, fmt.Errorf("cfg: [%s] %s: %w", section.Name, key.Name, err)
+			}
+			buf = append(buf, value...)
+			if key.Comment != "" {
+				buf = append(buf, ' ')
+				buf = append(buf, key.Comment...)
+			}
+			if !strings.HasSuffix(value, "\n") || key.Comment != "" {
+				buf = append(buf, '\n')
+			}
+		}
+	}
+	comments(f.Comments)
+	return buf, nil
+}
+
+// WriteTo writes the file to w in the ConfigFile format.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	data, err := f.Bytes()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
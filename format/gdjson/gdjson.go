@@ -0,0 +1,105 @@
+// Package gdjson encodes and decodes JSON in the same way as Godot's JSON class, without the
+// engine, so that Go programs can exchange JSON with GDScript peers.
+//
+// [Marshal] and [Unmarshal] follow JSON.stringify and JSON.parse, builtin math types are written
+// as their String form (a [Vector2.XY] is "(1.0, 2.0)"), dictionary keys are converted to strings
+// and sorted, floats always have a decimal point and byte arrays are arrays of numbers. The
+// variant container types ([Array.Contains], [Dictionary.Map], [Packed.Array], [Packed.Bytes],
+// [Packed.Strings], [variant.Any] and [String.Name]) implement the [encoding/json] interfaces with
+// the same representation, so they can also be used with the standard library.
+//
+// [MarshalNative] and [UnmarshalNative] follow JSON.from_native and JSON.to_native, which keep
+// the type of every value, so that a value survives a round trip through JSON unchanged:
+//
+//	nil, true, false          null, true, false
+//	int                       "i:1"
+//	float64                   "f:1.5"
+//	string                    "s:text"
+//	[String.Name]             "sn:name"
+//	[Path.ToNode]             "np:a/b"
+//	[Vector2.XY]              {"__gdtype": "Vector2", "args": [1.0, 2.0]} (and so on)
+//	[Array.Contains]          {"__gdtype": "Array", "elem_type": "int", "args": [...]}
+//	[Dictionary.Map]          {"__gdtype": "Dictionary", "args": [key, value, ...]}
+//	[Packed.Bytes]            {"__gdtype": "PackedByteArray", "args": [1, 2]} (and so on)
+//
+// Objects are not supported.
+package gdjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"graphics.gd/internal/stringify"
+	"graphics.gd/variant"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/Dictionary"
+)
+
+// Marshal returns the JSON encoding of value, in the same form as JSON.stringify.
+func Marshal(value any) ([]byte, error) {
+	return stringify.Marshal(value)
+}
+
+// MarshalIndent is like [Marshal] but indents the output, in the same way as JSON.stringify
+// with the given indent (ie. "\t").
+func MarshalIndent(value any, indent string) ([]byte, error) {
+	return stringify.MarshalIndent(value, indent)
+}
+
+// Unmarshal parses JSON data into the value pointed to by ptr, see [encoding/json.Unmarshal],
+// builtin math types are parsed from their String form.
+func Unmarshal(data []byte, ptr any) error {
+	return stringify.Unmarshal(data, ptr)
+}
+
+// Parse parses JSON data in the same way as JSON.parse, numbers are float64, arrays are
+// [Array.Any] and objects are [Dictionary.Any] (with string keys, in the order they appear).
+func Parse(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	value, err := parse(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("gdjson: invalid data after top-level value")
+	}
+	return value, nil
+}
+
+func parse(dec *json.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('['):
+		array := Array.New[variant.Any]()
+		for dec.More() {
+			elem, err := parse(dec)
+			if err != nil {
+				return nil, err
+			}
+			array.Append(variant.New(elem))
+		}
+		_, err := dec.Token()
+		return array, err
+	case json.Delim('{'):
+		dict := Dictionary.New[variant.Any, variant.Any]()
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := parse(dec)
+			if err != nil {
+				return nil, err
+			}
+			dict.SetIndex(variant.New(key), variant.New(value))
+		}
+		_, err := dec.Token()
+		return dict, err
+	}
+	return token, nil
+}
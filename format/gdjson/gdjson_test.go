@@ -0,0 +1,212 @@
+package gdjson_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"graphics.gd/format/gdjson"
+	"graphics.gd/variant"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/Basis"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Dictionary"
+	"graphics.gd/variant/Packed"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/String"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+)
+
+func TestMarshal(t *testing.T) {
+	type Player struct {
+		Name     string             `json:"name"`
+		Position Vector2.XY         `json:"position"`
+		Tint     Color.RGBA         `json:"tint"`
+		Cell     Vector2i.XY        `json:"cell"`
+		Bounds   Rect2.PositionSize `json:"bounds"`
+		Health   float64            `json:"health"`
+		Level    int                `json:"level"`
+		Save     []byte             `json:"save"`
+		Skip     string             `json:"-"`
+		Empty    string             `json:"empty,omitempty"`
+		Tags     Array.Contains[string]
+		Stats    Dictionary.Map[string, int]
+	}
+	stats := Dictionary.New[string, int]()
+	stats.SetIndex("str", 3)
+	stats.SetIndex("dex", 5)
+	player := Player{
+		Name:     "<a&b>",
+		Position: Vector2.XY{1, 2.5},
+		Tint:     Color.RGBA{1, 0, 0, 1},
+		Cell:     Vector2i.XY{3, -4},
+		Bounds:   Rect2.PositionSize{Size: Vector2.XY{2, 2}},
+		Health:   100,
+		Level:    7,
+		Save:     []byte{1, 2},
+		Skip:     "skip",
+		Tags:     Array.New("a", "b"),
+		Stats:    stats,
+	}
+	data, err := gdjson.Marshal(player)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `{"name":"<a&b>","position":"(1.0, 2.5)","tint":"(1.0, 0.0, 0.0, 1.0)","cell":"(3, -4)",` +
+		`"bounds":"[P: (0.0, 0.0), S: (2.0, 2.0)]","health":100.0,"level":7,"save":[1,2],` +
+		`"Tags":["a","b"],"Stats":{"dex":5,"str":3}}`
+	if string(data) != expected {
+		t.Fatalf("unexpected JSON\n%s\n%s", data, expected)
+	}
+	var decoded Player
+	if err := gdjson.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Position != player.Position || decoded.Tint != player.Tint || decoded.Cell != player.Cell ||
+		decoded.Bounds != player.Bounds || decoded.Level != 7 || string(decoded.Save) != "\x01\x02" {
+		t.Fatalf("unexpected decoded value %+v", decoded)
+	}
+	if decoded.Tags.Len() != 2 || decoded.Tags.Index(1) != "b" {
+		t.Fatalf("unexpected tags %v", decoded.Tags.Slice())
+	}
+	if keys := decoded.Stats.Keys(); len(keys) != 2 || keys[0] != "dex" || decoded.Stats.Index("str") != 3 {
+		t.Fatalf("unexpected stats %v", keys)
+	}
+}
+
+func TestMarshalFloats(t *testing.T) {
+	for _, test := range []struct {
+		value  any
+		expect string
+	}{
+		{0.1 + 0.2, "0.3"},
+		{1.0 / 3, "0.33333333333333"},
+		{-2.0 / 3, "-0.66666666666667"},
+		{100.0 / 3, "33.333333333333"},
+		{float32(0.1), "0.1"},
+		{1e-7, "0.0000001"},
+		{0.0, "0.0"},
+		{1e22, "1e+22"},
+	} {
+		data, err := gdjson.Marshal(test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != test.expect {
+			t.Errorf("%v: expected %s, got %s", test.value, test.expect, data)
+		}
+	}
+}
+
+func TestEncodingJSON(t *testing.T) {
+	dict := Dictionary.New[Vector2i.XY, Packed.Bytes]()
+	dict.SetIndex(Vector2i.XY{1, 2}, Packed.Bytes(Packed.New[byte](3, 4)))
+	data, err := json.Marshal(map[string]any{
+		"dict":    dict,
+		"name":    String.Name(String.New("node")),
+		"strings": Packed.MakeStrings("a"),
+		"variant": variant.New(Vector3.XYZ{1, 2, 3}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `{"dict":{"(1, 2)":[3,4]},"name":"node","strings":["a"],"variant":"(1.0, 2.0, 3.0)"}`
+	if string(data) != expected {
+		t.Fatalf("unexpected JSON\n%s\n%s", data, expected)
+	}
+	var decoded struct {
+		Dict    Dictionary.Map[Vector2i.XY, Packed.Bytes]
+		Name    String.Name
+		Strings Packed.Strings
+		Variant variant.Any
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if bytes := decoded.Dict.Index(Vector2i.XY{1, 2}); string(bytes.Bytes()) != "\x03\x04" {
+		t.Fatalf("unexpected dictionary value %v", bytes.Bytes())
+	}
+	if decoded.Name.String() != "node" || decoded.Strings.Index(0).String() != "a" {
+		t.Fatalf("unexpected decoded value %+v", decoded)
+	}
+	if decoded.Variant.Interface() != "(1.0, 2.0, 3.0)" {
+		t.Fatalf("unexpected variant %v", decoded.Variant)
+	}
+}
+
+func TestParse(t *testing.T) {
+	value, err := gdjson.Parse([]byte(`{"b": [1, "two", null], "a": {"c": true}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict, ok := value.(Dictionary.Any)
+	if !ok {
+		t.Fatalf("unexpected value %T", value)
+	}
+	keys, values := dict.Keys(), dict.Values()
+	if len(keys) != 2 || keys[0].Interface() != "b" || keys[1].Interface() != "a" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+	array, ok := values[0].Interface().(Array.Any)
+	if !ok || array.Len() != 3 || array.Index(0).Interface() != 1.0 || array.Index(2).Interface() != nil {
+		t.Fatalf("unexpected array %v", values[0])
+	}
+	if _, err := gdjson.Parse([]byte(`[1] 2`)); err == nil {
+		t.Fatal("expected an error for trailing data")
+	}
+}
+
+func TestNative(t *testing.T) {
+	basis := Basis.XYZ{X: Vector3.XYZ{1, 2, 3}, Y: Vector3.XYZ{4, 5, 6}, Z: Vector3.XYZ{7, 8, 9}}
+	for _, test := range []struct {
+		value    any
+		expected string
+	}{
+		{nil, `null`},
+		{true, `true`},
+		{int32(-3), `"i:-3"`},
+		{1.0, `"f:1.0"`},
+		{"text", `"s:text"`},
+		{String.Name(String.New("name")), `"sn:name"`},
+		{Vector2.XY{1, 2}, `{"__gdtype":"Vector2","args":[1.0,2.0]}`},
+		{Vector2i.XY{1, 2}, `{"__gdtype":"Vector2i","args":[1,2]}`},
+		{basis, `{"__gdtype":"Basis","args":[1.0,4.0,7.0,2.0,5.0,8.0,3.0,6.0,9.0]}`},
+		{[]byte{1, 2}, `{"__gdtype":"PackedByteArray","args":[1,2]}`},
+		{Packed.New(Vector2.XY{1, 2}, Vector2.XY{3, 4}), `{"__gdtype":"PackedVector2Array","args":[1.0,2.0,3.0,4.0]}`},
+		{Packed.MakeStrings("a", "b"), `{"__gdtype":"PackedStringArray","args":["a","b"]}`},
+		{Array.New(1, 2), `{"__gdtype":"Array","args":["i:1","i:2"],"elem_type":"int"}`},
+		{[]any{"a", 1.5}, `{"__gdtype":"Array","args":["s:a","f:1.5"]}`},
+		{map[string]Vector2.XY{"b": {}, "a": {1, 1}}, `{"__gdtype":"Dictionary","args":["s:a",{"__gdtype":"Vector2","args":[1.0,1.0]},` +
+			`"s:b",{"__gdtype":"Vector2","args":[0.0,0.0]}],"key_type":"String","value_type":"Vector2"}`},
+	} {
+		data, err := gdjson.MarshalNative(test.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != test.expected {
+			t.Fatalf("unexpected JSON for %T\n%s\n%s", test.value, data, test.expected)
+		}
+		if _, err := gdjson.UnmarshalNative(data); err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+	}
+	value, err := gdjson.UnmarshalNative([]byte(`{"__gdtype":"Basis","args":[1,4,7,2,5,8,3,6,9]}`))
+	if err != nil || value != basis {
+		t.Fatalf("unexpected basis %v (%v)", value, err)
+	}
+	value, err = gdjson.UnmarshalNative([]byte(`{"__gdtype":"Dictionary","args":["i:1",{"__gdtype":"PackedInt32Array","args":[5]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dict, ok := value.(Dictionary.Any)
+	if !ok || dict.Len() != 1 {
+		t.Fatalf("unexpected dictionary %v", value)
+	}
+	if ints, ok := dict.Index(variant.New(int64(1))).Interface().(Packed.Array[int32]); !ok || ints.Index(0) != 5 {
+		t.Fatalf("unexpected value %v", dict.Values())
+	}
+	if _, err := gdjson.UnmarshalNative([]byte(`{"__gdtype":"Vector2","args":[1]}`)); err == nil {
+		t.Fatal("expected an error for missing components")
+	}
+}
@@ -0,0 +1,546 @@
+package gdjson
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"graphics.gd/internal/stringify"
+	"graphics.gd/variant"
+	"graphics.gd/variant/AABB"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/Basis"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Dictionary"
+	"graphics.gd/variant/Packed"
+	"graphics.gd/variant/Path"
+	"graphics.gd/variant/Plane"
+	"graphics.gd/variant/Projection"
+	"graphics.gd/variant/Quaternion"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Rect2i"
+	"graphics.gd/variant/String"
+	"graphics.gd/variant/Transform2D"
+	"graphics.gd/variant/Transform3D"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector3i"
+	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/Vector4i"
+)
+
+// keys of the objects used by the native form.
+const (
+	keyType      = "__gdtype"
+	keyArgs      = "args"
+	keyElemType  = "elem_type"
+	keyKeyType   = "key_type"
+	keyValueType = "value_type"
+)
+
+// builtins that are encoded as a list of numbers.
+var builtins = map[string]reflect.Type{
+	"Vector2":     reflect.TypeFor[Vector2.XY](),
+	"Vector2i":    reflect.TypeFor[Vector2i.XY](),
+	"Rect2":       reflect.TypeFor[Rect2.PositionSize](),
+	"Rect2i":      reflect.TypeFor[Rect2i.PositionSize](),
+	"Vector3":     reflect.TypeFor[Vector3.XYZ](),
+	"Vector3i":    reflect.TypeFor[Vector3i.XYZ](),
+	"Transform2D": reflect.TypeFor[Transform2D.OriginXY](),
+	"Vector4":     reflect.TypeFor[Vector4.XYZW](),
+	"Vector4i":    reflect.TypeFor[Vector4i.XYZW](),
+	"Plane":       reflect.TypeFor[Plane.NormalD](),
+	"Quaternion":  reflect.TypeFor[Quaternion.IJKX](),
+	"AABB":        reflect.TypeFor[AABB.PositionSize](),
+	"Basis":       reflect.TypeFor[Basis.XYZ](),
+	"Transform3D": reflect.TypeFor[Transform3D.BasisOrigin](),
+	"Projection":  reflect.TypeFor[Projection.XYZW](),
+	"Color":       reflect.TypeFor[Color.RGBA](),
+}
+
+// integers reports whether the components of the builtin are integers.
+func integers(name string) bool { return strings.HasSuffix(name, "i") }
+
+// MarshalNative returns the JSON encoding of value, in the same form as
+// JSON.stringify(JSON.from_native(value)).
+func MarshalNative(value any) ([]byte, error) {
+	native, err := FromNative(value)
+	if err != nil {
+		return nil, err
+	}
+	return stringify.Marshal(native)
+}
+
+// UnmarshalNative parses JSON data in the same way as JSON.to_native(JSON.parse_string(data)).
+func UnmarshalNative(data []byte) (any, error) {
+	var generic any
+	if err := stringify.Decode(data, &generic); err != nil {
+		return nil, err
+	}
+	return ToNative(stringify.Plain(generic))
+}
+
+// FromNative converts value into the JSON-compatible form used by JSON.from_native, the result
+// is made up of nil, bool, float64, int64, string, []any and map[string]any values. The entries
+// of Go maps are sorted by key, as they have no order of their own.
+func FromNative(value any) (any, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case variant.Any:
+		return FromNative(v.Interface())
+	case bool:
+		return v, nil
+	case string:
+		return "s:" + v, nil
+	case String.Readable:
+		return "s:" + v.String(), nil
+	case String.Name:
+		return "sn:" + v.String(), nil
+	case Path.ToNode:
+		return "np:" + v.String(), nil
+	case []byte:
+		return packed("PackedByteArray", v), nil
+	case Packed.Bytes:
+		return packed("PackedByteArray", v.Bytes()), nil
+	case []int32:
+		return packed("PackedInt32Array", v), nil
+	case Packed.Array[int32]:
+		return packed("PackedInt32Array", collect(v)), nil
+	case []int64:
+		return packed("PackedInt64Array", v), nil
+	case Packed.Array[int64]:
+		return packed("PackedInt64Array", collect(v)), nil
+	case []float32:
+		return packed("PackedFloat32Array", v), nil
+	case Packed.Array[float32]:
+		return packed("PackedFloat32Array", collect(v)), nil
+	case []float64:
+		return packed("PackedFloat64Array", v), nil
+	case Packed.Array[float64]:
+		return packed("PackedFloat64Array", collect(v)), nil
+	case []string:
+		return packed("PackedStringArray", v), nil
+	case Packed.Strings:
+		return packed("PackedStringArray", v.Strings()), nil
+	case []Vector2.XY:
+		return flatten("PackedVector2Array", v), nil
+	case Packed.Array[Vector2.XY]:
+		return flatten("PackedVector2Array", collect(v)), nil
+	case []Vector3.XYZ:
+		return flatten("PackedVector3Array", v), nil
+	case Packed.Array[Vector3.XYZ]:
+		return flatten("PackedVector3Array", collect(v)), nil
+	case []Color.RGBA:
+		return flatten("PackedColorArray", v), nil
+	case Packed.Array[Color.RGBA]:
+		return flatten("PackedColorArray", collect(v)), nil
+	case []Vector4.XYZW:
+		return flatten("PackedVector4Array", v), nil
+	case Packed.Array[Vector4.XYZW]:
+		return flatten("PackedVector4Array", collect(v)), nil
+	}
+	rvalue := reflect.ValueOf(value)
+	rtype := rvalue.Type()
+	if components, ok := stringify.Components(value); ok {
+		name := builtinName(rtype)
+		return map[string]any{keyType: name, keyArgs: numbers(name, components)}, nil
+	}
+	switch rtype.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "i:" + strconv.FormatInt(rvalue.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "i:" + strconv.FormatUint(rvalue.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return "f:" + string(stringify.AppendReal(nil, rvalue.Float(), rtype.Bits())), nil
+	case reflect.String:
+		return "s:" + rvalue.String(), nil
+	case reflect.Slice, reflect.Array:
+		args := make([]any, 0, rvalue.Len())
+		for i := range rvalue.Len() {
+			elem, err := FromNative(rvalue.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, elem)
+		}
+		return container("Array", map[string]reflect.Type{keyElemType: rtype.Elem()}, args)
+	case reflect.Map:
+		type entry struct {
+			sort       string
+			key, value any
+		}
+		var entries []entry
+		for iter := rvalue.MapRange(); iter.Next(); {
+			key, err := FromNative(iter.Key().Interface())
+			if err != nil {
+				return nil, err
+			}
+			value, err := FromNative(iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			sort, err := stringify.Marshal(key)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry{string(sort), key, value})
+		}
+		slices.SortFunc(entries, func(a, b entry) int { return strings.Compare(a.sort, b.sort) })
+		var args []any
+		for _, entry := range entries {
+			args = append(args, entry.key, entry.value)
+		}
+		return container("Dictionary", map[string]reflect.Type{keyKeyType: rtype.Key(), keyValueType: rtype.Elem()}, args)
+	}
+	switch v := value.(type) {
+	case Array.Interface:
+		var args []any
+		for _, elem := range v.Any().Iter() {
+			native, err := FromNative(elem)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, native)
+		}
+		index, _ := rtype.MethodByName("Index")
+		return container("Array", map[string]reflect.Type{keyElemType: index.Type.Out(0)}, args)
+	case Dictionary.Interface:
+		var args []any
+		dict := v.Any()
+		for key, value := range dict.Iter() {
+			nativeKey, err := FromNative(key)
+			if err != nil {
+				return nil, err
+			}
+			nativeValue, err := FromNative(value)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, nativeKey, nativeValue)
+		}
+		index, _ := rtype.MethodByName("Index")
+		return container("Dictionary", map[string]reflect.Type{keyKeyType: index.Type.In(1), keyValueType: index.Type.Out(0)}, args)
+	}
+	return nil, fmt.Errorf("gdjson: cannot convert %T to JSON", value)
+}
+
+func builtinName(rtype reflect.Type) string {
+	for name, builtin := range builtins {
+		if builtin == rtype {
+			return name
+		}
+	}
+	return ""
+}
+
+// numbers returns the args of a builtin or packed array.
+func numbers(name string, components []float64) []any {
+	args := make([]any, len(components))
+	for i, f := range components {
+		if integers(name) {
+			args[i] = int64(f)
+		} else {
+			args[i] = f
+		}
+	}
+	return args
+}
+
+func collect[T Packed.Type](array Packed.Array[T]) []T {
+	values := make([]T, 0, array.Len())
+	for _, value := range array.Iter() {
+		values = append(values, value)
+	}
+	return values
+}
+
+func packed[T int32 | int64 | byte | float32 | float64 | string](name string, values []T) map[string]any {
+	args := make([]any, len(values))
+	for i, value := range values {
+		switch v := any(value).(type) {
+		case float32:
+			args[i], _ = strconv.ParseFloat(strconv.FormatFloat(float64(v), 'g', -1, 32), 64)
+		case string:
+			args[i] = v
+		default:
+			args[i] = reflect.ValueOf(v).Convert(reflect.TypeFor[int64]()).Interface()
+		}
+	}
+	return map[string]any{keyType: name, keyArgs: args}
+}
+
+func flatten[T Vector2.XY | Vector3.XYZ | Color.RGBA | Vector4.XYZW](name string, values []T) map[string]any {
+	var args []any
+	for _, value := range values {
+		components, _ := stringify.Components(value)
+		args = append(args, numbers(name, components)...)
+	}
+	if args == nil {
+		args = []any{}
+	}
+	return map[string]any{keyType: name, keyArgs: args}
+}
+
+func container(name string, types map[string]reflect.Type, args []any) (any, error) {
+	if args == nil {
+		args = []any{}
+	}
+	result := map[string]any{keyType: name, keyArgs: args}
+	for key, rtype := range types {
+		if rtype.Kind() == reflect.Interface || rtype == reflect.TypeFor[variant.Any]() {
+			continue
+		}
+		typeName, ok := TypeName(rtype)
+		if !ok {
+			return nil, fmt.Errorf("gdjson: cannot convert %s to JSON", rtype)
+		}
+		result[key] = typeName
+	}
+	return result, nil
+}
+
+// TypeName returns the name of the variant type that values of the given Go type are converted
+// to, ie. "int" for int32 or "Vector2" for [Vector2.XY].
+func TypeName(rtype reflect.Type) (string, bool) {
+	if name := builtinName(rtype); name != "" {
+		return name, true
+	}
+	switch rtype {
+	case reflect.TypeFor[String.Readable]():
+		return "String", true
+	case reflect.TypeFor[String.Name]():
+		return "StringName", true
+	case reflect.TypeFor[Path.ToNode]():
+		return "NodePath", true
+	case reflect.TypeFor[Packed.Bytes](), reflect.TypeFor[[]byte]():
+		return "PackedByteArray", true
+	case reflect.TypeFor[Packed.Strings](), reflect.TypeFor[[]string]():
+		return "PackedStringArray", true
+	case reflect.TypeFor[Packed.Array[int32]]():
+		return "PackedInt32Array", true
+	case reflect.TypeFor[Packed.Array[int64]]():
+		return "PackedInt64Array", true
+	case reflect.TypeFor[Packed.Array[float32]]():
+		return "PackedFloat32Array", true
+	case reflect.TypeFor[Packed.Array[float64]]():
+		return "PackedFloat64Array", true
+	case reflect.TypeFor[Packed.Array[Vector2.XY]]():
+		return "PackedVector2Array", true
+	case reflect.TypeFor[Packed.Array[Vector3.XYZ]]():
+		return "PackedVector3Array", true
+	case reflect.TypeFor[Packed.Array[Color.RGBA]]():
+		return "PackedColorArray", true
+	case reflect.TypeFor[Packed.Array[Vector4.XYZW]]():
+		return "PackedVector4Array", true
+	}
+	switch rtype.Kind() {
+	case reflect.Bool:
+		return "bool", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "int", true
+	case reflect.Float32, reflect.Float64:
+		return "float", true
+	case reflect.String:
+		return "String", true
+	case reflect.Map:
+		return "Dictionary", true
+	case reflect.Slice, reflect.Array:
+		switch rtype.Elem() {
+		case reflect.TypeFor[int32]():
+			return "PackedInt32Array", true
+		case reflect.TypeFor[int64]():
+			return "PackedInt64Array", true
+		case reflect.TypeFor[float32]():
+			return "PackedFloat32Array", true
+		case reflect.TypeFor[float64]():
+			return "PackedFloat64Array", true
+		case reflect.TypeFor[Vector2.XY]():
+			return "PackedVector2Array", true
+		case reflect.TypeFor[Vector3.XYZ]():
+			return "PackedVector3Array", true
+		case reflect.TypeFor[Color.RGBA]():
+			return "PackedColorArray", true
+		case reflect.TypeFor[Vector4.XYZW]():
+			return "PackedVector4Array", true
+		}
+		return "Array", true
+	}
+	if rtype.Implements(reflect.TypeFor[Array.Interface]()) {
+		return "Array", true
+	}
+	if rtype.Implements(reflect.TypeFor[Dictionary.Interface]()) {
+		return "Dictionary", true
+	}
+	return "", false
+}
+
+// ToNative converts a value in the JSON-compatible form used by JSON.to_native (as decoded by
+// [encoding/json], with float64 numbers) back into variant values, arrays are returned as
+// [Array.Any] and dictionaries as [Dictionary.Any].
+func ToNative(value any) (any, error) {
+	switch v := value.(type) {
+	case nil, bool:
+		return v, nil
+	case float64:
+		return v, nil
+	case string:
+		prefix, text, ok := strings.Cut(v, ":")
+		if !ok {
+			return v, nil
+		}
+		switch prefix {
+		case "i":
+			return strconv.ParseInt(text, 10, 64)
+		case "f":
+			return parseFloat(text)
+		case "s":
+			return text, nil
+		case "sn":
+			return String.Name(String.New(text)), nil
+		case "np":
+			return Path.ToNode(String.New(text)), nil
+		}
+		return v, nil
+	case []any:
+		array := Array.New[variant.Any]()
+		for _, elem := range v {
+			native, err := ToNative(elem)
+			if err != nil {
+				return nil, err
+			}
+			array.Append(variant.New(native))
+		}
+		return array, nil
+	case map[string]any:
+		name, ok := v[keyType].(string)
+		if !ok {
+			return nil, fmt.Errorf("gdjson: object is missing %q", keyType)
+		}
+		args, ok := v[keyArgs].([]any)
+		if !ok {
+			return nil, fmt.Errorf("gdjson: %s is missing %q", name, keyArgs)
+		}
+		return toNative(name, args)
+	}
+	return nil, fmt.Errorf("gdjson: cannot convert %T from JSON", value)
+}
+
+func parseFloat(text string) (float64, error) {
+	switch text {
+	case "inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(text, 64)
+}
+
+func toNative(name string, args []any) (any, error) {
+	floats := func() ([]float64, error) {
+		result := make([]float64, len(args))
+		for i, arg := range args {
+			f, ok := arg.(float64)
+			if !ok {
+				return nil, fmt.Errorf("gdjson: %s argument %d is %T, not a number", name, i, arg)
+			}
+			result[i] = f
+		}
+		return result, nil
+	}
+	if rtype, ok := builtins[name]; ok {
+		f, err := floats()
+		if err != nil {
+			return nil, err
+		}
+		ptr := reflect.New(rtype)
+		if err := stringify.SetComponents(ptr.Interface(), f); err != nil {
+			return nil, fmt.Errorf("gdjson: %w", err)
+		}
+		return ptr.Elem().Interface(), nil
+	}
+	switch name {
+	case "Array":
+		return ToNative(args)
+	case "Dictionary":
+		if len(args)%2 != 0 {
+			return nil, fmt.Errorf("gdjson: Dictionary has an odd number of arguments")
+		}
+		dict := Dictionary.New[variant.Any, variant.Any]()
+		for i := 0; i < len(args); i += 2 {
+			key, err := ToNative(args[i])
+			if err != nil {
+				return nil, err
+			}
+			value, err := ToNative(args[i+1])
+			if err != nil {
+				return nil, err
+			}
+			dict.SetIndex(variant.New(key), variant.New(value))
+		}
+		return dict, nil
+	case "PackedStringArray":
+		strings := make([]string, len(args))
+		for i, arg := range args {
+			s, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("gdjson: %s argument %d is %T, not a string", name, i, arg)
+			}
+			strings[i] = s
+		}
+		return Packed.MakeStrings(strings...), nil
+	}
+	f, err := floats()
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "PackedByteArray":
+		return Packed.Bytes(Packed.New(convert[byte](f)...)), nil
+	case "PackedInt32Array":
+		return Packed.New(convert[int32](f)...), nil
+	case "PackedInt64Array":
+		return Packed.New(convert[int64](f)...), nil
+	case "PackedFloat32Array":
+		return Packed.New(convert[float32](f)...), nil
+	case "PackedFloat64Array":
+		return Packed.New(f...), nil
+	case "PackedVector2Array":
+		return unflatten[Vector2.XY](name, f, 2)
+	case "PackedVector3Array":
+		return unflatten[Vector3.XYZ](name, f, 3)
+	case "PackedColorArray":
+		return unflatten[Color.RGBA](name, f, 4)
+	case "PackedVector4Array":
+		return unflatten[Vector4.XYZW](name, f, 4)
+	}
+	return nil, fmt.Errorf("gdjson: unsupported type %q", name)
+}
+
+func convert[T byte | int32 | int64 | float32](f []float64) []T {
+	result := make([]T, len(f))
+	for i := range f {
+		result[i] = T(f[i])
+	}
+	return result
+}
+
+func unflatten[T Vector2.XY | Vector3.XYZ | Color.RGBA | Vector4.XYZW](name string, f []float64, size int) (Packed.Array[T], error) {
+	if len(f)%size != 0 {
+		return Packed.Array[T]{}, fmt.Errorf("gdjson: %s requires a multiple of %d arguments", name, size)
+	}
+	values := make([]T, len(f)/size)
+	for i := range values {
+		if err := stringify.SetComponents(&values[i], f[i*size:(i+1)*size]); err != nil {
+			return Packed.Array[T]{}, err
+		}
+	}
+	return Packed.New(values...), nil
+}
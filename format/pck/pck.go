@@ -0,0 +1,144 @@
+// Package pck reads and writes Godot .pck archives, without the engine.
+//
+// A .pck archive starts with a header, followed by a directory of files (which may be
+// encrypted) and then the file data itself. Each file in the directory records the
+// MD5 of its contents, so that archives can be verified and compared.
+package pck
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Magic number at the start of every .pck archive ("GDPC").
+const Magic uint32 = 0x43504447
+
+// FormatVersion of the archives written by this package (as used by Godot 4.3).
+const FormatVersion uint32 = 2
+
+// Flags for the archive.
+type Flags uint32
+
+const (
+	DirectoryEncrypted Flags = 1 << iota // the file directory is encrypted.
+	RelativeFileBase                     // file offsets are relative to the file base.
+)
+
+// Header of a .pck archive.
+type Header struct {
+	FormatVersion uint32
+	Major         uint32 // engine version that the archive was written for.
+	Minor         uint32
+	Patch         uint32
+	Flags         Flags
+	FileBase      uint64 // offset of the file data, relative to the start of the archive.
+}
+
+// File describes an entry in the archive's directory.
+type File struct {
+	Path      string // resource path, ie. "res://icon.svg"
+	Offset    uint64 // offset of the data, relative to the file base.
+	Size      uint64 // size of the (unencrypted) data.
+	MD5       [16]byte
+	Encrypted bool
+}
+
+// fileEncrypted flag for each file in the directory.
+const fileEncrypted = 1 << 0
+
+// padding between the file data in an archive.
+const padding = 16
+
+// KeySize is the size of the AES-256 encryption key used for encrypted archives.
+const KeySize = 32
+
+var (
+	ErrFormat   = errors.New("pck: not a valid .pck archive")
+	ErrVersion  = errors.New("pck: unsupported .pck format version")
+	ErrKey      = errors.New("pck: encrypted archive requires a 32 byte key")
+	ErrChecksum = errors.New("pck: checksum mismatch (invalid key?)")
+)
+
+func pad(n, alignment uint64) uint64 {
+	if rem := n % alignment; rem != 0 {
+		return alignment - rem
+	}
+	return 0
+}
+
+// encrypt the given data in the same format as the engine's FileAccessEncrypted (without the
+// magic header), using AES-256 in CFB mode.
+func encrypt(key, iv, data []byte) ([]byte, error) {
+	if len(key) != KeySize {
+		return nil, ErrKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	sum := md5.Sum(data)
+	var out bytes.Buffer
+	out.Write(sum[:])
+	binary.Write(&out, binary.LittleEndian, uint64(len(data)))
+	out.Write(iv)
+	padded := make([]byte, uint64(len(data))+pad(uint64(len(data)), aes.BlockSize))
+	copy(padded, data)
+	cipher.NewCFBEncrypter(block, iv).XORKeyStream(padded, padded)
+	out.Write(padded)
+	return out.Bytes(), nil
+}
+
+// readN reads exactly n bytes from r, the buffer grows as they are read, so that a corrupt size
+// cannot allocate more memory than the data that is actually there.
+func readN(r io.Reader, n uint64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, int64(n)))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data)) != n {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return data, nil
+}
+
+// decrypt reads data written by encrypt.
+func decrypt(key []byte, r io.Reader) ([]byte, error) {
+	if len(key) != KeySize {
+		return nil, ErrKey
+	}
+	var header struct {
+		MD5    [16]byte
+		Length uint64
+		IV     [16]byte
+	}
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	if header.Length > 1<<40 {
+		return nil, ErrFormat
+	}
+	padded, err := readN(r, header.Length+pad(header.Length, aes.BlockSize))
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	cipher.NewCFBDecrypter(block, header.IV[:]).XORKeyStream(padded, padded)
+	data := padded[:header.Length]
+	if md5.Sum(data) != header.MD5 {
+		return nil, ErrChecksum
+	}
+	return data, nil
+}
+
+func (h Header) String() string {
+	return fmt.Sprintf("pck v%d (engine %d.%d.%d)", h.FormatVersion, h.Major, h.Minor, h.Patch)
+}
@@ -0,0 +1,135 @@
+package pck_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"testing"
+
+	"graphics.gd/format/pck"
+)
+
+func TestRoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, pck.KeySize)
+	for _, encryptDirectory := range []bool{false, true} {
+		var buf bytes.Buffer
+		w := pck.NewWriter(&buf)
+		w.Key = key
+		w.EncryptDirectory = encryptDirectory
+		if err := w.Add("project.binary", []byte("config_version=5")); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Add("res://icon.svg", []byte("<svg></svg>")); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.AddEncrypted("res://secret.txt", []byte("hidden in plain sight, seventeen+")); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		r, err := pck.NewReader(bytes.NewReader(buf.Bytes()), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Major != 4 || r.Minor != 3 || r.FormatVersion != pck.FormatVersion {
+			t.Fatalf("unexpected header %v", r.Header)
+		}
+		if len(r.Files) != 3 || r.Files[0].Path != "res://project.binary" {
+			t.Fatalf("unexpected directory %v", r.Files)
+		}
+		for path, expected := range map[string]string{
+			"project.binary":   "config_version=5",
+			"res://icon.svg":   "<svg></svg>",
+			"res://secret.txt": "hidden in plain sight, seventeen+",
+		} {
+			data, err := r.ReadFile(path)
+			if err != nil {
+				t.Fatal(path, err)
+			}
+			if string(data) != expected {
+				t.Fatalf("%s: got %q, want %q", path, data, expected)
+			}
+		}
+		if _, err := r.ReadFile("missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("expected fs.ErrNotExist, got %v", err)
+		}
+		wrong := bytes.Repeat([]byte{0x24}, pck.KeySize)
+		if encryptDirectory {
+			if _, err := pck.NewReader(bytes.NewReader(buf.Bytes()), wrong); err == nil {
+				t.Fatal("expected an error, reading an encrypted directory with the wrong key")
+			}
+			continue
+		}
+		r, err = pck.NewReader(bytes.NewReader(buf.Bytes()), wrong)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.ReadFile("secret.txt"); !errors.Is(err, pck.ErrChecksum) {
+			t.Fatalf("expected pck.ErrChecksum, got %v", err)
+		}
+	}
+}
+
+func TestInvalid(t *testing.T) {
+	if _, err := pck.NewReader(bytes.NewReader([]byte("PK\x03\x04")), nil); !errors.Is(err, pck.ErrFormat) {
+		t.Fatalf("expected pck.ErrFormat, got %v", err)
+	}
+}
+
+// testdata/export.pck is laid out as the editor exports a project (EditorExportPlatform::save_pack
+// in Godot 4.3), with a relative file base and each path and file padded, it was assembled from
+// that layout rather than exported by the editor.
+func TestExported(t *testing.T) {
+	r, err := pck.OpenReader("testdata/export.pck", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if r.Major != 4 || r.Minor != 3 || r.Flags != pck.RelativeFileBase || r.FileBase != 224 {
+		t.Fatalf("unexpected header %v, file base %d", r.Header, r.FileBase)
+	}
+	if len(r.Files) != 2 || r.Files[0].Path != "res://project.binary" || r.Files[1].Offset != 16 {
+		t.Fatalf("unexpected directory %v", r.Files)
+	}
+	data, err := r.ReadFile("res://icon.svg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte("<svg")) || len(data) != int(r.Files[1].Size) {
+		t.Fatalf("unexpected icon %q", data)
+	}
+}
+
+// onlyReaderAt hides the size of an archive.
+type onlyReaderAt struct{ io.ReaderAt }
+
+func TestCorrupt(t *testing.T) {
+	archive, err := os.ReadFile("testdata/export.pck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the size of the first file, after the header, its path length and path, and its offset.
+	const size = 4*5 + 4 + 8 + 16*4 + 4 + 4 + len("res://project.binary") + 8
+	for _, corrupt := range []uint64{1 << 40, 1<<64 - 1} {
+		data := bytes.Clone(archive)
+		binary.LittleEndian.PutUint64(data[size:], corrupt)
+		r, err := pck.NewReader(bytes.NewReader(data), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.ReadFile("project.binary"); !errors.Is(err, pck.ErrFormat) {
+			t.Fatalf("expected pck.ErrFormat for a size of %d, got %v", corrupt, err)
+		}
+		r, err = pck.NewReader(onlyReaderAt{bytes.NewReader(data)}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.ReadFile("project.binary"); err == nil {
+			t.Fatalf("expected an error for a size of %d in an archive of unknown size", corrupt)
+		}
+	}
+}
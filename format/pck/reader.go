@@ -0,0 +1,185 @@
+package pck
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/binary"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// Reader provides access to the files within a .pck archive.
+type Reader struct {
+	Header
+	Files []File
+
+	r    io.ReaderAt
+	key  []byte
+	base int64 // absolute offset of the file data.
+	size int64 // size of the archive, or -1 when it is unknown.
+}
+
+// ReadCloser is a [Reader] that must be closed when no longer needed.
+type ReadCloser struct {
+	Reader
+	f *os.File
+}
+
+// OpenReader opens the .pck archive at the given path, key is only required for encrypted
+// archives (it may be nil).
+func OpenReader(name string, key []byte) (*ReadCloser, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewReader(f, key)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &ReadCloser{Reader: *r, f: f}, nil
+}
+
+// Close the underlying file.
+func (rc *ReadCloser) Close() error { return rc.f.Close() }
+
+// NewReader reads the header and directory of the .pck archive, key is only required for
+// encrypted archives (it may be nil).
+func NewReader(r io.ReaderAt, key []byte) (*Reader, error) {
+	pck := &Reader{r: r, key: key, size: sizeOf(r)}
+	section := io.NewSectionReader(r, 0, 1<<62)
+	var header struct {
+		Magic         uint32
+		FormatVersion uint32
+		Major         uint32
+		Minor         uint32
+		Patch         uint32
+		Flags         Flags
+		FileBase      uint64
+		Reserved      [16]uint32
+		FileCount     uint32
+	}
+	if err := binary.Read(section, binary.LittleEndian, &header); err != nil {
+		return nil, ErrFormat
+	}
+	if header.Magic != Magic {
+		return nil, ErrFormat
+	}
+	if header.FormatVersion != FormatVersion {
+		return nil, ErrVersion
+	}
+	pck.Header = Header{
+		FormatVersion: header.FormatVersion,
+		Major:         header.Major,
+		Minor:         header.Minor,
+		Patch:         header.Patch,
+		Flags:         header.Flags,
+		FileBase:      header.FileBase,
+	}
+	var directory io.Reader = section
+	if header.Flags&DirectoryEncrypted != 0 {
+		data, err := decrypt(key, section)
+		if err != nil {
+			return nil, err
+		}
+		directory = bytes.NewReader(data)
+	}
+	for range header.FileCount {
+		var length uint32
+		if err := binary.Read(directory, binary.LittleEndian, &length); err != nil {
+			return nil, ErrFormat
+		}
+		if length > 1<<16 {
+			return nil, ErrFormat
+		}
+		path := make([]byte, length)
+		if _, err := io.ReadFull(directory, path); err != nil {
+			return nil, ErrFormat
+		}
+		var entry struct {
+			Offset uint64
+			Size   uint64
+			MD5    [16]byte
+			Flags  uint32
+		}
+		if err := binary.Read(directory, binary.LittleEndian, &entry); err != nil {
+			return nil, ErrFormat
+		}
+		pck.Files = append(pck.Files, File{
+			Path:      string(bytes.TrimRight(path, "\x00")),
+			Offset:    entry.Offset,
+			Size:      entry.Size,
+			MD5:       entry.MD5,
+			Encrypted: entry.Flags&fileEncrypted != 0,
+		})
+	}
+	if header.Flags&RelativeFileBase != 0 {
+		pck.base = int64(header.FileBase)
+	}
+	return pck, nil
+}
+
+// Lookup returns the directory entry for the given path, "res://" is optional.
+func (pck *Reader) Lookup(path string) (File, bool) {
+	path = strings.TrimPrefix(path, "res://")
+	for _, file := range pck.Files {
+		if strings.TrimPrefix(file.Path, "res://") == path {
+			return file, true
+		}
+	}
+	return File{}, false
+}
+
+// ReadFile returns the contents of the file at the given path, decrypting it if required and
+// verifying it against the MD5 recorded in the directory.
+func (pck *Reader) ReadFile(path string) ([]byte, error) {
+	file, ok := pck.Lookup(path)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	}
+	return pck.Read(file)
+}
+
+// Read returns the contents of the given file.
+func (pck *Reader) Read(file File) ([]byte, error) {
+	if file.Offset > 1<<62 || file.Size > 1<<62 {
+		return nil, ErrFormat
+	}
+	start := pck.base + int64(file.Offset)
+	if pck.size >= 0 && (start > pck.size || int64(file.Size) > pck.size-start) {
+		return nil, ErrFormat
+	}
+	section := io.NewSectionReader(pck.r, start, 1<<62)
+	var data []byte
+	if file.Encrypted {
+		var err error
+		data, err = decrypt(pck.key, section)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if data, err = readN(section, file.Size); err != nil {
+			return nil, err
+		}
+	}
+	if file.MD5 != ([16]byte{}) && md5.Sum(data) != file.MD5 {
+		return nil, ErrChecksum
+	}
+	return data, nil
+}
+
+// sizeOf returns the size of r, or -1 when it is unknown.
+func sizeOf(r io.ReaderAt) int64 {
+	switch r := r.(type) {
+	case interface{ Size() int64 }:
+		return r.Size()
+	case interface{ Stat() (fs.FileInfo, error) }:
+		if info, err := r.Stat(); err == nil {
+			return info.Size()
+		}
+	}
+	return -1
+}
@@ -0,0 +1,147 @@
+package pck
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Writer writes a .pck archive, files are buffered until [Writer.Close] is called, as the
+// directory needs to be written before the file data.
+type Writer struct {
+	Major, Minor, Patch uint32 // engine version to write into the header (defaults to 4.3.0).
+
+	// Key used to encrypt files added with [Writer.AddEncrypted] and the directory if
+	// EncryptDirectory is set.
+	Key              []byte
+	EncryptDirectory bool
+
+	w      io.Writer
+	files  []pending
+	closed bool
+}
+
+type pending struct {
+	path      string
+	data      []byte
+	encrypted bool
+}
+
+// NewWriter returns a new [Writer] that writes a .pck archive to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{Major: 4, Minor: 3, w: w}
+}
+
+// Add a file to the archive, "res://" will be prefixed to the path if missing.
+func (w *Writer) Add(path string, data []byte) error {
+	return w.add(path, data, false)
+}
+
+// AddEncrypted adds a file to the archive that will be encrypted with the [Writer.Key].
+func (w *Writer) AddEncrypted(path string, data []byte) error {
+	if len(w.Key) != KeySize {
+		return ErrKey
+	}
+	return w.add(path, data, true)
+}
+
+func (w *Writer) add(path string, data []byte, encrypted bool) error {
+	if w.closed {
+		return errors.New("pck: write to closed Writer")
+	}
+	if !strings.HasPrefix(path, "res://") {
+		path = "res://" + strings.TrimPrefix(path, "/")
+	}
+	w.files = append(w.files, pending{path: path, data: data, encrypted: encrypted})
+	return nil
+}
+
+// Close writes the archive, it does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.EncryptDirectory && len(w.Key) != KeySize {
+		return ErrKey
+	}
+	var (
+		directory bytes.Buffer
+		contents  [][]byte
+		offset    uint64
+	)
+	for _, file := range w.files {
+		data := file.data
+		if file.encrypted {
+			var err error
+			if data, err = encrypt(w.Key, randomIV(), data); err != nil {
+				return err
+			}
+		}
+		path := []byte(file.path)
+		length := uint64(len(path))
+		path = append(path, make([]byte, pad(length, 4))...)
+		binary.Write(&directory, binary.LittleEndian, uint32(len(path)))
+		directory.Write(path)
+		var flags uint32
+		if file.encrypted {
+			flags |= fileEncrypted
+		}
+		binary.Write(&directory, binary.LittleEndian, struct {
+			Offset uint64
+			Size   uint64
+			MD5    [16]byte
+			Flags  uint32
+		}{offset, uint64(len(file.data)), md5.Sum(file.data), flags})
+		contents = append(contents, data)
+		offset += uint64(len(data)) + pad(uint64(len(data)), padding)
+	}
+	var flags = RelativeFileBase
+	dir := directory.Bytes()
+	if w.EncryptDirectory {
+		flags |= DirectoryEncrypted
+		var err error
+		if dir, err = encrypt(w.Key, randomIV(), dir); err != nil {
+			return err
+		}
+	}
+	const headerSize = 4*6 + 8 + 16*4 + 4
+	base := uint64(headerSize + len(dir))
+	base += pad(base, padding)
+	var out bytes.Buffer
+	binary.Write(&out, binary.LittleEndian, struct {
+		Magic         uint32
+		FormatVersion uint32
+		Major         uint32
+		Minor         uint32
+		Patch         uint32
+		Flags         Flags
+		FileBase      uint64
+		Reserved      [16]uint32
+		FileCount     uint32
+	}{Magic, FormatVersion, w.Major, w.Minor, w.Patch, flags, base, [16]uint32{}, uint32(len(w.files))})
+	out.Write(dir)
+	out.Write(make([]byte, base-uint64(out.Len())))
+	if _, err := w.w.Write(out.Bytes()); err != nil {
+		return err
+	}
+	for _, data := range contents {
+		if _, err := w.w.Write(data); err != nil {
+			return err
+		}
+		if _, err := w.w.Write(make([]byte, pad(uint64(len(data)), padding))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func randomIV() []byte {
+	var iv [16]byte
+	rand.Read(iv[:])
+	return iv[:]
+}
@@ -0,0 +1,97 @@
+package res
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"graphics.gd/internal/fastlz"
+	"graphics.gd/internal/zstd"
+)
+
+// compression modes, as used by the engine's FileAccessCompressed.
+const (
+	compressionFastLZ  = 0
+	compressionDeflate = 1
+	compressionZstd    = 2
+	compressionGZip    = 3
+)
+
+// maxBlockSize is the largest block size that is accepted, the engine writes 4096 byte blocks.
+const maxBlockSize = 1 << 24
+
+// decompress the data that follows the magic number of a file written by FileAccessCompressed.
+func decompress(data []byte) ([]byte, error) {
+	var header struct {
+		Mode      uint32
+		BlockSize uint32
+		Total     uint32
+	}
+	r := bytes.NewReader(data)
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil || header.BlockSize == 0 || header.BlockSize > maxBlockSize {
+		return nil, ErrFormat
+	}
+	// the header is checked against the remaining data before anything is allocated, so that a
+	// corrupt file cannot allocate more memory than it could possibly decompress to.
+	count := int64(header.Total/header.BlockSize) + 1
+	if count*4 > int64(r.Len()) {
+		return nil, ErrFormat
+	}
+	sizes := make([]uint32, count)
+	if err := binary.Read(r, binary.LittleEndian, sizes); err != nil {
+		return nil, ErrFormat
+	}
+	var compressed int64
+	for _, size := range sizes {
+		compressed += int64(size)
+	}
+	if compressed > int64(r.Len()) {
+		return nil, ErrFormat
+	}
+	out := make([]byte, 0, min(header.Total, header.BlockSize))
+	for i, size := range sizes {
+		block := make([]byte, size)
+		if _, err := io.ReadFull(r, block); err != nil {
+			return nil, ErrFormat
+		}
+		expected := header.BlockSize
+		if i == len(sizes)-1 {
+			expected = header.Total % header.BlockSize
+		}
+		var (
+			decompressed []byte
+			err          error
+		)
+		switch header.Mode {
+		case compressionFastLZ:
+			decompressed, err = fastlz.Decompress(block, int(expected))
+		case compressionDeflate:
+			decompressed, err = inflate(zlib.NewReader(bytes.NewReader(block)))
+		case compressionZstd:
+			decompressed, err = zstd.Decompress(block, int(expected))
+		case compressionGZip:
+			decompressed, err = inflate(gzip.NewReader(bytes.NewReader(block)))
+		default:
+			return nil, fmt.Errorf("%w %d", ErrCompression, header.Mode)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("res: block %d: %w", i, err)
+		}
+		if len(decompressed) != int(expected) {
+			return nil, fmt.Errorf("res: block %d: decompressed to %d bytes, expected %d", i, len(decompressed), expected)
+		}
+		out = append(out, decompressed...)
+	}
+	return out, nil
+}
+
+func inflate(r io.ReadCloser, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,193 @@
+package res
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Parse a binary resource, which may be compressed.
+func Parse(data []byte) (*File, error) {
+	if len(data) < 4 {
+		return nil, ErrFormat
+	}
+	base := uint64(0) // position of the data within the file, that offsets are relative to.
+	switch string(data[:4]) {
+	case Magic:
+		data, base = data[4:], 4
+	case MagicCompressed:
+		var err error
+		if data, err = decompress(data[4:]); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrFormat
+	}
+	r := &reader{data: data, order: binary.LittleEndian}
+	var file File
+	file.BigEndian = r.u32() != 0
+	if file.BigEndian {
+		r.order = binary.BigEndian
+	}
+	r.real64 = r.u32() != 0
+	file.Major = r.u32()
+	file.Minor = r.u32()
+	file.Format = r.u32()
+	file.Type = r.string()
+	r.u64() // offset of the import metadata.
+	file.Flags = Flags(r.u32())
+	file.UID = UID(r.u64())
+	if file.Flags&HasUIDs == 0 {
+		file.UID = -1
+	}
+	if file.Flags&RealIsDouble != 0 {
+		r.real64 = true
+	}
+	if file.Flags&HasScriptClass != 0 {
+		file.ScriptClass = r.string()
+	}
+	for range reservedFields {
+		r.u32()
+	}
+	r.strings = make([]string, r.count())
+	for i := range r.strings {
+		r.strings[i] = r.string()
+	}
+	file.External = make([]ExtResource, r.count())
+	for i := range file.External {
+		ext := &file.External[i]
+		ext.Type = r.string()
+		ext.Path = r.string()
+		ext.UID = -1
+		if file.Flags&HasUIDs != 0 {
+			ext.UID = UID(r.u64())
+		}
+	}
+	file.Internal = make([]Resource, r.count())
+	offsets := make([]uint64, len(file.Internal))
+	for i := range file.Internal {
+		file.Internal[i].Path = r.string()
+		offsets[i] = r.u64()
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.named = file.Flags&NamedSceneIDs != 0
+	r.file = &file
+	for i, offset := range offsets {
+		if offset < base || offset-base > uint64(len(data)) {
+			return nil, fmt.Errorf("res: resource %d has an invalid offset", i)
+		}
+		r.pos = int(offset - base)
+		resource := &file.Internal[i]
+		resource.Type = r.string()
+		resource.Properties = make([]Property, r.count())
+		for j := range resource.Properties {
+			resource.Properties[j].Name = r.name()
+			resource.Properties[j].Value = r.variant(0)
+		}
+		if r.err != nil {
+			return nil, fmt.Errorf("res: %s (%s): %w", resource.Path, resource.Type, r.err)
+		}
+	}
+	return &file, nil
+}
+
+// reader decodes the binary format, after the first error, all reads return zero values.
+type reader struct {
+	data    []byte
+	pos     int
+	order   binary.ByteOrder
+	real64  bool
+	named   bool
+	strings []string
+	file    *File
+	err     error
+}
+
+func (r *reader) errorf(format string, args ...any) {
+	if r.err == nil {
+		r.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data)-r.pos {
+		r.errorf("unexpected end of data at offset %d", r.pos)
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *reader) u16() uint16 {
+	if b := r.bytes(2); b != nil {
+		return r.order.Uint16(b)
+	}
+	return 0
+}
+
+func (r *reader) u32() uint32 {
+	if b := r.bytes(4); b != nil {
+		return r.order.Uint32(b)
+	}
+	return 0
+}
+
+func (r *reader) u64() uint64 {
+	if b := r.bytes(8); b != nil {
+		return r.order.Uint64(b)
+	}
+	return 0
+}
+
+func (r *reader) f32() float32 { return math.Float32frombits(r.u32()) }
+func (r *reader) f64() float64 { return math.Float64frombits(r.u64()) }
+
+// real reads a real_t, which is either 32 or 64 bits, depending on the file.
+func (r *reader) real() float64 {
+	if r.real64 {
+		return r.f64()
+	}
+	return float64(r.f32())
+}
+
+// count reads a length, that must fit within the remaining data.
+func (r *reader) count() int {
+	n := r.u32()
+	if uint64(n) > uint64(len(r.data)-r.pos) {
+		r.errorf("invalid length %d at offset %d", n, r.pos)
+		return 0
+	}
+	return int(n)
+}
+
+// string reads a length-prefixed, null-terminated UTF-8 string.
+func (r *reader) string() string {
+	return strings.TrimRight(string(r.bytes(r.count())), "\x00")
+}
+
+// name reads a string from the string table, or an inline string when the high bit is set.
+func (r *reader) name() string {
+	id := r.u32()
+	if id&0x80000000 != 0 {
+		return strings.TrimRight(string(r.bytes(int(id&0x7FFFFFFF))), "\x00")
+	}
+	if int(id) >= len(r.strings) {
+		r.errorf("invalid string index %d", id)
+		return ""
+	}
+	return r.strings[id]
+}
+
+// padding skips the padding after a byte array of length n.
+func (r *reader) padding(n int) {
+	if extra := 4 - n%4; extra < 4 {
+		r.bytes(extra)
+	}
+}
@@ -0,0 +1,150 @@
+// Package res reads Godot's binary resource format, as used by .res and .scn files (and the
+// resources that the editor imports into .godot/imported), without the engine.
+//
+// A [File] lists the external resources that it depends on and the internal resources that it
+// contains, the last of which is the main resource. Properties are decoded into the
+// corresponding variant types, using the same Go types as [graphics.gd/format/tres], so that
+// assets can be inspected by tests and CI checks, for example:
+//
+//	file, err := res.Parse(data)
+//	if err != nil {
+//		return err
+//	}
+//	for _, ext := range file.External {
+//		fmt.Println(ext.Type, ext.Path)
+//	}
+//	width, _ := file.Main().Get("width")
+//
+// Compressed textures (.ctex) use a different container, their header can be read with
+// [ParseTexture].
+package res
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Magic number at the start of every binary resource ("RSRC").
+const Magic = "RSRC"
+
+// MagicCompressed is the magic number of compressed binary resources ("RSCC").
+const MagicCompressed = "RSCC"
+
+// Flags in the header of a binary resource.
+type Flags uint32
+
+const (
+	NamedSceneIDs  Flags = 1 << iota // internal resources have named ids, rather than indices.
+	HasUIDs                          // external resources include their UID.
+	RealIsDouble                     // real numbers are stored as 64-bit floats.
+	HasScriptClass                   // the header includes the script class of the main resource.
+)
+
+// reservedFields after the header.
+const reservedFields = 11
+
+var (
+	ErrFormat      = errors.New("res: not a binary resource")
+	ErrCompression = errors.New("res: unsupported compression mode")
+)
+
+// File is a binary resource or scene.
+type File struct {
+	BigEndian   bool
+	Major       uint32 // engine version that the file was written by.
+	Minor       uint32
+	Format      uint32 // version of the binary format.
+	Type        string // class of the main resource, ie. "PackedScene".
+	Flags       Flags
+	UID         UID
+	ScriptClass string // global class name of the main resource's script, if any.
+	External    []ExtResource
+	Internal    []Resource
+}
+
+// ExtResource is a resource that the file depends on.
+type ExtResource struct {
+	Type string
+	Path string
+	UID  UID
+}
+
+// Resource stored within the file.
+type Resource struct {
+	Path       string // ie. "local://Mesh_abc12", or the path of the main resource.
+	Type       string
+	Properties []Property
+}
+
+// Property is a named value.
+type Property struct {
+	Name  string
+	Value any
+}
+
+// External refers to File.External by index.
+type External int
+
+// Internal refers to File.Internal by index.
+type Internal int
+
+// UID of a resource, as assigned by the engine's ResourceUID, -1 when invalid.
+type UID int64
+
+// uidChars are the digits used for the text form of a UID.
+const uidChars = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// String returns the text form of the UID, ie. "uid://b4xj0d1y2qdvq".
+func (id UID) String() string {
+	if id < 0 {
+		return "uid://<invalid>"
+	}
+	var buf [16]byte
+	i := len(buf)
+	for n := uint64(id); ; n /= uint64(len(uidChars)) {
+		i--
+		buf[i] = uidChars[n%uint64(len(uidChars))]
+		if n < uint64(len(uidChars)) {
+			break
+		}
+	}
+	return "uid://" + string(buf[i:])
+}
+
+// Main returns the main resource, which is the last internal resource in the file.
+func (f *File) Main() *Resource {
+	if len(f.Internal) == 0 {
+		return nil
+	}
+	return &f.Internal[len(f.Internal)-1]
+}
+
+// ExtResource returns the external resource that the reference refers to.
+func (f *File) ExtResource(ref External) (*ExtResource, bool) {
+	if ref < 0 || int(ref) >= len(f.External) {
+		return nil, false
+	}
+	return &f.External[ref], true
+}
+
+// SubResource returns the internal resource that the reference refers to.
+func (f *File) SubResource(ref Internal) (*Resource, bool) {
+	if ref < 0 || int(ref) >= len(f.Internal) {
+		return nil, false
+	}
+	return &f.Internal[ref], true
+}
+
+// Get returns the value of the property with the given name.
+func (r *Resource) Get(name string) (any, bool) {
+	for _, prop := range r.Properties {
+		if prop.Name == name {
+			return prop.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (f *File) String() string {
+	return fmt.Sprintf("%s (engine %d.%d, format %d)", f.Type, f.Major, f.Minor, f.Format)
+}
@@ -0,0 +1,244 @@
+package res_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"graphics.gd/format/res"
+	"graphics.gd/format/tres"
+	"graphics.gd/variant/Packed"
+	"graphics.gd/variant/Vector2"
+)
+
+// encoder writes the binary resource format for tests.
+type encoder struct{ bytes.Buffer }
+
+func (e *encoder) u16(v uint16)  { binary.Write(e, binary.LittleEndian, v) }
+func (e *encoder) u32(v uint32)  { binary.Write(e, binary.LittleEndian, v) }
+func (e *encoder) u64(v uint64)  { binary.Write(e, binary.LittleEndian, v) }
+func (e *encoder) f32(v float32) { e.u32(math.Float32bits(v)) }
+
+func (e *encoder) string(s string) {
+	e.u32(uint32(len(s) + 1))
+	e.WriteString(s)
+	e.WriteByte(0)
+}
+
+// sample returns a binary resource, with a sub-resource that refers to an external texture, the
+// offsets of compressed resources are relative to the data after the magic.
+func sample(compressed bool) []byte {
+	var e encoder
+	e.WriteString("RSRC")
+	e.u32(0)             // little endian
+	e.u32(0)             // 32-bit reals
+	e.u32(4)             // major
+	e.u32(3)             // minor
+	e.u32(6)             // format
+	e.string("Resource") // type
+	e.u64(0)             // import metadata
+	e.u32(1 | 2)         // named scene ids, uids
+	e.u64(42)            // uid
+	for range 11 {
+		e.u32(0)
+	}
+	e.u32(3)
+	e.string("size")
+	e.string("texture")
+	e.string("metadata")
+	e.u32(1)
+	e.string("Texture2D")
+	e.string("res://icon.svg")
+	e.u64(7)
+	e.u32(2)
+	e.string("local://Sub_1")
+	offsets := e.Len()
+	e.u64(0)
+	e.string("res://sample.res")
+	e.u64(0)
+	sub := e.Len()
+	e.string("Sub")
+	e.u32(2)
+	e.u32(0) // size
+	e.u32(10)
+	e.f32(4096)
+	e.f32(2048)
+	e.u32(1) // texture
+	e.u32(24)
+	e.u32(3)
+	e.u32(0)
+	main := e.Len()
+	e.string("Resource")
+	e.u32(2)
+	e.u32(0x80000000 | 4) // inline name
+	e.WriteString("sub\x00")
+	e.u32(24)
+	e.u32(2)
+	e.u32(0)
+	e.u32(2) // metadata
+	e.u32(26)
+	e.u32(3)
+	e.u32(44)
+	e.string("path")
+	e.u32(22)
+	e.u16(2)
+	e.u16(1 | 0x8000)
+	e.u32(0x80000000 | 5)
+	e.WriteString("root\x00")
+	e.u32(0x80000000 | 5)
+	e.WriteString("node\x00")
+	e.u32(0x80000000 | 9)
+	e.WriteString("position\x00")
+	e.u32(5)
+	e.string("bytes")
+	e.u32(31)
+	e.u32(3)
+	e.Write([]byte{1, 2, 3, 0})
+	e.u32(5)
+	e.string("float")
+	e.u32(41)
+	e.u64(math.Float64bits(0.1))
+	data := e.Bytes()
+	if compressed {
+		sub, main = sub-4, main-4
+	}
+	binary.LittleEndian.PutUint64(data[offsets:], uint64(sub))
+	binary.LittleEndian.PutUint64(data[offsets+8+4+len("res://sample.res")+1:], uint64(main))
+	return data
+}
+
+func check(t *testing.T, file *res.File) {
+	t.Helper()
+	if file.Major != 4 || file.Minor != 3 || file.Format != 6 || file.Type != "Resource" || file.UID != 42 {
+		t.Fatalf("unexpected header %v", file)
+	}
+	if len(file.External) != 1 || file.External[0] != (res.ExtResource{Type: "Texture2D", Path: "res://icon.svg", UID: 7}) {
+		t.Fatalf("unexpected external resources %v", file.External)
+	}
+	sub, ok := file.SubResource(0)
+	if !ok || sub.Type != "Sub" {
+		t.Fatalf("unexpected sub resource %v", sub)
+	}
+	if size, _ := sub.Get("size"); size != (Vector2.XY{4096, 2048}) {
+		t.Fatalf("unexpected size %v", size)
+	}
+	texture, _ := sub.Get("texture")
+	if ext, ok := file.ExtResource(texture.(res.External)); !ok || ext.Path != "res://icon.svg" {
+		t.Fatalf("unexpected texture %v", texture)
+	}
+	main := file.Main()
+	if ref, _ := main.Get("sub"); ref != res.Internal(0) {
+		t.Fatalf("unexpected sub %v", ref)
+	}
+	metadata, _ := main.Get("metadata")
+	dict, ok := metadata.(tres.Dictionary)
+	if !ok || len(dict) != 3 {
+		t.Fatalf("unexpected metadata %#v", metadata)
+	}
+	if path, err := tres.FormatValue(dict[0].Value); err != nil || path != `NodePath("/root/node:position")` {
+		t.Fatalf("unexpected path %v", path)
+	}
+	if bytes, ok := dict[1].Value.(Packed.Bytes); !ok || string(bytes.Bytes()) != "\x01\x02\x03" {
+		t.Fatalf("unexpected bytes %v", dict[1].Value)
+	}
+	if dict[2].Value != 0.1 {
+		t.Fatalf("unexpected float %v", dict[2].Value)
+	}
+}
+
+func TestParse(t *testing.T) {
+	file, err := res.Parse(sample(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(t, file)
+}
+
+func TestParseCompressed(t *testing.T) {
+	data := sample(true)[4:]
+	const blockSize = 64
+	var e encoder
+	e.WriteString("RSCC")
+	e.u32(1) // deflate
+	e.u32(blockSize)
+	e.u32(uint32(len(data)))
+	var blocks [][]byte
+	for i := 0; i <= len(data)/blockSize; i++ {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		w.Write(data[i*blockSize : min((i+1)*blockSize, len(data))])
+		w.Close()
+		blocks = append(blocks, buf.Bytes())
+		e.u32(uint32(buf.Len()))
+	}
+	for _, block := range blocks {
+		e.Write(block)
+	}
+	file, err := res.Parse(e.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(t, file)
+}
+
+func TestParseCompressedCorrupt(t *testing.T) {
+	for _, header := range []struct {
+		name             string
+		blockSize, total uint32
+	}{
+		{"zero block size", 0, 100},
+		{"huge block size", 1 << 31, 100},
+		{"too many blocks", 1, 1<<32 - 1},
+		{"blocks larger than the file", 4096, 100},
+	} {
+		var e encoder
+		e.WriteString("RSCC")
+		e.u32(1) // deflate
+		e.u32(header.blockSize)
+		e.u32(header.total)
+		e.u32(1 << 30)
+		if _, err := res.Parse(e.Bytes()); !errors.Is(err, res.ErrFormat) {
+			t.Errorf("%s: expected res.ErrFormat, got %v", header.name, err)
+		}
+	}
+}
+
+func TestParseTexture(t *testing.T) {
+	var e encoder
+	e.WriteString("GST2")
+	e.u32(1)
+	e.u32(8192)
+	e.u32(4096)
+	e.u32(0)
+	e.u32(0)
+	e.u32(0)
+	e.u32(0)
+	e.u32(0)
+	e.u32(uint32(res.TextureWebP))
+	e.u16(8192)
+	e.u16(4096)
+	e.u32(13)
+	e.u32(5)
+	texture, err := res.ParseTexture(e.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if texture.Width != 8192 || texture.Height != 4096 || texture.DataFormat != res.TextureWebP || texture.Mipmaps != 13 {
+		t.Fatalf("unexpected texture %+v", texture)
+	}
+}
+
+func TestUID(t *testing.T) {
+	if s := res.UID(0).String(); s != "uid://a" {
+		t.Fatal(s)
+	}
+	if s := res.UID(36*36 + 27).String(); s != "uid://ba1" {
+		t.Fatal(s)
+	}
+	if s := res.UID(-1).String(); s != "uid://<invalid>" {
+		t.Fatal(s)
+	}
+}
@@ -0,0 +1,89 @@
+package res
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// MagicTexture is the magic number at the start of every compressed texture (.ctex) ("GST2").
+const MagicTexture = "GST2"
+
+// TextureFormatVersion is the latest version of the compressed texture format.
+const TextureFormatVersion = 1
+
+// TextureDataFormat describes how the image data of a compressed texture is stored.
+type TextureDataFormat uint32
+
+const (
+	TextureImage          TextureDataFormat = iota // uncompressed or VRAM compressed image data.
+	TexturePNG                                     // lossless, PNG encoded.
+	TextureWebP                                    // lossy or lossless, WebP encoded.
+	TextureBasisUniversal                          // Basis Universal encoded.
+)
+
+func (f TextureDataFormat) String() string {
+	switch f {
+	case TextureImage:
+		return "Image"
+	case TexturePNG:
+		return "PNG"
+	case TextureWebP:
+		return "WebP"
+	case TextureBasisUniversal:
+		return "BasisUniversal"
+	}
+	return fmt.Sprintf("TextureDataFormat(%d)", uint32(f))
+}
+
+// Texture is the header of a compressed texture (.ctex).
+type Texture struct {
+	Version     uint32
+	Width       uint32 // size of the texture, as seen by the engine.
+	Height      uint32
+	Flags       uint32 // stream, mipmap and detection flags.
+	MipmapLimit int32
+	DataFormat  TextureDataFormat
+	ImageWidth  uint16 // size of the stored image, which may differ when the texture is resized.
+	ImageHeight uint16
+	Mipmaps     uint32 // number of mipmaps stored after the base image.
+	ImageFormat uint32 // Image.Format of the stored image.
+}
+
+// ParseTexture parses the header of a compressed texture (.ctex).
+func ParseTexture(data []byte) (*Texture, error) {
+	if len(data) < 4 || string(data[:4]) != MagicTexture {
+		return nil, fmt.Errorf("res: not a compressed texture")
+	}
+	var header struct {
+		Version     uint32
+		Width       uint32
+		Height      uint32
+		Flags       uint32
+		MipmapLimit int32
+		Reserved    [3]uint32
+		DataFormat  TextureDataFormat
+		ImageWidth  uint16
+		ImageHeight uint16
+		Mipmaps     uint32
+		ImageFormat uint32
+	}
+	if err := binary.Read(bytes.NewReader(data[4:]), binary.LittleEndian, &header); err != nil {
+		return nil, fmt.Errorf("res: invalid compressed texture: %w", err)
+	}
+	if header.Version > TextureFormatVersion {
+		return nil, fmt.Errorf("res: unsupported compressed texture version %d", header.Version)
+	}
+	return &Texture{
+		Version:     header.Version,
+		Width:       header.Width,
+		Height:      header.Height,
+		Flags:       header.Flags,
+		MipmapLimit: header.MipmapLimit,
+		DataFormat:  header.DataFormat,
+		ImageWidth:  header.ImageWidth,
+		ImageHeight: header.ImageHeight,
+		Mipmaps:     header.Mipmaps,
+		ImageFormat: header.ImageFormat,
+	}, nil
+}
@@ -0,0 +1,338 @@
+package res
+
+import (
+	"strconv"
+	"strings"
+
+	"graphics.gd/format/tres"
+	"graphics.gd/variant/AABB"
+	"graphics.gd/variant/Basis"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Float"
+	"graphics.gd/variant/Packed"
+	"graphics.gd/variant/Path"
+	"graphics.gd/variant/Plane"
+	"graphics.gd/variant/Projection"
+	"graphics.gd/variant/Quaternion"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Rect2i"
+	"graphics.gd/variant/String"
+	"graphics.gd/variant/StringName"
+	"graphics.gd/variant/Transform2D"
+	"graphics.gd/variant/Transform3D"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector3i"
+	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/Vector4i"
+)
+
+// variant types, as numbered by the binary resource format (not the same as Variant.Type).
+const (
+	variantNil                = 1
+	variantBool               = 2
+	variantInt                = 3
+	variantFloat              = 4
+	variantString             = 5
+	variantVector2            = 10
+	variantRect2              = 11
+	variantVector3            = 12
+	variantPlane              = 13
+	variantQuaternion         = 14
+	variantAABB               = 15
+	variantBasis              = 16
+	variantTransform3D        = 17
+	variantTransform2D        = 18
+	variantColor              = 20
+	variantNodePath           = 22
+	variantRID                = 23
+	variantObject             = 24
+	variantInputEvent         = 25
+	variantDictionary         = 26
+	variantArray              = 30
+	variantPackedByteArray    = 31
+	variantPackedInt32Array   = 32
+	variantPackedFloat32Array = 33
+	variantPackedStringArray  = 34
+	variantPackedVector3Array = 35
+	variantPackedColorArray   = 36
+	variantPackedVector2Array = 37
+	variantInt64              = 40
+	variantDouble             = 41
+	variantCallable           = 42
+	variantSignal             = 43
+	variantStringName         = 44
+	variantVector2i           = 45
+	variantRect2i             = 46
+	variantVector3i           = 47
+	variantPackedInt64Array   = 48
+	variantPackedFloat64Array = 49
+	variantVector4            = 50
+	variantVector4i           = 51
+	variantProjection         = 52
+	variantPackedVector4Array = 53
+)
+
+// object kinds, for variantObject.
+const (
+	objectEmpty                 = 0
+	objectExternalResource      = 1
+	objectInternalResource      = 2
+	objectExternalResourceIndex = 3
+)
+
+// maxDepth of nested arrays and dictionaries.
+const maxDepth = 512
+
+// formatNoNodePathProperty is the first format version where node paths store their property
+// as a subname.
+const formatNoNodePathProperty = 3
+
+func (r *reader) reals(n int) []Float.X {
+	values := make([]Float.X, n)
+	for i := range values {
+		values[i] = Float.X(r.real())
+	}
+	return values
+}
+
+func (r *reader) int32s(n int) []int32 {
+	values := make([]int32, n)
+	for i := range values {
+		values[i] = int32(r.u32())
+	}
+	return values
+}
+
+func (r *reader) vector2() Vector2.XY {
+	v := r.reals(2)
+	return Vector2.XY{X: v[0], Y: v[1]}
+}
+
+func (r *reader) vector3() Vector3.XYZ {
+	v := r.reals(3)
+	return Vector3.XYZ{X: v[0], Y: v[1], Z: v[2]}
+}
+
+func (r *reader) vector4() Vector4.XYZW {
+	v := r.reals(4)
+	return Vector4.XYZW{X: v[0], Y: v[1], Z: v[2], W: v[3]}
+}
+
+func (r *reader) color() Color.RGBA {
+	return Color.RGBA{R: Float.X(r.f32()), G: Float.X(r.f32()), B: Float.X(r.f32()), A: Float.X(r.f32())}
+}
+
+func (r *reader) basis() Basis.XYZ {
+	return Basis.XYZ{X: r.vector3(), Y: r.vector3(), Z: r.vector3()}
+}
+
+// variant reads a value of any type.
+func (r *reader) variant(depth int) any {
+	if depth > maxDepth {
+		r.errorf("values nested too deeply")
+		return nil
+	}
+	if r.err != nil {
+		return nil
+	}
+	switch vtype := r.u32(); vtype {
+	case variantNil, variantInputEvent, variantCallable, variantSignal:
+		return nil
+	case variantBool:
+		return r.u32() != 0
+	case variantInt:
+		return int(int32(r.u32()))
+	case variantInt64:
+		return int(int64(r.u64()))
+	case variantFloat:
+		return r.real()
+	case variantDouble:
+		return r.f64()
+	case variantString:
+		return r.string()
+	case variantStringName:
+		return StringName.New(r.string())
+	case variantVector2:
+		return r.vector2()
+	case variantVector2i:
+		v := r.int32s(2)
+		return Vector2i.XY{X: v[0], Y: v[1]}
+	case variantRect2:
+		return Rect2.PositionSize{Position: r.vector2(), Size: r.vector2()}
+	case variantRect2i:
+		v := r.int32s(4)
+		return Rect2i.PositionSize{Position: Vector2i.XY{X: v[0], Y: v[1]}, Size: Vector2i.XY{X: v[2], Y: v[3]}}
+	case variantVector3:
+		return r.vector3()
+	case variantVector3i:
+		v := r.int32s(3)
+		return Vector3i.XYZ{X: v[0], Y: v[1], Z: v[2]}
+	case variantVector4:
+		return r.vector4()
+	case variantVector4i:
+		v := r.int32s(4)
+		return Vector4i.XYZW{X: v[0], Y: v[1], Z: v[2], W: v[3]}
+	case variantPlane:
+		return Plane.NormalD{Normal: r.vector3(), D: Float.X(r.real())}
+	case variantQuaternion:
+		v := r.reals(4)
+		return Quaternion.IJKX{I: v[0], J: v[1], K: v[2], X: v[3]}
+	case variantAABB:
+		return AABB.PositionSize{Position: r.vector3(), Size: r.vector3()}
+	case variantBasis:
+		return r.basis()
+	case variantTransform3D:
+		return Transform3D.BasisOrigin{Basis: r.basis(), Origin: r.vector3()}
+	case variantTransform2D:
+		return Transform2D.OriginXY{X: r.vector2(), Y: r.vector2(), Origin: r.vector2()}
+	case variantProjection:
+		return Projection.XYZW{X: r.vector4(), Y: r.vector4(), Z: r.vector4(), W: r.vector4()}
+	case variantColor:
+		return r.color()
+	case variantNodePath:
+		return r.nodePath()
+	case variantRID:
+		return RID.Any(r.u32())
+	case variantObject:
+		return r.object()
+	case variantDictionary:
+		n := r.count() & 0x7FFFFFFF // the high bit is the legacy 'shared' flag.
+		dictionary := make(tres.Dictionary, 0, n)
+		for range n {
+			key := r.variant(depth + 1)
+			value := r.variant(depth + 1)
+			dictionary = append(dictionary, tres.Entry{Key: key, Value: value})
+		}
+		return dictionary
+	case variantArray:
+		n := r.count() & 0x7FFFFFFF
+		array := make([]any, n)
+		for i := range array {
+			array[i] = r.variant(depth + 1)
+		}
+		return array
+	case variantPackedByteArray:
+		n := r.count()
+		data := r.bytes(n)
+		r.padding(n)
+		return Packed.Bytes(Packed.New(data...))
+	case variantPackedInt32Array:
+		return Packed.New(r.int32s(r.count())...)
+	case variantPackedInt64Array:
+		values := make([]int64, r.count())
+		for i := range values {
+			values[i] = int64(r.u64())
+		}
+		return Packed.New(values...)
+	case variantPackedFloat32Array:
+		values := make([]float32, r.count())
+		for i := range values {
+			values[i] = r.f32()
+		}
+		return Packed.New(values...)
+	case variantPackedFloat64Array:
+		values := make([]float64, r.count())
+		for i := range values {
+			values[i] = r.f64()
+		}
+		return Packed.New(values...)
+	case variantPackedStringArray:
+		var array Packed.Strings
+		for range r.count() {
+			array.Append(String.New(r.string()))
+		}
+		return array
+	case variantPackedVector2Array:
+		values := make([]Vector2.XY, r.count())
+		for i := range values {
+			values[i] = r.vector2()
+		}
+		return Packed.New(values...)
+	case variantPackedVector3Array:
+		values := make([]Vector3.XYZ, r.count())
+		for i := range values {
+			values[i] = r.vector3()
+		}
+		return Packed.New(values...)
+	case variantPackedVector4Array:
+		values := make([]Vector4.XYZW, r.count())
+		for i := range values {
+			values[i] = r.vector4()
+		}
+		return Packed.New(values...)
+	case variantPackedColorArray:
+		values := make([]Color.RGBA, r.count())
+		for i := range values {
+			values[i] = r.color()
+		}
+		return Packed.New(values...)
+	default:
+		r.errorf("unsupported variant type %d", vtype)
+		return nil
+	}
+}
+
+func (r *reader) nodePath() Path.ToNode {
+	names := int(r.u16())
+	subnames := r.u16()
+	absolute := subnames&0x8000 != 0
+	subnames &= 0x7FFF
+	if r.file.Format < formatNoNodePathProperty {
+		subnames++
+	}
+	var path strings.Builder
+	if absolute {
+		path.WriteByte('/')
+	}
+	for i := range names {
+		if i > 0 {
+			path.WriteByte('/')
+		}
+		path.WriteString(r.name())
+	}
+	for range subnames {
+		path.WriteByte(':')
+		path.WriteString(r.name())
+	}
+	return Path.ToNode(String.New(path.String()))
+}
+
+func (r *reader) object() any {
+	switch kind := r.u32(); kind {
+	case objectEmpty:
+		return nil
+	case objectExternalResource:
+		return ExtResource{Type: r.string(), Path: r.string(), UID: -1}
+	case objectExternalResourceIndex:
+		index := r.u32()
+		if int(index) >= len(r.file.External) {
+			r.errorf("invalid external resource %d", index)
+			return nil
+		}
+		return External(index)
+	case objectInternalResource:
+		index := r.u32()
+		if r.named {
+			if int(index) >= len(r.file.Internal) {
+				r.errorf("invalid internal resource %d", index)
+				return nil
+			}
+			return Internal(index)
+		}
+		// older files refer to internal resources by their local://N path.
+		path := "local://" + strconv.Itoa(int(index))
+		for i, resource := range r.file.Internal {
+			if resource.Path == path {
+				return Internal(i)
+			}
+		}
+		r.errorf("invalid internal resource %s", path)
+		return nil
+	default:
+		r.errorf("unsupported object kind %d", kind)
+		return nil
+	}
+}
@@ -0,0 +1,914 @@
+package tres
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"graphics.gd/variant/AABB"
+	"graphics.gd/variant/Basis"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Float"
+	"graphics.gd/variant/Packed"
+	"graphics.gd/variant/Path"
+	"graphics.gd/variant/Plane"
+	"graphics.gd/variant/Projection"
+	"graphics.gd/variant/Quaternion"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Rect2i"
+	"graphics.gd/variant/String"
+	"graphics.gd/variant/StringName"
+	"graphics.gd/variant/Transform2D"
+	"graphics.gd/variant/Transform3D"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector3i"
+	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/Vector4i"
+)
+
+// SyntaxError describes a problem with the text being parsed.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string { return fmt.Sprintf("tres: line %d: %s", e.Line, e.Msg) }
+
+// Parse a text resource or scene.
+func Parse(data []byte) (*File, error) {
+	p := &parser{src: string(data), line: 1}
+	var file File
+	for {
+		p.space()
+		if p.eof() {
+			return &file, nil
+		}
+		section, err := p.section()
+		if err != nil {
+			return nil, err
+		}
+		file.Sections = append(file.Sections, section)
+	}
+}
+
+// ParseValue parses a single value, such as "Vector2(1, 2)".
+func ParseValue(text string) (any, error) {
+	p := &parser{src: text, line: 1}
+	value, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	p.space()
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q after value", p.rest())
+	}
+	return value, nil
+}
+
+// CutValue parses the value at the start of text and returns it along with the text that
+// follows it, so that values can be read from other formats with the same syntax.
+func CutValue(text string) (value any, rest string, err error) {
+	p := &parser{src: text, line: 1}
+	value, err = p.value()
+	if err != nil {
+		return nil, text, err
+	}
+	return value, text[p.pos:], nil
+}
+
+type parser struct {
+	src  string
+	pos  int
+	line int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &SyntaxError{Line: p.line, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) rest() string {
+	rest := p.src[p.pos:]
+	if i := strings.IndexByte(rest, '\n'); i >= 0 {
+		rest = rest[:i]
+	}
+	return rest
+}
+
+// space skips whitespace and ; comments.
+func (p *parser) space() {
+	for !p.eof() {
+		switch c := p.src[p.pos]; {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == ';':
+			for !p.eof() && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) expect(c byte) error {
+	p.space()
+	if p.peek() != c {
+		if p.eof() {
+			return p.errorf("expected %q, found end of file", c)
+		}
+		return p.errorf("expected %q, found %q", c, p.rest())
+	}
+	p.pos++
+	return nil
+}
+
+// accept consumes c if it is next.
+func (p *parser) accept(c byte) bool {
+	p.space()
+	if p.peek() == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func isIdent(c byte, first bool) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || !first && c >= '0' && c <= '9'
+}
+
+func (p *parser) ident() string {
+	p.space()
+	start := p.pos
+	for !p.eof() && isIdent(p.src[p.pos], p.pos == start) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) section() (Section, error) {
+	if err := p.expect('['); err != nil {
+		return Section{}, err
+	}
+	section := Section{Tag: p.ident()}
+	if section.Tag == "" {
+		return section, p.errorf("expected section tag, found %q", p.rest())
+	}
+	for !p.accept(']') {
+		if p.eof() {
+			return section, p.errorf("unterminated [%s] section", section.Tag)
+		}
+		name := p.ident()
+		if name == "" {
+			return section, p.errorf("expected attribute name, found %q", p.rest())
+		}
+		if err := p.expect('='); err != nil {
+			return section, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return section, err
+		}
+		section.Attributes = append(section.Attributes, Property{Name: name, Value: value})
+	}
+	for {
+		p.space()
+		if p.eof() || p.peek() == '[' {
+			return section, nil
+		}
+		name, err := p.key()
+		if err != nil {
+			return section, err
+		}
+		if err := p.expect('='); err != nil {
+			return section, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return section, err
+		}
+		section.Properties = append(section.Properties, Property{Name: name, Value: value})
+	}
+}
+
+// key returns a property name, which is either quoted or runs up to the '='.
+func (p *parser) key() (string, error) {
+	if p.peek() == '"' {
+		return p.string()
+	}
+	start := p.pos
+	for !p.eof() {
+		c := p.src[p.pos]
+		if c == '=' || c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			break
+		}
+		p.pos++
+	}
+	if start == p.pos {
+		return "", p.errorf("expected property name, found %q", p.rest())
+	}
+	return p.src[start:p.pos], nil
+}
+
+func (p *parser) string() (string, error) {
+	if err := p.expect('"'); err != nil {
+		return "", err
+	}
+	var s strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+		c := p.src[p.pos]
+		p.pos++
+		switch c {
+		case '"':
+			return s.String(), nil
+		case '\n':
+			p.line++
+			s.WriteByte(c)
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated string")
+			}
+			e := p.src[p.pos]
+			p.pos++
+			switch e {
+			case 'b':
+				s.WriteByte('\b')
+			case 't':
+				s.WriteByte('\t')
+			case 'n':
+				s.WriteByte('\n')
+			case 'f':
+				s.WriteByte('\f')
+			case 'r':
+				s.WriteByte('\r')
+			case 'u', 'U':
+				n := 4
+				if e == 'U' {
+					n = 6
+				}
+				if p.pos+n > len(p.src) {
+					return "", p.errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid unicode escape")
+				}
+				p.pos += n
+				s.WriteRune(rune(r))
+			default:
+				s.WriteByte(e)
+			}
+		default:
+			s.WriteByte(c)
+		}
+	}
+}
+
+func (p *parser) number() (any, error) {
+	p.space()
+	start := p.pos
+	for !p.eof() {
+		c := p.src[p.pos]
+		if c == '-' || c == '+' || c == '.' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	text := p.src[start:p.pos]
+	switch text {
+	case "inf":
+		return math.Inf(1), nil
+	case "inf_neg":
+		return math.Inf(-1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+	isHex := strings.HasPrefix(strings.TrimLeft(text, "+-"), "0x")
+	if !isHex && strings.ContainsAny(text, ".eE") {
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", text)
+		}
+		return f, nil
+	}
+	base := 10
+	if isHex {
+		base = 0
+	}
+	i, err := strconv.ParseInt(text, base, 64)
+	if err != nil {
+		return nil, p.errorf("invalid number %q", text)
+	}
+	return int(i), nil
+}
+
+// args parses the comma separated values within parenthesis.
+func (p *parser) args() ([]any, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+	var args []any
+	for !p.accept(')') {
+		if len(args) > 0 {
+			if err := p.expect(','); err != nil {
+				return nil, err
+			}
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+	return args, nil
+}
+
+// floats parses the arguments of a constructor that expects n floats (or any number of floats if
+// n is negative).
+func (p *parser) floats(name string, n int) ([]Float.X, error) {
+	args, err := p.args()
+	if err != nil {
+		return nil, err
+	}
+	if n >= 0 && len(args) != n {
+		return nil, p.errorf("%s expects %d arguments, found %d", name, n, len(args))
+	}
+	floats := make([]Float.X, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case int:
+			floats[i] = Float.X(arg)
+		case float64:
+			floats[i] = Float.X(arg)
+		default:
+			return nil, p.errorf("%s expects numbers", name)
+		}
+	}
+	return floats, nil
+}
+
+func (p *parser) ints(name string, n int) ([]int, error) {
+	args, err := p.args()
+	if err != nil {
+		return nil, err
+	}
+	if n >= 0 && len(args) != n {
+		return nil, p.errorf("%s expects %d arguments, found %d", name, n, len(args))
+	}
+	ints := make([]int, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case int:
+			ints[i] = arg
+		case float64:
+			ints[i] = int(arg)
+		default:
+			return nil, p.errorf("%s expects integers", name)
+		}
+	}
+	return ints, nil
+}
+
+// stringArg parses a constructor with a single string argument.
+func (p *parser) stringArg(name string) (string, error) {
+	args, err := p.args()
+	if err != nil {
+		return "", err
+	}
+	if len(args) == 0 {
+		return "", nil
+	}
+	s, ok := args[0].(string)
+	if len(args) != 1 || !ok {
+		return "", p.errorf("%s expects a string", name)
+	}
+	return s, nil
+}
+
+func (p *parser) array() ([]any, error) {
+	if err := p.expect('['); err != nil {
+		return nil, err
+	}
+	elements := []any{}
+	for !p.accept(']') {
+		if len(elements) > 0 {
+			if err := p.expect(','); err != nil {
+				return nil, err
+			}
+			if p.accept(']') {
+				break // trailing comma.
+			}
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, value)
+	}
+	return elements, nil
+}
+
+func (p *parser) dictionary() (Dictionary, error) {
+	if err := p.expect('{'); err != nil {
+		return nil, err
+	}
+	entries := Dictionary{}
+	for !p.accept('}') {
+		if len(entries) > 0 {
+			if err := p.expect(','); err != nil {
+				return nil, err
+			}
+			if p.accept('}') {
+				break // trailing comma.
+			}
+		}
+		key, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, Entry{Key: key, Value: value})
+	}
+	return entries, nil
+}
+
+// containerType parses the type within the brackets of Array[T] or Dictionary[K, V].
+func (p *parser) containerType() (any, error) {
+	p.space()
+	if p.peek() == '"' {
+		return p.string()
+	}
+	start := p.pos
+	name := p.ident()
+	if name == "" {
+		return nil, p.errorf("expected type, found %q", p.rest())
+	}
+	if name == "ExtResource" || name == "SubResource" || name == "Resource" {
+		p.pos = start
+		return p.value()
+	}
+	return name, nil
+}
+
+// raw skips over the arguments of an unknown constructor, returning its full text.
+func (p *parser) raw(start int) (Raw, error) {
+	if _, err := p.args(); err != nil {
+		return "", err
+	}
+	return Raw(p.src[start:p.pos]), nil
+}
+
+func (p *parser) value() (any, error) {
+	p.space()
+	if p.eof() {
+		return nil, p.errorf("expected value, found end of file")
+	}
+	start := p.pos
+	switch c := p.peek(); {
+	case c == '"':
+		return p.string()
+	case c == '&':
+		p.pos++
+		s, err := p.string()
+		return StringName.New(s), err
+	case c == '^':
+		p.pos++
+		s, err := p.string()
+		return Path.ToNode(String.New(s)), err
+	case c == '[':
+		return p.array()
+	case c == '{':
+		return p.dictionary()
+	case c == '-' || c == '+' || c == '.' || c >= '0' && c <= '9':
+		return p.number()
+	case !isIdent(c, true):
+		return nil, p.errorf("unexpected %q", p.rest())
+	}
+	name := p.ident()
+	switch name {
+	case "null", "nil":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "inf_neg", "nan":
+		p.pos = start
+		return p.number()
+	case "Vector2":
+		f, err := p.floats(name, 2)
+		if err != nil {
+			return nil, err
+		}
+		return Vector2.XY{f[0], f[1]}, nil
+	case "Vector2i":
+		i, err := p.ints(name, 2)
+		if err != nil {
+			return nil, err
+		}
+		return Vector2i.XY{int32(i[0]), int32(i[1])}, nil
+	case "Rect2":
+		f, err := p.floats(name, 4)
+		if err != nil {
+			return nil, err
+		}
+		return Rect2.PositionSize{Position: Vector2.XY{f[0], f[1]}, Size: Vector2.XY{f[2], f[3]}}, nil
+	case "Rect2i":
+		i, err := p.ints(name, 4)
+		if err != nil {
+			return nil, err
+		}
+		return Rect2i.PositionSize{Position: Vector2i.XY{int32(i[0]), int32(i[1])}, Size: Vector2i.XY{int32(i[2]), int32(i[3])}}, nil
+	case "Vector3":
+		f, err := p.floats(name, 3)
+		if err != nil {
+			return nil, err
+		}
+		return Vector3.XYZ{f[0], f[1], f[2]}, nil
+	case "Vector3i":
+		i, err := p.ints(name, 3)
+		if err != nil {
+			return nil, err
+		}
+		return Vector3i.XYZ{int32(i[0]), int32(i[1]), int32(i[2])}, nil
+	case "Vector4":
+		f, err := p.floats(name, 4)
+		if err != nil {
+			return nil, err
+		}
+		return Vector4.XYZW{f[0], f[1], f[2], f[3]}, nil
+	case "Vector4i":
+		i, err := p.ints(name, 4)
+		if err != nil {
+			return nil, err
+		}
+		return Vector4i.XYZW{int32(i[0]), int32(i[1]), int32(i[2]), int32(i[3])}, nil
+	case "Transform2D", "Matrix32":
+		f, err := p.floats(name, 6)
+		if err != nil {
+			return nil, err
+		}
+		return Transform2D.OriginXY{X: Vector2.XY{f[0], f[1]}, Y: Vector2.XY{f[2], f[3]}, Origin: Vector2.XY{f[4], f[5]}}, nil
+	case "Plane":
+		f, err := p.floats(name, 4)
+		if err != nil {
+			return nil, err
+		}
+		return Plane.NormalD{Normal: Vector3.XYZ{f[0], f[1], f[2]}, D: f[3]}, nil
+	case "Quaternion", "Quat":
+		f, err := p.floats(name, 4)
+		if err != nil {
+			return nil, err
+		}
+		return Quaternion.IJKX{I: f[0], J: f[1], K: f[2], X: f[3]}, nil
+	case "AABB", "Rect3":
+		f, err := p.floats(name, 6)
+		if err != nil {
+			return nil, err
+		}
+		return AABB.PositionSize{Position: Vector3.XYZ{f[0], f[1], f[2]}, Size: Vector3.XYZ{f[3], f[4], f[5]}}, nil
+	case "Basis", "Matrix3":
+		f, err := p.floats(name, 9)
+		if err != nil {
+			return nil, err
+		}
+		return Basis.XYZ{X: Vector3.XYZ{f[0], f[1], f[2]}, Y: Vector3.XYZ{f[3], f[4], f[5]}, Z: Vector3.XYZ{f[6], f[7], f[8]}}, nil
+	case "Transform3D", "Transform":
+		f, err := p.floats(name, 12)
+		if err != nil {
+			return nil, err
+		}
+		return Transform3D.BasisOrigin{
+			Basis:  Basis.XYZ{X: Vector3.XYZ{f[0], f[1], f[2]}, Y: Vector3.XYZ{f[3], f[4], f[5]}, Z: Vector3.XYZ{f[6], f[7], f[8]}},
+			Origin: Vector3.XYZ{f[9], f[10], f[11]},
+		}, nil
+	case "Projection":
+		f, err := p.floats(name, 16)
+		if err != nil {
+			return nil, err
+		}
+		return Projection.XYZW{
+			X: Vector4.XYZW{f[0], f[1], f[2], f[3]},
+			Y: Vector4.XYZW{f[4], f[5], f[6], f[7]},
+			Z: Vector4.XYZW{f[8], f[9], f[10], f[11]},
+			W: Vector4.XYZW{f[12], f[13], f[14], f[15]},
+		}, nil
+	case "Color":
+		f, err := p.floats(name, -1)
+		if err != nil {
+			return nil, err
+		}
+		switch len(f) {
+		case 3:
+			return Color.RGBA{R: f[0], G: f[1], B: f[2], A: 1}, nil
+		case 4:
+			return Color.RGBA{R: f[0], G: f[1], B: f[2], A: f[3]}, nil
+		}
+		return nil, p.errorf("Color expects 3 or 4 arguments, found %d", len(f))
+	case "RID":
+		i, err := p.ints(name, -1)
+		if err != nil {
+			return nil, err
+		}
+		if len(i) == 0 {
+			return RID.Any(0), nil
+		}
+		return RID.Any(i[0]), nil
+	case "NodePath":
+		s, err := p.stringArg(name)
+		return Path.ToNode(String.New(s)), err
+	case "StringName":
+		s, err := p.stringArg(name)
+		return StringName.New(s), err
+	case "ExtResource":
+		args, err := p.args()
+		if err != nil {
+			return nil, err
+		}
+		if len(args) != 1 {
+			return nil, p.errorf("ExtResource expects an id")
+		}
+		switch id := args[0].(type) {
+		case string:
+			return ExtResource(id), nil
+		case int:
+			return ExtResource(strconv.Itoa(id)), nil // format=2
+		}
+		return nil, p.errorf("ExtResource expects an id")
+	case "SubResource":
+		args, err := p.args()
+		if err != nil {
+			return nil, err
+		}
+		if len(args) != 1 {
+			return nil, p.errorf("SubResource expects an id")
+		}
+		switch id := args[0].(type) {
+		case string:
+			return SubResource(id), nil
+		case int:
+			return SubResource(strconv.Itoa(id)), nil // format=2
+		}
+		return nil, p.errorf("SubResource expects an id")
+	case "Resource":
+		s, err := p.stringArg(name)
+		return Resource(s), err
+	case "Object":
+		return p.object()
+	case "Array":
+		if !p.accept('[') {
+			p.pos = start
+			return p.raw(start)
+		}
+		typ, err := p.containerType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(']'); err != nil {
+			return nil, err
+		}
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		elements, err := p.array()
+		if err != nil {
+			return nil, err
+		}
+		return TypedArray{Type: typ, Elements: elements}, p.expect(')')
+	case "Dictionary":
+		if !p.accept('[') {
+			p.pos = start
+			return p.raw(start)
+		}
+		key, err := p.containerType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		value, err := p.containerType()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(']'); err != nil {
+			return nil, err
+		}
+		if err := p.expect('('); err != nil {
+			return nil, err
+		}
+		entries, err := p.dictionary()
+		if err != nil {
+			return nil, err
+		}
+		return TypedDictionary{Key: key, Value: value, Entries: entries}, p.expect(')')
+	case "PackedByteArray", "PoolByteArray", "ByteArray":
+		args, err := p.args()
+		if err != nil {
+			return nil, err
+		}
+		if len(args) == 1 {
+			if encoded, ok := args[0].(string); ok {
+				data, err := base64.StdEncoding.DecodeString(encoded)
+				if err != nil {
+					return nil, p.errorf("invalid base64 in PackedByteArray")
+				}
+				return Packed.Bytes(Packed.New(data...)), nil
+			}
+		}
+		var bytes Packed.Bytes
+		for _, arg := range args {
+			i, ok := arg.(int)
+			if !ok {
+				return nil, p.errorf("PackedByteArray expects integers")
+			}
+			bytes.Append(byte(i))
+		}
+		return bytes, nil
+	case "PackedInt32Array", "PoolIntArray", "IntArray":
+		i, err := p.ints(name, -1)
+		if err != nil {
+			return nil, err
+		}
+		array := Packed.New[int32]()
+		for _, v := range i {
+			array.Append(int32(v))
+		}
+		return array, nil
+	case "PackedInt64Array":
+		i, err := p.ints(name, -1)
+		if err != nil {
+			return nil, err
+		}
+		array := Packed.New[int64]()
+		for _, v := range i {
+			array.Append(int64(v))
+		}
+		return array, nil
+	case "PackedFloat32Array", "PoolRealArray", "FloatArray":
+		args, err := p.args()
+		if err != nil {
+			return nil, err
+		}
+		array := Packed.New[float32]()
+		for _, arg := range args {
+			f, ok := toFloat(arg)
+			if !ok {
+				return nil, p.errorf("%s expects numbers", name)
+			}
+			array.Append(float32(f))
+		}
+		return array, nil
+	case "PackedFloat64Array":
+		args, err := p.args()
+		if err != nil {
+			return nil, err
+		}
+		array := Packed.New[float64]()
+		for _, arg := range args {
+			f, ok := toFloat(arg)
+			if !ok {
+				return nil, p.errorf("%s expects numbers", name)
+			}
+			array.Append(f)
+		}
+		return array, nil
+	case "PackedStringArray", "PoolStringArray", "StringArray":
+		args, err := p.args()
+		if err != nil {
+			return nil, err
+		}
+		var array Packed.Strings
+		for _, arg := range args {
+			s, ok := arg.(string)
+			if !ok {
+				return nil, p.errorf("%s expects strings", name)
+			}
+			array.Append(String.New(s))
+		}
+		return array, nil
+	case "PackedVector2Array", "PoolVector2Array", "Vector2Array":
+		f, err := p.floats(name, -1)
+		if err != nil {
+			return nil, err
+		}
+		if len(f)%2 != 0 {
+			return nil, p.errorf("%s expects pairs of numbers", name)
+		}
+		array := Packed.New[Vector2.XY]()
+		for i := 0; i < len(f); i += 2 {
+			array.Append(Vector2.XY{f[i], f[i+1]})
+		}
+		return array, nil
+	case "PackedVector3Array", "PoolVector3Array", "Vector3Array":
+		f, err := p.floats(name, -1)
+		if err != nil {
+			return nil, err
+		}
+		if len(f)%3 != 0 {
+			return nil, p.errorf("%s expects triples of numbers", name)
+		}
+		array := Packed.New[Vector3.XYZ]()
+		for i := 0; i < len(f); i += 3 {
+			array.Append(Vector3.XYZ{f[i], f[i+1], f[i+2]})
+		}
+		return array, nil
+	case "PackedVector4Array":
+		f, err := p.floats(name, -1)
+		if err != nil {
+			return nil, err
+		}
+		if len(f)%4 != 0 {
+			return nil, p.errorf("%s expects groups of four numbers", name)
+		}
+		array := Packed.New[Vector4.XYZW]()
+		for i := 0; i < len(f); i += 4 {
+			array.Append(Vector4.XYZW{f[i], f[i+1], f[i+2], f[i+3]})
+		}
+		return array, nil
+	case "PackedColorArray", "PoolColorArray", "ColorArray":
+		f, err := p.floats(name, -1)
+		if err != nil {
+			return nil, err
+		}
+		if len(f)%4 != 0 {
+			return nil, p.errorf("%s expects groups of four numbers", name)
+		}
+		array := Packed.New[Color.RGBA]()
+		for i := 0; i < len(f); i += 4 {
+			array.Append(Color.RGBA{R: f[i], G: f[i+1], B: f[i+2], A: f[i+3]})
+		}
+		return array, nil
+	}
+	p.space()
+	if p.peek() != '(' {
+		return nil, p.errorf("unexpected identifier %q", name)
+	}
+	return p.raw(start)
+}
+
+// object parses Object(Class, "property": value, ...)
+func (p *parser) object() (Object, error) {
+	if err := p.expect('('); err != nil {
+		return Object{}, err
+	}
+	object := Object{Class: p.ident()}
+	if object.Class == "" {
+		return object, p.errorf("Object expects a class name")
+	}
+	for !p.accept(')') {
+		if err := p.expect(','); err != nil {
+			return object, err
+		}
+		p.space()
+		name, err := p.string()
+		if err != nil {
+			return object, err
+		}
+		if err := p.expect(':'); err != nil {
+			return object, err
+		}
+		value, err := p.value()
+		if err != nil {
+			return object, err
+		}
+		object.Properties = append(object.Properties, Property{Name: name, Value: value})
+	}
+	return object, nil
+}
+
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
@@ -0,0 +1,244 @@
+// Package tres reads and writes Godot's text resource format, as used by .tscn scenes and
+// .tres resources, without the engine.
+//
+// A [File] is a sequence of sections, starting with a [gd_scene] or [gd_resource] header, followed
+// by [ext_resource], [sub_resource], [node], [connection] and [resource] sections, each section
+// has attributes (within the brackets) and properties (the key = value lines that follow). All
+// sections, attributes and properties are kept in order, so that a file can be parsed, edited
+// and written back out with the same layout as the editor.
+//
+// Values are decoded into the corresponding variant types:
+//
+//	null                      nil
+//	true, false               bool
+//	1                         int
+//	1.0, inf, nan             float64
+//	"text"                    string
+//	&"name"                   [String.Name]
+//	NodePath("a/b")           [Path.ToNode]
+//	Vector2(1, 2)             [Vector2.XY] (and so on, for each builtin type)
+//	[1, 2]                    []any
+//	Array[int]([1, 2])        [TypedArray]
+//	{"key": "value"}          [Dictionary]
+//	PackedInt32Array(1, 2)    [Packed.Array] (of int32, and so on for each packed type)
+//	PackedByteArray(1, 2)     [Packed.Bytes]
+//	PackedStringArray("a")    [Packed.Strings]
+//	ExtResource("1_abc")      [ExtResource]
+//	SubResource("Shape_xyz")  [SubResource]
+//	Resource("res://a.tres")  [Resource]
+//	Object(Class, "k": v)     [Object]
+//
+// Any other constructors are preserved as [Raw] values.
+package tres
+
+import (
+	"iter"
+	"strconv"
+)
+
+// File is a text resource or scene.
+type File struct {
+	Sections []Section
+}
+
+// Section of a file, such as [node name="Root" type="Node2D"].
+type Section struct {
+	Tag        string     // ie. gd_scene, ext_resource, sub_resource, node, connection or resource.
+	Attributes []Property // within the section header.
+	Properties []Property // the key = value lines following the header.
+}
+
+// Property is a named value.
+type Property struct {
+	Name  string
+	Value any
+}
+
+// ExtResource refers to an [ext_resource] section by id.
+type ExtResource string
+
+// SubResource refers to a [sub_resource] section by id.
+type SubResource string
+
+// Resource refers to a resource by path.
+type Resource string
+
+// Raw is the text of a value, that this package does not decode, such as Callable().
+type Raw string
+
+// Object is an inline object value, as used for input events in project.godot.
+type Object struct {
+	Class      string
+	Properties []Property
+}
+
+// Entry in a [Dictionary].
+type Entry struct {
+	Key, Value any
+}
+
+// Dictionary value, in order.
+type Dictionary []Entry
+
+// TypedArray is an array value with a static element type.
+type TypedArray struct {
+	Type     any // builtin or class name (string) or script ([ExtResource]).
+	Elements []any
+}
+
+// TypedDictionary is a dictionary value with static key and value types.
+type TypedDictionary struct {
+	Key, Value any // builtin or class name (string) or script ([ExtResource]).
+	Entries    Dictionary
+}
+
+// Header returns the first section of the file, the [gd_scene] or [gd_resource] header.
+func (f *File) Header() *Section {
+	if len(f.Sections) == 0 {
+		return nil
+	}
+	return &f.Sections[0]
+}
+
+// All returns an iterator over the sections with the given tag.
+func (f *File) All(tag string) iter.Seq[*Section] {
+	return func(yield func(*Section) bool) {
+		for i := range f.Sections {
+			if f.Sections[i].Tag == tag && !yield(&f.Sections[i]) {
+				return
+			}
+		}
+	}
+}
+
+// ExtResource returns the [ext_resource] section with the given id.
+func (f *File) ExtResource(id ExtResource) (*Section, bool) {
+	return f.lookup("ext_resource", string(id))
+}
+
+// SubResource returns the [sub_resource] section with the given id.
+func (f *File) SubResource(id SubResource) (*Section, bool) {
+	return f.lookup("sub_resource", string(id))
+}
+
+func (f *File) lookup(tag, id string) (*Section, bool) {
+	for section := range f.All(tag) {
+		if value, _ := section.Attribute("id"); value == id {
+			return section, true
+		}
+	}
+	return nil, false
+}
+
+// Node returns the [node] section at the given path relative to the root node ("." for the
+// root node itself).
+func (f *File) Node(path string) (*Section, bool) {
+	for section := range f.All("node") {
+		name, _ := section.Attribute("name")
+		parent, hasParent := section.Attribute("parent")
+		switch {
+		case !hasParent && path == ".":
+			return section, true
+		case hasParent && parent == "." && path == name:
+			return section, true
+		case hasParent && parent != "." && path == parent+"/"+name:
+			return section, true
+		}
+	}
+	return nil, false
+}
+
+// Attribute returns the value of a string attribute in the section header.
+func (s *Section) Attribute(name string) (string, bool) {
+	for _, attr := range s.Attributes {
+		if attr.Name == name {
+			value, ok := attr.Value.(string)
+			return value, ok
+		}
+	}
+	return "", false
+}
+
+// Get returns the value of the property with the given name.
+func (s *Section) Get(name string) (any, bool) {
+	for _, prop := range s.Properties {
+		if prop.Name == name {
+			return prop.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Set the value of the property with the given name, adding it if it is missing.
+func (s *Section) Set(name string, value any) {
+	for i, prop := range s.Properties {
+		if prop.Name == name {
+			s.Properties[i].Value = value
+			return
+		}
+	}
+	s.Properties = append(s.Properties, Property{Name: name, Value: value})
+}
+
+// Delete the property with the given name.
+func (s *Section) Delete(name string) {
+	for i, prop := range s.Properties {
+		if prop.Name == name {
+			s.Properties = append(s.Properties[:i], s.Properties[i+1:]...)
+			return
+		}
+	}
+}
+
+// AddExtResource adds an [ext_resource] section for the resource at the given path and returns
+// a reference to it.
+func (f *File) AddExtResource(typ, path string) ExtResource {
+	id := f.nextID("ext_resource", "")
+	section := Section{Tag: "ext_resource", Attributes: []Property{
+		{Name: "type", Value: typ},
+		{Name: "path", Value: path},
+		{Name: "id", Value: id},
+	}}
+	at := 1
+	for i, existing := range f.Sections {
+		if existing.Tag == "ext_resource" {
+			at = i + 1
+		}
+	}
+	f.insert(min(at, len(f.Sections)), section)
+	return ExtResource(id)
+}
+
+// AddSubResource adds an empty [sub_resource] section of the given type and returns it,
+// along with a reference to it.
+func (f *File) AddSubResource(typ string) (*Section, SubResource) {
+	id := f.nextID("sub_resource", typ+"_")
+	section := Section{Tag: "sub_resource", Attributes: []Property{
+		{Name: "type", Value: typ},
+		{Name: "id", Value: id},
+	}}
+	at := min(1, len(f.Sections))
+	for i, existing := range f.Sections {
+		if existing.Tag == "ext_resource" || existing.Tag == "sub_resource" {
+			at = i + 1
+		}
+	}
+	f.insert(at, section)
+	return &f.Sections[at], SubResource(id)
+}
+
+func (f *File) insert(at int, section Section) {
+	f.Sections = append(f.Sections, Section{})
+	copy(f.Sections[at+1:], f.Sections[at:])
+	f.Sections[at] = section
+}
+
+// nextID returns an unused id with the given prefix.
+func (f *File) nextID(tag, prefix string) string {
+	for i := 1; ; i++ {
+		id := prefix + strconv.Itoa(i)
+		if _, exists := f.lookup(tag, id); !exists {
+			return id
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package tres_test
+
+import (
+	"testing"
+
+	"graphics.gd/format/tres"
+	"graphics.gd/variant/Basis"
+	"graphics.gd/variant/Packed"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector3"
+)
+
+const scene = `[gd_scene load_steps=4 format=3 uid="uid://c2bq6y0dwlxtm"]
+
+[ext_resource type="Script" path="res://player.gd" id="1_kq3xv"]
+[ext_resource type="Texture2D" uid="uid://b4xj0d1y2qdvq" path="res://icon.svg" id="2_b7m1e"]
+
+[sub_resource type="RectangleShape2D" id="RectangleShape2D_x0f2a"]
+size = Vector2(32, 48.5)
+
+[node name="Player" type="CharacterBody2D"]
+position = Vector2(100, 200)
+script = ExtResource("1_kq3xv")
+speed = 300.0
+tags = Array[String](["hero", "\"quoted\""])
+metadata/_edit_group_ = true
+
+[node name="Sprite" type="Sprite2D" parent="."]
+texture = ExtResource("2_b7m1e")
+modulate = Color(1, 0.5, 0.25, 1)
+
+[node name="Shape" type="CollisionShape2D" parent="."]
+shape = SubResource("RectangleShape2D_x0f2a")
+
+[node name="Timer" type="Timer" parent="Sprite"]
+wait_time = 0.1
+frames = PackedInt32Array(1, 2, 3)
+points = PackedVector2Array(0, 0, 1.5, -2)
+data = {
+"key": "value",
+&"name": NodePath("../Shape")
+}
+
+[connection signal="timeout" from="Sprite/Timer" to="." method="_on_timer_timeout"]
+[connection signal="ready" from="." to="." method="_on_ready"]
+`
+
+func TestRoundTrip(t *testing.T) {
+	file, err := tres.Parse([]byte(scene))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := file.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != scene {
+		t.Fatalf("round trip mismatch:\n%s", data)
+	}
+	root, ok := file.Node(".")
+	if !ok {
+		t.Fatal("missing root node")
+	}
+	if pos, _ := root.Get("position"); pos != (Vector2.XY{100, 200}) {
+		t.Fatalf("unexpected position %v", pos)
+	}
+	if speed, _ := root.Get("speed"); speed != 300.0 {
+		t.Fatalf("unexpected speed %v", speed)
+	}
+	timer, ok := file.Node("Sprite/Timer")
+	if !ok {
+		t.Fatal("missing Sprite/Timer")
+	}
+	if wait, _ := timer.Get("wait_time"); wait != 0.1 {
+		t.Fatalf("unexpected wait_time %v", wait)
+	}
+	script, _ := root.Get("script")
+	ext, ok := file.ExtResource(script.(tres.ExtResource))
+	if !ok {
+		t.Fatalf("missing %v", script)
+	}
+	if path, _ := ext.Attribute("path"); path != "res://player.gd" {
+		t.Fatalf("unexpected path %q", path)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	file, err := tres.Parse([]byte("[gd_resource type=\"Theme\" format=3]\n\n[resource]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	font := file.AddExtResource("FontFile", "res://font.ttf")
+	style, id := file.AddSubResource("StyleBoxFlat")
+	style.Set("bg_color", tres.Raw("Color(0, 0, 0, 1)"))
+	resource, _ := file.Node(".")
+	if resource != nil {
+		t.Fatal("resources have no nodes")
+	}
+	for section := range file.All("resource") {
+		section.Set("default_font", font)
+		section.Set("panel", id)
+	}
+	data, err := file.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expected = `[gd_resource type="Theme" format=3]
+
+[ext_resource type="FontFile" path="res://font.ttf" id="1"]
+
+[sub_resource type="StyleBoxFlat" id="StyleBoxFlat_1"]
+bg_color = Color(0, 0, 0, 1)
+
+[resource]
+default_font = ExtResource("1")
+panel = SubResource("StyleBoxFlat_1")
+`
+	if string(data) != expected {
+		t.Fatalf("unexpected output:\n%s", data)
+	}
+}
+
+func TestValues(t *testing.T) {
+	for _, text := range []string{
+		`null`,
+		`1.0`,
+		`-2.5e+20`,
+		`inf_neg`,
+		`"a \"b\" \\ c"`,
+		`Vector3i(1, -2, 3)`,
+		`Transform3D(1, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0)`,
+		`Object(InputEventKey,"resource_local_to_scene":false,"keycode":0,"physical_keycode":32)
+`,
+		`Dictionary[String, int]({
+"a": 1
+})`,
+		`[1, [], {}, Callable()]`,
+		`PackedStringArray("a", "b")`,
+	} {
+		value, err := tres.ParseValue(text)
+		if err != nil {
+			t.Fatalf("%s: %v", text, err)
+		}
+		formatted, err := tres.FormatValue(value)
+		if err != nil {
+			t.Fatalf("%s: %v", text, err)
+		}
+		if formatted != text {
+			t.Fatalf("expected %s, got %s", text, formatted)
+		}
+	}
+}
+
+func TestBasisRows(t *testing.T) {
+	value, err := tres.ParseValue(`Basis(1, 2, 3, 4, 5, 6, 7, 8, 9)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if basis, ok := value.(Basis.XYZ); !ok || basis.X != (Vector3.XYZ{1, 2, 3}) || basis.Z != (Vector3.XYZ{7, 8, 9}) {
+		t.Fatalf("unexpected basis %v", value)
+	}
+}
+
+func TestPackedByteArray(t *testing.T) {
+	for _, format := range []int{3, 4} {
+		file := tres.File{Sections: []tres.Section{
+			{Tag: "gd_resource", Attributes: []tres.Property{{Name: "format", Value: format}}},
+			{Tag: "resource", Properties: []tres.Property{{Name: "data", Value: Packed.Bytes(Packed.New[byte](1, 2, 3))}}},
+		}}
+		data, err := file.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := "[gd_resource format=3]\n\n[resource]\ndata = PackedByteArray(1, 2, 3)\n"
+		if format == 4 {
+			expected = "[gd_resource format=4]\n\n[resource]\ndata = PackedByteArray(\"AQID\")\n"
+		}
+		if string(data) != expected {
+			t.Fatalf("unexpected output:\n%s", data)
+		}
+		parsed, err := tres.Parse(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, _ := parsed.Sections[1].Get("data")
+		if bytes, ok := value.(Packed.Bytes); !ok || string(bytes.Bytes()) != "\x01\x02\x03" {
+			t.Fatalf("unexpected value %#v", value)
+		}
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	_, err := tres.Parse([]byte("[gd_scene format=3]\n\n[node name=\"A\"]\nposition = Vector2(1,\n"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, ok := err.(*tres.SyntaxError); !ok {
+		t.Fatalf("unexpected error type %T", err)
+	}
+}
@@ -0,0 +1,445 @@
+package tres
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"graphics.gd/variant/AABB"
+	"graphics.gd/variant/Basis"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Float"
+	"graphics.gd/variant/Packed"
+	"graphics.gd/variant/Path"
+	"graphics.gd/variant/Plane"
+	"graphics.gd/variant/Projection"
+	"graphics.gd/variant/Quaternion"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Rect2i"
+	"graphics.gd/variant/String"
+	"graphics.gd/variant/Transform2D"
+	"graphics.gd/variant/Transform3D"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector3i"
+	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/Vector4i"
+)
+
+// Bytes returns the file in the text resource format, laid out in the same way as the editor.
+func (f *File) Bytes() ([]byte, error) {
+	w := writer{base64: f.format() >= 4}
+	for i, section := range f.Sections {
+		if i > 0 && !(grouped(f.Sections[i-1]) && grouped(section) && f.Sections[i-1].Tag == section.Tag) {
+			w.buf = append(w.buf, '\n')
+		}
+		w.buf = append(w.buf, '[')
+		w.buf = append(w.buf, section.Tag...)
+		for _, attr := range section.Attributes {
+			w.buf = append(w.buf, ' ')
+			w.buf = append(w.buf, attr.Name...)
+			w.buf = append(w.buf, '=')
+			if err := w.value(attr.Value); err != nil {
+				return nil, err
+			}
+		}
+		w.buf = append(w.buf, "]\n"...)
+		for _, prop := range section.Properties {
+			w.buf = appendName(w.buf, prop.Name)
+			w.buf = append(w.buf, " = "...)
+			if err := w.value(prop.Value); err != nil {
+				return nil, fmt.Errorf("tres: %s: %w", prop.Name, err)
+			}
+			w.buf = append(w.buf, '\n')
+		}
+	}
+	return w.buf, nil
+}
+
+// WriteTo writes the file to w in the text resource format.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	data, err := f.Bytes()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// format returns the format version in the header.
+func (f *File) format() int {
+	if header := f.Header(); header != nil {
+		for _, attr := range header.Attributes {
+			if attr.Name == "format" {
+				format, _ := attr.Value.(int)
+				return format
+			}
+		}
+	}
+	return 0
+}
+
+// grouped reports whether the section is written on consecutive lines along with any other
+// sections with the same tag.
+func grouped(section Section) bool {
+	switch section.Tag {
+	case "ext_resource", "connection", "editable":
+		return len(section.Properties) == 0
+	}
+	return false
+}
+
+// FormatValue returns the text representation of a value.
+func FormatValue(value any) (string, error) {
+	var w writer
+	if err := w.value(value); err != nil {
+		return "", err
+	}
+	return string(w.buf), nil
+}
+
+type writer struct {
+	buf    []byte
+	base64 bool // write PackedByteArray as base64 (format=4).
+}
+
+// appendName appends the property name, quoting it if required.
+func appendName(buf []byte, name string) []byte {
+	if name == "" || strings.ContainsAny(name, " \t\r\n=\"[]{}:;,") {
+		return appendString(buf, name)
+	}
+	return append(buf, name...)
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\', '"':
+			buf = append(buf, '\\')
+		}
+		buf = append(buf, s[i])
+	}
+	return append(buf, '"')
+}
+
+// appendReal appends a floating point number in the same way as the engine's rtos_fix.
+func appendReal(buf []byte, f float64) []byte {
+	switch {
+	case f == 0:
+		return append(buf, '0')
+	case math.IsNaN(f):
+		return append(buf, "nan"...)
+	case math.IsInf(f, 1):
+		return append(buf, "inf"...)
+	case math.IsInf(f, -1):
+		return append(buf, "inf_neg"...)
+	}
+	return strconv.AppendFloat(buf, f, 'g', 6, 64)
+}
+
+func (w *writer) reals(name string, values ...Float.X) {
+	w.buf = append(w.buf, name...)
+	w.buf = append(w.buf, '(')
+	for i, value := range values {
+		if i > 0 {
+			w.buf = append(w.buf, ", "...)
+		}
+		w.buf = appendReal(w.buf, float64(value))
+	}
+	w.buf = append(w.buf, ')')
+}
+
+func (w *writer) ints(name string, values ...int32) {
+	w.buf = append(w.buf, name...)
+	w.buf = append(w.buf, '(')
+	for i, value := range values {
+		if i > 0 {
+			w.buf = append(w.buf, ", "...)
+		}
+		w.buf = strconv.AppendInt(w.buf, int64(value), 10)
+	}
+	w.buf = append(w.buf, ')')
+}
+
+func (w *writer) value(value any) error {
+	switch v := value.(type) {
+	case nil:
+		w.buf = append(w.buf, "null"...)
+	case bool:
+		w.buf = strconv.AppendBool(w.buf, v)
+	case int:
+		w.buf = strconv.AppendInt(w.buf, int64(v), 10)
+	case int8:
+		w.buf = strconv.AppendInt(w.buf, int64(v), 10)
+	case int16:
+		w.buf = strconv.AppendInt(w.buf, int64(v), 10)
+	case int32:
+		w.buf = strconv.AppendInt(w.buf, int64(v), 10)
+	case int64:
+		w.buf = strconv.AppendInt(w.buf, v, 10)
+	case uint8:
+		w.buf = strconv.AppendUint(w.buf, uint64(v), 10)
+	case uint16:
+		w.buf = strconv.AppendUint(w.buf, uint64(v), 10)
+	case uint32:
+		w.buf = strconv.AppendUint(w.buf, uint64(v), 10)
+	case float32:
+		w.float(float64(v))
+	case float64:
+		w.float(v)
+	case string:
+		w.buf = appendString(w.buf, v)
+	case String.Readable:
+		w.buf = appendString(w.buf, v.String())
+	case String.Name:
+		w.buf = append(w.buf, '&')
+		w.buf = appendString(w.buf, v.String())
+	case Path.ToNode:
+		w.buf = append(w.buf, "NodePath("...)
+		w.buf = appendString(w.buf, v.String())
+		w.buf = append(w.buf, ')')
+	case Vector2.XY:
+		w.reals("Vector2", v.X, v.Y)
+	case Vector2i.XY:
+		w.ints("Vector2i", v.X, v.Y)
+	case Rect2.PositionSize:
+		w.reals("Rect2", v.Position.X, v.Position.Y, v.Size.X, v.Size.Y)
+	case Rect2i.PositionSize:
+		w.ints("Rect2i", v.Position.X, v.Position.Y, v.Size.X, v.Size.Y)
+	case Vector3.XYZ:
+		w.reals("Vector3", v.X, v.Y, v.Z)
+	case Vector3i.XYZ:
+		w.ints("Vector3i", v.X, v.Y, v.Z)
+	case Vector4.XYZW:
+		w.reals("Vector4", v.X, v.Y, v.Z, v.W)
+	case Vector4i.XYZW:
+		w.ints("Vector4i", v.X, v.Y, v.Z, v.W)
+	case Transform2D.OriginXY:
+		w.reals("Transform2D", v.X.X, v.X.Y, v.Y.X, v.Y.Y, v.Origin.X, v.Origin.Y)
+	case Plane.NormalD:
+		w.reals("Plane", v.Normal.X, v.Normal.Y, v.Normal.Z, v.D)
+	case Quaternion.IJKX:
+		w.reals("Quaternion", v.I, v.J, v.K, v.X)
+	case AABB.PositionSize:
+		w.reals("AABB", v.Position.X, v.Position.Y, v.Position.Z, v.Size.X, v.Size.Y, v.Size.Z)
+	case Basis.XYZ:
+		w.reals("Basis", v.X.X, v.X.Y, v.X.Z, v.Y.X, v.Y.Y, v.Y.Z, v.Z.X, v.Z.Y, v.Z.Z)
+	case Transform3D.BasisOrigin:
+		b := v.Basis
+		w.reals("Transform3D", b.X.X, b.X.Y, b.X.Z, b.Y.X, b.Y.Y, b.Y.Z, b.Z.X, b.Z.Y, b.Z.Z, v.Origin.X, v.Origin.Y, v.Origin.Z)
+	case Projection.XYZW:
+		w.reals("Projection", v.X.X, v.X.Y, v.X.Z, v.X.W, v.Y.X, v.Y.Y, v.Y.Z, v.Y.W,
+			v.Z.X, v.Z.Y, v.Z.Z, v.Z.W, v.W.X, v.W.Y, v.W.Z, v.W.W)
+	case Color.RGBA:
+		w.reals("Color", v.R, v.G, v.B, v.A)
+	case RID.Any:
+		if v == 0 {
+			w.buf = append(w.buf, "RID()"...)
+		} else {
+			w.buf = fmt.Appendf(w.buf, "RID(%d)", uint64(v))
+		}
+	case ExtResource:
+		w.buf = append(w.buf, "ExtResource("...)
+		w.buf = appendString(w.buf, string(v))
+		w.buf = append(w.buf, ')')
+	case SubResource:
+		w.buf = append(w.buf, "SubResource("...)
+		w.buf = appendString(w.buf, string(v))
+		w.buf = append(w.buf, ')')
+	case Resource:
+		w.buf = append(w.buf, "Resource("...)
+		w.buf = appendString(w.buf, string(v))
+		w.buf = append(w.buf, ')')
+	case Raw:
+		w.buf = append(w.buf, v...)
+	case Object:
+		w.buf = append(w.buf, "Object("...)
+		w.buf = append(w.buf, v.Class...)
+		for _, prop := range v.Properties {
+			w.buf = append(w.buf, ',')
+			w.buf = appendString(w.buf, prop.Name)
+			w.buf = append(w.buf, ':')
+			if err := w.value(prop.Value); err != nil {
+				return err
+			}
+		}
+		w.buf = append(w.buf, ")\n"...)
+	case []any:
+		return w.array(v)
+	case TypedArray:
+		w.buf = append(w.buf, "Array["...)
+		if err := w.containerType(v.Type); err != nil {
+			return err
+		}
+		w.buf = append(w.buf, "]("...)
+		if err := w.array(v.Elements); err != nil {
+			return err
+		}
+		w.buf = append(w.buf, ')')
+	case Dictionary:
+		return w.dictionary(v)
+	case TypedDictionary:
+		w.buf = append(w.buf, "Dictionary["...)
+		if err := w.containerType(v.Key); err != nil {
+			return err
+		}
+		w.buf = append(w.buf, ", "...)
+		if err := w.containerType(v.Value); err != nil {
+			return err
+		}
+		w.buf = append(w.buf, "]("...)
+		if err := w.dictionary(v.Entries); err != nil {
+			return err
+		}
+		w.buf = append(w.buf, ')')
+	case Packed.Bytes:
+		w.buf = append(w.buf, "PackedByteArray("...)
+		if w.base64 {
+			if v.Len() > 0 {
+				w.buf = append(w.buf, '"')
+				w.buf = base64.StdEncoding.AppendEncode(w.buf, v.Bytes())
+				w.buf = append(w.buf, '"')
+			}
+		} else {
+			for i, b := range v.Iter() {
+				if i > 0 {
+					w.buf = append(w.buf, ", "...)
+				}
+				w.buf = strconv.AppendUint(w.buf, uint64(b), 10)
+			}
+		}
+		w.buf = append(w.buf, ')')
+	case Packed.Array[byte]:
+		return w.value(Packed.Bytes(v))
+	case Packed.Array[int32]:
+		var ints []int32
+		for _, n := range v.Iter() {
+			ints = append(ints, n)
+		}
+		w.ints("PackedInt32Array", ints...)
+	case Packed.Array[int64]:
+		w.buf = append(w.buf, "PackedInt64Array("...)
+		for i, n := range v.Iter() {
+			if i > 0 {
+				w.buf = append(w.buf, ", "...)
+			}
+			w.buf = strconv.AppendInt(w.buf, n, 10)
+		}
+		w.buf = append(w.buf, ')')
+	case Packed.Array[float32]:
+		w.buf = append(w.buf, "PackedFloat32Array("...)
+		for i, f := range v.Iter() {
+			if i > 0 {
+				w.buf = append(w.buf, ", "...)
+			}
+			w.buf = appendReal(w.buf, float64(f))
+		}
+		w.buf = append(w.buf, ')')
+	case Packed.Array[float64]:
+		w.buf = append(w.buf, "PackedFloat64Array("...)
+		for i, f := range v.Iter() {
+			if i > 0 {
+				w.buf = append(w.buf, ", "...)
+			}
+			w.buf = appendReal(w.buf, f)
+		}
+		w.buf = append(w.buf, ')')
+	case Packed.Strings:
+		w.buf = append(w.buf, "PackedStringArray("...)
+		for i, s := range v.Iter() {
+			if i > 0 {
+				w.buf = append(w.buf, ", "...)
+			}
+			w.buf = appendString(w.buf, s.String())
+		}
+		w.buf = append(w.buf, ')')
+	case Packed.Array[Vector2.XY]:
+		var reals []Float.X
+		for _, v := range v.Iter() {
+			reals = append(reals, v.X, v.Y)
+		}
+		w.reals("PackedVector2Array", reals...)
+	case Packed.Array[Vector3.XYZ]:
+		var reals []Float.X
+		for _, v := range v.Iter() {
+			reals = append(reals, v.X, v.Y, v.Z)
+		}
+		w.reals("PackedVector3Array", reals...)
+	case Packed.Array[Vector4.XYZW]:
+		var reals []Float.X
+		for _, v := range v.Iter() {
+			reals = append(reals, v.X, v.Y, v.Z, v.W)
+		}
+		w.reals("PackedVector4Array", reals...)
+	case Packed.Array[Color.RGBA]:
+		var reals []Float.X
+		for _, v := range v.Iter() {
+			reals = append(reals, v.R, v.G, v.B, v.A)
+		}
+		w.reals("PackedColorArray", reals...)
+	default:
+		return fmt.Errorf("tres: unsupported value of type %T", value)
+	}
+	return nil
+}
+
+// float appends a float value, which always includes a decimal point (unlike the components of
+// vectors).
+func (w *writer) float(f float64) {
+	start := len(w.buf)
+	w.buf = appendReal(w.buf, f)
+	if !math.IsInf(f, 0) && !math.IsNaN(f) && !bytes.ContainsAny(w.buf[start:], ".e") {
+		w.buf = append(w.buf, ".0"...)
+	}
+}
+
+func (w *writer) array(elements []any) error {
+	w.buf = append(w.buf, '[')
+	for i, element := range elements {
+		if i > 0 {
+			w.buf = append(w.buf, ", "...)
+		}
+		if err := w.value(element); err != nil {
+			return err
+		}
+	}
+	w.buf = append(w.buf, ']')
+	return nil
+}
+
+func (w *writer) dictionary(entries Dictionary) error {
+	if len(entries) == 0 {
+		w.buf = append(w.buf, "{}"...)
+		return nil
+	}
+	w.buf = append(w.buf, "{\n"...)
+	for i, entry := range entries {
+		if i > 0 {
+			w.buf = append(w.buf, ",\n"...)
+		}
+		if err := w.value(entry.Key); err != nil {
+			return err
+		}
+		w.buf = append(w.buf, ": "...)
+		if err := w.value(entry.Value); err != nil {
+			return err
+		}
+	}
+	w.buf = append(w.buf, "\n}"...)
+	return nil
+}
+
+func (w *writer) containerType(typ any) error {
+	if name, ok := typ.(string); ok {
+		w.buf = append(w.buf, name...)
+		return nil
+	}
+	return w.value(typ)
+}
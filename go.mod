@@ -6,11 +6,9 @@ require runtime.link v0.0.0-20250131052539-992a5f0be9db
 
 require (
 	github.com/tetratelabs/wazero v1.8.2
+	golang.org/x/mod v0.22.0
 	golang.org/x/text v0.15.0
 	golang.org/x/tools v0.29.1-0.20250128153832-8171d94fe98a
 )
 
-require (
-	golang.org/x/mod v0.22.0 // indirect
-	golang.org/x/sync v0.10.0 // indirect
-)
+require golang.org/x/sync v0.10.0 // indirect
@@ -3,10 +3,12 @@
 package gd
 
 import (
+	"fmt"
 	"unsafe"
 
 	"graphics.gd/internal/callframe"
 	"graphics.gd/internal/pointers"
+	ErrorType "graphics.gd/variant/Error"
 	PackedType "graphics.gd/variant/Packed"
 
 	"runtime.link/api"
@@ -234,31 +236,70 @@ const (
 	ErrMethodNotConst
 )
 
+// CallError describes why a dynamic call failed, it unwraps to the closest [Error] code, such that
+// errors.Is can match it against the [graphics.gd/variant/Error] constants.
 type CallError struct {
 	ErrorType CallErrorType
 	Argument  int32
 	Expected  int32
+
+	Method string // name of the method that was called, if known.
 }
 
 type InstanceBindingType unsafe.Pointer
 
 func (err CallError) Error() string {
+	var message string
 	switch err.ErrorType {
 	case ErrInvalidMethod:
-		return "invalid method"
+		message = "invalid method"
 	case ErrInvalidArgument:
-		return "invalid argument"
+		message = fmt.Sprintf("invalid argument %d, expected %v", err.Argument+1, VariantType(err.Expected))
 	case ErrTooManyArguments:
-		return "too many arguments"
+		message = fmt.Sprintf("too many arguments, expected %d", err.Expected)
 	case ErrTooFewArguments:
-		return "too few arguments"
+		message = fmt.Sprintf("too few arguments, expected %d", err.Expected)
 	case ErrInstanceIsNil:
-		return "instance is nil"
+		message = "instance is nil"
 	case ErrMethodNotConst:
-		return "method not const"
+		message = "method not const"
+	default:
+		message = "unknown error"
+	}
+	if err.Method != "" {
+		return err.Method + ": " + message
+	}
+	return message
+}
+
+// Unwrap returns the [Error] code that best describes the call error.
+func (err CallError) Unwrap() error {
+	switch err.ErrorType {
+	case ErrInvalidMethod:
+		return ErrorType.MethodNotFound
+	case ErrInvalidArgument, ErrTooManyArguments, ErrTooFewArguments:
+		return ErrorType.InvalidParameter
+	case ErrInstanceIsNil:
+		return ErrorType.DoesNotExist
 	default:
-		return "unknown error"
+		return ErrorType.Failed
+	}
+}
+
+// withMethod adds the name of the method to any [CallError] that does not already have one.
+func withMethod(err error, method StringName) error {
+	switch call := err.(type) {
+	case *CallError:
+		if call.Method == "" {
+			call.Method = method.String()
+		}
+	case CallError:
+		if call.Method == "" {
+			call.Method = method.String()
+		}
+		return call
 	}
+	return err
 }
 
 type Version struct {
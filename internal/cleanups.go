@@ -21,3 +21,7 @@ func Cleanups() []func() {
 
 var StartupFunctions []func()
 var PostStartupFunctions []func()
+
+// HostedMainLoop is set by engines that are hosted inside of the Go program, rather than loading
+// it as an extension, it runs the GoMainLoop class as the engine's main loop until it finishes.
+var HostedMainLoop func()
@@ -39,6 +39,22 @@ func convertVariantToDesiredGoType(value Variant, rtype reflect.Type) (reflect.V
 			return result.Elem(), nil
 		}
 		fallthrough
+	case reflect.Pointer:
+		// pointers to Go extension classes are resolved to the existing Go value of the object.
+		if rtype.Kind() == reflect.Pointer && rtype.Implements(reflect.TypeOf([0]IsClass{}).Elem()) {
+			switch value.Type() {
+			case TypeNil:
+				return reflect.Zero(rtype), nil
+			case TypeObject:
+				object := LetVariantAsPointerType[Object](value, TypeObject)
+				ext, ok := ExtensionInstances.Load(pointers.Get(object)[0])
+				if !ok || !reflect.TypeOf(ext).AssignableTo(rtype) {
+					return reflect.Value{}, xray.New(fmt.Errorf("cannot convert %s to %s", object.GetClass().String(), rtype))
+				}
+				return reflect.ValueOf(ext), nil
+			}
+		}
+		fallthrough
 	default:
 		val, err := ConvertToDesiredGoType(value.Interface(), rtype)
 		if err != nil {
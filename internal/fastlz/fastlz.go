@@ -0,0 +1,251 @@
+// Package fastlz is a pure Go implementation of FastLZ (version 0.5.0), as used by the engine for
+// its CompressionFastLZ mode, the compressed output is identical to the engine's.
+package fastlz
+
+import "errors"
+
+// ErrCorrupt is returned when decompressing invalid FastLZ data.
+var ErrCorrupt = errors.New("corrupt FastLZ data")
+
+const (
+	maxCopy        = 32
+	maxLen         = 264 // 256 + 8
+	maxL1Distance  = 8192
+	maxL2Distance  = 8191
+	maxFarDistance = 65535 + maxL2Distance - 1
+	hashLog        = 13
+	hashSize       = 1 << hashLog
+	hashMask       = hashSize - 1
+)
+
+// Compress the input, using level 1 for inputs shorter than 64 KiB and level 2 otherwise, in
+// the same way as fastlz_compress. The input must be at least 16 bytes long, as FastLZ is unable
+// to compress shorter inputs (the engine pads them with zeros).
+func Compress(in []byte) []byte {
+	if len(in) < 65536 {
+		return CompressLevel(in, 1)
+	}
+	return CompressLevel(in, 2)
+}
+
+func readU32(b []byte, i int) uint32 {
+	return uint32(b[i]) | uint32(b[i+1])<<8 | uint32(b[i+2])<<16 | uint32(b[i+3])<<24
+}
+
+func hash(v uint32) uint32 {
+	return uint32(uint64(v)*2654435769) >> (32 - hashLog) & hashMask
+}
+
+func literals(out []byte, in []byte) []byte {
+	for len(in) >= maxCopy {
+		out = append(out, maxCopy-1)
+		out = append(out, in[:maxCopy]...)
+		in = in[maxCopy:]
+	}
+	if len(in) > 0 {
+		out = append(out, byte(len(in)-1))
+		out = append(out, in...)
+	}
+	return out
+}
+
+func match1(out []byte, length, distance int) []byte {
+	distance--
+	for length > maxLen-2 {
+		out = append(out, 7<<5+byte(distance>>8), maxLen-2-7-2, byte(distance))
+		length -= maxLen - 2
+	}
+	if length < 7 {
+		return append(out, byte(length<<5)+byte(distance>>8), byte(distance))
+	}
+	return append(out, 7<<5+byte(distance>>8), byte(length-7), byte(distance))
+}
+
+func match2(out []byte, length, distance int) []byte {
+	distance--
+	if distance < maxL2Distance {
+		if length < 7 {
+			return append(out, byte(length<<5)+byte(distance>>8), byte(distance))
+		}
+		out = append(out, 7<<5+byte(distance>>8))
+		for length -= 7; length >= 255; length -= 255 {
+			out = append(out, 255)
+		}
+		return append(out, byte(length), byte(distance))
+	}
+	distance -= maxL2Distance
+	if length < 7 {
+		return append(out, byte(length<<5)+31, 255, byte(distance>>8), byte(distance))
+	}
+	out = append(out, 7<<5+31)
+	for length -= 7; length >= 255; length -= 255 {
+		out = append(out, 255)
+	}
+	return append(out, byte(length), 255, byte(distance>>8), byte(distance))
+}
+
+// compare returns the length of the match between in[p:] and in[q:], with q bounded by r, which
+// (as in the reference implementation) includes the first mismatching byte.
+func compare(in []byte, p, q, r int) int {
+	start := p
+	if readU32(in, p) == readU32(in, q) {
+		p += 4
+		q += 4
+	}
+	for q < r {
+		p++
+		q++
+		if in[p-1] != in[q-1] {
+			break
+		}
+	}
+	return p - start
+}
+
+// CompressLevel compresses the input with the given FastLZ level (1 or 2).
+func CompressLevel(in []byte, level int) []byte {
+	var (
+		length  = len(in)
+		ipBound = length - 4
+		ipLimit = length - 12 - 1
+		out     = make([]byte, 0, length+length/16+64)
+		htab    [hashSize]uint32
+		anchor  = 0
+		ip      = 2
+	)
+	maxDistance := maxL1Distance
+	if level == 2 {
+		maxDistance = maxFarDistance
+	}
+	for ip < ipLimit {
+		var (
+			ref, distance int
+			seq, cmp      uint32
+		)
+		for {
+			seq = readU32(in, ip) & 0xffffff
+			h := hash(seq)
+			ref = int(htab[h])
+			htab[h] = uint32(ip)
+			distance = ip - ref
+			cmp = 0x1000000
+			if distance < maxDistance {
+				cmp = readU32(in, ref) & 0xffffff
+			}
+			if ip >= ipLimit {
+				break
+			}
+			ip++
+			if seq == cmp {
+				break
+			}
+		}
+		if ip >= ipLimit {
+			break
+		}
+		ip--
+		if level == 2 && distance >= maxL2Distance {
+			if in[ref+3] != in[ip+3] || in[ref+4] != in[ip+4] {
+				ip++
+				continue
+			}
+		}
+		if ip > anchor {
+			out = literals(out, in[anchor:ip])
+		}
+		n := compare(in, ref+3, ip+3, ipBound)
+		if level == 2 {
+			out = match2(out, n, distance)
+		} else {
+			out = match1(out, n, distance)
+		}
+		ip += n
+		seq = readU32(in, ip)
+		htab[hash(seq&0xffffff)] = uint32(ip)
+		ip++
+		seq >>= 8
+		htab[hash(seq)] = uint32(ip)
+		ip++
+		anchor = ip
+	}
+	out = literals(out, in[anchor:])
+	if level == 2 && len(out) > 0 {
+		out[0] |= 1 << 5
+	}
+	return out
+}
+
+// Decompress a FastLZ (level 1 or 2) block with the given decompressed size.
+func Decompress(in []byte, size int) ([]byte, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	level := in[0]>>5 + 1
+	out := make([]byte, 0, size)
+	ip := 0
+	ctrl := int(in[ip] & 31)
+	ip++
+	next := func() (int, error) {
+		if ip >= len(in) {
+			return 0, ErrCorrupt
+		}
+		ip++
+		return int(in[ip-1]), nil
+	}
+	for {
+		if ctrl >= 32 {
+			length := ctrl>>5 - 1
+			offset := (ctrl & 31) << 8
+			if length == 6 {
+				for {
+					code, err := next()
+					if err != nil {
+						return nil, err
+					}
+					length += code
+					if level == 1 || code != 255 {
+						break
+					}
+				}
+			}
+			code, err := next()
+			if err != nil {
+				return nil, err
+			}
+			offset += code
+			if level == 2 && code == 255 && offset == 31<<8+255 {
+				hi, err := next()
+				if err != nil {
+					return nil, err
+				}
+				lo, err := next()
+				if err != nil {
+					return nil, err
+				}
+				offset = hi<<8 + lo + maxL2Distance
+			}
+			ref := len(out) - offset - 1
+			if ref < 0 {
+				return nil, ErrCorrupt
+			}
+			for i := range length + 3 {
+				out = append(out, out[ref+i])
+			}
+		} else {
+			ctrl++
+			if ip+ctrl > len(in) {
+				return nil, ErrCorrupt
+			}
+			out = append(out, in[ip:ip+ctrl]...)
+			ip += ctrl
+		}
+		if len(out) > size {
+			return nil, ErrCorrupt
+		}
+		if ip >= len(in) {
+			return out, nil
+		}
+		ctrl = int(in[ip])
+		ip++
+	}
+}
@@ -0,0 +1,51 @@
+package fastlz_test
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"graphics.gd/internal/fastlz"
+)
+
+func TestDecompress(t *testing.T) {
+	// three literals, followed by a six byte match at a distance of three.
+	out, err := fastlz.Decompress([]byte{0x02, 'a', 'b', 'c', 0x80, 0x02}, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "abcabcabc" {
+		t.Fatalf("unexpected output %q", out)
+	}
+	if _, err := fastlz.Decompress([]byte{0x02, 'a', 0x80}, 9); err == nil {
+		t.Fatal("expected an error for truncated data")
+	}
+}
+
+func TestCompress(t *testing.T) {
+	// three literals, a sixteen byte match at a distance of three and the five remaining literals.
+	in := []byte("abcabcabcabcabcabcabcabc")
+	if out := fastlz.Compress(in); !bytes.Equal(out, []byte{0x02, 'a', 'b', 'c', 0xe0, 0x07, 0x02, 0x04, 'b', 'c', 'a', 'b', 'c'}) {
+		t.Fatalf("unexpected output % x", out)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for _, size := range []int{16, 100, 4096, 70000, 200000} {
+		in := make([]byte, size)
+		for i := range in {
+			if rng.Intn(4) == 0 {
+				in[i] = byte(rng.Intn(256))
+			} else if i > 300 {
+				in[i] = in[i-rng.Intn(300)-1]
+			}
+		}
+		for _, level := range []int{1, 2} {
+			out, err := fastlz.Decompress(fastlz.CompressLevel(in, level), size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(out, in) {
+				t.Fatalf("level %d, size %d: round trip failed", level, size)
+			}
+		}
+	}
+}
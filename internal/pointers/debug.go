@@ -0,0 +1,51 @@
+package pointers
+
+import (
+	"fmt"
+	"io"
+)
+
+// Counts of the pointers managed by the package.
+type Counts struct {
+	Live   int // pointers that have not been freed yet.
+	Pinned int // live pointers that are pinned, see [Pin].
+	Static int // pointers allocated with [Add], these are never freed.
+}
+
+// Count returns the number of pointers currently managed by the package.
+func Count() Counts {
+	var counts Counts
+	for s := range shapesMax {
+		tab := &tables[s]
+		for j := range tab.len.Load() {
+			page := tab.Index(j)
+			for i := uint64(0); i < pageSize; i += uint64(s + 2) {
+				rev := revision(page[i+offsetRevision].Load())
+				if rev == revisionEOF {
+					break // end of the table.
+				}
+				if rev == revisionLocked || rev.isClosed() {
+					continue
+				}
+				counts.Live++
+				if rev.isPinned() {
+					counts.Pinned++
+				}
+			}
+		}
+		if s > 0 {
+			counts.Static += int(countsOf(s))
+		}
+	}
+	return counts
+}
+
+func countsOf(s int) uint64 { return counts[s].Load() / uint64(s) }
+
+// Dump writes a summary of the live pointers to w. When built with the pointers_debug build tag,
+// the live pointers are grouped by type and by the call site that allocated them.
+func Dump(w io.Writer) {
+	counts := Count()
+	fmt.Fprintf(w, "pointers: %d live, %d pinned, %d static\n", counts.Live, counts.Pinned, counts.Static)
+	dump(w)
+}
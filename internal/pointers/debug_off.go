@@ -0,0 +1,18 @@
+//go:build !pointers_debug
+
+package pointers
+
+import "io"
+
+// Debugging is true when the package is built with the pointers_debug build tag, in which case
+// the allocation and free sites of each pointer are recorded, so that they can be included in
+// use-after-free panics and in the output of [Dump].
+const Debugging = false
+
+func allocated[T any](s int, p uint64, rev revision) {}
+func freed(s int, p uint64, rev revision)            {}
+func expired(s int, p uint64, rev revision) string   { return "expired pointer" }
+
+func dump(w io.Writer) {
+	io.WriteString(w, "pointers: build with -tags pointers_debug to record where live pointers were allocated\n")
+}
@@ -0,0 +1,164 @@
+//go:build pointers_debug
+
+package pointers
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Debugging is true when the package is built with the pointers_debug build tag, in which case
+// the allocation and free sites of each pointer are recorded, so that they can be included in
+// use-after-free panics and in the output of [Dump].
+const Debugging = true
+
+// lifetime of a single pointer revision.
+type lifetime struct {
+	kind     string
+	revision revision
+	alloc    []uintptr
+	free     []uintptr // nil while the pointer is live.
+}
+
+// history keeps the current and the previous lifetime of each entry in the
+// [tables], as an entry is reused as soon as it has been freed.
+var history struct {
+	sync.Mutex
+	entries map[[2]uint64]*[2]lifetime
+}
+
+func callers(skip int) []uintptr {
+	var pcs [32]uintptr
+	return slices.Clone(pcs[:runtime.Callers(skip+1, pcs[:])])
+}
+
+func allocated[T any](s int, p uint64, rev revision) {
+	stack := callers(3)
+	history.Lock()
+	defer history.Unlock()
+	if history.entries == nil {
+		history.entries = make(map[[2]uint64]*[2]lifetime)
+	}
+	entry := history.entries[[2]uint64{uint64(s), p}]
+	if entry == nil {
+		entry = new([2]lifetime)
+		history.entries[[2]uint64{uint64(s), p}] = entry
+	}
+	entry[1] = entry[0]
+	entry[0] = lifetime{
+		kind:     reflect.TypeFor[T]().String(),
+		revision: rev,
+		alloc:    stack,
+	}
+}
+
+func freed(s int, p uint64, rev revision) {
+	stack := callers(3)
+	history.Lock()
+	defer history.Unlock()
+	if entry := history.entries[[2]uint64{uint64(s), p}]; entry != nil {
+		for i := range entry {
+			if entry[i].free == nil && entry[i].revision.matches(rev) {
+				entry[i].free = stack
+			}
+		}
+	}
+}
+
+func expired(s int, p uint64, rev revision) string {
+	history.Lock()
+	defer history.Unlock()
+	var message strings.Builder
+	message.WriteString("expired pointer")
+	if entry := history.entries[[2]uint64{uint64(s), p}]; entry != nil {
+		for _, life := range entry {
+			if life.alloc == nil || !life.revision.matches(rev) {
+				continue
+			}
+			fmt.Fprintf(&message, " (%s)\n\nallocated at:\n", life.kind)
+			writeStack(&message, life.alloc)
+			if life.free != nil {
+				message.WriteString("\nfreed at:\n")
+				writeStack(&message, life.free)
+			}
+		}
+	}
+	return message.String()
+}
+
+func writeStack(w io.Writer, stack []uintptr) {
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(w, "\t%s\n\t\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+}
+
+// site returns the first frame of the stack that is outside of graphics.gd, or
+// failing that, outside of its internals, such that pointers are grouped by the
+// code that caused them to be allocated.
+func site(stack []uintptr) string {
+	var (
+		best  runtime.Frame
+		score = -1
+	)
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		var rank int
+		switch name := frame.Function; {
+		case strings.Contains(name, "_test."), !strings.HasPrefix(name, "graphics.gd/"):
+			rank = 2
+		case !strings.HasPrefix(name, "graphics.gd/internal"):
+			rank = 1
+		}
+		if rank > score {
+			best, score = frame, rank
+		}
+		if rank == 2 || !more {
+			break
+		}
+	}
+	return fmt.Sprintf("%s (%s:%d)", best.Function, best.File, best.Line)
+}
+
+func dump(w io.Writer) {
+	type group struct {
+		kind  string
+		site  string
+		count int
+	}
+	groups := make(map[[2]string]*group)
+	history.Lock()
+	for _, entry := range history.entries {
+		life := entry[0]
+		if life.alloc == nil || life.free != nil {
+			continue
+		}
+		key := [2]string{life.kind, site(life.alloc)}
+		if groups[key] == nil {
+			groups[key] = &group{kind: key[0], site: key[1]}
+		}
+		groups[key].count++
+	}
+	history.Unlock()
+	sorted := make([]*group, 0, len(groups))
+	for _, group := range groups {
+		sorted = append(sorted, group)
+	}
+	slices.SortFunc(sorted, func(a, b *group) int {
+		return cmp.Or(cmp.Compare(b.count, a.count), cmp.Compare(a.kind, b.kind), cmp.Compare(a.site, b.site))
+	})
+	for _, group := range sorted {
+		fmt.Fprintf(w, "%8d %s allocated by %s\n", group.count, group.kind, group.site)
+	}
+}
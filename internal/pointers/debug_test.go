@@ -0,0 +1,35 @@
+//go:build pointers_debug
+
+package pointers_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"graphics.gd/internal/pointers"
+)
+
+func TestExpiredSites(t *testing.T) {
+	ptr := pointers.New[MySlice]([3]uint64{1, 2, 3})
+	pointers.End(ptr)
+	defer func() {
+		message := fmt.Sprint(recover())
+		for _, expected := range []string{"expired pointer", "MySlice", "allocated at", "freed at", "TestExpiredSites"} {
+			if !strings.Contains(message, expected) {
+				t.Fatalf("expected %q in the panic message:\n%s", expected, message)
+			}
+		}
+	}()
+	pointers.Get(ptr)
+}
+
+func TestDump(t *testing.T) {
+	ptr := pointers.New[MySlice]([3]uint64{1, 2, 3})
+	defer pointers.End(ptr)
+	var buf strings.Builder
+	pointers.Dump(&buf)
+	if !strings.Contains(buf.String(), "pointers_test.MySlice allocated by graphics.gd/internal/pointers_test.TestDump") {
+		t.Fatal(buf.String())
+	}
+}
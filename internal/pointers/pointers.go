@@ -255,6 +255,9 @@ func malloc[T Generic[T, P], P Size](ptr P, free func(T)) T {
 			}
 			current.revision = rev
 			current.checksum = ptr
+			if Debugging {
+				allocated[T](len(ptr), idx, rev)
+			}
 			return T(current)
 		}
 	}
@@ -272,6 +275,9 @@ func end(rev revision, s int, p uint64) bool {
 	}
 	if arr[addr+offsetRevision].CompareAndSwap(uint64(existing), revisionLocked) {
 		arr[addr+offsetRevision].Store(uint64(existing.close())) // next free.
+		if Debugging {
+			freed(s, p, existing)
+		}
 		for {
 			end := writes[s].Load()
 			arr[addr+offsetPointers].Store(end)
@@ -311,8 +317,7 @@ func Get[T Generic[T, P], P Size](ptr T) P {
 	}
 	rev := revision(arr[addr+offsetRevision].Load())
 	if !rev.matches(p.revision) {
-		fmt.Printf("%b != %b\b", rev, p.revision)
-		panic("expired pointer")
+		panic(expired(len(p.checksum), p.sentinal, p.revision))
 	}
 	if !rev.isActive() {
 		arr[addr+offsetRevision].CompareAndSwap(uint64(rev), uint64(rev.active()))
@@ -393,7 +398,7 @@ func Set[T Generic[T, P], P Size](ptr T, val P) {
 	arr := tables[len(p.checksum)].Index(page)
 	rev := revision(arr[addr+offsetRevision].Load())
 	if !rev.matches(p.revision) {
-		panic("expired pointer")
+		panic(expired(len(p.checksum), p.sentinal, p.revision))
 	}
 	if arr[addr+offsetRevision].CompareAndSwap(uint64(rev), revisionLocked) {
 		var local [3]uint64
@@ -464,7 +469,7 @@ func Pin[T Generic[T, P], P Size](ptr T) T {
 	arr := tables[len(p.checksum)].Index(page)
 	rev := revision(arr[addr+offsetRevision].Load())
 	if !rev.matches(p.revision) {
-		panic("expired pointer")
+		panic(expired(len(p.checksum), p.sentinal, p.revision))
 	}
 	arr[addr+offsetRevision].CompareAndSwap(uint64(rev), uint64(rev.pinned()))
 	return ptr
@@ -511,7 +516,7 @@ func Lay[T Generic[T, P], P Size](ptr T) T {
 	arr := tables[len(p.checksum)].Index(page)
 	rev := revision(arr[addr+offsetRevision].Load())
 	if !rev.matches(p.revision) {
-		panic("expired pointer")
+		panic(expired(len(p.checksum), p.sentinal, p.revision))
 	}
 	if arr[addr+offsetRevision].CompareAndSwap(uint64(rev), revisionLocked) {
 		arr[addr+offsetFreeFunc].Store(0)
@@ -571,7 +576,17 @@ func End[T Generic[T, Raw], Raw Size](ptr T) (Raw, bool) {
 		checksum Raw
 	})(ptr)
 	if p.checksum == [1]Raw{}[0] {
-		return [1]Raw{}[0], false
+		if p.revision < 2 {
+			return [1]Raw{}[0], false
+		}
+		// the pointer was reconstructed with Load, which does not keep its value.
+		page, addr := uint64(p.sentinal/pageSize), uint64(p.sentinal%pageSize)
+		arr := tables[len(p.checksum)].Index(page)
+		var ptrs [3]uint64
+		for i := 0; i < len(p.checksum); i++ {
+			ptrs[i] = arr[addr+offsetPointers+uint64(i)].Load()
+		}
+		p.checksum = *(*Raw)(unsafe.Pointer(&ptrs))
 	}
 	if end(p.revision, len(p.checksum), uint64(p.sentinal)) {
 		return p.checksum, true
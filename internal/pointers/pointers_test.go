@@ -49,3 +49,27 @@ func TestPointersV2(t *testing.T) {
 		t.Fatal("simulated pointers not freed")
 	}
 }
+
+func TestCount(t *testing.T) {
+	before := pointers.Count()
+	ptr := pointers.Pin(pointers.New[MyString]([2]uint64{1, 2}))
+	after := pointers.Count()
+	if after.Live != before.Live+1 || after.Pinned != before.Pinned+1 {
+		t.Fatalf("unexpected counts %+v, before %+v", after, before)
+	}
+	pointers.End(ptr)
+	if counts := pointers.Count(); counts != before {
+		t.Fatalf("unexpected counts %+v, expected %+v", counts, before)
+	}
+}
+
+func TestEndLoaded(t *testing.T) {
+	ptr := pointers.New[MyString]([2]uint64{1, 2})
+	loaded := pointers.Load[MyString](pointers.Pack(ptr))
+	if raw, ok := pointers.End(loaded); !ok || raw != [2]uint64{1, 2} {
+		t.Fatalf("expected to end the loaded pointer, got %v %v", raw, ok)
+	}
+	if _, ok := pointers.End(ptr); ok {
+		t.Fatal("expected the pointer to have ended")
+	}
+}
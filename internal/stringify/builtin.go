@@ -0,0 +1,325 @@
+// Package stringify implements the JSON representation used by the engine's JSON.stringify and
+// JSON.parse, where builtin math types are represented by their String form, so that it can be
+// shared by the variant packages (which implement [encoding/json] interfaces with it) and
+// graphics.gd/format/gdjson.
+package stringify
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"unsafe"
+
+	"graphics.gd/variant/AABB"
+	"graphics.gd/variant/Basis"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Float"
+	"graphics.gd/variant/Plane"
+	"graphics.gd/variant/Projection"
+	"graphics.gd/variant/Quaternion"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Rect2i"
+	"graphics.gd/variant/Transform2D"
+	"graphics.gd/variant/Transform3D"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector3i"
+	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/Vector4i"
+)
+
+// realBits is the size of Float.X.
+const realBits = int(unsafe.Sizeof(Float.X(0)) * 8)
+
+// AppendReal appends a real number in the same form as the engine's String::num_real, which
+// always includes a decimal point.
+func AppendReal(buf []byte, f float64, bits int) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(buf, "nan"...)
+	case math.IsInf(f, 1):
+		return append(buf, "inf"...)
+	case math.IsInf(f, -1):
+		return append(buf, "-inf"...)
+	}
+	buf = strconv.AppendFloat(buf, f, 'f', -1, bits)
+	if f == math.Trunc(f) {
+		buf = append(buf, ".0"...)
+	}
+	return buf
+}
+
+type builder []byte
+
+func (b *builder) reals(values ...Float.X) {
+	*b = append(*b, '(')
+	for i, v := range values {
+		if i > 0 {
+			*b = append(*b, ", "...)
+		}
+		*b = AppendReal(*b, float64(v), realBits)
+	}
+	*b = append(*b, ')')
+}
+
+func (b *builder) ints(values ...int32) {
+	*b = append(*b, '(')
+	for i, v := range values {
+		if i > 0 {
+			*b = append(*b, ", "...)
+		}
+		*b = strconv.AppendInt(*b, int64(v), 10)
+	}
+	*b = append(*b, ')')
+}
+
+func (b *builder) label(sep, label string) {
+	*b = append(*b, sep...)
+	*b = append(*b, label...)
+	*b = append(*b, ": "...)
+}
+
+// Builtin returns the String form of a builtin math type, ie. "(1.0, 2.0)" for a [Vector2.XY].
+func Builtin(value any) (string, bool) {
+	var b builder
+	switch v := value.(type) {
+	case Vector2.XY:
+		b.reals(v.X, v.Y)
+	case Vector2i.XY:
+		b.ints(v.X, v.Y)
+	case Vector3.XYZ:
+		b.reals(v.X, v.Y, v.Z)
+	case Vector3i.XYZ:
+		b.ints(v.X, v.Y, v.Z)
+	case Vector4.XYZW:
+		b.reals(v.X, v.Y, v.Z, v.W)
+	case Vector4i.XYZW:
+		b.ints(v.X, v.Y, v.Z, v.W)
+	case Quaternion.IJKX:
+		b.reals(v.I, v.J, v.K, v.X)
+	case Color.RGBA:
+		b.reals(v.R, v.G, v.B, v.A)
+	case Rect2.PositionSize:
+		b.label("[", "P")
+		b.reals(v.Position.X, v.Position.Y)
+		b.label(", ", "S")
+		b.reals(v.Size.X, v.Size.Y)
+		b = append(b, ']')
+	case Rect2i.PositionSize:
+		b.label("[", "P")
+		b.ints(v.Position.X, v.Position.Y)
+		b.label(", ", "S")
+		b.ints(v.Size.X, v.Size.Y)
+		b = append(b, ']')
+	case AABB.PositionSize:
+		b.label("[", "P")
+		b.reals(v.Position.X, v.Position.Y, v.Position.Z)
+		b.label(", ", "S")
+		b.reals(v.Size.X, v.Size.Y, v.Size.Z)
+		b = append(b, ']')
+	case Plane.NormalD:
+		b.label("[", "N")
+		b.reals(v.Normal.X, v.Normal.Y, v.Normal.Z)
+		b.label(", ", "D")
+		b = AppendReal(b, float64(v.D), realBits)
+		b = append(b, ']')
+	case Transform2D.OriginXY:
+		b.label("[", "X")
+		b.reals(v.X.X, v.X.Y)
+		b.label(", ", "Y")
+		b.reals(v.Y.X, v.Y.Y)
+		b.label(", ", "O")
+		b.reals(v.Origin.X, v.Origin.Y)
+		b = append(b, ']')
+	case Basis.XYZ:
+		b.basis(v)
+		b = append(b, ']')
+	case Transform3D.BasisOrigin:
+		b.basis(v.Basis)
+		b.label(", ", "O")
+		b.reals(v.Origin.X, v.Origin.Y, v.Origin.Z)
+		b = append(b, ']')
+	case Projection.XYZW:
+		b.label("[", "X")
+		b.reals(v.X.X, v.X.Y, v.X.Z, v.X.W)
+		b.label(", ", "Y")
+		b.reals(v.Y.X, v.Y.Y, v.Y.Z, v.Y.W)
+		b.label(", ", "Z")
+		b.reals(v.Z.X, v.Z.Y, v.Z.Z, v.Z.W)
+		b.label(", ", "W")
+		b.reals(v.W.X, v.W.Y, v.W.Z, v.W.W)
+		b = append(b, ']')
+	default:
+		return "", false
+	}
+	return string(b), true
+}
+
+// basis appends the columns of the basis, without the closing bracket. The fields of the basis
+// are laid out in the same way as the engine's rows, so each column is made up of a component
+// from each field.
+func (b *builder) basis(v Basis.XYZ) {
+	b.label("[", "X")
+	b.reals(v.X.X, v.Y.X, v.Z.X)
+	b.label(", ", "Y")
+	b.reals(v.X.Y, v.Y.Y, v.Z.Y)
+	b.label(", ", "Z")
+	b.reals(v.X.Z, v.Y.Z, v.Z.Z)
+}
+
+// builtins are the types supported by [Builtin] and [ParseBuiltin], along with the number of
+// components in their String form.
+var builtins = map[reflect.Type]int{
+	reflect.TypeFor[Vector2.XY]():              2,
+	reflect.TypeFor[Vector2i.XY]():             2,
+	reflect.TypeFor[Vector3.XYZ]():             3,
+	reflect.TypeFor[Vector3i.XYZ]():            3,
+	reflect.TypeFor[Vector4.XYZW]():            4,
+	reflect.TypeFor[Vector4i.XYZW]():           4,
+	reflect.TypeFor[Quaternion.IJKX]():         4,
+	reflect.TypeFor[Color.RGBA]():              4,
+	reflect.TypeFor[Rect2.PositionSize]():      4,
+	reflect.TypeFor[Rect2i.PositionSize]():     4,
+	reflect.TypeFor[AABB.PositionSize]():       6,
+	reflect.TypeFor[Plane.NormalD]():           4,
+	reflect.TypeFor[Transform2D.OriginXY]():    6,
+	reflect.TypeFor[Basis.XYZ]():               9,
+	reflect.TypeFor[Transform3D.BasisOrigin](): 12,
+	reflect.TypeFor[Projection.XYZW]():         16,
+}
+
+// IsBuiltin reports whether the type is supported by [Builtin] and [ParseBuiltin].
+func IsBuiltin(rtype reflect.Type) bool {
+	_, ok := builtins[rtype]
+	return ok
+}
+
+// numbers returns the numbers within the String form of a builtin, ignoring any labels.
+func numbers(text string) ([]float64, error) {
+	var result []float64
+	for i := 0; i < len(text); {
+		c := text[i]
+		start := i
+		switch {
+		case c == '-' || c == '+' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			for i < len(text) {
+				c := text[i]
+				if c == '-' || c == '+' || c == '.' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+					i++
+					continue
+				}
+				break
+			}
+		default:
+			i++
+			continue
+		}
+		word := text[start:i]
+		switch word {
+		case "P", "S", "N", "D", "X", "Y", "Z", "W", "O":
+			continue // label
+		case "inf", "+inf":
+			result = append(result, math.Inf(1))
+		case "-inf", "inf_neg":
+			result = append(result, math.Inf(-1))
+		case "nan":
+			result = append(result, math.NaN())
+		default:
+			f, err := strconv.ParseFloat(word, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", word)
+			}
+			result = append(result, f)
+		}
+	}
+	return result, nil
+}
+
+// ParseBuiltin parses the String form of a builtin math type into value, which must point to
+// one of the types supported by [Builtin].
+func ParseBuiltin(text string, value any) error {
+	f, err := numbers(text)
+	if err != nil {
+		return err
+	}
+	if err := SetComponents(value, f); err != nil {
+		return fmt.Errorf("cannot parse %q: %w", text, err)
+	}
+	return nil
+}
+
+// Components returns the numbers that make up a builtin math type, in the same order as they
+// appear in its String form (basis columns, followed by the origin).
+func Components(value any) ([]float64, bool) {
+	s, ok := Builtin(value)
+	if !ok {
+		return nil, false
+	}
+	f, err := numbers(s)
+	return f, err == nil
+}
+
+// SetComponents is the inverse of [Components], value must point to one of the types supported by
+// [Builtin].
+func SetComponents(value any, f []float64) error {
+	rvalue := reflect.ValueOf(value)
+	if rvalue.Kind() != reflect.Pointer || rvalue.IsNil() || !IsBuiltin(rvalue.Type().Elem()) {
+		return fmt.Errorf("stringify: unsupported builtin %T", value)
+	}
+	if count := builtins[rvalue.Type().Elem()]; len(f) != count {
+		return fmt.Errorf("%s requires %d components, not %d", rvalue.Type().Elem(), count, len(f))
+	}
+	r := func(i int) Float.X { return Float.X(f[i]) }
+	n := func(i int) int32 { return int32(f[i]) }
+	switch v := value.(type) {
+	case *Vector2.XY:
+		*v = Vector2.XY{r(0), r(1)}
+	case *Vector2i.XY:
+		*v = Vector2i.XY{n(0), n(1)}
+	case *Vector3.XYZ:
+		*v = Vector3.XYZ{r(0), r(1), r(2)}
+	case *Vector3i.XYZ:
+		*v = Vector3i.XYZ{n(0), n(1), n(2)}
+	case *Vector4.XYZW:
+		*v = Vector4.XYZW{r(0), r(1), r(2), r(3)}
+	case *Vector4i.XYZW:
+		*v = Vector4i.XYZW{n(0), n(1), n(2), n(3)}
+	case *Quaternion.IJKX:
+		*v = Quaternion.IJKX{r(0), r(1), r(2), r(3)}
+	case *Color.RGBA:
+		*v = Color.RGBA{r(0), r(1), r(2), r(3)}
+	case *Rect2.PositionSize:
+		*v = Rect2.PositionSize{Position: Vector2.XY{r(0), r(1)}, Size: Vector2.XY{r(2), r(3)}}
+	case *Rect2i.PositionSize:
+		*v = Rect2i.PositionSize{Position: Vector2i.XY{n(0), n(1)}, Size: Vector2i.XY{n(2), n(3)}}
+	case *AABB.PositionSize:
+		*v = AABB.PositionSize{Position: Vector3.XYZ{r(0), r(1), r(2)}, Size: Vector3.XYZ{r(3), r(4), r(5)}}
+	case *Plane.NormalD:
+		*v = Plane.NormalD{Normal: Vector3.XYZ{r(0), r(1), r(2)}, D: r(3)}
+	case *Transform2D.OriginXY:
+		*v = Transform2D.OriginXY{X: Vector2.XY{r(0), r(1)}, Y: Vector2.XY{r(2), r(3)}, Origin: Vector2.XY{r(4), r(5)}}
+	case *Basis.XYZ:
+		*v = basis(r)
+	case *Transform3D.BasisOrigin:
+		*v = Transform3D.BasisOrigin{Basis: basis(r), Origin: Vector3.XYZ{r(9), r(10), r(11)}}
+	case *Projection.XYZW:
+		*v = Projection.XYZW{
+			X: Vector4.XYZW{r(0), r(1), r(2), r(3)},
+			Y: Vector4.XYZW{r(4), r(5), r(6), r(7)},
+			Z: Vector4.XYZW{r(8), r(9), r(10), r(11)},
+			W: Vector4.XYZW{r(12), r(13), r(14), r(15)},
+		}
+	}
+	return nil
+}
+
+// basis from its columns.
+func basis(r func(int) Float.X) Basis.XYZ {
+	return Basis.XYZ{
+		X: Vector3.XYZ{r(0), r(3), r(6)},
+		Y: Vector3.XYZ{r(1), r(4), r(7)},
+		Z: Vector3.XYZ{r(2), r(5), r(8)},
+	}
+}
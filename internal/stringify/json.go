@@ -0,0 +1,245 @@
+package stringify
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Marshal returns the JSON encoding of value, in the same form as JSON.stringify, builtin math
+// types are encoded as strings, maps have their keys sorted and, unlike [json.Marshal], byte
+// slices are encoded as arrays of numbers.
+func Marshal(value any) ([]byte, error) {
+	return appendValue(nil, reflect.ValueOf(value))
+}
+
+// MarshalIndent is like [Marshal] but indents the output, with the given indent (ie. "\t").
+func MarshalIndent(value any, indent string) ([]byte, error) {
+	data, err := Marshal(value)
+	if err != nil || indent == "" {
+		return data, err
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", indent); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var (
+	marshalerType     = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func appendString(buf []byte, s string) []byte {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return append(buf, bytes.TrimSuffix(b.Bytes(), []byte("\n"))...)
+}
+
+// appendFloat appends f with up to 14 significant digits, like JSON.stringify, such that 0.1+0.2
+// is encoded as 0.3.
+func appendFloat(buf []byte, f float64, bits int) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, fmt.Errorf("json: unsupported value: %v", f)
+	}
+	f, _ = strconv.ParseFloat(strconv.FormatFloat(f, 'e', 13, 64), 64)
+	if f == math.Trunc(f) && math.Abs(f) < 1e21 {
+		return append(strconv.AppendFloat(buf, f, 'f', -1, bits), ".0"...), nil
+	}
+	if math.Abs(f) < 1e21 {
+		return strconv.AppendFloat(buf, f, 'f', -1, bits), nil
+	}
+	return strconv.AppendFloat(buf, f, 'g', -1, bits), nil
+}
+
+func appendValue(buf []byte, rvalue reflect.Value) ([]byte, error) {
+	if !rvalue.IsValid() {
+		return append(buf, "null"...), nil
+	}
+	rtype := rvalue.Type()
+	if rtype.Implements(marshalerType) && (rtype.Kind() != reflect.Pointer || !rvalue.IsNil()) {
+		data, err := rvalue.Interface().(json.Marshaler).MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return append(buf, data...), nil
+	}
+	if IsBuiltin(rtype) {
+		s, _ := Builtin(rvalue.Interface())
+		return appendString(buf, s), nil
+	}
+	if rtype.Implements(textMarshalerType) && (rtype.Kind() != reflect.Pointer || !rvalue.IsNil()) {
+		text, err := rvalue.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return appendString(buf, string(text)), nil
+	}
+	switch rtype.Kind() {
+	case reflect.Bool:
+		return strconv.AppendBool(buf, rvalue.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(buf, rvalue.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(buf, rvalue.Uint(), 10), nil
+	case reflect.Float32:
+		return appendFloat(buf, rvalue.Float(), 32)
+	case reflect.Float64:
+		return appendFloat(buf, rvalue.Float(), 64)
+	case reflect.String:
+		return appendString(buf, rvalue.String()), nil
+	case reflect.Pointer, reflect.Interface:
+		if rvalue.IsNil() {
+			return append(buf, "null"...), nil
+		}
+		return appendValue(buf, rvalue.Elem())
+	case reflect.Slice, reflect.Array:
+		if rtype.Kind() == reflect.Slice && rvalue.IsNil() {
+			return append(buf, "[]"...), nil
+		}
+		buf = append(buf, '[')
+		for i := range rvalue.Len() {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			var err error
+			if buf, err = appendValue(buf, rvalue.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, ']'), nil
+	case reflect.Map:
+		type entry struct {
+			key   string
+			value reflect.Value
+		}
+		var entries []entry
+		for iter := rvalue.MapRange(); iter.Next(); {
+			key, err := Key(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry{key, iter.Value()})
+		}
+		slices.SortFunc(entries, func(a, b entry) int { return strings.Compare(a.key, b.key) })
+		buf = append(buf, '{')
+		for i, entry := range entries {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendString(buf, entry.key)
+			buf = append(buf, ':')
+			var err error
+			if buf, err = appendValue(buf, entry.value); err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, '}'), nil
+	case reflect.Struct:
+		buf = append(buf, '{')
+		first := true
+		for _, field := range fields(rtype) {
+			value := rvalue.FieldByIndex(field.index)
+			if field.omitEmpty && value.IsZero() {
+				continue
+			}
+			if !first {
+				buf = append(buf, ',')
+			}
+			first = false
+			buf = appendString(buf, field.name)
+			buf = append(buf, ':')
+			var err error
+			if buf, err = appendValue(buf, value); err != nil {
+				return nil, err
+			}
+		}
+		return append(buf, '}'), nil
+	}
+	return nil, fmt.Errorf("json: unsupported type: %s", rtype)
+}
+
+// Key returns the string form of a dictionary key, in the same way as the engine converts keys
+// to strings with String().
+func Key(key reflect.Value) (string, error) {
+	for key.Kind() == reflect.Interface && !key.IsNil() {
+		key = key.Elem()
+	}
+	if !key.IsValid() || key.Kind() == reflect.Interface {
+		return "null", nil
+	}
+	if s, ok := Builtin(key.Interface()); ok {
+		return s, nil
+	}
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
+	}
+	switch key.Kind() {
+	case reflect.String:
+		return key.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(key.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return string(AppendReal(nil, key.Float(), key.Type().Bits())), nil
+	}
+	return "", fmt.Errorf("json: unsupported key type: %s", key.Type())
+}
+
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+}
+
+// fields returns the encoded fields of a struct, following the same json tag conventions as
+// [encoding/json], with embedded structs inlined.
+func fields(rtype reflect.Type) []field {
+	var result []field
+	for i := range rtype.NumField() {
+		sf := rtype.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if sf.Anonymous && name == "" {
+			embedded := sf.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && sf.Type.Kind() != reflect.Pointer {
+				for _, inner := range fields(embedded) {
+					inner.index = append([]int{i}, inner.index...)
+					result = append(result, inner)
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		result = append(result, field{
+			name:      name,
+			index:     []int{i},
+			omitEmpty: slices.Contains(strings.Split(options, ","), "omitempty"),
+		})
+	}
+	return result
+}
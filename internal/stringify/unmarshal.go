@@ -0,0 +1,275 @@
+package stringify
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal parses JSON data into the value pointed to by ptr, builtin math types are parsed
+// from their String form, byte slices from arrays of numbers and numbers decoded into an empty
+// interface are float64, as they are with JSON.parse.
+func Unmarshal(data []byte, ptr any) error {
+	rvalue := reflect.ValueOf(ptr)
+	if rvalue.Kind() != reflect.Pointer || rvalue.IsNil() {
+		return fmt.Errorf("json: Unmarshal requires a non-nil pointer, not %T", ptr)
+	}
+	var generic any
+	if err := Decode(data, &generic); err != nil {
+		return err
+	}
+	return Assign(rvalue.Elem(), generic)
+}
+
+// Decode parses JSON data into generic values, with numbers decoded as [json.Number] so that
+// integers are not rounded before they are assigned.
+func Decode(data []byte, generic *any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(generic); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("json: invalid character after top-level value")
+	}
+	return nil
+}
+
+var (
+	unmarshalerType     = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// Assign a generic value decoded by [Decode] to dst, which must be settable.
+func Assign(dst reflect.Value, src any) error {
+	rtype := dst.Type()
+	if rtype.Kind() != reflect.Pointer && reflect.PointerTo(rtype).Implements(unmarshalerType) {
+		data, err := json.Marshal(src)
+		if err != nil {
+			return err
+		}
+		return dst.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(data)
+	}
+	if IsBuiltin(rtype) {
+		s, ok := src.(string)
+		if !ok {
+			return mismatch(src, rtype)
+		}
+		return ParseBuiltin(s, dst.Addr().Interface())
+	}
+	if s, ok := src.(string); ok && rtype.Kind() != reflect.Pointer && reflect.PointerTo(rtype).Implements(textUnmarshalerType) {
+		return dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	if src == nil {
+		dst.SetZero()
+		return nil
+	}
+	switch rtype.Kind() {
+	case reflect.Interface:
+		if rtype.NumMethod() != 0 {
+			return mismatch(src, rtype)
+		}
+		dst.Set(reflect.ValueOf(Plain(src)))
+		return nil
+	case reflect.Pointer:
+		elem := reflect.New(rtype.Elem())
+		if err := Assign(elem.Elem(), src); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	case reflect.Bool:
+		b, ok := src.(bool)
+		if !ok {
+			return mismatch(src, rtype)
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := src.(json.Number)
+		if !ok {
+			return mismatch(src, rtype)
+		}
+		i, err := strconv.ParseInt(string(n), 10, 64)
+		if err != nil {
+			f, ferr := n.Float64()
+			if ferr != nil {
+				return err
+			}
+			i = int64(f)
+		}
+		if dst.OverflowInt(i) {
+			return fmt.Errorf("json: %v overflows %s", n, rtype)
+		}
+		dst.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := src.(json.Number)
+		if !ok {
+			return mismatch(src, rtype)
+		}
+		u, err := strconv.ParseUint(string(n), 10, 64)
+		if err != nil {
+			f, ferr := n.Float64()
+			if ferr != nil || f < 0 {
+				return err
+			}
+			u = uint64(f)
+		}
+		if dst.OverflowUint(u) {
+			return fmt.Errorf("json: %v overflows %s", n, rtype)
+		}
+		dst.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		n, ok := src.(json.Number)
+		if !ok {
+			return mismatch(src, rtype)
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return err
+		}
+		dst.SetFloat(f)
+		return nil
+	case reflect.String:
+		s, ok := src.(string)
+		if !ok {
+			return mismatch(src, rtype)
+		}
+		dst.SetString(s)
+		return nil
+	case reflect.Slice:
+		list, ok := src.([]any)
+		if !ok {
+			return mismatch(src, rtype)
+		}
+		slice := reflect.MakeSlice(rtype, len(list), len(list))
+		for i, elem := range list {
+			if err := Assign(slice.Index(i), elem); err != nil {
+				return err
+			}
+		}
+		dst.Set(slice)
+		return nil
+	case reflect.Array:
+		list, ok := src.([]any)
+		if !ok || len(list) != rtype.Len() {
+			return mismatch(src, rtype)
+		}
+		for i, elem := range list {
+			if err := Assign(dst.Index(i), elem); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		object, ok := src.(map[string]any)
+		if !ok {
+			return mismatch(src, rtype)
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMapWithSize(rtype, len(object)))
+		}
+		for name, value := range object {
+			key := reflect.New(rtype.Key()).Elem()
+			if err := Assign(key, ParseKey(name, rtype.Key())); err != nil {
+				return err
+			}
+			elem := reflect.New(rtype.Elem()).Elem()
+			if err := Assign(elem, value); err != nil {
+				return err
+			}
+			dst.SetMapIndex(key, elem)
+		}
+		return nil
+	case reflect.Struct:
+		object, ok := src.(map[string]any)
+		if !ok {
+			return mismatch(src, rtype)
+		}
+		fields := fields(rtype)
+		for name, value := range object {
+			for _, field := range fields {
+				if field.name == name || strings.EqualFold(field.name, name) {
+					if err := Assign(dst.FieldByIndex(field.index), value); err != nil {
+						return fmt.Errorf("json: field %s: %w", name, err)
+					}
+					break
+				}
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("json: unsupported type: %s", rtype)
+}
+
+// ParseKey returns the generic value to assign to a dictionary key of the given type, from the
+// string that it was encoded as.
+func ParseKey(name string, rtype reflect.Type) any {
+	switch rtype.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return json.Number(name)
+	case reflect.Bool:
+		return name == "true"
+	}
+	return name
+}
+
+// Plain converts a generic value decoded by [Decode] into the values produced by JSON.parse,
+// where numbers are float64.
+func Plain(src any) any {
+	switch v := src.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case []any:
+		for i := range v {
+			v[i] = Plain(v[i])
+		}
+	case map[string]any:
+		for key := range v {
+			v[key] = Plain(v[key])
+		}
+	}
+	return src
+}
+
+func mismatch(src any, rtype reflect.Type) error {
+	return fmt.Errorf("json: cannot unmarshal %T into %s", src, rtype)
+}
+
+// Object parses a JSON object into its member names and values, in the order that they appear
+// in data, so that dictionaries can preserve the order of their keys.
+func Object(data []byte) (names []string, values []json.RawMessage, err error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	token, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if token != json.Delim('{') {
+		return nil, nil, fmt.Errorf("json: cannot unmarshal %v into an object", token)
+	}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		names = append(names, token.(string))
+		values = append(values, value)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, nil, err
+	}
+	return names, values, nil
+}
@@ -27,12 +27,14 @@ func (variant Variant) Set(key, val Variant) bool {
 
 // Call calls a method on the variant dynamically.
 func (variant Variant) Call(method StringName, args ...Variant) (Variant, error) {
-	return Global.Variants.Call(variant, method, args...)
+	result, err := Global.Variants.Call(variant, method, args...)
+	return result, withMethod(err, method)
 }
 
 // Call a static method on a variant type.
 func (variant VariantType) Call(method StringName, args ...Variant) (Variant, error) {
-	return Global.Variants.CallStatic(variant, method, args...)
+	result, err := Global.Variants.CallStatic(variant, method, args...)
+	return result, withMethod(err, method)
 }
 
 // New calls the variant constructor with the given arguments and returns the
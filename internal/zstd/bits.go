@@ -0,0 +1,71 @@
+package zstd
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// forward reads a little-endian bitstream from the start, as used by FSE table descriptions.
+type forward struct {
+	data []byte
+	pos  int // in bits.
+}
+
+func (r *forward) read(n int) (uint32, error) {
+	if r.pos+n > len(r.data)*8 {
+		return 0, ErrCorrupt
+	}
+	var v uint32
+	for i := range n {
+		p := r.pos + i
+		v |= uint32(r.data[p>>3]>>(p&7)&1) << i
+	}
+	r.pos += n
+	return v, nil
+}
+
+// bytes returns the number of whole bytes used by the bits read so far.
+func (r *forward) bytes() int { return (r.pos + 7) / 8 }
+
+// backward reads a bitstream from the end, as used by Huffman and FSE coded data, the most
+// significant bit of the last byte marks the end of the stream.
+type backward struct {
+	data []byte
+	pos  int // number of unread bits, negative after reading past the start.
+}
+
+func newBackward(data []byte) (backward, error) {
+	if len(data) == 0 || data[len(data)-1] == 0 {
+		return backward{}, ErrCorrupt
+	}
+	return backward{data: data, pos: (len(data)-1)*8 + bits.Len8(data[len(data)-1]) - 1}, nil
+}
+
+// peek returns the next n (at most 56) bits, without consuming them, any bits before the start
+// of the stream are zeros.
+func (r *backward) peek(n int) uint64 {
+	if n == 0 || r.pos <= 0 {
+		return 0
+	}
+	low := r.pos - n
+	shift := 0
+	if low < 0 {
+		shift = -low
+		low = 0
+	}
+	var word [8]byte
+	copy(word[:], r.data[low>>3:])
+	v := binary.LittleEndian.Uint64(word[:]) >> (low & 7)
+	return (v & (1<<(n-shift) - 1)) << shift
+}
+
+func (r *backward) skip(n int) { r.pos -= n }
+
+func (r *backward) read(n int) uint64 {
+	v := r.peek(n)
+	r.pos -= n
+	return v
+}
+
+// overflow reports whether bits have been read past the start of the stream.
+func (r *backward) overflow() bool { return r.pos < 0 }
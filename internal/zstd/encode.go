@@ -0,0 +1,270 @@
+package zstd
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	minMatch    = 4
+	hashLog     = 16
+	maxDistance = 1 << 27 // keeps offset codes within the predefined distribution.
+)
+
+// Compress the input into a single frame, the output favours simplicity over ratio: literals are
+// stored raw and sequences use the predefined distributions, found with a greedy matcher.
+func Compress(in []byte) []byte {
+	out := binary.LittleEndian.AppendUint32(make([]byte, 0, len(in)/2+32), magic)
+	switch size := uint64(len(in)); {
+	case size < 256:
+		out = append(out, 0x20, byte(size))
+	case size < 65536+256:
+		out = binary.LittleEndian.AppendUint16(append(out, 1<<6|0x20), uint16(size-256))
+	case size <= 0xFFFFFFFF:
+		out = binary.LittleEndian.AppendUint32(append(out, 2<<6|0x20), uint32(size))
+	default:
+		out = binary.LittleEndian.AppendUint64(append(out, 3<<6|0x20), size)
+	}
+	var (
+		e     = encoder{in: in}
+		start = 0
+	)
+	for {
+		end := min(start+maxBlockSize, len(in))
+		last := 0
+		if end == len(in) {
+			last = 1
+		}
+		block := e.block(start, end)
+		if len(block) < end-start {
+			out = appendBlockHeader(out, last|2<<1|len(block)<<3)
+			out = append(out, block...)
+		} else {
+			out = appendBlockHeader(out, last|(end-start)<<3)
+			out = append(out, in[start:end]...)
+		}
+		if last == 1 {
+			return out
+		}
+		start = end
+	}
+}
+
+func appendBlockHeader(out []byte, header int) []byte {
+	return append(out, byte(header), byte(header>>8), byte(header>>16))
+}
+
+// sequence of literals followed by a match.
+type sequence struct {
+	literals, match, offset int
+}
+
+type encoder struct {
+	in        []byte
+	table     [1 << hashLog]int32 // positions + 1, zero when empty.
+	literals  []byte
+	sequences []sequence
+	scratch   []byte
+}
+
+func hash4(b []byte) uint32 {
+	return binary.LittleEndian.Uint32(b) * 2654435761 >> (32 - hashLog)
+}
+
+// block returns the compressed content of in[start:end].
+func (e *encoder) block(start, end int) []byte {
+	in := e.in
+	e.literals = e.literals[:0]
+	e.sequences = e.sequences[:0]
+	anchor := start
+	for i := start; i+minMatch <= end; {
+		h := hash4(in[i:])
+		candidate := int(e.table[h]) - 1
+		e.table[h] = int32(i + 1)
+		if candidate < 0 || i-candidate >= maxDistance ||
+			binary.LittleEndian.Uint32(in[candidate:]) != binary.LittleEndian.Uint32(in[i:]) {
+			i++
+			continue
+		}
+		n := minMatch
+		for i+n < end && in[candidate+n] == in[i+n] {
+			n++
+		}
+		e.literals = append(e.literals, in[anchor:i]...)
+		e.sequences = append(e.sequences, sequence{literals: i - anchor, match: n, offset: i - candidate})
+		for j := i + 1; j < i+n && j+minMatch <= end; j++ {
+			e.table[hash4(in[j:])] = int32(j + 1)
+		}
+		i += n
+		anchor = i
+	}
+	e.literals = append(e.literals, in[anchor:end]...)
+
+	out := e.scratch[:0]
+	switch size := len(e.literals); {
+	case size < 32:
+		out = append(out, byte(size<<3))
+	case size < 4096:
+		out = append(out, byte(size<<4|1<<2), byte(size>>4))
+	default:
+		out = append(out, byte(size<<4|3<<2), byte(size>>4), byte(size>>12))
+	}
+	out = append(out, e.literals...)
+	switch n := len(e.sequences); {
+	case n == 0:
+		e.scratch = append(out, 0)
+		return e.scratch
+	case n < 128:
+		out = append(out, byte(n))
+	case n < 0x7F00:
+		out = append(out, byte(n>>8+128), byte(n))
+	default:
+		out = append(out, 255, byte(n-0x7F00), byte((n-0x7F00)>>8))
+	}
+	out = append(out, 0) // predefined distributions.
+	e.scratch = e.encodeSequences(out)
+	return e.scratch
+}
+
+// codeOf returns the code with the largest base less than or equal to the value.
+func codeOf(codes []code, value int) uint8 {
+	i := len(codes) - 1
+	for int(codes[i].base) > value {
+		i--
+	}
+	return uint8(i)
+}
+
+// encodeSequences appends the bitstream of e.sequences, which is written in reverse, so that the
+// decoder reads the sequences in order.
+func (e *encoder) encodeSequences(out []byte) []byte {
+	w := bitWriter{out: out}
+	var literalLength, matchLength, offset fseEncoder
+	for i := len(e.sequences) - 1; i >= 0; i-- {
+		s := e.sequences[i]
+		ll := codeOf(literalLengthCodes[:], s.literals)
+		ml := codeOf(matchLengthCodes[:], s.match)
+		value := s.offset + 3
+		of := uint8(bits.Len(uint(value)) - 1)
+		if i == len(e.sequences)-1 {
+			matchLength.init(predefinedMatchLengthEncoder, ml)
+			offset.init(predefinedOffsetEncoder, of)
+			literalLength.init(predefinedLiteralLengthEncoder, ll)
+		} else {
+			offset.encode(&w, of)
+			matchLength.encode(&w, ml)
+			literalLength.encode(&w, ll)
+		}
+		w.add(uint64(s.literals)-uint64(literalLengthCodes[ll].base), int(literalLengthCodes[ll].bits))
+		w.add(uint64(s.match)-uint64(matchLengthCodes[ml].base), int(matchLengthCodes[ml].bits))
+		w.add(uint64(value)-1<<of, int(of))
+	}
+	matchLength.flush(&w)
+	offset.flush(&w)
+	literalLength.flush(&w)
+	return w.close()
+}
+
+// bitWriter writes a bitstream to be read backwards.
+type bitWriter struct {
+	out  []byte
+	bits uint64
+	n    int
+}
+
+func (w *bitWriter) add(v uint64, n int) {
+	w.bits |= (v & (1<<n - 1)) << w.n
+	w.n += n
+	for w.n >= 8 {
+		w.out = append(w.out, byte(w.bits))
+		w.bits >>= 8
+		w.n -= 8
+	}
+}
+
+// close the stream with the end marker.
+func (w *bitWriter) close() []byte {
+	w.add(1, 1)
+	if w.n > 0 {
+		w.out = append(w.out, byte(w.bits))
+	}
+	return w.out
+}
+
+// fseSymbolTransform describes how to encode a symbol from any state.
+type fseSymbolTransform struct {
+	deltaBits      int32
+	deltaFindState int32
+}
+
+// fseEncodingTable encodes symbols with Finite State Entropy, the inverse of an fseTable.
+type fseEncodingTable struct {
+	log     int
+	states  []uint16
+	symbols []fseSymbolTransform
+}
+
+var (
+	predefinedLiteralLengthEncoder = newFSEEncodingTable(predefinedLiteralLengths, 6)
+	predefinedMatchLengthEncoder   = newFSEEncodingTable(predefinedMatchLengths, 6)
+	predefinedOffsetEncoder        = newFSEEncodingTable(predefinedOffsets, 5)
+)
+
+func newFSEEncodingTable(counts []int16, log int) *fseEncodingTable {
+	size := 1 << log
+	symbols, _ := spread(counts, log)
+	cumulative := make([]int, len(counts)+1)
+	for s, count := range counts {
+		cumulative[s+1] = cumulative[s] + max(int(count), -int(count))
+	}
+	table := &fseEncodingTable{
+		log:     log,
+		states:  make([]uint16, size),
+		symbols: make([]fseSymbolTransform, len(counts)),
+	}
+	for u, s := range symbols {
+		table.states[cumulative[s]] = uint16(size + u)
+		cumulative[s]++
+	}
+	total := int32(0)
+	for s, count := range counts {
+		switch count {
+		case 0:
+			table.symbols[s].deltaBits = int32((log+1)<<16 - size)
+		case -1, 1:
+			table.symbols[s] = fseSymbolTransform{int32(log<<16 - size), total - 1}
+			total++
+		default:
+			maxBitsOut := log - (bits.Len(uint(count-1)) - 1)
+			minStatePlus := int32(count) << maxBitsOut
+			table.symbols[s] = fseSymbolTransform{int32(maxBitsOut<<16) - minStatePlus, total - int32(count)}
+			total += int32(count)
+		}
+	}
+	return table
+}
+
+// fseEncoder is the state of an FSE encoder.
+type fseEncoder struct {
+	table *fseEncodingTable
+	state int32
+}
+
+func (e *fseEncoder) init(table *fseEncodingTable, symbol uint8) {
+	e.table = table
+	tt := table.symbols[symbol]
+	n := (tt.deltaBits + 1<<15) >> 16
+	value := n<<16 - tt.deltaBits
+	e.state = int32(table.states[value>>n+tt.deltaFindState])
+}
+
+func (e *fseEncoder) encode(w *bitWriter, symbol uint8) {
+	tt := e.table.symbols[symbol]
+	n := (e.state + tt.deltaBits) >> 16
+	w.add(uint64(e.state), int(n))
+	e.state = int32(e.table.states[e.state>>n+tt.deltaFindState])
+}
+
+func (e *fseEncoder) flush(w *bitWriter) {
+	w.add(uint64(e.state), e.table.log)
+}
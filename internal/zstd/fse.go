@@ -0,0 +1,172 @@
+package zstd
+
+import "math/bits"
+
+// fseEntry is a state of an FSE decoding table.
+type fseEntry struct {
+	symbol uint8
+	bits   uint8  // number of bits to read for the next state.
+	base   uint16 // added to the bits read, to give the next state.
+}
+
+// fseTable decodes symbols with Finite State Entropy.
+type fseTable struct {
+	log     int
+	entries []fseEntry
+}
+
+// readCounts reads an FSE table description, returning the normalized counts of each symbol (-1
+// for symbols with a "less than one" probability), the accuracy log and the number of bytes read.
+func readCounts(data []byte, maxSymbol, maxLog int) (counts []int16, log, n int, err error) {
+	r := forward{data: data}
+	v, err := r.read(4)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	log = int(v) + 5
+	if log > maxLog {
+		return nil, 0, 0, ErrCorrupt
+	}
+	remaining := 1<<log + 1
+	threshold := 1 << log
+	nbBits := log + 1
+	previous0 := false
+	for remaining > 1 {
+		if previous0 {
+			for {
+				repeat, err := r.read(2)
+				if err != nil {
+					return nil, 0, 0, err
+				}
+				for range repeat {
+					counts = append(counts, 0)
+				}
+				if repeat != 3 {
+					break
+				}
+			}
+		}
+		if len(counts) > maxSymbol {
+			return nil, 0, 0, ErrCorrupt
+		}
+		max := 2*threshold - 1 - remaining
+		low, err := r.read(nbBits - 1)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		var count int
+		if int(low) < max {
+			count = int(low)
+		} else {
+			high, err := r.read(1)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			count = int(low) | int(high)<<(nbBits-1)
+			if count >= threshold {
+				count -= max
+			}
+		}
+		count--
+		if count < 0 {
+			remaining--
+		} else {
+			remaining -= count
+		}
+		counts = append(counts, int16(count))
+		previous0 = count == 0
+		for remaining < threshold {
+			nbBits--
+			threshold >>= 1
+		}
+	}
+	if remaining != 1 || len(counts) > maxSymbol+1 {
+		return nil, 0, 0, ErrCorrupt
+	}
+	return counts, log, r.bytes(), nil
+}
+
+// spread the symbols across a table of 1<<log states, in the same order as the reference
+// implementation, symbols with a "less than one" probability are placed at the end.
+func spread(counts []int16, log int) (symbols []uint8, high int) {
+	size := 1 << log
+	symbols = make([]uint8, size)
+	high = size - 1
+	for s, count := range counts {
+		if count == -1 {
+			symbols[high] = uint8(s)
+			high--
+		}
+	}
+	step := size>>1 + size>>3 + 3
+	mask := size - 1
+	position := 0
+	for s, count := range counts {
+		for range max(count, 0) {
+			symbols[position] = uint8(s)
+			for {
+				position = (position + step) & mask
+				if position <= high {
+					break
+				}
+			}
+		}
+	}
+	return symbols, high
+}
+
+func newFSETable(counts []int16, log int) (*fseTable, error) {
+	size := 1 << log
+	total := 0
+	for _, count := range counts {
+		total += max(int(count), -int(count)) // "less than one" probabilities take one state.
+	}
+	if total != size {
+		return nil, ErrCorrupt
+	}
+	symbols, _ := spread(counts, log)
+	next := make([]int, len(counts))
+	for s, count := range counts {
+		if count == -1 {
+			next[s] = 1
+		} else {
+			next[s] = int(count)
+		}
+	}
+	table := &fseTable{log: log, entries: make([]fseEntry, size)}
+	for u := range size {
+		s := symbols[u]
+		state := next[s]
+		next[s]++
+		nb := log - (bits.Len(uint(state)) - 1)
+		table.entries[u] = fseEntry{
+			symbol: s,
+			bits:   uint8(nb),
+			base:   uint16(state<<nb - size),
+		}
+	}
+	return table, nil
+}
+
+// rleTable always decodes the same symbol.
+func rleTable(symbol uint8) *fseTable {
+	return &fseTable{log: 0, entries: []fseEntry{{symbol: symbol}}}
+}
+
+// fseState is the state of an FSE decoder.
+type fseState struct {
+	table *fseTable
+	state int
+}
+
+func (s *fseState) init(table *fseTable, r *backward) {
+	s.table = table
+	s.state = int(r.read(table.log))
+}
+
+func (s *fseState) symbol() uint8 { return s.table.entries[s.state].symbol }
+
+func (s *fseState) update(r *backward) {
+	entry := s.table.entries[s.state]
+	s.state = int(entry.base) + int(r.read(int(entry.bits)))
+}
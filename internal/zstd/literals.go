@@ -0,0 +1,257 @@
+package zstd
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const maxHuffmanBits = 11
+
+// huffmanTable decodes literals, indexed by the next maxBits of the stream.
+type huffmanTable struct {
+	maxBits int
+	symbols []uint8
+	lengths []uint8
+}
+
+// readLiterals reads the literals section of a block into d.literals, returning its size.
+func (d *decoder) readLiterals(in []byte) (int, error) {
+	if len(in) < 1 {
+		return 0, ErrCorrupt
+	}
+	kind := in[0] & 3
+	format := in[0] >> 2 & 3
+	if kind < 2 {
+		var size, header int
+		switch format {
+		case 0, 2:
+			size, header = int(in[0]>>3), 1
+		case 1:
+			if len(in) < 2 {
+				return 0, ErrCorrupt
+			}
+			size, header = int(in[0]>>4)|int(in[1])<<4, 2
+		case 3:
+			if len(in) < 3 {
+				return 0, ErrCorrupt
+			}
+			size, header = int(in[0]>>4)|int(in[1])<<4|int(in[2])<<12, 3
+		}
+		if size > maxBlockSize {
+			return 0, ErrCorrupt
+		}
+		if kind == 0 {
+			if len(in) < header+size {
+				return 0, ErrCorrupt
+			}
+			d.literals = append(d.literals[:0], in[header:header+size]...)
+			return header + size, nil
+		}
+		if len(in) < header+1 {
+			return 0, ErrCorrupt
+		}
+		d.literals = d.literals[:0]
+		for range size {
+			d.literals = append(d.literals, in[header])
+		}
+		return header + 1, nil
+	}
+	var (
+		header  = [4]int{3, 3, 4, 5}[format]
+		width   = [4]uint{10, 10, 14, 18}[format]
+		streams = 4
+	)
+	if format == 0 {
+		streams = 1
+	}
+	if len(in) < header {
+		return 0, ErrCorrupt
+	}
+	var h uint64
+	for i := range header {
+		h |= uint64(in[i]) << (8 * i)
+	}
+	size := int(h >> 4 & (1<<width - 1))
+	compressed := int(h >> (4 + width) & (1<<width - 1))
+	if size > maxBlockSize || len(in) < header+compressed {
+		return 0, ErrCorrupt
+	}
+	data := in[header : header+compressed]
+	if kind == 2 {
+		table, n, err := readHuffman(data)
+		if err != nil {
+			return 0, err
+		}
+		d.huffman = table
+		data = data[n:]
+	} else if d.huffman == nil {
+		return 0, ErrCorrupt
+	}
+	d.literals = d.literals[:0]
+	if streams == 1 {
+		if err := d.decodeHuffman(data, size); err != nil {
+			return 0, err
+		}
+		return header + compressed, nil
+	}
+	if len(data) < 6 {
+		return 0, ErrCorrupt
+	}
+	var (
+		sizes = [4]int{
+			int(binary.LittleEndian.Uint16(data)),
+			int(binary.LittleEndian.Uint16(data[2:])),
+			int(binary.LittleEndian.Uint16(data[4:])),
+		}
+		each = (size + 3) / 4
+	)
+	data = data[6:]
+	sizes[3] = len(data) - sizes[0] - sizes[1] - sizes[2]
+	if sizes[3] < 0 || 3*each > size {
+		return 0, ErrCorrupt
+	}
+	for i, n := range sizes {
+		regenerated := each
+		if i == 3 {
+			regenerated = size - 3*each
+		}
+		if err := d.decodeHuffman(data[:n], regenerated); err != nil {
+			return 0, err
+		}
+		data = data[n:]
+	}
+	return header + compressed, nil
+}
+
+// decodeHuffman appends n literals, decoded from a single Huffman coded stream.
+func (d *decoder) decodeHuffman(data []byte, n int) error {
+	r, err := newBackward(data)
+	if err != nil {
+		return err
+	}
+	table := d.huffman
+	for range n {
+		i := r.peek(table.maxBits)
+		d.literals = append(d.literals, table.symbols[i])
+		r.skip(int(table.lengths[i]))
+	}
+	if r.pos != 0 {
+		return ErrCorrupt
+	}
+	return nil
+}
+
+// readHuffman reads a Huffman tree description, returning the decoding table and the number of
+// bytes read.
+func readHuffman(in []byte) (*huffmanTable, int, error) {
+	if len(in) < 1 {
+		return nil, 0, ErrCorrupt
+	}
+	var (
+		weights []uint8
+		n       int
+	)
+	if header := int(in[0]); header >= 128 {
+		count := header - 127
+		n = 1 + (count+1)/2
+		if len(in) < n {
+			return nil, 0, ErrCorrupt
+		}
+		for i := range count {
+			b := in[1+i/2]
+			if i%2 == 0 {
+				weights = append(weights, b>>4)
+			} else {
+				weights = append(weights, b&15)
+			}
+		}
+	} else {
+		n = 1 + header
+		if len(in) < n {
+			return nil, 0, ErrCorrupt
+		}
+		var err error
+		if weights, err = readWeights(in[1:n]); err != nil {
+			return nil, 0, err
+		}
+	}
+	if len(weights) > 255 {
+		return nil, 0, ErrCorrupt
+	}
+	total := 0
+	for _, w := range weights {
+		if w > maxHuffmanBits {
+			return nil, 0, ErrCorrupt
+		}
+		if w > 0 {
+			total += 1 << (w - 1)
+		}
+	}
+	if total == 0 {
+		return nil, 0, ErrCorrupt
+	}
+	maxBits := bits.Len(uint(total))
+	left := 1<<maxBits - total
+	if maxBits > maxHuffmanBits || left&(left-1) != 0 {
+		return nil, 0, ErrCorrupt
+	}
+	weights = append(weights, uint8(bits.Len(uint(left))))
+	table := &huffmanTable{
+		maxBits: maxBits,
+		symbols: make([]uint8, 1<<maxBits),
+		lengths: make([]uint8, 1<<maxBits),
+	}
+	// codes are assigned in order of increasing weight, then by symbol.
+	position := 0
+	for w := 1; w <= maxBits; w++ {
+		for s, weight := range weights {
+			if int(weight) != w {
+				continue
+			}
+			span := 1 << (w - 1)
+			for i := position; i < position+span; i++ {
+				table.symbols[i] = uint8(s)
+				table.lengths[i] = uint8(maxBits + 1 - w)
+			}
+			position += span
+		}
+	}
+	return table, n, nil
+}
+
+// readWeights reads FSE compressed Huffman weights.
+func readWeights(in []byte) ([]uint8, error) {
+	counts, log, n, err := readCounts(in, 255, 6)
+	if err != nil {
+		return nil, err
+	}
+	table, err := newFSETable(counts, log)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newBackward(in[n:])
+	if err != nil {
+		return nil, err
+	}
+	var (
+		weights    []uint8
+		one, other fseState
+	)
+	one.init(table, &r)
+	other.init(table, &r)
+	for {
+		if len(weights) > 253 {
+			return nil, ErrCorrupt
+		}
+		weights = append(weights, one.symbol())
+		one.update(&r)
+		if r.overflow() {
+			return append(weights, other.symbol()), nil
+		}
+		weights = append(weights, other.symbol())
+		other.update(&r)
+		if r.overflow() {
+			return append(weights, one.symbol()), nil
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package zstd
+
+// code describes the value of a literal length, match length or offset code, as its baseline and
+// the number of extra bits added to it.
+type code struct {
+	base uint32
+	bits uint8
+}
+
+var literalLengthCodes = func() (codes [36]code) {
+	for i := range 16 {
+		codes[i] = code{uint32(i), 0}
+	}
+	copy(codes[16:], []code{
+		{16, 1}, {18, 1}, {20, 1}, {22, 1}, {24, 2}, {28, 2}, {32, 3}, {40, 3}, {48, 4}, {64, 6},
+		{128, 7}, {256, 8}, {512, 9}, {1024, 10}, {2048, 11}, {4096, 12}, {8192, 13}, {16384, 14},
+		{32768, 15}, {65536, 16},
+	})
+	return
+}()
+
+var matchLengthCodes = func() (codes [53]code) {
+	for i := range 32 {
+		codes[i] = code{uint32(i + 3), 0}
+	}
+	copy(codes[32:], []code{
+		{35, 1}, {37, 1}, {39, 1}, {41, 1}, {43, 2}, {47, 2}, {51, 3}, {59, 3}, {67, 4}, {83, 4},
+		{99, 5}, {131, 7}, {259, 8}, {515, 9}, {1027, 10}, {2051, 11}, {4099, 12}, {8195, 13},
+		{16387, 14}, {32771, 15}, {65539, 16},
+	})
+	return
+}()
+
+const (
+	maxLiteralLengthCode = 35
+	maxMatchLengthCode   = 52
+	maxOffsetCode        = 31
+
+	maxLiteralLengthLog = 9
+	maxMatchLengthLog   = 9
+	maxOffsetLog        = 8
+)
+
+// predefined distributions, used when a block does not describe its own.
+var (
+	predefinedLiteralLengths = []int16{
+		4, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1, 1, 1, 2, 2, 2, 2, 2, 2, 2, 2, 2, 3, 2, 1, 1, 1, 1, 1,
+		-1, -1, -1, -1,
+	}
+	predefinedMatchLengths = []int16{
+		1, 4, 3, 2, 2, 2, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1, -1, -1,
+	}
+	predefinedOffsets = []int16{
+		1, 1, 1, 1, 1, 1, 2, 2, 2, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, -1, -1, -1, -1, -1,
+	}
+
+	predefinedLiteralLengthTable = mustFSETable(predefinedLiteralLengths, 6)
+	predefinedMatchLengthTable   = mustFSETable(predefinedMatchLengths, 6)
+	predefinedOffsetTable        = mustFSETable(predefinedOffsets, 5)
+)
+
+func mustFSETable(counts []int16, log int) *fseTable {
+	table, err := newFSETable(counts, log)
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+// readTable reads the decoding table for one of the sequence symbols, given its compression mode,
+// returning the number of bytes read.
+func readTable(in []byte, mode byte, previous, predefined *fseTable, maxSymbol, maxLog int) (*fseTable, int, error) {
+	switch mode {
+	case 0:
+		return predefined, 0, nil
+	case 1:
+		if len(in) < 1 || int(in[0]) > maxSymbol {
+			return nil, 0, ErrCorrupt
+		}
+		return rleTable(in[0]), 1, nil
+	case 2:
+		counts, log, n, err := readCounts(in, maxSymbol, maxLog)
+		if err != nil {
+			return nil, 0, err
+		}
+		table, err := newFSETable(counts, log)
+		return table, n, err
+	default:
+		if previous == nil {
+			return nil, 0, ErrCorrupt
+		}
+		return previous, 0, nil
+	}
+}
+
+// sequences decodes the sequences section of a block and executes them, using d.literals.
+func (d *decoder) sequences(in []byte) error {
+	if len(in) < 1 {
+		return ErrCorrupt
+	}
+	var count, pos int
+	switch b := int(in[0]); {
+	case b < 128:
+		count, pos = b, 1
+	case b < 255:
+		if len(in) < 2 {
+			return ErrCorrupt
+		}
+		count, pos = (b-128)<<8+int(in[1]), 2
+	default:
+		if len(in) < 3 {
+			return ErrCorrupt
+		}
+		count, pos = int(in[1])+int(in[2])<<8+0x7F00, 3
+	}
+	if count == 0 {
+		if pos != len(in) {
+			return ErrCorrupt
+		}
+		if err := d.grow(len(d.literals)); err != nil {
+			return err
+		}
+		d.out = append(d.out, d.literals...)
+		return nil
+	}
+	if len(in) < pos+1 {
+		return ErrCorrupt
+	}
+	modes := in[pos]
+	pos++
+	if modes&3 != 0 {
+		return ErrCorrupt
+	}
+	var (
+		n   int
+		err error
+	)
+	if d.literalLengths, n, err = readTable(in[pos:], modes>>6, d.literalLengths, predefinedLiteralLengthTable, maxLiteralLengthCode, maxLiteralLengthLog); err != nil {
+		return err
+	}
+	pos += n
+	if d.offsets, n, err = readTable(in[pos:], modes>>4&3, d.offsets, predefinedOffsetTable, maxOffsetCode, maxOffsetLog); err != nil {
+		return err
+	}
+	pos += n
+	if d.matchLengths, n, err = readTable(in[pos:], modes>>2&3, d.matchLengths, predefinedMatchLengthTable, maxMatchLengthCode, maxMatchLengthLog); err != nil {
+		return err
+	}
+	pos += n
+	r, err := newBackward(in[pos:])
+	if err != nil {
+		return err
+	}
+	var literalLength, offset, matchLength fseState
+	literalLength.init(d.literalLengths, &r)
+	offset.init(d.offsets, &r)
+	matchLength.init(d.matchLengths, &r)
+	literals := d.literals
+	for i := range count {
+		offsetCode := offset.symbol()
+		literalCode := literalLength.symbol()
+		matchCode := matchLength.symbol()
+		if literalCode > maxLiteralLengthCode || matchCode > maxMatchLengthCode || offsetCode > maxOffsetCode {
+			return ErrCorrupt
+		}
+		value := 1<<offsetCode + int(r.read(int(offsetCode)))
+		ml := matchLengthCodes[matchCode]
+		match := int(ml.base) + int(r.read(int(ml.bits)))
+		ll := literalLengthCodes[literalCode]
+		length := int(ll.base) + int(r.read(int(ll.bits)))
+		if i < count-1 {
+			literalLength.update(&r)
+			matchLength.update(&r)
+			offset.update(&r)
+		}
+		if r.overflow() {
+			return ErrCorrupt
+		}
+		distance := d.repeat(value, length)
+		if length > len(literals) {
+			return ErrCorrupt
+		}
+		if err := d.grow(length + match); err != nil {
+			return err
+		}
+		d.out = append(d.out, literals[:length]...)
+		literals = literals[length:]
+		from := len(d.out) - distance
+		if distance <= 0 || from < d.start {
+			return ErrCorrupt
+		}
+		for j := range match {
+			d.out = append(d.out, d.out[from+j])
+		}
+	}
+	if r.pos != 0 {
+		return ErrCorrupt
+	}
+	if err := d.grow(len(literals)); err != nil {
+		return err
+	}
+	d.out = append(d.out, literals...)
+	return nil
+}
+
+// repeat resolves an offset value into a distance, updating the repeated offsets.
+func (d *decoder) repeat(value, literalLength int) int {
+	if value > 3 {
+		distance := value - 3
+		d.repeats = [3]int{distance, d.repeats[0], d.repeats[1]}
+		return distance
+	}
+	index := value - 1
+	if literalLength == 0 {
+		index++
+	}
+	switch index {
+	case 0:
+		return d.repeats[0]
+	case 1:
+		d.repeats = [3]int{d.repeats[1], d.repeats[0], d.repeats[2]}
+	case 2:
+		d.repeats = [3]int{d.repeats[2], d.repeats[0], d.repeats[1]}
+	default:
+		distance := max(d.repeats[0]-1, 1)
+		d.repeats = [3]int{distance, d.repeats[0], d.repeats[1]}
+	}
+	return d.repeats[0]
+}
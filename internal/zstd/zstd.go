@@ -0,0 +1,202 @@
+// Package zstd is a pure Go implementation of Zstandard (RFC 8878), as used by the engine for its
+// CompressionZstd mode. Dictionaries are not supported.
+package zstd
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	// ErrCorrupt is returned when decompressing invalid Zstandard data.
+	ErrCorrupt = errors.New("corrupt Zstandard data")
+	// ErrTooLarge is returned when the decompressed data would exceed the requested size.
+	ErrTooLarge = errors.New("decompressed Zstandard data is too large")
+	// ErrDictionary is returned when a frame requires a dictionary.
+	ErrDictionary = errors.New("Zstandard dictionaries are not supported")
+	// ErrChecksum is returned when the checksum of a frame does not match its content.
+	ErrChecksum = errors.New("Zstandard checksum mismatch")
+)
+
+const (
+	magic          = 0xFD2FB528
+	skippableMagic = 0x184D2A50
+	skippableMask  = 0xFFFFFFF0
+	maxBlockSize   = 128 << 10
+)
+
+// Decompress all of the frames in the input, up to a maximum of size bytes (a negative size means
+// there is no limit).
+func Decompress(in []byte, size int) ([]byte, error) {
+	var d decoder
+	d.limit = size
+	for len(in) > 0 {
+		if len(in) < 4 {
+			return nil, ErrCorrupt
+		}
+		switch m := binary.LittleEndian.Uint32(in); {
+		case m&skippableMask == skippableMagic:
+			if len(in) < 8 {
+				return nil, ErrCorrupt
+			}
+			n := binary.LittleEndian.Uint32(in[4:])
+			if uint64(len(in)-8) < uint64(n) {
+				return nil, ErrCorrupt
+			}
+			in = in[8+n:]
+		case m == magic:
+			n, err := d.frame(in[4:])
+			if err != nil {
+				return nil, err
+			}
+			in = in[4+n:]
+		default:
+			return nil, ErrCorrupt
+		}
+	}
+	return d.out, nil
+}
+
+// decoder holds the state shared between the blocks of a frame.
+type decoder struct {
+	out   []byte
+	limit int
+	start int // of the current frame within out.
+
+	huffman        *huffmanTable
+	literalLengths *fseTable
+	offsets        *fseTable
+	matchLengths   *fseTable
+	repeats        [3]int
+	literals       []byte
+}
+
+// frame decodes a single frame (after the magic number), returning the number of bytes read.
+func (d *decoder) frame(in []byte) (int, error) {
+	if len(in) < 1 {
+		return 0, ErrCorrupt
+	}
+	descriptor := in[0]
+	if descriptor&0x08 != 0 {
+		return 0, ErrCorrupt
+	}
+	var (
+		sizeFlag      = descriptor >> 6
+		singleSegment = descriptor&0x20 != 0
+		checksum      = descriptor&0x04 != 0
+		dictionaryIDs = [4]int{0, 1, 2, 4}[descriptor&3]
+		contentSizes  = [4]int{0, 2, 4, 8}[sizeFlag]
+		pos           = 1
+	)
+	if sizeFlag == 0 && singleSegment {
+		contentSizes = 1
+	}
+	if !singleSegment {
+		pos++
+	}
+	if len(in) < pos+dictionaryIDs+contentSizes {
+		return 0, ErrCorrupt
+	}
+	var dictionary uint64
+	for i := range dictionaryIDs {
+		dictionary |= uint64(in[pos+i]) << (8 * i)
+	}
+	if dictionary != 0 {
+		return 0, ErrDictionary
+	}
+	pos += dictionaryIDs
+	contentSize := int64(-1)
+	if contentSizes > 0 {
+		var v uint64
+		for i := range contentSizes {
+			v |= uint64(in[pos+i]) << (8 * i)
+		}
+		if contentSizes == 2 {
+			v += 256
+		}
+		contentSize = int64(v)
+		if d.limit >= 0 && v > uint64(d.limit-len(d.out)) {
+			return 0, ErrTooLarge
+		}
+	}
+	pos += contentSizes
+
+	d.start = len(d.out)
+	d.huffman = nil
+	d.literalLengths, d.offsets, d.matchLengths = nil, nil, nil
+	d.repeats = [3]int{1, 4, 8}
+	for {
+		if len(in) < pos+3 {
+			return 0, ErrCorrupt
+		}
+		header := int(in[pos]) | int(in[pos+1])<<8 | int(in[pos+2])<<16
+		pos += 3
+		last := header&1 != 0
+		size := header >> 3
+		switch header >> 1 & 3 {
+		case 0:
+			if len(in) < pos+size {
+				return 0, ErrCorrupt
+			}
+			if err := d.grow(size); err != nil {
+				return 0, err
+			}
+			d.out = append(d.out, in[pos:pos+size]...)
+			pos += size
+		case 1:
+			if len(in) < pos+1 {
+				return 0, ErrCorrupt
+			}
+			if err := d.grow(size); err != nil {
+				return 0, err
+			}
+			for range size {
+				d.out = append(d.out, in[pos])
+			}
+			pos++
+		case 2:
+			if size > maxBlockSize || len(in) < pos+size {
+				return 0, ErrCorrupt
+			}
+			if err := d.block(in[pos : pos+size]); err != nil {
+				return 0, err
+			}
+			pos += size
+		default:
+			return 0, ErrCorrupt
+		}
+		if last {
+			break
+		}
+	}
+	if contentSize >= 0 && int64(len(d.out)-d.start) != contentSize {
+		return 0, ErrCorrupt
+	}
+	if checksum {
+		if len(in) < pos+4 {
+			return 0, ErrCorrupt
+		}
+		if uint32(xxh64(d.out[d.start:])) != binary.LittleEndian.Uint32(in[pos:]) {
+			return 0, ErrChecksum
+		}
+		pos += 4
+	}
+	return pos, nil
+}
+
+// grow checks that n more bytes can be written to the output.
+func (d *decoder) grow(n int) error {
+	if d.limit >= 0 && n > d.limit-len(d.out) {
+		return ErrTooLarge
+	}
+	return nil
+}
+
+// block decodes a compressed block.
+func (d *decoder) block(in []byte) error {
+	n, err := d.readLiterals(in)
+	if err != nil {
+		return err
+	}
+	return d.sequences(in[n:])
+}
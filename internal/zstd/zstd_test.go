@@ -0,0 +1,141 @@
+package zstd_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"graphics.gd/internal/zstd"
+)
+
+// compressed with the reference implementation (zstd -19), it has a checksum, Huffman coded
+// literals and FSE coded sequences.
+const reference = "28b52ffd6451064504000287161670d90150fa43e90fa58fffff0a725744444eaffa7e9aeefe327bd3ffa972bfe7b4" +
+	"fd05cdbf9ccfecbfad19b8dd7ef55c758e59371ffbb36fabe773020854a0b609cd645c105291632d9841073e18340dc81" +
+	"08a25a19154180428a8311c7bcffa1bb09b03113404ffffef0faaa8ab2447a4a65655af781bd1a4a5a13449638d19280" +
+	"490aa658e2878"
+
+func text() []byte {
+	var b strings.Builder
+	for i := range 40 {
+		fmt.Fprintf(&b, "%d the quick brown fox jumps over the lazy dog\n", i*i%97)
+	}
+	return []byte(b.String())
+}
+
+func TestDecompress(t *testing.T) {
+	in, err := hex.DecodeString(reference)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := zstd.Decompress(in, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(out, text()) {
+		t.Fatalf("unexpected output %q", out)
+	}
+	if _, err := zstd.Decompress(in, 100); !errors.Is(err, zstd.ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+	corrupt := bytes.Clone(in)
+	corrupt[len(corrupt)-5] ^= 1
+	if _, err := zstd.Decompress(corrupt, -1); err == nil {
+		t.Fatal("expected an error for corrupt data")
+	}
+}
+
+// The frames in testdata were compressed with the zstd 1.5.6 command line tool, "name.level.zst"
+// at the given level, with --no-check at level 3 to match the engine's default of
+// compress(COMPRESSION_ZSTD). Large enough inputs use several blocks, raw, RLE and compressed.
+func TestDecompressReference(t *testing.T) {
+	random := make([]byte, 5000)
+	rand.New(rand.NewSource(1)).Read(random)
+	inputs := map[string][]byte{
+		"text":     text(),
+		"repeated": bytes.Repeat(text(), 200),
+		"zeros":    make([]byte, 200000),
+		"random":   random,
+		"mixed":    append(append(bytes.Repeat(text(), 50), random...), make([]byte, 150000)...),
+	}
+	frames, err := filepath.Glob(filepath.Join("testdata", "*.zst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) == 0 {
+		t.Fatal("no reference frames in testdata")
+	}
+	for _, frame := range frames {
+		name, _, _ := strings.Cut(filepath.Base(frame), ".")
+		expect, ok := inputs[name]
+		if !ok {
+			t.Fatalf("%s: unknown input %q", frame, name)
+		}
+		in, err := os.ReadFile(frame)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := zstd.Decompress(in, len(expect))
+		if err != nil {
+			t.Errorf("%s: %v", frame, err)
+			continue
+		}
+		if !bytes.Equal(out, expect) {
+			t.Errorf("%s: decompressed %d bytes that differ from the input", frame, len(out))
+		}
+	}
+}
+
+// TestCompressReference checks that the reference implementation can decompress our frames, it is
+// skipped unless the zstd command line tool is installed.
+func TestCompressReference(t *testing.T) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		t.Skip("zstd is not installed")
+	}
+	dir := t.TempDir()
+	for i, in := range [][]byte{text(), bytes.Repeat(text(), 200), make([]byte, 200000)} {
+		frame := filepath.Join(dir, fmt.Sprint(i, ".zst"))
+		if err := os.WriteFile(frame, zstd.Compress(in), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		out, err := exec.Command("zstd", "-q", "-d", "-c", frame).Output()
+		if err != nil {
+			t.Fatalf("zstd could not decompress %d bytes: %v", len(in), err)
+		}
+		if !bytes.Equal(out, in) {
+			t.Fatalf("zstd decompressed %d bytes that differ from the input", len(in))
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	random := make([]byte, 300000)
+	rand.New(rand.NewSource(1)).Read(random)
+	for _, in := range [][]byte{
+		nil,
+		[]byte("abc"),
+		text(),
+		bytes.Repeat(text(), 200), // several blocks.
+		make([]byte, 200000),
+		random,
+	} {
+		compressed := zstd.Compress(in)
+		out, err := zstd.Decompress(compressed, len(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(out, in) {
+			t.Fatalf("round trip of %d bytes failed", len(in))
+		}
+	}
+	if n := len(zstd.Compress(text())); n > len(text())/2 {
+		t.Fatalf("poor compression, %d bytes", n)
+	}
+}
@@ -30,11 +30,23 @@ var classDB internal.ExtensionToken
 var dlsymGD func(string) unsafe.Pointer
 
 func init() {
+	standalone = func() bool { return pause_main == nil && internal.HostedMainLoop == nil }
 	internal.Global = api.Import[internal.API](stub.API, "", errors.New("gdextension not linked"))
 }
 
 //export loadExtension
 func loadExtension(lookupFunc uintptr, classes, configuration unsafe.Pointer) uint8 {
+	return LoadExtension(lookupFunc, classes, configuration)
+}
+
+// LoadExtension initializes graphics.gd as a GDExtension, it is only needed by libraries that
+// export their own entry_symbol, in order to be loaded alongside other graphics.gd extensions:
+//
+//	//export loadEditorTools
+//	func loadEditorTools(lookupFunc uintptr, classes, configuration unsafe.Pointer) uint8 {
+//		return startup.LoadExtension(lookupFunc, classes, configuration)
+//	}
+func LoadExtension(lookupFunc uintptr, classes, configuration unsafe.Pointer) uint8 {
 	dlsymGD = func(s string) unsafe.Pointer {
 		return get_proc_address(lookupFunc, s)
 	}
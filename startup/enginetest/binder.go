@@ -0,0 +1,146 @@
+package enginetest
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	gd "graphics.gd/internal"
+	"graphics.gd/internal/callframe"
+)
+
+// native is an engine function implemented in Go. Each parameter and result must be one of the
+// value types used to represent engine values, where any represents a Variant and a trailing
+// ...any parameter accepts a variable number of Variants. Methods receive their object as the
+// first parameter, unless they are static.
+type native struct {
+	fn       reflect.Value
+	in       []gd.VariantType
+	out      gd.VariantType
+	returns  bool
+	variadic bool
+	static   bool
+}
+
+var variadicType = reflect.TypeFor[[]any]()
+
+func bind(fn any) *native {
+	rvalue := reflect.ValueOf(fn)
+	ftype := rvalue.Type()
+	n := &native{fn: rvalue, variadic: ftype.IsVariadic()}
+	for i := range ftype.NumIn() {
+		if n.variadic && i == ftype.NumIn()-1 {
+			if ftype.In(i) != variadicType {
+				panic(fmt.Sprintf("enginetest: variadic native %s must accept ...any", ftype))
+			}
+			break
+		}
+		n.in = append(n.in, variantTypeOf(ftype.In(i)))
+	}
+	switch ftype.NumOut() {
+	case 0:
+	case 1:
+		n.out, n.returns = variantTypeOf(ftype.Out(0)), true
+	default:
+		panic(fmt.Sprintf("enginetest: native %s must return at most one value", ftype))
+	}
+	return n
+}
+
+func variantTypeOf(rtype reflect.Type) gd.VariantType {
+	for vtype, candidate := range valueTypes {
+		if candidate == rtype {
+			return gd.VariantType(vtype)
+		}
+	}
+	panic(fmt.Sprintf("enginetest: %s does not represent an engine value", rtype))
+}
+
+// call the native with the given values, which must already be of the expected types.
+func (n *native) call(values []any) any {
+	args := make([]reflect.Value, len(values))
+	for i, value := range values {
+		if i < len(n.in) && n.in[i] != gd.TypeNil {
+			args[i] = reflect.ValueOf(value)
+			if value == nil {
+				args[i] = reflect.Zero(valueTypes[n.in[i]])
+			}
+			continue
+		}
+		args[i] = reflect.ValueOf(&value).Elem()
+	}
+	results := n.fn.Call(args)
+	if n.returns {
+		return results[0].Interface()
+	}
+	return nil
+}
+
+// pointerCall calls the native with arguments laid out in engine memory, starting with the given
+// leading values, count is the number of arguments available for a variadic native.
+func (n *native) pointerCall(leading []any, args []unsafe.Pointer, ret unsafe.Pointer) {
+	values := append(make([]any, 0, len(n.in)+len(args)), leading...)
+	for i, vtype := range n.in[len(leading):] {
+		values = append(values, read(vtype, args[i]))
+	}
+	if n.variadic {
+		for _, arg := range args[len(n.in)-len(leading):] {
+			values = append(values, readVariant(arg))
+		}
+	}
+	result := n.call(values)
+	if n.returns {
+		// RefCounted objects returned to Go are referenced on its behalf, as Go will unreference
+		// them when they are freed.
+		if obj, ok := result.(*object); ok && obj != nil && obj.class.is("RefCounted") {
+			obj.refcount++
+		}
+		write(n.out, ret, result)
+	}
+}
+
+// variantCall calls the native with arguments converted from Variants, reporting a [gd.CallError]
+// when they do not match the native's parameters.
+func (n *native) variantCall(leading []any, args []any) (any, error) {
+	fixed := len(n.in) - len(leading)
+	if len(args) < fixed {
+		return nil, gd.CallError{ErrorType: gd.ErrTooFewArguments, Expected: int32(fixed)}
+	}
+	if len(args) > fixed && !n.variadic {
+		return nil, gd.CallError{ErrorType: gd.ErrTooManyArguments, Expected: int32(fixed)}
+	}
+	values := append(make([]any, 0, len(n.in)+len(args)), leading...)
+	for i, arg := range args {
+		if i >= fixed {
+			values = append(values, arg)
+			continue
+		}
+		vtype := n.in[len(leading)+i]
+		if vtype != gd.TypeNil && arg != nil && typeOf(arg) != vtype {
+			if !convertible(typeOf(arg), vtype) {
+				return nil, gd.CallError{ErrorType: gd.ErrInvalidArgument, Argument: int32(i), Expected: int32(vtype)}
+			}
+			arg = convert(arg, vtype)
+		}
+		values = append(values, arg)
+	}
+	return n.call(values), nil
+}
+
+// pointersOf returns the first n argument pointers of a call frame.
+func pointersOf(args callframe.Args, n int) []unsafe.Pointer {
+	ptrs := make([]unsafe.Pointer, n)
+	for i := range ptrs {
+		ptrs[i] = args.Index(i).UnsafePointer()
+	}
+	return ptrs
+}
+
+// addressOf returns the pointers as an engine array of argument pointers, the slice must be kept
+// alive until the call returns.
+func addressOf(ptrs []unsafe.Pointer) gd.Address {
+	if len(ptrs) == 0 {
+		return 0
+	}
+	return gd.Address(unsafe.Pointer(&ptrs[0]))
+}
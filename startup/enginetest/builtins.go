@@ -0,0 +1,1245 @@
+package enginetest
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	gd "graphics.gd/internal"
+	"graphics.gd/internal/callframe"
+)
+
+// typeNames are the engine names of each variant type.
+var typeNames = [gd.TypeMax]string{
+	"Nil", "bool", "int", "float", "String", "Vector2", "Vector2i", "Rect2", "Rect2i", "Vector3",
+	"Vector3i", "Transform2D", "Vector4", "Vector4i", "Plane", "Quaternion", "AABB", "Basis",
+	"Transform3D", "Projection", "Color", "StringName", "NodePath", "RID", "Object", "Callable",
+	"Signal", "Dictionary", "Array", "PackedByteArray", "PackedInt32Array", "PackedInt64Array",
+	"PackedFloat32Array", "PackedFloat64Array", "PackedStringArray", "PackedVector2Array",
+	"PackedVector3Array", "PackedColorArray", "PackedVector4Array",
+}
+
+// builtins implements the methods of the builtin variant types supported by the fake engine, the
+// first parameter of each method is the value the method is called on. StringName and NodePath
+// values fall back to the methods of String.
+var builtins = map[gd.VariantType]map[string]*native{
+	gd.TypeString:             bindAll(stringMethods),
+	gd.TypeStringName:         bindAll(stringNameMethods),
+	gd.TypeNodePath:           bindAll(nodePathMethods),
+	gd.TypeArray:              bindAll(arrayMethods),
+	gd.TypeDictionary:         bindAll(dictionaryMethods),
+	gd.TypeCallable:           bindAll(callableMethods),
+	gd.TypeSignal:             bindAll(signalMethods),
+	gd.TypePackedByteArray:    bindAll(packedMethods(func(v int64) byte { return byte(v) }, func(v byte) int64 { return int64(v) })),
+	gd.TypePackedInt32Array:   bindAll(packedMethods(func(v int64) int32 { return int32(v) }, func(v int32) int64 { return int64(v) })),
+	gd.TypePackedInt64Array:   bindAll(packedMethods(same[int64], same[int64])),
+	gd.TypePackedFloat32Array: bindAll(packedMethods(func(v float64) float32 { return float32(v) }, func(v float32) float64 { return float64(v) })),
+	gd.TypePackedFloat64Array: bindAll(packedMethods(same[float64], same[float64])),
+	gd.TypePackedStringArray:  bindAll(packedMethods(same[str], same[str])),
+	gd.TypePackedVector2Array: bindAll(packedMethods(same[gd.Vector2], same[gd.Vector2])),
+	gd.TypePackedVector3Array: bindAll(packedMethods(same[gd.Vector3], same[gd.Vector3])),
+	gd.TypePackedVector4Array: bindAll(packedMethods(same[gd.Vector4], same[gd.Vector4])),
+	gd.TypePackedColorArray:   bindAll(packedMethods(same[gd.Color], same[gd.Color])),
+}
+
+func same[T any](v T) T { return v }
+
+// builtinMethod returns the Go implementation of the builtin method, or nil.
+func builtinMethod(vtype gd.VariantType, method string) *native {
+	if n, ok := builtins[vtype][method]; ok {
+		return n
+	}
+	if vtype == gd.TypeStringName || vtype == gd.TypeNodePath {
+		return builtins[gd.TypeString][method]
+	}
+	return nil
+}
+
+// callBuiltin calls the builtin method on the value, with the given arguments.
+func callBuiltin(value any, method string, args []any) (any, error) {
+	if obj, ok := value.(*object); ok {
+		return obj.call(method, args)
+	}
+	n := builtinMethod(typeOf(value), method)
+	if n == nil {
+		return nil, gd.CallError{ErrorType: gd.ErrInvalidMethod}
+	}
+	return n.variantCall([]any{convert(value, n.in[0])}, args)
+}
+
+func pointerBuiltin(vtype gd.VariantType, method string) func(base callframe.Addr, args callframe.Args, ret callframe.Addr, c int32) {
+	n := builtinMethod(vtype, method)
+	if n == nil {
+		return func(callframe.Addr, callframe.Args, callframe.Addr, int32) {
+			panic(fmt.Sprintf("enginetest: %s.%s is not supported", typeNames[vtype], method))
+		}
+	}
+	return func(base callframe.Addr, args callframe.Args, ret callframe.Addr, c int32) {
+		self := convert(read(vtype, base.UnsafePointer()), n.in[0])
+		n.pointerCall([]any{self}, pointersOf(args, int(c)), ret.UnsafePointer())
+	}
+}
+
+func runes(s str) []rune { return []rune(string(s)) }
+
+// span clamps the rune range [from, from+length) to the string, a negative length extends to the end.
+func span(s []rune, from, length int64) []rune {
+	from = max(0, min(from, int64(len(s))))
+	if length < 0 || from+length > int64(len(s)) {
+		length = int64(len(s)) - from
+	}
+	return s[from : from+length]
+}
+
+func runeIndex(s str, byteIndex int) int64 {
+	if byteIndex < 0 {
+		return -1
+	}
+	return int64(utf8.RuneCountInString(string(s)[:byteIndex]))
+}
+
+var stringMethods = map[string]any{
+	"length":   func(s str) int64 { return int64(utf8.RuneCountInString(string(s))) },
+	"is_empty": func(s str) bool { return s == "" },
+	"substr":   func(s str, from, length int64) str { return str(span(runes(s), from, length)) },
+	"left": func(s str, length int64) str {
+		r := runes(s)
+		if length < 0 {
+			length += int64(len(r))
+		}
+		return str(span(r, 0, max(length, 0)))
+	},
+	"right": func(s str, length int64) str {
+		r := runes(s)
+		if length < 0 {
+			length += int64(len(r))
+		}
+		length = max(0, min(length, int64(len(r))))
+		return str(r[int64(len(r))-length:])
+	},
+	"find": func(s, what str, from int64) int64 {
+		prefix := len(string(span(runes(s), 0, max(from, 0))))
+		i := strings.Index(string(s)[prefix:], string(what))
+		if i < 0 {
+			return -1
+		}
+		return runeIndex(s, prefix+i)
+	},
+	"rfind": func(s, what str, from int64) int64 {
+		end := len(s)
+		if from >= 0 {
+			end = len(string(span(runes(s), 0, from+int64(utf8.RuneCountInString(string(what))))))
+		}
+		return runeIndex(s, strings.LastIndex(string(s)[:end], string(what)))
+	},
+	"contains": func(s, what str) bool { return strings.Contains(string(s), string(what)) },
+	"containsn": func(s, what str) bool {
+		return strings.Contains(strings.ToLower(string(s)), strings.ToLower(string(what)))
+	},
+	"begins_with": func(s, text str) bool { return strings.HasPrefix(string(s), string(text)) },
+	"ends_with":   func(s, text str) bool { return strings.HasSuffix(string(s), string(text)) },
+	"count": func(s, what str, from, to int64) int64 {
+		if what == "" {
+			return 0
+		}
+		r := runes(s)
+		if to <= 0 {
+			to = int64(len(r))
+		}
+		return int64(strings.Count(string(span(r, from, to-from)), string(what)))
+	},
+	"to_upper":    func(s str) str { return str(strings.ToUpper(string(s))) },
+	"to_lower":    func(s str) str { return str(strings.ToLower(string(s))) },
+	"capitalize":  func(s str) str { return str(capitalize(string(s))) },
+	"replace":     func(s, what, with str) str { return str(strings.ReplaceAll(string(s), string(what), string(with))) },
+	"repeat":      func(s str, count int64) str { return str(strings.Repeat(string(s), int(max(count, 0)))) },
+	"reverse":     func(s str) str { r := runes(s); slices.Reverse(r); return str(r) },
+	"strip_edges": func(s str, left, right bool) str { return str(strip(string(s), left, right)) },
+	"to_int":      func(s str) int64 { return convert(s, gd.TypeInt).(int64) },
+	"to_float":    func(s str) float64 { return convert(s, gd.TypeFloat).(float64) },
+	"casecmp_to":  func(s, to str) int64 { return int64(strings.Compare(string(s), string(to))) },
+	"nocasecmp_to": func(s, to str) int64 {
+		return int64(strings.Compare(strings.ToLower(string(s)), strings.ToLower(string(to))))
+	},
+	"split": func(s, delimiter str, allowEmpty bool, maxsplit int64) *packed[str] {
+		n := -1
+		if maxsplit > 0 {
+			n = int(maxsplit) + 1
+		}
+		var parts []string
+		if delimiter == "" {
+			for _, r := range string(s) {
+				parts = append(parts, string(r))
+			}
+		} else {
+			parts = strings.SplitN(string(s), string(delimiter), n)
+		}
+		result := &packed[str]{}
+		for _, part := range parts {
+			if part != "" || allowEmpty {
+				result.elems = append(result.elems, str(part))
+			}
+		}
+		return result
+	},
+	"get_slice": func(s, delimiter str, slice int64) str {
+		parts := strings.Split(string(s), string(delimiter))
+		if slice < 0 || slice >= int64(len(parts)) {
+			return ""
+		}
+		return str(parts[slice])
+	},
+	"join": func(s str, parts *packed[str]) str {
+		elems := make([]string, len(parts.elems))
+		for i, part := range parts.elems {
+			elems[i] = string(part)
+		}
+		return str(strings.Join(elems, string(s)))
+	},
+	"path_join": func(s, file str) str {
+		if s == "" {
+			return file
+		}
+		return str(strings.TrimSuffix(string(s), "/") + "/" + strings.TrimPrefix(string(file), "/"))
+	},
+	"unicode_at": func(s str, at int64) int64 {
+		r := runes(s)
+		if at < 0 || at >= int64(len(r)) {
+			return 0
+		}
+		return int64(r[at])
+	},
+	"hash": func(s str) int64 { return hashOf(s) },
+}
+
+func capitalize(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool { return r == '_' || unicode.IsSpace(r) })
+	for i, word := range words {
+		r := []rune(strings.ToLower(word))
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+	return strings.Join(words, " ")
+}
+
+func strip(s string, left, right bool) string {
+	if left {
+		s = strings.TrimLeftFunc(s, func(r rune) bool { return r <= ' ' })
+	}
+	if right {
+		s = strings.TrimRightFunc(s, func(r rune) bool { return r <= ' ' })
+	}
+	return s
+}
+
+var stringNameMethods = map[string]any{
+	"hash": func(s name) int64 { return hashOf(s) },
+}
+
+var nodePathMethods = map[string]any{
+	"is_absolute": func(p path) bool { return strings.HasPrefix(string(p), "/") },
+	"is_empty":    func(p path) bool { return p == "" },
+	"get_name_count": func(p path) int64 {
+		return int64(len(pathNames(p)))
+	},
+	"get_name": func(p path, idx int64) name {
+		names := pathNames(p)
+		if idx < 0 || idx >= int64(len(names)) {
+			reportError(fmt.Sprintf("Index p_idx = %d is out of bounds (%d).", idx, len(names)))
+			return ""
+		}
+		return name(names[idx])
+	},
+	"get_subname_count": func(p path) int64 {
+		return int64(len(pathSubnames(p)))
+	},
+	"get_subname": func(p path, idx int64) name {
+		names := pathSubnames(p)
+		if idx < 0 || idx >= int64(len(names)) {
+			reportError(fmt.Sprintf("Index p_idx = %d is out of bounds (%d).", idx, len(names)))
+			return ""
+		}
+		return name(names[idx])
+	},
+	"get_concatenated_names": func(p path) name {
+		names, _, _ := strings.Cut(string(p), ":")
+		return name(strings.TrimPrefix(names, "/"))
+	},
+	"get_concatenated_subnames": func(p path) name {
+		return name(strings.Join(pathSubnames(p), ":"))
+	},
+	"hash": func(p path) int64 { return hashOf(p) },
+}
+
+func pathNames(p path) []string {
+	names, _, _ := strings.Cut(strings.TrimPrefix(string(p), "/"), ":")
+	if names == "" {
+		return nil
+	}
+	return strings.Split(names, "/")
+}
+
+func pathSubnames(p path) []string {
+	_, subnames, ok := strings.Cut(string(p), ":")
+	if !ok {
+		return nil
+	}
+	return strings.Split(subnames, ":")
+}
+
+// mutable reports an error and returns false, if the array or dictionary is read-only.
+func mutable(readonly bool) bool {
+	if readonly {
+		reportError("Condition \"_p->read_only\" is true.")
+	}
+	return !readonly
+}
+
+func (a *array) index(i int64) (int, bool) {
+	if i < 0 {
+		i += int64(len(a.elems))
+	}
+	if i < 0 || i >= int64(len(a.elems)) {
+		reportError(fmt.Sprintf("Index p_index = %d is out of bounds (%d).", i, len(a.elems)))
+		return 0, false
+	}
+	return int(i), true
+}
+
+func (a *array) find(value any, from int) int {
+	for i := max(from, 0); i < len(a.elems); i++ {
+		if equal(a.elems[i], value) {
+			return i
+		}
+	}
+	return -1
+}
+
+var arrayMethods = map[string]any{
+	"size":     func(a *array) int64 { return int64(len(a.elems)) },
+	"is_empty": func(a *array) bool { return len(a.elems) == 0 },
+	"clear": func(a *array) {
+		if mutable(a.readonly) {
+			a.elems = nil
+		}
+	},
+	"append": func(a *array, value any) {
+		if mutable(a.readonly) {
+			a.elems = append(a.elems, nil)
+			a.assign(len(a.elems)-1, value)
+		}
+	},
+	"push_back": func(a *array, value any) {
+		if mutable(a.readonly) {
+			a.elems = append(a.elems, nil)
+			a.assign(len(a.elems)-1, value)
+		}
+	},
+	"push_front": func(a *array, value any) {
+		if mutable(a.readonly) {
+			a.elems = slices.Insert(a.elems, 0, nil)
+			a.assign(0, value)
+		}
+	},
+	"append_array": func(a *array, other *array) {
+		if mutable(a.readonly) {
+			for _, value := range other.elems {
+				a.elems = append(a.elems, nil)
+				a.assign(len(a.elems)-1, value)
+			}
+		}
+	},
+	"pop_back": func(a *array) any {
+		if len(a.elems) == 0 || !mutable(a.readonly) {
+			return nil
+		}
+		value := a.elems[len(a.elems)-1]
+		a.elems = a.elems[:len(a.elems)-1]
+		return value
+	},
+	"pop_front": func(a *array) any {
+		if len(a.elems) == 0 || !mutable(a.readonly) {
+			return nil
+		}
+		value := a.elems[0]
+		a.elems = slices.Delete(a.elems, 0, 1)
+		return value
+	},
+	"resize": func(a *array, size int64) int64 {
+		if size < 0 || !mutable(a.readonly) {
+			return 1
+		}
+		for int64(len(a.elems)) < size {
+			a.elems = append(a.elems, zeroElem(a))
+		}
+		a.elems = a.elems[:size]
+		return 0
+	},
+	"insert": func(a *array, pos int64, value any) int64 {
+		if pos < 0 {
+			pos += int64(len(a.elems))
+		}
+		if pos < 0 || pos > int64(len(a.elems)) || !mutable(a.readonly) {
+			return 31 // ERR_INVALID_PARAMETER
+		}
+		a.elems = slices.Insert(a.elems, int(pos), nil)
+		a.assign(int(pos), value)
+		return 0
+	},
+	"remove_at": func(a *array, pos int64) {
+		if i, ok := a.index(pos); ok && mutable(a.readonly) {
+			a.elems = slices.Delete(a.elems, i, i+1)
+		}
+	},
+	"erase": func(a *array, value any) {
+		if i := a.find(value, 0); i >= 0 && mutable(a.readonly) {
+			a.elems = slices.Delete(a.elems, i, i+1)
+		}
+	},
+	"fill": func(a *array, value any) {
+		if mutable(a.readonly) {
+			for i := range a.elems {
+				a.assign(i, value)
+			}
+		}
+	},
+	"get": func(a *array, i int64) any {
+		if i, ok := a.index(i); ok {
+			return a.elems[i]
+		}
+		return nil
+	},
+	"set": func(a *array, i int64, value any) {
+		if i, ok := a.index(i); ok && mutable(a.readonly) {
+			a.assign(i, value)
+		}
+	},
+	"front": func(a *array) any {
+		if len(a.elems) == 0 {
+			reportError("Can't take value from empty array.")
+			return nil
+		}
+		return a.elems[0]
+	},
+	"back": func(a *array) any {
+		if len(a.elems) == 0 {
+			reportError("Can't take value from empty array.")
+			return nil
+		}
+		return a.elems[len(a.elems)-1]
+	},
+	"has":  func(a *array, value any) bool { return a.find(value, 0) >= 0 },
+	"find": func(a *array, value any, from int64) int64 { return int64(a.find(value, int(from))) },
+	"rfind": func(a *array, value any, from int64) int64 {
+		if from < 0 {
+			from += int64(len(a.elems))
+		}
+		for i := min(int(from), len(a.elems)-1); i >= 0; i-- {
+			if equal(a.elems[i], value) {
+				return int64(i)
+			}
+		}
+		return -1
+	},
+	"count": func(a *array, value any) int64 {
+		var count int64
+		for _, elem := range a.elems {
+			if equal(elem, value) {
+				count++
+			}
+		}
+		return count
+	},
+	"reverse": func(a *array) {
+		if mutable(a.readonly) {
+			slices.Reverse(a.elems)
+		}
+	},
+	"sort": func(a *array) {
+		if mutable(a.readonly) {
+			slices.SortStableFunc(a.elems, compare)
+		}
+	},
+	"slice": func(a *array, begin, end, step int64, deep bool) *array {
+		result := &array{typed: a.typed, class: a.class}
+		n := int64(len(a.elems))
+		if begin < 0 {
+			begin += n
+		}
+		if end < 0 {
+			end += n
+		}
+		begin, end = max(0, min(begin, n)), max(0, min(end, n))
+		if step <= 0 {
+			step = 1
+		}
+		for i := begin; i < end; i += step {
+			result.elems = append(result.elems, duplicate(a.elems[i], deep))
+		}
+		return result
+	},
+	"duplicate":            func(a *array, deep bool) *array { return duplicate(a, deep).(*array) },
+	"hash":                 func(a *array) int64 { return hashOf(a) },
+	"is_read_only":         func(a *array) bool { return a.readonly },
+	"make_read_only":       func(a *array) { a.readonly = true },
+	"is_typed":             func(a *array) bool { return a.typed != gd.TypeNil },
+	"get_typed_builtin":    func(a *array) int64 { return int64(a.typed) },
+	"get_typed_class_name": func(a *array) name { return name(a.class) },
+	"max": func(a *array) any {
+		if len(a.elems) == 0 {
+			return nil
+		}
+		return slices.MaxFunc(a.elems, compare)
+	},
+	"min": func(a *array) any {
+		if len(a.elems) == 0 {
+			return nil
+		}
+		return slices.MinFunc(a.elems, compare)
+	},
+}
+
+func zeroElem(a *array) any {
+	if a.typed == gd.TypeNil || a.typed == gd.TypeObject {
+		return nil
+	}
+	return zero(a.typed)
+}
+
+// compare orders values in the same way as the engine's < operator, values of different types are
+// ordered by type.
+func compare(a, b any) int {
+	if less, ok := evaluate(gd.Less, a, b); ok {
+		switch {
+		case less.(bool):
+			return -1
+		case equal(a, b):
+			return 0
+		default:
+			return 1
+		}
+	}
+	return int(typeOf(a)) - int(typeOf(b))
+}
+
+var dictionaryMethods = map[string]any{
+	"size":     func(d *dictionary) int64 { return int64(len(d.keys)) },
+	"is_empty": func(d *dictionary) bool { return len(d.keys) == 0 },
+	"clear": func(d *dictionary) {
+		if mutable(d.readonly) {
+			d.keys, d.vals = nil, nil
+		}
+	},
+	"has": func(d *dictionary, key any) bool { return d.find(key) >= 0 },
+	"has_all": func(d *dictionary, keys *array) bool {
+		for _, key := range keys.elems {
+			if d.find(key) < 0 {
+				return false
+			}
+		}
+		return true
+	},
+	"erase": func(d *dictionary, key any) bool { return mutable(d.readonly) && d.erase(key) },
+	"keys":  func(d *dictionary) *array { return newArray(slices.Clone(d.keys)...) },
+	"values": func(d *dictionary) *array {
+		return newArray(slices.Clone(d.vals)...)
+	},
+	"get": func(d *dictionary, key, fallback any) any {
+		if value, ok := d.get(key); ok {
+			return value
+		}
+		return fallback
+	},
+	"get_or_add": func(d *dictionary, key, fallback any) any {
+		if value, ok := d.get(key); ok {
+			return value
+		}
+		if mutable(d.readonly) {
+			d.set(key, fallback)
+		}
+		return fallback
+	},
+	"set": func(d *dictionary, key, value any) bool {
+		if !mutable(d.readonly) {
+			return false
+		}
+		d.set(key, value)
+		return true
+	},
+	"find_key": func(d *dictionary, value any) any {
+		for i, v := range d.vals {
+			if equal(v, value) {
+				return d.keys[i]
+			}
+		}
+		return nil
+	},
+	"merge": func(d *dictionary, other *dictionary, overwrite bool) {
+		if !mutable(d.readonly) {
+			return
+		}
+		for i, key := range other.keys {
+			if _, ok := d.get(key); !ok || overwrite {
+				d.set(key, other.vals[i])
+			}
+		}
+	},
+	"duplicate":      func(d *dictionary, deep bool) *dictionary { return duplicate(d, deep).(*dictionary) },
+	"hash":           func(d *dictionary) int64 { return hashOf(d) },
+	"is_read_only":   func(d *dictionary) bool { return d.readonly },
+	"make_read_only": func(d *dictionary) { d.readonly = true },
+}
+
+var callableMethods = map[string]any{
+	"call": func(c *callable, args ...any) any {
+		result, err := callCallable(c, args)
+		if err != nil {
+			reportError(fmt.Sprintf("Error calling method from 'call': '%s': %v.", stringOf(c), err))
+		}
+		return result
+	},
+	"callv": func(c *callable, args *array) any {
+		result, err := callCallable(c, slices.Clone(args.elems))
+		if err != nil {
+			reportError(fmt.Sprintf("Error calling method from 'callv': '%s': %v.", stringOf(c), err))
+		}
+		return result
+	},
+	"call_deferred": func(c *callable, args ...any) {
+		c = clone(c).(*callable)
+		deferCall(func() {
+			if _, err := callCallable(c, args); err != nil {
+				reportError(fmt.Sprintf("Error calling deferred method: '%s': %v.", stringOf(c), err))
+			}
+		})
+	},
+	"bind": func(c *callable, args ...any) *callable {
+		bound := clone(c).(*callable)
+		bound.bound = append(slices.Clone(args), bound.bound...)
+		return bound
+	},
+	"bindv": func(c *callable, args *array) *callable {
+		bound := clone(c).(*callable)
+		bound.bound = append(slices.Clone(args.elems), bound.bound...)
+		return bound
+	},
+	"get_object": func(c *callable) *object { return c.object },
+	"get_object_id": func(c *callable) int64 {
+		if c.object == nil {
+			return 0
+		}
+		return int64(c.object.id)
+	},
+	"get_method":                func(c *callable) name { return name(c.method) },
+	"get_bound_arguments":       func(c *callable) *array { return newArray(slices.Clone(c.bound)...) },
+	"get_bound_arguments_count": func(c *callable) int64 { return int64(len(c.bound)) },
+	"get_argument_count": func(c *callable) int64 {
+		if c.object == nil || c.object.freed {
+			return 0
+		}
+		if m := c.object.class.method(c.method); m != nil {
+			return int64(len(m.Arguments) - len(c.bound))
+		}
+		if n := c.object.class.native(c.method); n != nil {
+			return int64(len(n.in) - 1 - len(c.bound))
+		}
+		return 0
+	},
+	"is_valid": func(c *callable) bool {
+		return c.fn != nil || c.object != nil && !c.object.freed && c.object.hasMethod(c.method)
+	},
+	"is_null":     func(c *callable) bool { return c.fn == nil && c.object == nil },
+	"is_custom":   func(c *callable) bool { return c.fn != nil },
+	"is_standard": func(c *callable) bool { return c.fn == nil },
+	"hash":        func(c *callable) int64 { return hashOf(c) },
+}
+
+var signalMethods = map[string]any{
+	"emit": func(s signal, args ...any) {
+		if s.object != nil && !s.object.freed {
+			s.object.emit(s.name, args)
+		}
+	},
+	"connect": func(s signal, target *callable, flags int64) int64 {
+		if s.object == nil || s.object.freed {
+			return 31 // ERR_INVALID_PARAMETER
+		}
+		return int64(s.object.connect(s.name, target, flags))
+	},
+	"disconnect": func(s signal, target *callable) {
+		if s.object != nil && !s.object.freed && !s.object.disconnect(s.name, target) {
+			reportError(fmt.Sprintf("Attempt to disconnect a nonexistent connection from '%s'. Signal: '%s', callable: '%s'.", stringOf(s.object), s.name, stringOf(target)))
+		}
+	},
+	"is_connected": func(s signal, target *callable) bool {
+		return s.object != nil && !s.object.freed && s.object.isConnected(s.name, target)
+	},
+	"get_connections": func(s signal) *array {
+		connections := newArray()
+		if s.object == nil {
+			return connections
+		}
+		for _, conn := range s.object.connections[s.name] {
+			info := &dictionary{}
+			info.set(str("signal"), s)
+			info.set(str("callable"), conn.target)
+			info.set(str("flags"), conn.flags)
+			connections.elems = append(connections.elems, info)
+		}
+		return connections
+	},
+	"has_connections": func(s signal) bool { return s.object != nil && len(s.object.connections[s.name]) > 0 },
+	"get_name":        func(s signal) name { return name(s.name) },
+	"get_object":      func(s signal) *object { return s.object },
+	"get_object_id": func(s signal) int64 {
+		if s.object == nil {
+			return 0
+		}
+		return int64(s.object.id)
+	},
+	"is_null": func(s signal) bool { return s.object == nil },
+}
+
+// packedMethods returns the methods of a packed array with elements of type T, where the methods
+// accept and return elements as values of type V.
+func packedMethods[T, V any](in func(V) T, out func(T) V) map[string]any {
+	find := func(p *packed[T], value V, from int) int {
+		for i := max(from, 0); i < len(p.elems); i++ {
+			if equal(normalize(any(p.elems[i])), normalize(any(in(value)))) {
+				return i
+			}
+		}
+		return -1
+	}
+	return map[string]any{
+		"size":     func(p *packed[T]) int64 { return int64(len(p.elems)) },
+		"is_empty": func(p *packed[T]) bool { return len(p.elems) == 0 },
+		"clear":    func(p *packed[T]) { p.elems = nil },
+		"get": func(p *packed[T], i int64) V {
+			if i < 0 || i >= int64(len(p.elems)) {
+				reportError(fmt.Sprintf("Index p_index = %d is out of bounds (%d).", i, len(p.elems)))
+				return [1]V{}[0]
+			}
+			return out(p.elems[i])
+		},
+		"set": func(p *packed[T], i int64, value V) {
+			if i < 0 || i >= int64(len(p.elems)) {
+				reportError(fmt.Sprintf("Index p_index = %d is out of bounds (%d).", i, len(p.elems)))
+				return
+			}
+			p.elems[i] = in(value)
+		},
+		"append":       func(p *packed[T], value V) bool { p.elems = append(p.elems, in(value)); return false },
+		"push_back":    func(p *packed[T], value V) bool { p.elems = append(p.elems, in(value)); return false },
+		"append_array": func(p *packed[T], other *packed[T]) { p.elems = append(p.elems, other.elems...) },
+		"resize": func(p *packed[T], size int64) int64 {
+			if size < 0 {
+				return 31 // ERR_INVALID_PARAMETER
+			}
+			if size <= int64(len(p.elems)) {
+				p.elems = p.elems[:size]
+			} else {
+				p.elems = append(p.elems, make([]T, size-int64(len(p.elems)))...)
+			}
+			return 0
+		},
+		"insert": func(p *packed[T], at int64, value V) int64 {
+			if at < 0 || at > int64(len(p.elems)) {
+				return 31 // ERR_INVALID_PARAMETER
+			}
+			p.elems = slices.Insert(p.elems, int(at), in(value))
+			return 0
+		},
+		"remove_at": func(p *packed[T], at int64) {
+			if at < 0 || at >= int64(len(p.elems)) {
+				reportError(fmt.Sprintf("Index p_index = %d is out of bounds (%d).", at, len(p.elems)))
+				return
+			}
+			p.elems = slices.Delete(p.elems, int(at), int(at)+1)
+		},
+		"fill": func(p *packed[T], value V) {
+			for i := range p.elems {
+				p.elems[i] = in(value)
+			}
+		},
+		"has":  func(p *packed[T], value V) bool { return find(p, value, 0) >= 0 },
+		"find": func(p *packed[T], value V, from int64) int64 { return int64(find(p, value, int(from))) },
+		"count": func(p *packed[T], value V) int64 {
+			var count int64
+			for i := find(p, value, 0); i >= 0; i = find(p, value, i+1) {
+				count++
+			}
+			return count
+		},
+		"reverse":   func(p *packed[T]) { slices.Reverse(p.elems) },
+		"duplicate": func(p *packed[T]) *packed[T] { return &packed[T]{slices.Clone(p.elems)} },
+		"slice": func(p *packed[T], begin, end int64) *packed[T] {
+			n := int64(len(p.elems))
+			if begin < 0 {
+				begin += n
+			}
+			if end < 0 {
+				end += n
+			}
+			begin, end = max(0, min(begin, n)), max(0, min(end, n))
+			return &packed[T]{slices.Clone(p.elems[begin:max(begin, end)])}
+		},
+	}
+}
+
+// hashOf returns a hash of the value, equal values have equal hashes.
+func hashOf(value any) int64 {
+	h := fnv.New32a()
+	switch v := value.(type) {
+	case *object:
+		fmt.Fprintf(h, "%d", v.id)
+	case name:
+		h.Write([]byte(v))
+	default:
+		fmt.Fprintf(h, "%d:%s", typeOf(value), stringOf(value))
+	}
+	return int64(h.Sum32())
+}
+
+// evaluate implements the engine's operators, it reports false if the operator is not supported
+// for the given values.
+func evaluate(op gd.Operator, a, b any) (any, bool) {
+	switch op {
+	case gd.Equal:
+		return equal(a, b), true
+	case gd.NotEqual:
+		return !equal(a, b), true
+	case gd.LogicalAnd:
+		return truthy(a) && truthy(b), true
+	case gd.LogicalOr:
+		return truthy(a) || truthy(b), true
+	case gd.LogicalXor:
+		return truthy(a) != truthy(b), true
+	case gd.LogicalNegate:
+		return !truthy(a), true
+	case gd.In:
+		return contains(b, a)
+	}
+	switch x := a.(type) {
+	case bool:
+		if y, ok := b.(bool); ok {
+			return compareOrdered(op, btoi(x), btoi(y))
+		}
+	case int64:
+		switch y := b.(type) {
+		case nil:
+			switch op {
+			case gd.Negate:
+				return -x, true
+			case gd.Positive:
+				return x, true
+			case gd.BitNegate:
+				return ^x, true
+			}
+		case int64:
+			return evaluateInt(op, x, y)
+		case float64:
+			return evaluateFloat(op, float64(x), y)
+		}
+		if isVector(b) && op == gd.Multiply {
+			return scale(b, float64(x)), true
+		}
+	case float64:
+		switch y := b.(type) {
+		case nil:
+			switch op {
+			case gd.Negate:
+				return -x, true
+			case gd.Positive:
+				return x, true
+			}
+		case int64:
+			return evaluateFloat(op, x, float64(y))
+		case float64:
+			return evaluateFloat(op, x, y)
+		}
+		if isVector(b) && op == gd.Multiply {
+			return scale(b, x), true
+		}
+	case str, name:
+		switch b.(type) {
+		case str, name:
+			x, y := stringOf(a), stringOf(b)
+			if op == gd.Add {
+				return str(x + y), true
+			}
+			return compareOrdered(op, x, y)
+		}
+	case *array:
+		if y, ok := b.(*array); ok && op == gd.Add {
+			return newArray(append(slices.Clone(x.elems), y.elems...)...), true
+		}
+	}
+	if isVector(a) {
+		return evaluateVector(op, a, b)
+	}
+	return nil, false
+}
+
+func btoi(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func compareOrdered[T int64 | float64 | string](op gd.Operator, x, y T) (any, bool) {
+	switch op {
+	case gd.Less:
+		return x < y, true
+	case gd.LessEqual:
+		return x <= y, true
+	case gd.Greater:
+		return x > y, true
+	case gd.GreaterEqual:
+		return x >= y, true
+	}
+	return nil, false
+}
+
+func evaluateInt(op gd.Operator, x, y int64) (any, bool) {
+	switch op {
+	case gd.Add:
+		return x + y, true
+	case gd.Subtract:
+		return x - y, true
+	case gd.Multiply:
+		return x * y, true
+	case gd.Divide:
+		if y == 0 {
+			reportError("Division by zero error.")
+			return nil, false
+		}
+		return x / y, true
+	case gd.Module:
+		if y == 0 {
+			reportError("Modulo by zero error.")
+			return nil, false
+		}
+		return x % y, true
+	case gd.Power:
+		return int64(math.Pow(float64(x), float64(y))), true
+	case gd.ShiftLeft:
+		return x << y, true
+	case gd.ShiftRight:
+		return x >> y, true
+	case gd.BitAnd:
+		return x & y, true
+	case gd.BitOr:
+		return x | y, true
+	case gd.BitXor:
+		return x ^ y, true
+	}
+	return compareOrdered(op, x, y)
+}
+
+func evaluateFloat(op gd.Operator, x, y float64) (any, bool) {
+	switch op {
+	case gd.Add:
+		return x + y, true
+	case gd.Subtract:
+		return x - y, true
+	case gd.Multiply:
+		return x * y, true
+	case gd.Divide:
+		return x / y, true
+	case gd.Module:
+		return math.Mod(x, y), true
+	case gd.Power:
+		return math.Pow(x, y), true
+	}
+	return compareOrdered(op, x, y)
+}
+
+// contains implements the in operator.
+func contains(container, value any) (any, bool) {
+	switch c := container.(type) {
+	case *array:
+		return c.find(value, 0) >= 0, true
+	case *dictionary:
+		return c.find(value) >= 0, true
+	case str, name:
+		switch value.(type) {
+		case str, name:
+			return strings.Contains(stringOf(c), stringOf(value)), true
+		}
+	case *object:
+		if s, ok := value.(str); ok && c != nil {
+			_, has := c.get(string(s))
+			return has, true
+		}
+	}
+	return nil, false
+}
+
+// isVector reports whether the value is a vector or color, which support component-wise arithmetic.
+func isVector(value any) bool {
+	switch value.(type) {
+	case gd.Vector2, gd.Vector2i, gd.Vector3, gd.Vector3i, gd.Vector4, gd.Vector4i, gd.Color:
+		return true
+	}
+	return false
+}
+
+func evaluateVector(op gd.Operator, a, b any) (any, bool) {
+	x := reflect.ValueOf(a)
+	switch y := b.(type) {
+	case nil:
+		switch op {
+		case gd.Negate:
+			return scale(a, -1), true
+		case gd.Positive:
+			return a, true
+		}
+		return nil, false
+	case int64:
+		return evaluateVectorScalar(op, a, float64(y))
+	case float64:
+		return evaluateVectorScalar(op, a, y)
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return nil, false
+	}
+	y := reflect.ValueOf(b)
+	switch op {
+	case gd.Add, gd.Subtract, gd.Multiply, gd.Divide:
+		result := reflect.New(x.Type()).Elem()
+		for i := range x.NumField() {
+			xf, yf := component(x.Field(i)), component(y.Field(i))
+			var r float64
+			switch op {
+			case gd.Add:
+				r = xf + yf
+			case gd.Subtract:
+				r = xf - yf
+			case gd.Multiply:
+				r = xf * yf
+			case gd.Divide:
+				r = xf / yf
+			}
+			setComponent(result.Field(i), r)
+		}
+		return result.Interface(), true
+	case gd.Less, gd.LessEqual, gd.Greater, gd.GreaterEqual:
+		for i := range x.NumField() {
+			xf, yf := component(x.Field(i)), component(y.Field(i))
+			if xf != yf || i == x.NumField()-1 {
+				return compareOrdered(op, xf, yf)
+			}
+		}
+	}
+	return nil, false
+}
+
+func evaluateVectorScalar(op gd.Operator, a any, s float64) (any, bool) {
+	switch op {
+	case gd.Multiply:
+		return scale(a, s), true
+	case gd.Divide:
+		return scale(a, 1/s), true
+	}
+	return nil, false
+}
+
+func scale(a any, s float64) any {
+	x := reflect.ValueOf(a)
+	result := reflect.New(x.Type()).Elem()
+	for i := range x.NumField() {
+		setComponent(result.Field(i), component(x.Field(i))*s)
+	}
+	return result.Interface()
+}
+
+func component(v reflect.Value) float64 {
+	if v.CanInt() {
+		return float64(v.Int())
+	}
+	return v.Float()
+}
+
+func setComponent(v reflect.Value, f float64) {
+	if v.CanInt() {
+		v.SetInt(int64(f))
+		return
+	}
+	v.SetFloat(f)
+}
+
+// utilities implements the utility functions supported by the fake engine.
+var utilities = map[string]any{
+	"print":         func(args ...any) { fmt.Fprintln(Output, join(args, "")) },
+	"print_rich":    func(args ...any) { fmt.Fprintln(Output, join(args, "")) },
+	"print_verbose": func(args ...any) {},
+	"printerr":      func(args ...any) { fmt.Fprintln(Output, join(args, "")) },
+	"printraw":      func(args ...any) { fmt.Fprint(Output, join(args, "")) },
+	"prints":        func(args ...any) { fmt.Fprintln(Output, join(args, " ")) },
+	"printt":        func(args ...any) { fmt.Fprintln(Output, join(args, "\t")) },
+	"push_error":    func(args ...any) { reportError(join(args, "")) },
+	"push_warning":  func(args ...any) { reportWarning(join(args, "")) },
+	"str":           func(args ...any) str { return str(join(args, "")) },
+	"typeof":        func(value any) int64 { return int64(typeOf(value)) },
+	"type_string": func(vtype int64) str {
+		if vtype < 0 || vtype >= int64(gd.TypeMax) {
+			return "<invalid type>"
+		}
+		return str(typeNames[vtype])
+	},
+	"var_to_str": func(value any) str {
+		var b strings.Builder
+		appendString(&b, value, true)
+		return str(b.String())
+	},
+	"hash":             func(value any) int64 { return hashOf(value) },
+	"instance_from_id": func(id int64) *object { return objectOf(uint64(id)) },
+	"is_instance_id_valid": func(id int64) bool {
+		return objectOf(uint64(id)) != nil
+	},
+	"is_instance_valid": func(value any) bool {
+		obj, ok := value.(*object)
+		return ok && obj != nil && !obj.freed
+	},
+	"is_same": func(a, b any) bool {
+		if typeOf(a) != typeOf(b) {
+			return false
+		}
+		switch a.(type) {
+		case *array, *dictionary, *object:
+			return a == b
+		}
+		return equal(a, b)
+	},
+}
+
+func join(args []any, sep string) string {
+	elems := make([]string, len(args))
+	for i, arg := range args {
+		elems[i] = stringOf(arg)
+	}
+	return strings.Join(elems, sep)
+}
+
+func pointerUtility(method string) func(ret callframe.Addr, args callframe.Args, c int32) {
+	fn, ok := utilities[method]
+	if !ok {
+		return func(callframe.Addr, callframe.Args, int32) {
+			panic(fmt.Sprintf("enginetest: utility function %s is not supported", method))
+		}
+	}
+	n := bind(fn)
+	return func(ret callframe.Addr, args callframe.Args, c int32) {
+		n.pointerCall(nil, pointersOf(args, int(c)), ret.UnsafePointer())
+	}
+}
+
+func pointerOperator(op gd.Operator, a, b gd.VariantType) func(a, b, ret callframe.Addr) {
+	return func(x, y, ret callframe.Addr) {
+		lhs := read(a, x.UnsafePointer())
+		var rhs any
+		if y.UnsafePointer() != nil {
+			rhs = read(b, y.UnsafePointer())
+		}
+		result, ok := evaluate(op, lhs, rhs)
+		if !ok {
+			panic(fmt.Sprintf("enginetest: operator %d is not supported for %s and %s", op, typeNames[a], typeNames[b]))
+		}
+		write(typeOf(result), ret.UnsafePointer(), result)
+	}
+}
+
+func pointerConstructor(vtype gd.VariantType, index int32) func(base callframe.Addr, args callframe.Args) {
+	arg := func(args callframe.Args, i int, vtype gd.VariantType) any {
+		return read(vtype, args.Index(i).UnsafePointer())
+	}
+	construct := func(base callframe.Addr, value any) {
+		write(vtype, base.UnsafePointer(), value)
+	}
+	switch index {
+	case 0:
+		return func(base callframe.Addr, args callframe.Args) { construct(base, zero(vtype)) }
+	case 1:
+		return func(base callframe.Addr, args callframe.Args) { construct(base, arg(args, 0, vtype)) }
+	}
+	switch {
+	case vtype == gd.TypeString && index == 2:
+		return func(base callframe.Addr, args callframe.Args) {
+			construct(base, str(arg(args, 0, gd.TypeStringName).(name)))
+		}
+	case vtype == gd.TypeString && index == 3:
+		return func(base callframe.Addr, args callframe.Args) {
+			construct(base, str(arg(args, 0, gd.TypeNodePath).(path)))
+		}
+	case (vtype == gd.TypeStringName || vtype == gd.TypeNodePath) && index == 2:
+		return func(base callframe.Addr, args callframe.Args) {
+			construct(base, convert(arg(args, 0, gd.TypeString), vtype))
+		}
+	case vtype == gd.TypeCallable && index == 2:
+		return func(base callframe.Addr, args callframe.Args) {
+			obj, _ := arg(args, 0, gd.TypeObject).(*object)
+			construct(base, &callable{object: obj, method: string(arg(args, 1, gd.TypeStringName).(name))})
+		}
+	case vtype == gd.TypeSignal && index == 2:
+		return func(base callframe.Addr, args callframe.Args) {
+			obj, _ := arg(args, 0, gd.TypeObject).(*object)
+			construct(base, signal{object: obj, name: string(arg(args, 1, gd.TypeStringName).(name))})
+		}
+	case vtype == gd.TypeArray && index == 2:
+		return func(base callframe.Addr, args callframe.Args) {
+			from := arg(args, 0, gd.TypeArray).(*array)
+			typed := &array{typed: gd.VariantType(arg(args, 1, gd.TypeInt).(int64)), class: string(arg(args, 2, gd.TypeStringName).(name))}
+			for _, elem := range from.elems {
+				typed.elems = append(typed.elems, convert(elem, typed.typed))
+			}
+			construct(base, typed)
+		}
+	case vtype == gd.TypeArray && index >= 3 && index <= 12:
+		from := gd.TypePackedByteArray + gd.VariantType(index-3)
+		return func(base callframe.Addr, args callframe.Args) { construct(base, arrayOf(arg(args, 0, from))) }
+	case vtype >= gd.TypePackedByteArray && index == 2:
+		return func(base callframe.Addr, args callframe.Args) {
+			construct(base, packedOf(vtype, arg(args, 0, gd.TypeArray).(*array)))
+		}
+	}
+	return func(base callframe.Addr, args callframe.Args) {
+		panic(fmt.Sprintf("enginetest: constructor %d of %s is not supported", index, typeNames[vtype]))
+	}
+}
+
+// arrayOf returns the elements of a packed array, as an Array.
+func arrayOf(p any) *array {
+	elems := reflect.ValueOf(p).Elem().Field(0)
+	result := newArray()
+	for i := range elems.Len() {
+		result.elems = append(result.elems, normalize(elems.Index(i).Interface()))
+	}
+	return result
+}
+
+// packedOf returns the elements of an Array, as a packed array of the given type.
+func packedOf(vtype gd.VariantType, a *array) any {
+	result := zero(vtype)
+	elems := reflect.ValueOf(result).Elem().Field(0)
+	etype := elems.Type().Elem()
+	for _, elem := range a.elems {
+		value := reflect.ValueOf(orZero(convert(elem, variantTypeOf(reflect.TypeOf(normalize(reflect.Zero(etype).Interface())))), 0))
+		elems.Set(reflect.Append(elems, value.Convert(etype)))
+	}
+	return result
+}
+
+func pointerDestructor(vtype gd.VariantType) func(base callframe.Addr) {
+	return func(base callframe.Addr) {
+		if ptr := base.UnsafePointer(); ptr != nil {
+			destroy(vtype, ptr)
+		}
+	}
+}
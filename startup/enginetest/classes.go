@@ -0,0 +1,631 @@
+package enginetest
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"unsafe"
+
+	gd "graphics.gd/internal"
+	"graphics.gd/internal/callframe"
+	"graphics.gd/internal/pointers"
+	"graphics.gd/variant/Error"
+)
+
+// class in the fake ClassDB, either an engine class with methods implemented by [natives], or an
+// extension class registered through the API.
+type class struct {
+	name   string
+	parent *class
+	tag    gd.ClassTag
+
+	extension  gd.ClassInterface
+	methods    map[string]*gd.Method
+	properties []property
+	signals    map[string][]property
+	constants  map[string]int64
+	virtuals   map[string]any
+}
+
+type property struct {
+	info           gd.PropertyInfo
+	name           string
+	hintString     string
+	getter, setter string
+}
+
+// propertyOf copies the strings of a registered property, like the engine does, as the Go values
+// of the property info do not outlive the registration.
+func propertyOf(info gd.PropertyInfo, getter, setter gd.StringName) property {
+	return property{
+		info:       info,
+		name:       nameOf(info.Name),
+		hintString: stringOfString(info.HintString),
+		getter:     nameOf(getter),
+		setter:     nameOf(setter),
+	}
+}
+
+var classdb struct {
+	sync.Mutex
+	byName map[string]*class
+	byTag  []*class
+	binds  []methodBind
+}
+
+type methodBind struct {
+	class  *class
+	method string
+}
+
+// classNamed returns the class with the given name, or nil if the class does not exist.
+func classNamed(name string) *class {
+	classdb.Lock()
+	defer classdb.Unlock()
+	return lookupClass(name)
+}
+
+func lookupClass(name string) *class {
+	if c, ok := classdb.byName[name]; ok {
+		return c
+	}
+	parent, ok := inherits[name]
+	if !ok && name != "Object" {
+		return nil
+	}
+	c := &class{name: name}
+	if ok {
+		c.parent = lookupClass(parent)
+	}
+	addClass(c)
+	return c
+}
+
+func addClass(c *class) {
+	if classdb.byName == nil {
+		classdb.byName = make(map[string]*class)
+	}
+	classdb.byTag = append(classdb.byTag, c)
+	c.tag = gd.ClassTag(len(classdb.byTag))
+	classdb.byName[c.name] = c
+}
+
+// is reports whether the class is, or inherits from the named class.
+func (c *class) is(name string) bool {
+	for ; c != nil; c = c.parent {
+		if c.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// native returns the Go implementation of the named engine method, as defined by the class
+// or any of its parents.
+func (c *class) native(method string) *native {
+	for ; c != nil; c = c.parent {
+		if n, ok := natives[c.name][method]; ok {
+			return n
+		}
+	}
+	return nil
+}
+
+// method returns the extension method with the given name.
+func (c *class) method(name string) *gd.Method {
+	for ; c != nil; c = c.parent {
+		if m, ok := c.methods[name]; ok {
+			return m
+		}
+	}
+	return nil
+}
+
+// property returns the registered property with the given name.
+func (c *class) property(name string) (property, bool) {
+	for ; c != nil; c = c.parent {
+		for _, p := range c.properties {
+			if p.name == name {
+				return p, true
+			}
+		}
+	}
+	return property{}, false
+}
+
+func (c *class) hasSignal(name string) bool {
+	for ; c != nil; c = c.parent {
+		if _, ok := c.signals[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SignalArguments returns the names of the arguments of a signal that was registered by an
+// extension class, and false if the class has no such signal.
+func SignalArguments(className, signal string) ([]string, bool) {
+	c := classNamed(className)
+	if c == nil {
+		return nil, false
+	}
+	args, ok := c.signals[signal]
+	if !ok {
+		return nil, false
+	}
+	names := make([]string, len(args))
+	for i, arg := range args {
+		names[i] = arg.name
+	}
+	return names, true
+}
+
+// extensionClass returns the most derived extension class, or nil if the class is an engine class.
+func (c *class) extensionClass() *class {
+	for ; c != nil; c = c.parent {
+		if c.extension != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// nameOf returns the Go string for the StringName.
+func nameOf(s gd.StringName) string {
+	v, _ := load(pointers.Get(s)[0]).(name)
+	return string(v)
+}
+
+func stringOfString(s gd.String) string {
+	v, _ := load(pointers.Get(s)[0]).(str)
+	return string(v)
+}
+
+// borrowedName returns a StringName that remains owned by the fake engine.
+func borrowedName(s string) gd.StringName {
+	return pointers.Let[gd.StringName]([1]uint64{intern(s)})
+}
+
+func linkClassDB(API *gd.API) {
+	API.ClassDB.ConstructObject = func(name gd.StringName) [1]gd.Object {
+		c := classNamed(nameOf(name))
+		if c == nil {
+			panic(fmt.Sprintf("enginetest: cannot construct unknown class %s", nameOf(name)))
+		}
+		obj := construct(c)
+		if obj == nil {
+			return [1]gd.Object{}
+		}
+		return [1]gd.Object{pointers.New[gd.Object]([3]uint64{obj.id})}
+	}
+	API.ClassDB.GetClassTag = func(name gd.StringName) gd.ClassTag {
+		if c := classNamed(nameOf(name)); c != nil {
+			return c.tag
+		}
+		return 0
+	}
+	API.ClassDB.GetMethodBind = func(className, method gd.StringName, hash gd.Int) gd.MethodBind {
+		c := classNamed(nameOf(className))
+		if c == nil {
+			return 0
+		}
+		classdb.Lock()
+		defer classdb.Unlock()
+		classdb.binds = append(classdb.binds, methodBind{class: c, method: nameOf(method)})
+		return gd.MethodBind(len(classdb.binds))
+	}
+	API.ClassDB.RegisterClass = func(library gd.ExtensionToken, name, extends gd.StringName, info gd.ClassInterface) {
+		classdb.Lock()
+		defer classdb.Unlock()
+		parent := lookupClass(nameOf(extends))
+		if parent == nil {
+			panic(fmt.Sprintf("enginetest: %s extends unknown class %s", nameOf(name), nameOf(extends)))
+		}
+		c := &class{
+			name:      nameOf(name),
+			parent:    parent,
+			extension: info,
+			methods:   make(map[string]*gd.Method),
+			signals:   make(map[string][]property),
+			constants: make(map[string]int64),
+			virtuals:  make(map[string]any),
+		}
+		if existing, ok := classdb.byName[c.name]; ok {
+			*existing = *c
+			existing.tag = gd.ClassTag(slices.Index(classdb.byTag, existing) + 1)
+			return
+		}
+		addClass(c)
+	}
+	API.ClassDB.RegisterClassMethod = func(library gd.ExtensionToken, className gd.StringName, info gd.Method) {
+		c := mustExtend(className)
+		c.methods[nameOf(info.Name)] = &info
+	}
+	API.ClassDB.RegisterClassIntegerConstant = func(library gd.ExtensionToken, className, enum, name gd.StringName, value int64, bitfield bool) {
+		mustExtend(className).constants[nameOf(name)] = value
+	}
+	API.ClassDB.RegisterClassProperty = func(library gd.ExtensionToken, className gd.StringName, info gd.PropertyInfo, getter, setter gd.StringName) {
+		c := mustExtend(className)
+		c.properties = append(c.properties, propertyOf(info, getter, setter))
+	}
+	API.ClassDB.RegisterClassPropertyIndexed = func(library gd.ExtensionToken, className gd.StringName, info gd.PropertyInfo, getter, setter gd.StringName, index int64) {
+		c := mustExtend(className)
+		c.properties = append(c.properties, propertyOf(info, getter, setter))
+	}
+	API.ClassDB.RegisterClassPropertyGroup = func(library gd.ExtensionToken, className gd.StringName, group, prefix gd.String) {}
+	API.ClassDB.RegisterClassPropertySubGroup = func(library gd.ExtensionToken, className gd.StringName, subGroup, prefix gd.String) {}
+	API.ClassDB.RegisterClassSignal = func(library gd.ExtensionToken, className, signal gd.StringName, args []gd.PropertyInfo) {
+		c := mustExtend(className)
+		c.signals[nameOf(signal)] = make([]property, len(args))
+		for i, arg := range args {
+			c.signals[nameOf(signal)][i] = property{info: arg, name: nameOf(arg.Name)}
+		}
+	}
+	API.ClassDB.UnregisterClass = func(library gd.ExtensionToken, className gd.StringName) {
+		classdb.Lock()
+		defer classdb.Unlock()
+		if c, ok := classdb.byName[nameOf(className)]; ok && c.extension != nil {
+			delete(classdb.byName, c.name)
+		}
+	}
+	API.Object.MethodBindPointerCall = func(method gd.MethodBind, obj [1]gd.Object, args callframe.Args, ret callframe.Addr) {
+		bind, n := nativeBind(method)
+		if n.variadic {
+			panic(fmt.Sprintf("enginetest: %s.%s cannot be called with pointers", bind.class.name, bind.method))
+		}
+		if n.static {
+			n.pointerCall(nil, pointersOf(args, len(n.in)), ret.UnsafePointer())
+			return
+		}
+		n.pointerCall([]any{mustObject(obj)}, pointersOf(args, len(n.in)-1), ret.UnsafePointer())
+	}
+	API.Object.MethodBindCall = func(method gd.MethodBind, obj [1]gd.Object, args ...gd.Variant) (gd.Variant, error) {
+		_, n := nativeBind(method)
+		var leading []any
+		if !n.static {
+			leading = []any{mustObject(obj)}
+		}
+		values := make([]any, len(args))
+		for i, arg := range args {
+			values[i] = valueOf(arg)
+		}
+		result, err := n.variantCall(leading, values)
+		if err != nil {
+			return gd.Variant{}, err
+		}
+		return newVariant(result), nil
+	}
+}
+
+func mustExtend(className gd.StringName) *class {
+	c := classNamed(nameOf(className))
+	if c == nil || c.extension == nil {
+		panic(fmt.Sprintf("enginetest: %s is not a registered extension class", nameOf(className)))
+	}
+	return c
+}
+
+// nativeBind returns the Go implementation of the method bind, or panics if the method is not
+// supported by the fake engine.
+func nativeBind(method gd.MethodBind) (methodBind, *native) {
+	classdb.Lock()
+	if method == 0 || int(method) > len(classdb.binds) {
+		classdb.Unlock()
+		panic("enginetest: invalid method bind")
+	}
+	bind := classdb.binds[method-1]
+	classdb.Unlock()
+	n := bind.class.native(bind.method)
+	if n == nil {
+		panic(fmt.Sprintf("enginetest: %s.%s is not supported", bind.class.name, bind.method))
+	}
+	return bind, n
+}
+
+// object is an instance of a class.
+type object struct {
+	id       uint64
+	class    *class
+	instance gd.ObjectInterface
+	bindings map[gd.ExtensionToken]any
+	meta     *dictionary
+
+	connections map[string][]connection
+	userSignals map[string]bool
+	blocked     bool
+
+	refcount int64
+	queued   bool
+	freed    bool
+
+	node *node
+}
+
+type connection struct {
+	target *callable
+	flags  int64
+}
+
+// Connection flags.
+const (
+	connectDeferred   = 1
+	connectReferences = 8
+	connectOneShot    = 4
+)
+
+// construct a new instance of the class.
+func construct(c *class) *object {
+	if c.extension != nil {
+		if c.extension.IsAbstract() {
+			return nil
+		}
+		created := c.extension.CreateInstance()
+		return objectOf(pointers.Get(created[0])[0])
+	}
+	obj := &object{class: c, meta: &dictionary{}}
+	obj.id = alloc(obj)
+	if c.is("Node") {
+		obj.node = &node{}
+	}
+	obj.notify(notificationPostinitialize)
+	return obj
+}
+
+// objectOf returns the live object for the given instance id, or nil.
+func objectOf(id uint64) *object {
+	obj, _ := load(id).(*object)
+	if obj == nil || obj.freed {
+		return nil
+	}
+	return obj
+}
+
+func mustObject(obj [1]gd.Object) *object {
+	raw := pointers.Get(obj[0])
+	self := objectOf(raw[0])
+	if self == nil {
+		if raw[0] == 0 {
+			panic("enginetest: nil gd.Object dereference")
+		}
+		panic("enginetest: use after free")
+	}
+	return self
+}
+
+// ref returns a new Go reference to the object, that is owned by Go.
+func (obj *object) ref() [1]gd.Object {
+	return [1]gd.Object{pointers.Let[gd.Object]([3]uint64{obj.id, obj.id})}
+}
+
+// Notifications sent by the fake engine.
+const (
+	notificationPostinitialize = 0
+	notificationPredelete      = 1
+	notificationEnterTree      = 10
+	notificationExitTree       = 11
+	notificationReady          = 13
+	notificationPhysicsProcess = 16
+	notificationProcess        = 17
+	notificationParented       = 18
+	notificationUnparented     = 19
+)
+
+func (obj *object) notify(what int32) {
+	if obj.instance != nil {
+		obj.instance.Notification(what, false)
+	}
+}
+
+// virtual returns the virtual method implemented by the object's extension class, or nil.
+func (obj *object) virtual(method string) any {
+	ext := obj.class.extensionClass()
+	if ext == nil || obj.instance == nil {
+		return nil
+	}
+	classdb.Lock()
+	virtual, ok := ext.virtuals[method]
+	classdb.Unlock()
+	if !ok {
+		virtual = ext.extension.GetVirtual(borrowedName(method))
+		classdb.Lock()
+		ext.virtuals[method] = virtual
+		classdb.Unlock()
+	}
+	return virtual
+}
+
+// callVirtual calls the virtual method implemented by the object's extension class, if any, with
+// the given arguments laid out in engine memory, it reports whether a method was called.
+func (obj *object) callVirtual(method string, args []unsafe.Pointer, ret unsafe.Pointer) bool {
+	virtual := obj.virtual(method)
+	if virtual == nil {
+		return false
+	}
+	obj.instance.CallVirtual(borrowedName(method), virtual, addressOf(args), gd.Address(uintptr(ret)))
+	return true
+}
+
+// call the named method on the object, with the given arguments.
+func (obj *object) call(method string, args []any) (any, error) {
+	if m := obj.class.method(method); m != nil && obj.instance != nil {
+		variants, done := borrow(args)
+		defer done()
+		result, err := m.Call(obj.instance, variants...)
+		if err != nil {
+			return nil, err
+		}
+		return take(result), nil
+	}
+	if n := obj.class.native(method); n != nil {
+		return n.variantCall([]any{obj}, args)
+	}
+	return nil, gd.CallError{ErrorType: gd.ErrInvalidMethod}
+}
+
+func (obj *object) hasMethod(method string) bool {
+	return obj.class.method(method) != nil || obj.class.native(method) != nil
+}
+
+func (obj *object) set(property string, value any) bool {
+	if obj.instance != nil {
+		variants, done := borrow([]any{value})
+		ok := obj.instance.Set(borrowedName(property), variants[0])
+		done()
+		if ok {
+			return true
+		}
+	}
+	if p, ok := obj.class.property(property); ok && p.setter != "" {
+		_, err := obj.call(p.setter, []any{value})
+		return err == nil
+	}
+	if accessor, ok := nativeProperty(obj.class, property); ok && accessor.setter != "" {
+		_, err := obj.call(accessor.setter, []any{value})
+		return err == nil
+	}
+	return false
+}
+
+func (obj *object) get(property string) (any, bool) {
+	if obj.instance != nil {
+		if variant, ok := obj.instance.Get(borrowedName(property)); ok {
+			return take(variant), true
+		}
+	}
+	if p, ok := obj.class.property(property); ok && p.getter != "" {
+		value, err := obj.call(p.getter, nil)
+		return value, err == nil
+	}
+	if accessor, ok := nativeProperty(obj.class, property); ok {
+		value, err := obj.call(accessor.getter, nil)
+		return value, err == nil
+	}
+	return nil, false
+}
+
+func (obj *object) hasSignal(signal string) bool {
+	return obj.userSignals[signal] || obj.class.hasSignal(signal) || hasNativeSignal(obj.class, signal)
+}
+
+func (obj *object) connect(signal string, target *callable, flags int64) Error.Code {
+	if !obj.hasSignal(signal) {
+		return Error.InvalidParameter
+	}
+	for _, existing := range obj.connections[signal] {
+		if equal(existing.target, target) {
+			return Error.InvalidParameter
+		}
+	}
+	if obj.connections == nil {
+		obj.connections = make(map[string][]connection)
+	}
+	obj.connections[signal] = append(obj.connections[signal], connection{target: clone(target).(*callable), flags: flags})
+	return 0
+}
+
+func (obj *object) disconnect(signal string, target *callable) bool {
+	connections := obj.connections[signal]
+	for i, existing := range connections {
+		if equal(existing.target, target) {
+			obj.connections[signal] = slices.Delete(connections, i, i+1)
+			return true
+		}
+	}
+	return false
+}
+
+func (obj *object) isConnected(signal string, target *callable) bool {
+	for _, existing := range obj.connections[signal] {
+		if equal(existing.target, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (obj *object) emit(signal string, args []any) Error.Code {
+	if !obj.hasSignal(signal) {
+		return Error.Unavailable
+	}
+	if obj.blocked {
+		return Error.CantAcquireResource
+	}
+	for _, conn := range slices.Clone(obj.connections[signal]) {
+		if conn.flags&connectOneShot != 0 {
+			obj.disconnect(signal, conn.target)
+		}
+		if conn.flags&connectDeferred != 0 {
+			target, args := conn.target, slices.Clone(args)
+			deferCall(func() { callCallable(target, args) })
+			continue
+		}
+		if _, err := callCallable(conn.target, args); err != nil {
+			reportError(fmt.Sprintf("Error calling from signal '%s' to callable: '%s': %v.", signal, stringOf(conn.target), err))
+		}
+	}
+	return 0
+}
+
+// free the object, along with any children.
+func (obj *object) free() {
+	if obj.freed {
+		return
+	}
+	if obj.node != nil {
+		obj.freeNode()
+	}
+	obj.notify(notificationPredelete)
+	if obj.instance != nil {
+		obj.instance.Free()
+	}
+	obj.freed = true
+	obj.connections = nil
+	free(obj.id)
+}
+
+// callCallable calls the callable with the given arguments.
+func callCallable(c *callable, args []any) (any, error) {
+	args = append(slices.Clone(args), c.bound...)
+	switch {
+	case c.fn != nil:
+		variants, done := borrow(args)
+		defer done()
+		result, err := c.fn(variants...)
+		if err != nil {
+			return nil, err
+		}
+		return take(result), nil
+	case c.object != nil:
+		if c.object.freed {
+			return nil, gd.CallError{ErrorType: gd.ErrInstanceIsNil}
+		}
+		return c.object.call(c.method, args)
+	default:
+		return nil, gd.CallError{ErrorType: gd.ErrInstanceIsNil}
+	}
+}
+
+// borrow returns Variants for the values, which remain owned by the fake engine until done is called.
+func borrow(values []any) (variants []gd.Variant, done func()) {
+	raws := make([][3]uint64, len(values))
+	variants = make([]gd.Variant, len(values))
+	for i, value := range values {
+		writeVariant(unsafe.Pointer(&raws[i]), value)
+		variants[i] = pointers.Let[gd.Variant](raws[i])
+	}
+	return variants, func() {
+		for _, raw := range raws {
+			destroyVariant(raw)
+		}
+	}
+}
+
+// take returns the value of a Variant returned from Go, freeing it.
+func take(variant gd.Variant) any {
+	if variant == (gd.Variant{}) {
+		return nil
+	}
+	value := valueOf(variant)
+	variant.Free()
+	return value
+}
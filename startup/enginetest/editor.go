@@ -0,0 +1,292 @@
+package enginetest
+
+import (
+	"crypto/md5"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"graphics.gd/classdb/Control"
+	gd "graphics.gd/internal"
+	"graphics.gd/internal/gdclass"
+	"graphics.gd/variant/Error"
+	"graphics.gd/variant/Object"
+)
+
+// Editor makes the fake engine behave as the editor: Engine.is_editor_hint returns true, only tool
+// classes run their virtual methods and editor plugins are created when they are added. It should
+// be set before starting the fake engine, or before any editor plugins are added.
+var Editor bool
+
+// editor is the state of the fake editor.
+var editor struct {
+	sync.Mutex
+	plugins    map[string]*object // editor plugins, by class name.
+	pending    []string           // editor plugins added before the scene tree was created.
+	inspectors []*object
+	importers  []*object
+	docks      []*object
+}
+
+// addEditorPlugin creates the editor plugin and adds it to the scene tree, as an internal child of
+// the root, like the editor would.
+func addEditorPlugin(className string) {
+	if !Editor {
+		return
+	}
+	if tree.root == nil {
+		editor.Lock()
+		editor.pending = append(editor.pending, className)
+		editor.Unlock()
+		return
+	}
+	c := classNamed(className)
+	if c == nil || !c.is("EditorPlugin") {
+		reportError(fmt.Sprintf("Editor plugin class '%s' does not exist or does not inherit EditorPlugin.", className))
+		return
+	}
+	editor.Lock()
+	if _, ok := editor.plugins[className]; ok {
+		editor.Unlock()
+		return
+	}
+	plugin := construct(c)
+	if editor.plugins == nil {
+		editor.plugins = make(map[string]*object)
+	}
+	editor.plugins[className] = plugin
+	editor.Unlock()
+	tree.root.addChild(plugin, true, internalModeBack)
+}
+
+// addPendingEditorPlugins adds the editor plugins that were added before the scene tree was created.
+func addPendingEditorPlugins() {
+	editor.Lock()
+	pending := editor.pending
+	editor.pending = nil
+	editor.Unlock()
+	for _, className := range pending {
+		addEditorPlugin(className)
+	}
+}
+
+func removeEditorPlugin(className string) {
+	editor.Lock()
+	editor.pending = slices.DeleteFunc(editor.pending, func(pending string) bool { return pending == className })
+	plugin, ok := editor.plugins[className]
+	delete(editor.plugins, className)
+	editor.Unlock()
+	if ok {
+		plugin.free()
+	}
+}
+
+// release the editor's reference to a RefCounted object.
+func release(obj *object) {
+	obj.refcount--
+	if obj.refcount <= 0 {
+		obj.free()
+	}
+}
+
+var editorPluginMethods = map[string]any{
+	"add_inspector_plugin": func(self *object, plugin *object) {
+		editor.Lock()
+		defer editor.Unlock()
+		plugin.refcount++
+		editor.inspectors = append(editor.inspectors, plugin)
+	},
+	"remove_inspector_plugin": func(self *object, plugin *object) {
+		editor.Lock()
+		defer editor.Unlock()
+		if i := slices.Index(editor.inspectors, plugin); i >= 0 {
+			editor.inspectors = slices.Delete(editor.inspectors, i, i+1)
+			release(plugin)
+		}
+	},
+	"add_import_plugin": func(self *object, importer *object, first bool) {
+		editor.Lock()
+		defer editor.Unlock()
+		importer.refcount++
+		if first {
+			editor.importers = slices.Insert(editor.importers, 0, importer)
+		} else {
+			editor.importers = append(editor.importers, importer)
+		}
+	},
+	"remove_import_plugin": func(self *object, importer *object) {
+		editor.Lock()
+		defer editor.Unlock()
+		if i := slices.Index(editor.importers, importer); i >= 0 {
+			editor.importers = slices.Delete(editor.importers, i, i+1)
+			release(importer)
+		}
+	},
+	"add_control_to_dock": func(self *object, slot int64, control *object, shortcut *object) {
+		editor.Lock()
+		defer editor.Unlock()
+		if slices.Contains(editor.docks, control) {
+			reportError("Control is already in a dock.")
+			return
+		}
+		editor.docks = append(editor.docks, control)
+	},
+	"remove_control_from_docks": func(self *object, control *object) {
+		editor.Lock()
+		defer editor.Unlock()
+		editor.docks = slices.DeleteFunc(editor.docks, func(dock *object) bool { return dock == control })
+	},
+}
+
+// inspection collects the controls added by inspector plugins, while an object is inspected.
+var inspection struct {
+	current *Inspection
+}
+
+var inspectorPluginMethods = map[string]any{
+	"add_custom_control": func(self *object, control *object) {
+		if inspection.current == nil {
+			reportError("Inspector plugins can only add controls while an object is inspected.")
+			return
+		}
+		inspection.current.Controls = append(inspection.current.Controls, controlOf(control))
+	},
+	"add_property_editor": func(self *object, property str, control *object, addToEnd bool, label str) {
+		if inspection.current == nil {
+			reportError("Inspector plugins can only add property editors while an object is inspected.")
+			return
+		}
+		inspection.current.Properties[string(property)] = controlOf(control)
+	},
+}
+
+func controlOf(obj *object) Control.Instance {
+	return Control.Instance{gd.PointerMustAssertInstanceID[gdclass.Control](gd.EnginePointer(obj.id))}
+}
+
+// Docks returns the controls that editor plugins have added to the editor's docks, in order.
+func Docks() []Control.Instance {
+	editor.Lock()
+	defer editor.Unlock()
+	var docks []Control.Instance
+	for _, dock := range editor.docks {
+		docks = append(docks, controlOf(dock))
+	}
+	return docks
+}
+
+// Inspection records the controls that inspector plugins added while an object was inspected.
+type Inspection struct {
+	Controls   []Control.Instance          // custom controls, in the order they were added.
+	Properties map[string]Control.Instance // custom property editors, by property name.
+	Replaced   []string                    // properties whose built-in editor was removed.
+}
+
+// Inspect the object with the inspector plugins that editor plugins have added, calling their
+// virtual methods as the editor's inspector would, for each property of the object's class.
+func Inspect(target Object.Instance) Inspection {
+	obj := mustObject(target.AsObject())
+	editor.Lock()
+	var plugins []*object
+	for _, plugin := range editor.inspectors {
+		var handles bool
+		self := obj.id
+		if plugin.callVirtual("_can_handle", []unsafe.Pointer{unsafe.Pointer(&self)}, unsafe.Pointer(&handles)) && handles {
+			plugins = append(plugins, plugin)
+		}
+	}
+	editor.Unlock()
+	result := Inspection{Properties: make(map[string]Control.Instance)}
+	inspection.current = &result
+	defer func() { inspection.current = nil }()
+	self := obj.id
+	for _, plugin := range plugins {
+		plugin.callVirtual("_parse_begin", []unsafe.Pointer{unsafe.Pointer(&self)}, nil)
+	}
+	var properties []property
+	for c := obj.class; c != nil; c = c.parent {
+		properties = append(properties, c.properties...)
+	}
+	for _, prop := range properties {
+		info := prop.info
+		var name, hintString [1]uint64
+		write(gd.TypeString, unsafe.Pointer(&name), str(prop.name))
+		write(gd.TypeString, unsafe.Pointer(&hintString), str(prop.hintString))
+		vtype, hint, usage, wide := int64(info.Type), info.Hint, info.Usage, false
+		args := []unsafe.Pointer{
+			unsafe.Pointer(&self), unsafe.Pointer(&vtype), unsafe.Pointer(&name), unsafe.Pointer(&hint),
+			unsafe.Pointer(&hintString), unsafe.Pointer(&usage), unsafe.Pointer(&wide),
+		}
+		for _, plugin := range plugins {
+			var replace bool
+			if plugin.callVirtual("_parse_property", args, unsafe.Pointer(&replace)) && replace {
+				result.Replaced = append(result.Replaced, prop.name)
+				break
+			}
+		}
+		destroy(gd.TypeString, unsafe.Pointer(&name))
+		destroy(gd.TypeString, unsafe.Pointer(&hintString))
+	}
+	for _, plugin := range plugins {
+		plugin.callVirtual("_parse_end", []unsafe.Pointer{unsafe.Pointer(&self)}, nil)
+	}
+	return result
+}
+
+// Import the source file, added with [WriteFile], with the first import plugin that recognizes its
+// extension, as the editor would, returning the path of the imported resource.
+func Import(source string) (string, error) {
+	file := source[strings.LastIndex(source, "/")+1:]
+	var extension string
+	if i := strings.LastIndex(file, "."); i >= 0 {
+		extension = file[i+1:]
+	}
+	editor.Lock()
+	importers := slices.Clone(editor.importers)
+	editor.Unlock()
+	for _, importer := range importers {
+		var recognized [2]uint64
+		if !importer.callVirtual("_get_recognized_extensions", nil, unsafe.Pointer(&recognized)) {
+			continue
+		}
+		extensions, _ := read(gd.TypePackedStringArray, unsafe.Pointer(&recognized)).(*packed[str])
+		ok := extensions != nil && slices.Contains(extensions.elems, str(extension))
+		destroy(gd.TypePackedStringArray, unsafe.Pointer(&recognized))
+		if !ok {
+			continue
+		}
+		var saveExtension [1]uint64
+		importer.callVirtual("_get_save_extension", nil, unsafe.Pointer(&saveExtension))
+		imported, _ := read(gd.TypeString, unsafe.Pointer(&saveExtension)).(str)
+		destroy(gd.TypeString, unsafe.Pointer(&saveExtension))
+		save := fmt.Sprintf("res://.godot/imported/%s-%x", file, md5.Sum([]byte(source)))
+		var src, dst, options, variants, generated [2]uint64
+		write(gd.TypeString, unsafe.Pointer(&src), str(source))
+		write(gd.TypeString, unsafe.Pointer(&dst), str(save))
+		write(gd.TypeDictionary, unsafe.Pointer(&options), nil)
+		write(gd.TypeArray, unsafe.Pointer(&variants), nil)
+		write(gd.TypeArray, unsafe.Pointer(&generated), nil)
+		var code int64
+		importer.callVirtual("_import", []unsafe.Pointer{
+			unsafe.Pointer(&src), unsafe.Pointer(&dst), unsafe.Pointer(&options),
+			unsafe.Pointer(&variants), unsafe.Pointer(&generated),
+		}, unsafe.Pointer(&code))
+		for _, arg := range []struct {
+			vtype gd.VariantType
+			ptr   unsafe.Pointer
+		}{
+			{gd.TypeString, unsafe.Pointer(&src)}, {gd.TypeString, unsafe.Pointer(&dst)},
+			{gd.TypeDictionary, unsafe.Pointer(&options)},
+			{gd.TypeArray, unsafe.Pointer(&variants)}, {gd.TypeArray, unsafe.Pointer(&generated)},
+		} {
+			destroy(arg.vtype, arg.ptr)
+		}
+		if code != 0 {
+			return "", Error.Code(code)
+		}
+		return save + "." + string(imported), nil
+	}
+	return "", fmt.Errorf("enginetest: no import plugin recognizes %s", source)
+}
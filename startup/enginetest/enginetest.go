@@ -0,0 +1,178 @@
+// Package enginetest provides an in-process fake of the graphics engine, so that code written with
+// graphics.gd can be unit tested with go test, without needing Godot.
+//
+// The fake engine implements variants, strings, arrays, dictionaries and packed arrays in Go,
+// along with enough of the ClassDB, Object, RefCounted, Engine, Node and SceneTree classes to
+// register [graphics.gd/classdb.Extension] types, get and set their properties, wire up signals and
+// run them inside of a scene tree. Resources can be saved with ResourceSaver and loaded back with
+// ResourceLoader, which keep them in memory. [graphics.gd/startup.Run] (and so a
+// [graphics.gd/startup.Server]) runs the GoMainLoop as the engine's main loop until it quits,
+// without any delay between frames. Setting [Editor] runs the fake engine as a minimal
+// editor, so that editor plugins can be tested. Engine functionality that the fake does not support panics with a
+// descriptive message when it is called.
+//
+//	func TestMain(m *testing.M) {
+//		enginetest.Main(m)
+//	}
+//
+//	func TestPlayer(t *testing.T) {
+//		player := new(Player)
+//		enginetest.Root().AddChild(player.Super())
+//		enginetest.Step(1.0 / 60)
+//	}
+package enginetest
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"unsafe"
+
+	"graphics.gd/classdb/Node"
+	gd "graphics.gd/internal"
+	"graphics.gd/internal/gdclass"
+	"graphics.gd/internal/pointers"
+	"graphics.gd/variant/Callable"
+	"graphics.gd/variant/Float"
+)
+
+// Output receives everything printed by the fake engine, including errors and warnings.
+var Output io.Writer = os.Stderr
+
+// Capture replaces [Output] until the test finishes, returning everything that the fake engine
+// prints in the meantime.
+func Capture(tb testing.TB) *strings.Builder {
+	var output strings.Builder
+	previous := Output
+	Output = &output
+	tb.Cleanup(func() { Output = previous })
+	return &output
+}
+
+var started sync.Once
+
+// Start the fake engine, such that graphics.gd functions can be called. Any classes registered
+// with [graphics.gd/classdb.Register] are registered with the fake engine. Start may be called
+// more than once, only the first call has any effect.
+func Start() {
+	started.Do(func() {
+		linkHost(&gd.Global)
+		linkClassDB(&gd.Global)
+		unsupported(reflect.ValueOf(&gd.Global).Elem(), "")
+		gd.Global.ExtensionToken = 1
+		gd.Global.Init(gd.GDExtensionInitializationLevelScene)
+		for _, fn := range gd.StartupFunctions {
+			fn()
+		}
+		for _, fn := range gd.PostStartupFunctions {
+			fn()
+		}
+		if Editor {
+			gd.Global.Init(gd.GDExtensionInitializationLevelEditor)
+		}
+		newTree()
+		addPendingEditorPlugins()
+		Callable.Cycle()
+		gd.Linked = true
+		gd.HostedMainLoop = run
+	})
+}
+
+// Main starts the fake engine and then runs the tests, it is intended to be called from TestMain.
+func Main(m *testing.M) {
+	Start()
+	os.Exit(m.Run())
+}
+
+// Step advances the scene tree by one physics frame and one process frame, each of the given
+// duration. Any deferred calls, including signals sent on the channels of extension classes, are
+// made at the end of the frame, after which nodes queued for deletion are freed.
+func Step(delta Float.X) {
+	step(float64(delta))
+	Callable.Cycle()
+	pointers.Cycle()
+}
+
+// run the GoMainLoop class as the main loop, as the engine does for a project with a
+// run/main_loop_type of GoMainLoop, stepping frames until either the main loop or the SceneTree
+// quits. A GoMainLoop that extends SceneTree processes the nodes of the existing tree.
+func run() {
+	c := classNamed("GoMainLoop")
+	if c == nil || !c.is("MainLoop") {
+		reportError("The main loop specified in the project settings is of type 'GoMainLoop', which doesn't exist.")
+		return
+	}
+	loop := construct(c)
+	if loop == nil {
+		reportError("Can't instantiate the main loop of type 'GoMainLoop'.")
+		return
+	}
+	tree.mainLoop, tree.quit, tree.exitCode = loop, false, 0
+	defer func() {
+		tree.mainLoop = nil
+		loop.free()
+	}()
+	scene := c.is("SceneTree")
+	loop.callVirtual("_initialize", nil, nil)
+	delta := physicsDelta()
+	for !tree.quit {
+		var done, finished bool
+		loop.callVirtual("_physics_process", []unsafe.Pointer{unsafe.Pointer(&delta)}, unsafe.Pointer(&done))
+		if scene {
+			physicsFrame()
+		}
+		loop.callVirtual("_process", []unsafe.Pointer{unsafe.Pointer(&delta)}, unsafe.Pointer(&finished))
+		if scene {
+			processFrame(delta)
+		} else {
+			tree.physicsFrames++
+			tree.processFrames++
+			flush()
+		}
+		Callable.Cycle()
+		pointers.Cycle()
+		if done || finished {
+			break
+		}
+	}
+	loop.callVirtual("_finalize", nil, nil)
+}
+
+// Root returns the root node of the scene tree.
+func Root() Node.Instance {
+	return Node.Instance{gd.PointerMustAssertInstanceID[gdclass.Node](gd.EnginePointer(tree.root.id))}
+}
+
+// unsupported replaces each nil function in the API with one that panics, so that calling engine
+// functionality that the fake does not implement reports which function was called.
+func unsupported(rvalue reflect.Value, path string) {
+	switch rvalue.Kind() {
+	case reflect.Struct:
+		for i := range rvalue.NumField() {
+			field := rvalue.Type().Field(i)
+			if !field.IsExported() || field.Anonymous {
+				continue
+			}
+			unsupported(rvalue.Field(i), path+"."+field.Name)
+		}
+	case reflect.Func:
+		if rvalue.IsNil() {
+			message := fmt.Sprintf("enginetest: gd.Global%s is not implemented", path)
+			rvalue.Set(reflect.MakeFunc(rvalue.Type(), func([]reflect.Value) []reflect.Value {
+				panic(message)
+			}))
+		}
+	}
+}
+
+func reportError(message string) {
+	fmt.Fprintln(Output, "ERROR:", message)
+}
+
+func reportWarning(message string) {
+	fmt.Fprintln(Output, "WARNING:", message)
+}
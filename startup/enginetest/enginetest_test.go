@@ -0,0 +1,130 @@
+package enginetest_test
+
+import (
+	"testing"
+	"time"
+
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Node"
+	gd "graphics.gd/internal"
+	"graphics.gd/startup/enginetest"
+	"graphics.gd/variant"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/Float"
+	"graphics.gd/variant/Packed"
+	"graphics.gd/variant/String"
+	"graphics.gd/variant/StringName"
+)
+
+func TestMain(m *testing.M) {
+	classdb.Register[Player]()
+	enginetest.Main(m)
+}
+
+type Player struct {
+	classdb.Extension[Player, Node.Instance]
+
+	HealthChanged chan<- func() (old, new int)
+
+	Health int
+
+	ready   bool
+	elapsed Float.X
+}
+
+func (p *Player) Ready() {
+	p.ready = true
+	if p.Health == 0 {
+		p.Health = 10
+	}
+}
+
+func (p *Player) Process(delta Float.X) { p.elapsed += delta }
+
+func (p *Player) TakeDamage(amount int) {
+	old := p.Health
+	p.Health -= amount
+	p.HealthChanged <- func() (int, int) { return old, p.Health }
+}
+
+func TestStrings(t *testing.T) {
+	str := String.Append(String.New("Hello, "), String.New("World!"))
+	if str.String() != "Hello, World!" {
+		t.Fatal(str.String())
+	}
+	if StringName.New("Hello").String() != "Hello" {
+		t.Fatal("unexpected StringName")
+	}
+}
+
+func TestVariants(t *testing.T) {
+	if v := variant.New(3.5); v.Float64() != 3.5 {
+		t.Fatal(v.Float64())
+	}
+	if v := variant.New("text"); v.String() != "text" {
+		t.Fatal(v.String())
+	}
+}
+
+func TestArrays(t *testing.T) {
+	var numbers Array.Any
+	numbers.Append(variant.New(1))
+	numbers.Append(variant.New(2))
+	if numbers.Len() != 2 || numbers.Index(1).Int() != 2 {
+		t.Fatal("unexpected array contents")
+	}
+	var floats Packed.Array[float32]
+	floats.Resize(2)
+	floats.SetIndex(1, 2)
+	if floats.Index(1) != 2 {
+		t.Fatal("unexpected packed array contents")
+	}
+}
+
+func TestExtension(t *testing.T) {
+	player := new(Player)
+	defer player.Super().QueueFree()
+	object := player.AsObject()[0]
+	object.Set(gd.NewStringName("Health"), gd.NewVariant(5))
+	if health := object.Get(gd.NewStringName("Health")).Interface(); health != int64(5) {
+		t.Fatalf("expected health 5, got %v", health)
+	}
+	var changes [][2]int
+	object.Connect(gd.NewStringName("HealthChanged"), gd.NewCallable(func(old, new int) {
+		changes = append(changes, [2]int{old, new})
+	}), 0)
+	player.TakeDamage(2)
+	for range 100 { // channel signals are emitted as deferred calls.
+		if len(changes) > 0 {
+			break
+		}
+		enginetest.Step(0)
+		time.Sleep(time.Millisecond)
+	}
+	if len(changes) != 1 || changes[0] != [2]int{5, 3} {
+		t.Fatalf("unexpected signal emissions %v", changes)
+	}
+}
+
+func TestSceneTree(t *testing.T) {
+	player := new(Player)
+	player.Super().SetName("Player")
+	enginetest.Root().AddChild(player.Super())
+	if !player.ready || player.Health != 10 {
+		t.Fatal("expected Ready to be called when added to the tree")
+	}
+	found := Node.Instance(enginetest.Root().GetNode("Player"))
+	if found.AsObject()[0].GetInstanceId() != player.AsObject()[0].GetInstanceId() {
+		t.Fatal("expected to find the player by path")
+	}
+	enginetest.Step(0.5)
+	enginetest.Step(0.5)
+	if player.elapsed != 1 {
+		t.Fatalf("expected 1 second of processing, got %v", player.elapsed)
+	}
+	player.Super().QueueFree()
+	enginetest.Step(0)
+	if enginetest.Root().GetChildCount() != 0 {
+		t.Fatal("expected the player to be freed")
+	}
+}
@@ -0,0 +1,45 @@
+package enginetest
+
+import (
+	"slices"
+	"sync"
+
+	"graphics.gd/variant/Error"
+)
+
+// sources are the files that can be read with FileAccess, by path, see [WriteFile].
+var sources struct {
+	sync.Mutex
+	files map[string][]byte
+	err   Error.Code // result of the last attempt to open a file.
+}
+
+// WriteFile adds a file to the fake engine, so that it can be read with FileAccess, such as the
+// source file of an [Import].
+func WriteFile(path string, data []byte) {
+	sources.Lock()
+	defer sources.Unlock()
+	if sources.files == nil {
+		sources.files = make(map[string][]byte)
+	}
+	sources.files[path] = slices.Clone(data)
+}
+
+var fileAccessMethods = map[string]any{
+	"get_file_as_bytes": func(path str) *packed[byte] {
+		sources.Lock()
+		defer sources.Unlock()
+		data, ok := sources.files[string(path)]
+		if !ok {
+			sources.err = Error.FileNotFound
+			return &packed[byte]{}
+		}
+		sources.err = 0
+		return &packed[byte]{slices.Clone(data)}
+	},
+	"get_open_error": func() int64 {
+		sources.Lock()
+		defer sources.Unlock()
+		return int64(sources.err)
+	},
+}
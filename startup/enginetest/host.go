@@ -0,0 +1,589 @@
+package enginetest
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"unicode/utf8"
+	"unsafe"
+
+	gd "graphics.gd/internal"
+	"graphics.gd/internal/callframe"
+	"graphics.gd/internal/pointers"
+	PackedType "graphics.gd/variant/Packed"
+)
+
+// memory allocated on behalf of Go, kept alive until it is freed.
+var memory struct {
+	sync.Mutex
+	blocks map[gd.Address][]byte
+}
+
+func allocate(size uintptr) gd.Address {
+	block := make([]byte, max(size, 1))
+	addr := gd.Address(uintptr(unsafe.Pointer(&block[0])))
+	memory.Lock()
+	defer memory.Unlock()
+	if memory.blocks == nil {
+		memory.blocks = make(map[gd.Address][]byte)
+	}
+	memory.blocks[addr] = block
+	return addr
+}
+
+func deallocate(addr gd.Address) []byte {
+	memory.Lock()
+	defer memory.Unlock()
+	block := memory.blocks[addr]
+	delete(memory.blocks, addr)
+	return block
+}
+
+// pointerOf converts the address of Go-allocated memory back into a pointer.
+func pointerOf(addr gd.Address) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&addr))
+}
+
+func linkHost(API *gd.API) {
+	API.GetGodotVersion = func() gd.Version {
+		return gd.Version{Major: 4, Minor: 4, Value: "4.4.enginetest"}
+	}
+	API.GetNativeStructSize = func(gd.StringName) uintptr { return 0 }
+	API.GetLibraryPath = func(gd.ExtensionToken) gd.String {
+		return pointers.New[gd.String]([1]gd.EnginePointer{alloc(str(""))})
+	}
+	API.Memory.Allocate = allocate
+	API.Memory.Reallocate = func(addr gd.Address, size uintptr) gd.Address {
+		resized := allocate(size)
+		copy(unsafe.Slice((*byte)(pointerOf(resized)), size), deallocate(addr))
+		return resized
+	}
+	API.Memory.Free = func(addr gd.Address) { deallocate(addr) }
+	API.Memory.Index = func(addr gd.Address, n int, size uintptr) unsafe.Pointer {
+		return unsafe.Add(pointerOf(addr), uintptr(n)*size)
+	}
+	API.Memory.Write = func(dst gd.Address, src unsafe.Pointer, size uintptr) {
+		copy(unsafe.Slice((*byte)(pointerOf(dst)), size), unsafe.Slice((*byte)(src), size))
+	}
+	API.PrintError = func(code, function, file string, line int32, notifyEditor bool) {
+		reportError(fmt.Sprintf("%s\n   at: %s (%s:%d)", code, function, file, line))
+	}
+	API.PrintErrorMessage = func(code, message, function, file string, line int32, notifyEditor bool) {
+		reportError(fmt.Sprintf("%s\n   at: %s (%s:%d)", message, function, file, line))
+	}
+	API.PrintScriptError = API.PrintError
+	API.PrintScriptErrorMessage = API.PrintErrorMessage
+	API.PrintWarning = func(code, function, file string, line int32, notifyEditor bool) {
+		reportWarning(fmt.Sprintf("%s\n   at: %s (%s:%d)", code, function, file, line))
+	}
+	API.PrintWarningMessage = func(code, message, function, file string, line int32, notifyEditor bool) {
+		reportWarning(fmt.Sprintf("%s\n   at: %s (%s:%d)", message, function, file, line))
+	}
+	linkVariants(API)
+	linkStrings(API)
+	linkPacked(&API.PackedByteArray, handleOf[gd.PackedByteArray])
+	linkPacked(&API.PackedInt32Array, handleOf[gd.PackedInt32Array])
+	linkPacked(&API.PackedInt64Array, handleOf[gd.PackedInt64Array])
+	linkPacked(&API.PackedFloat32Array, handleOf[gd.PackedFloat32Array])
+	linkPacked(&API.PackedFloat64Array, handleOf[gd.PackedFloat64Array])
+	linkPacked(&API.PackedVector2Array, handleOf[gd.PackedVector2Array])
+	linkPacked(&API.PackedVector3Array, handleOf[gd.PackedVector3Array])
+	linkPacked(&API.PackedVector4Array, handleOf[gd.PackedVector4Array])
+	linkPacked(&API.PackedColorArray, handleOf[gd.PackedColorArray])
+	linkCollections(API)
+	linkObjects(API)
+	API.Callables.Create = func(fn func(...gd.Variant) (gd.Variant, error)) gd.Callable {
+		return pointers.New[gd.Callable]([2]uint64{alloc(&callable{fn: fn})})
+	}
+	API.Callables.Get = func(c gd.Callable) (func(...gd.Variant) (gd.Variant, error), bool) {
+		custom, _ := load(pointers.Get(c)[0]).(*callable)
+		if custom == nil || custom.fn == nil {
+			return nil, false
+		}
+		return custom.fn, true
+	}
+	API.EditorPlugins.Add = func(plugin gd.StringName) { addEditorPlugin(nameOf(plugin)) }
+	API.EditorPlugins.Remove = func(plugin gd.StringName) { removeEditorPlugin(nameOf(plugin)) }
+	API.EditorHelp.Load = func([]byte) {}
+}
+
+func linkVariants(API *gd.API) {
+	API.Variants.NewCopy = func(src gd.Variant) gd.Variant { return newVariant(valueOf(src)) }
+	API.Variants.NewNil = func() gd.Variant { return newVariant(nil) }
+	API.Variants.Destroy = func(self gd.Variant) { destroyVariant(pointers.Get(self)) }
+	API.Variants.Call = func(self gd.Variant, method gd.StringName, args ...gd.Variant) (gd.Variant, error) {
+		values := make([]any, len(args))
+		for i, arg := range args {
+			values[i] = valueOf(arg)
+		}
+		result, err := callBuiltin(valueOf(self), nameOf(method), values)
+		if err != nil {
+			return gd.Variant{}, err
+		}
+		return newVariant(result), nil
+	}
+	API.Variants.CallStatic = func(vtype gd.VariantType, method gd.StringName, args ...gd.Variant) (gd.Variant, error) {
+		return gd.Variant{}, gd.CallError{ErrorType: gd.ErrInvalidMethod}
+	}
+	API.Variants.Evaluate = func(op gd.Operator, a, b gd.Variant) (gd.Variant, bool) {
+		result, ok := evaluate(op, valueOf(a), valueOf(b))
+		if !ok {
+			return gd.Variant{}, false
+		}
+		return newVariant(result), true
+	}
+	API.Variants.Set = func(self, key, val gd.Variant) bool {
+		return setKey(valueOf(self), valueOf(key), valueOf(val))
+	}
+	API.Variants.SetKeyed = API.Variants.Set
+	API.Variants.SetNamed = func(self gd.Variant, key gd.StringName, val gd.Variant) bool {
+		return setKey(valueOf(self), name(nameOf(key)), valueOf(val))
+	}
+	API.Variants.SetIndexed = func(self gd.Variant, index gd.Int, val gd.Variant) (ok, oob bool) {
+		return setIndex(valueOf(self), index, valueOf(val))
+	}
+	API.Variants.Get = func(self, key gd.Variant) (gd.Variant, bool) {
+		value, ok := getKey(valueOf(self), valueOf(key))
+		if !ok {
+			return gd.Variant{}, false
+		}
+		return newVariant(value), true
+	}
+	API.Variants.GetKeyed = API.Variants.Get
+	API.Variants.GetNamed = func(self gd.Variant, key gd.StringName) (gd.Variant, bool) {
+		value, ok := getKey(valueOf(self), name(nameOf(key)))
+		if !ok {
+			return gd.Variant{}, false
+		}
+		return newVariant(value), true
+	}
+	API.Variants.GetIndexed = func(self gd.Variant, index gd.Int) (val gd.Variant, ok, oob bool) {
+		value, ok, oob := getIndex(valueOf(self), index)
+		if !ok {
+			return gd.Variant{}, ok, oob
+		}
+		return newVariant(value), ok, oob
+	}
+	API.Variants.IteratorInitialize = func(self gd.Variant) (gd.Variant, bool) {
+		n, ok := iterations(valueOf(self))
+		if !ok {
+			return gd.Variant{}, false
+		}
+		return newVariant(int64(0)), n > 0
+	}
+	API.Variants.IteratorNext = func(self gd.Variant, iterator gd.Variant) bool {
+		n, _ := iterations(valueOf(self))
+		i, _ := valueOf(iterator).(int64)
+		store(pointers.Get(iterator)[1], i+1)
+		return i+1 < n
+	}
+	API.Variants.IteratorGet = func(self gd.Variant, iterator gd.Variant) (gd.Variant, bool) {
+		i, _ := valueOf(iterator).(int64)
+		value := valueOf(self)
+		if _, ok := value.(int64); ok {
+			return newVariant(i), true
+		}
+		if d, ok := value.(*dictionary); ok {
+			if i < 0 || i >= int64(len(d.keys)) {
+				return gd.Variant{}, false
+			}
+			return newVariant(d.keys[i]), true
+		}
+		elem, ok, _ := getIndex(value, i)
+		if !ok {
+			return gd.Variant{}, false
+		}
+		return newVariant(elem), true
+	}
+	API.Variants.Hash = func(self gd.Variant) gd.Int { return hashOf(valueOf(self)) }
+	API.Variants.RecursiveHash = func(self gd.Variant, count gd.Int) gd.Int { return hashOf(valueOf(self)) }
+	API.Variants.HashCompare = func(self, variant gd.Variant) bool { return equal(valueOf(self), valueOf(variant)) }
+	API.Variants.Booleanize = func(self gd.Variant) bool { return truthy(valueOf(self)) }
+	API.Variants.Duplicate = func(self gd.Variant, deep bool) gd.Variant {
+		return newVariant(duplicate(valueOf(self), deep))
+	}
+	API.Variants.Stringify = func(self gd.Variant) gd.String {
+		return pointers.New[gd.String]([1]gd.EnginePointer{alloc(str(stringOf(valueOf(self))))})
+	}
+	API.Variants.GetType = func(self gd.Variant) gd.VariantType { return typeOf(valueOf(self)) }
+	API.Variants.HasMethod = func(self gd.Variant, method gd.StringName) bool {
+		value := valueOf(self)
+		if obj, ok := value.(*object); ok {
+			return obj.hasMethod(nameOf(method))
+		}
+		return builtinMethod(typeOf(value), nameOf(method)) != nil
+	}
+	API.Variants.HasMember = func(self gd.Variant, member gd.StringName) bool {
+		_, ok := getKey(valueOf(self), name(nameOf(member)))
+		return ok
+	}
+	API.Variants.HasKey = func(self gd.Variant, key gd.Variant) (hasKey, valid bool) {
+		switch value := valueOf(self).(type) {
+		case *dictionary:
+			return value.find(valueOf(key)) >= 0, true
+		case *object:
+			_, ok := getKey(value, valueOf(key))
+			return ok, true
+		}
+		return false, false
+	}
+	API.Variants.GetTypeName = func(vtype gd.VariantType) gd.String {
+		return pointers.New[gd.String]([1]gd.EnginePointer{alloc(str(typeNames[vtype]))})
+	}
+	API.Variants.CanConvert = func(self gd.Variant, to gd.VariantType) bool {
+		return convertible(typeOf(valueOf(self)), to)
+	}
+	API.Variants.CanConvertStrict = API.Variants.CanConvert
+	API.Variants.FromTypeConstructor = func(vtype gd.VariantType) func(ret callframe.Ptr[gd.VariantPointers], arg callframe.Addr) {
+		return func(ret callframe.Ptr[gd.VariantPointers], arg callframe.Addr) {
+			writeVariant(ret.UnsafePointer(), read(vtype, arg.UnsafePointer()))
+		}
+	}
+	API.Variants.ToTypeConstructor = func(vtype gd.VariantType) func(ret callframe.Addr, arg callframe.Ptr[gd.VariantPointers]) {
+		return func(ret callframe.Addr, arg callframe.Ptr[gd.VariantPointers]) {
+			write(vtype, ret.UnsafePointer(), readVariant(arg.UnsafePointer()))
+		}
+	}
+	API.Variants.PointerOperatorEvaluator = pointerOperator
+	API.Variants.GetPointerBuiltinMethod = func(vtype gd.VariantType, method gd.StringName, hash gd.Int) func(base callframe.Addr, args callframe.Args, ret callframe.Addr, c int32) {
+		return pointerBuiltin(vtype, nameOf(method))
+	}
+	API.Variants.GetPointerConstructor = pointerConstructor
+	API.Variants.GetPointerDestructor = pointerDestructor
+	API.Variants.GetPointerUtilityFunction = func(method gd.StringName, hash gd.Int) func(ret callframe.Addr, args callframe.Args, c int32) {
+		return pointerUtility(nameOf(method))
+	}
+	API.Variants.Construct = func(vtype gd.VariantType, args ...gd.Variant) (gd.Variant, error) {
+		switch len(args) {
+		case 0:
+			return newVariant(zero(vtype)), nil
+		case 1:
+			value := valueOf(args[0])
+			if !convertible(typeOf(value), vtype) {
+				return gd.Variant{}, gd.CallError{ErrorType: gd.ErrInvalidArgument, Expected: int32(vtype)}
+			}
+			return newVariant(convert(value, vtype)), nil
+		}
+		return gd.Variant{}, gd.CallError{ErrorType: gd.ErrTooManyArguments, Expected: 1}
+	}
+}
+
+// setKey implements value[key] = val, for objects and dictionaries.
+func setKey(value, key, val any) bool {
+	switch v := value.(type) {
+	case *object:
+		return v.set(stringOf(key), val)
+	case *dictionary:
+		if !mutable(v.readonly) {
+			return false
+		}
+		v.set(key, val)
+		return true
+	case *array:
+		if i, ok := key.(int64); ok {
+			ok, _ := setIndex(v, i, val)
+			return ok
+		}
+	}
+	return false
+}
+
+// getKey implements value[key], for objects and dictionaries.
+func getKey(value, key any) (any, bool) {
+	switch v := value.(type) {
+	case *object:
+		return v.get(stringOf(key))
+	case *dictionary:
+		return v.get(key)
+	case *array:
+		if i, ok := key.(int64); ok {
+			elem, ok, _ := getIndex(v, i)
+			return elem, ok
+		}
+	}
+	return nil, false
+}
+
+// setIndex implements value[index] = val, for arrays and packed arrays.
+func setIndex(value any, index int64, val any) (ok, oob bool) {
+	switch v := value.(type) {
+	case *array:
+		if index < 0 || index >= int64(len(v.elems)) {
+			return false, true
+		}
+		if !mutable(v.readonly) {
+			return false, false
+		}
+		v.assign(int(index), val)
+		return true, false
+	}
+	if typeOf(value) < gd.TypePackedByteArray {
+		return false, false
+	}
+	if n, _ := iterations(value); index < 0 || index >= n {
+		return false, true
+	}
+	builtinMethod(typeOf(value), "set").call([]any{value, index, val})
+	return true, false
+}
+
+// getIndex implements value[index], for strings, arrays and packed arrays.
+func getIndex(value any, index int64) (val any, ok, oob bool) {
+	n, ok := iterations(value)
+	if !ok {
+		return nil, false, false
+	}
+	if index < 0 {
+		index += n
+	}
+	if index < 0 || index >= n {
+		return nil, false, true
+	}
+	switch v := value.(type) {
+	case *array:
+		return v.elems[index], true, false
+	case str:
+		return str(runes(v)[index]), true, false
+	case int64:
+		return index, true, false
+	}
+	return builtinMethod(typeOf(value), "get").call([]any{value, index}), true, false
+}
+
+// iterations returns the number of iterations of a for loop over the value.
+func iterations(value any) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case str:
+		return int64(utf8.RuneCountInString(string(v))), true
+	case *array:
+		return int64(len(v.elems)), true
+	case *dictionary:
+		return int64(len(v.keys)), true
+	}
+	if typeOf(value) >= gd.TypePackedByteArray {
+		return builtinMethod(typeOf(value), "size").call([]any{value}).(int64), true
+	}
+	return 0, false
+}
+
+// textOf returns the text of a String, StringName or NodePath handle.
+func textOf(handle uint64) []rune {
+	return []rune(stringOf(load(handle)))
+}
+
+func linkStrings(API *gd.API) {
+	API.Strings.New = func(s string) gd.String {
+		return pointers.New[gd.String]([1]gd.EnginePointer{alloc(str(s))})
+	}
+	API.Strings.Get = func(s gd.String) string {
+		return stringOf(load(pointers.Get(s)[0]))
+	}
+	API.Strings.Index = func(s gd.String, index gd.Int) rune {
+		text := textOf(pointers.Get(s)[0])
+		if index < 0 || index >= gd.Int(len(text)) {
+			return 0
+		}
+		return text[index]
+	}
+	API.Strings.SetIndex = func(s gd.String, index gd.Int, r rune) {
+		handle := pointers.Get(s)[0]
+		text := textOf(handle)
+		switch {
+		case index >= 0 && index < gd.Int(len(text)):
+			text[index] = r
+		case index == gd.Int(len(text)) && r == 0:
+		default:
+			reportError(fmt.Sprintf("Index p_index = %d is out of bounds (%d).", index, len(text)))
+			return
+		}
+		store(handle, str(text))
+	}
+	API.Strings.Append = func(s, other gd.String) gd.String {
+		raw := pointers.Get(s)
+		store(raw[0], str(stringOf(load(raw[0]))+stringOf(load(pointers.Get(other)[0]))))
+		return pointers.New[gd.String](raw)
+	}
+	API.Strings.AppendRune = func(s gd.String, r rune) {
+		handle := pointers.Get(s)[0]
+		store(handle, str(stringOf(load(handle))+string(r)))
+	}
+	API.Strings.Resize = func(s gd.String, size gd.Int) {
+		handle := pointers.Get(s)[0]
+		text := textOf(handle)
+		if size <= 0 {
+			store(handle, str(""))
+			return
+		}
+		size-- // size includes the null terminator.
+		for gd.Int(len(text)) < size {
+			text = append(text, ' ')
+		}
+		store(handle, str(text[:size]))
+	}
+	API.StringNames.New = func(s string) gd.StringName {
+		return pointers.New[gd.StringName]([1]gd.EnginePointer{intern(s)})
+	}
+}
+
+// handleOf returns the handle of a packed array.
+func handleOf[T pointers.Generic[T, gd.PackedPointers]](p T) uint64 { return pointers.Get(p)[0] }
+
+func linkPacked[T gd.Packed[T, V], V PackedType.Type](fns *gd.PackedFunctionsFor[T, V], handle func(T) uint64) {
+	elems := func(p T) *packed[V] {
+		value, _ := load(handle(p)).(*packed[V])
+		if value == nil {
+			panic("enginetest: invalid packed array")
+		}
+		return value
+	}
+	fns.Index = func(p T, i gd.Int) V {
+		return elems(p).elems[i]
+	}
+	fns.SetIndex = func(p T, i gd.Int, v V) {
+		elems(p).elems[i] = v
+	}
+	fns.CopyAsSlice = func(p T) []V {
+		if handle(p) == 0 {
+			return nil
+		}
+		return slices.Clone(elems(p).elems)
+	}
+	fns.CopyFromSlice = func(p T, values []V) {
+		copy(elems(p).elems, values)
+	}
+}
+
+func linkCollections(API *gd.API) {
+	strings := func(p gd.PackedStringArray) *packed[str] {
+		value, _ := load(pointers.Get(p)[0]).(*packed[str])
+		if value == nil {
+			panic("enginetest: invalid packed array")
+		}
+		return value
+	}
+	API.PackedStringArray.Index = func(p gd.PackedStringArray, i gd.Int) gd.String {
+		return pointers.New[gd.String]([1]gd.EnginePointer{alloc(strings(p).elems[i])})
+	}
+	API.PackedStringArray.SetIndex = func(p gd.PackedStringArray, i gd.Int, s gd.String) {
+		strings(p).elems[i] = str(stringOf(load(pointers.Get(s)[0])))
+	}
+	API.PackedStringArray.CopyAsSlice = func(p gd.PackedStringArray) []gd.String {
+		if pointers.Get(p)[0] == 0 {
+			return nil
+		}
+		elems := strings(p).elems
+		result := make([]gd.String, len(elems))
+		for i, elem := range elems {
+			result[i] = pointers.New[gd.String]([1]gd.EnginePointer{alloc(elem)})
+		}
+		return result
+	}
+	API.PackedStringArray.CopyFromSlice = func(p gd.PackedStringArray, values []gd.String) {
+		elems := strings(p).elems
+		for i := range min(len(elems), len(values)) {
+			elems[i] = str(stringOf(load(pointers.Get(values[i])[0])))
+		}
+	}
+	arrayOf := func(a gd.Array) *array {
+		value, _ := load(pointers.Get(a)[0]).(*array)
+		if value == nil {
+			panic("enginetest: invalid array")
+		}
+		return value
+	}
+	API.Array.Index = func(a gd.Array, i gd.Int) gd.Variant {
+		value, ok, _ := getIndex(arrayOf(a), i)
+		if !ok {
+			reportError(fmt.Sprintf("Index p_index = %d is out of bounds (%d).", i, len(arrayOf(a).elems)))
+		}
+		return newVariant(value)
+	}
+	API.Array.SetIndex = func(a gd.Array, i gd.Int, v gd.Variant) {
+		if _, oob := setIndex(arrayOf(a), i, valueOf(v)); oob {
+			reportError(fmt.Sprintf("Index p_index = %d is out of bounds (%d).", i, len(arrayOf(a).elems)))
+		}
+	}
+	API.Array.Set = func(self, from gd.Array) {
+		store(pointers.Get(self)[0], arrayOf(from))
+	}
+	API.Array.SetTyped = func(self gd.Array, vtype gd.VariantType, className gd.StringName, script gd.Object) {
+		a := arrayOf(self)
+		a.typed, a.class = vtype, nameOf(className)
+	}
+	dictionaryOf := func(d gd.Dictionary) *dictionary {
+		value, _ := load(pointers.Get(d)[0]).(*dictionary)
+		if value == nil {
+			panic("enginetest: invalid dictionary")
+		}
+		return value
+	}
+	API.Dictionary.Index = func(d gd.Dictionary, key gd.Variant) gd.Variant {
+		value, _ := dictionaryOf(d).get(valueOf(key))
+		return newVariant(value)
+	}
+	API.Dictionary.SetIndex = func(d gd.Dictionary, key, val gd.Variant) {
+		setKey(dictionaryOf(d), valueOf(key), valueOf(val))
+	}
+}
+
+func linkObjects(API *gd.API) {
+	API.Object.Destroy = func(obj [1]gd.Object) { mustObject(obj).free() }
+	API.Object.GetSingleton = func(className gd.StringName) [1]gd.Object {
+		obj := singletonOf(nameOf(className))
+		if obj == nil {
+			return [1]gd.Object{}
+		}
+		return [1]gd.Object{pointers.Raw[gd.Object]([3]uint64{obj.id})}
+	}
+	API.Object.GetInstanceBinding = func(obj [1]gd.Object, token gd.ExtensionToken, _ gd.InstanceBindingType) any {
+		return mustObject(obj).bindings[token]
+	}
+	API.Object.SetInstanceBinding = func(obj [1]gd.Object, token gd.ExtensionToken, binding any, _ gd.InstanceBindingType) {
+		self := mustObject(obj)
+		if self.bindings == nil {
+			self.bindings = make(map[gd.ExtensionToken]any)
+		}
+		self.bindings[token] = binding
+	}
+	API.Object.FreeInstanceBinding = func(obj [1]gd.Object, token gd.ExtensionToken) {
+		delete(mustObject(obj).bindings, token)
+	}
+	API.Object.SetInstance = func(obj [1]gd.Object, className gd.StringName, instance gd.ObjectInterface) {
+		self := mustObject(obj)
+		if c := classNamed(nameOf(className)); c != nil {
+			self.class = c
+		}
+		self.instance = instance
+	}
+	API.Object.GetClassName = func(obj [1]gd.Object, token gd.ExtensionToken) gd.String {
+		return pointers.New[gd.String]([1]gd.EnginePointer{alloc(str(mustObject(obj).class.name))})
+	}
+	API.Object.CastTo = func(obj [1]gd.Object, tag gd.ClassTag) [1]gd.Object {
+		raw := pointers.Get(obj[0])
+		if raw[0] == 0 {
+			return [1]gd.Object{}
+		}
+		self := mustObject(obj)
+		classdb.Lock()
+		var target *class
+		if tag > 0 && int(tag) <= len(classdb.byTag) {
+			target = classdb.byTag[tag-1]
+		}
+		classdb.Unlock()
+		if target == nil || !self.class.is(target.name) {
+			return [1]gd.Object{}
+		}
+		return obj
+	}
+	API.Object.GetInstanceID = func(obj [1]gd.Object) gd.ObjectID {
+		return gd.ObjectID(mustObject(obj).id)
+	}
+	API.Object.GetInstanceFromID = func(id gd.ObjectID) [1]gd.Object {
+		if objectOf(uint64(id)) == nil {
+			return [1]gd.Object{}
+		}
+		return [1]gd.Object{gd.PointerMustAssertInstanceID[gd.Object](gd.EnginePointer(id))}
+	}
+}
@@ -0,0 +1,925 @@
+package enginetest
+
+// inherits maps each engine class to the class it directly extends, so that [gd.API] casts and
+// method binds resolve against the same hierarchy as the engine.
+var inherits = map[string]string{
+	"AESContext":                               "RefCounted",
+	"AStar2D":                                  "RefCounted",
+	"AStar3D":                                  "RefCounted",
+	"AStarGrid2D":                              "RefCounted",
+	"AcceptDialog":                             "Window",
+	"AnimatableBody2D":                         "StaticBody2D",
+	"AnimatableBody3D":                         "StaticBody3D",
+	"AnimatedSprite2D":                         "Node2D",
+	"AnimatedSprite3D":                         "SpriteBase3D",
+	"AnimatedTexture":                          "Texture2D",
+	"Animation":                                "Resource",
+	"AnimationLibrary":                         "Resource",
+	"AnimationMixer":                           "Node",
+	"AnimationNode":                            "Resource",
+	"AnimationNodeAdd2":                        "AnimationNodeSync",
+	"AnimationNodeAdd3":                        "AnimationNodeSync",
+	"AnimationNodeAnimation":                   "AnimationRootNode",
+	"AnimationNodeBlend2":                      "AnimationNodeSync",
+	"AnimationNodeBlend3":                      "AnimationNodeSync",
+	"AnimationNodeBlendSpace1D":                "AnimationRootNode",
+	"AnimationNodeBlendSpace2D":                "AnimationRootNode",
+	"AnimationNodeBlendTree":                   "AnimationRootNode",
+	"AnimationNodeOneShot":                     "AnimationNodeSync",
+	"AnimationNodeOutput":                      "AnimationNode",
+	"AnimationNodeStateMachine":                "AnimationRootNode",
+	"AnimationNodeStateMachinePlayback":        "Resource",
+	"AnimationNodeStateMachineTransition":      "Resource",
+	"AnimationNodeSub2":                        "AnimationNodeSync",
+	"AnimationNodeSync":                        "AnimationNode",
+	"AnimationNodeTimeScale":                   "AnimationNode",
+	"AnimationNodeTimeSeek":                    "AnimationNode",
+	"AnimationNodeTransition":                  "AnimationNodeSync",
+	"AnimationPlayer":                          "signedAnimation",
+	"AnimationRootNode":                        "AnimationNode",
+	"AnimationTree":                            "AnimationMixer",
+	"Area2D":                                   "CollisionObject2D",
+	"Area3D":                                   "CollisionObject3D",
+	"ArrayMesh":                                "Mesh",
+	"ArrayOccluder3D":                          "Occluder3D",
+	"AspectRatioContainer":                     "Container",
+	"AtlasTexture":                             "Texture2D",
+	"AudioBusLayout":                           "Resource",
+	"AudioEffect":                              "Resource",
+	"AudioEffectAmplify":                       "AudioEffect",
+	"AudioEffectBandLimitFilter":               "AudioEffectFilter",
+	"AudioEffectBandPassFilter":                "AudioEffectFilter",
+	"AudioEffectCapture":                       "AudioEffect",
+	"AudioEffectChorus":                        "AudioEffect",
+	"AudioEffectCompressor":                    "AudioEffect",
+	"AudioEffectDelay":                         "AudioEffect",
+	"AudioEffectDistortion":                    "AudioEffect",
+	"AudioEffectEQ10":                          "AudioEffectEQ",
+	"AudioEffectEQ21":                          "AudioEffectEQ",
+	"AudioEffectEQ6":                           "AudioEffectEQ",
+	"AudioEffectEQ":                            "AudioEffect",
+	"AudioEffectFilter":                        "AudioEffect",
+	"AudioEffectHardLimiter":                   "AudioEffect",
+	"AudioEffectHighPassFilter":                "AudioEffectFilter",
+	"AudioEffectHighShelfFilter":               "AudioEffectFilter",
+	"AudioEffectInstance":                      "RefCounted",
+	"AudioEffectLimiter":                       "AudioEffect",
+	"AudioEffectLowPassFilter":                 "AudioEffectFilter",
+	"AudioEffectLowShelfFilter":                "AudioEffectFilter",
+	"AudioEffectNotchFilter":                   "AudioEffectFilter",
+	"AudioEffectPanner":                        "AudioEffect",
+	"AudioEffectPhaser":                        "AudioEffect",
+	"AudioEffectPitchShift":                    "AudioEffect",
+	"AudioEffectRecord":                        "AudioEffect",
+	"AudioEffectReverb":                        "AudioEffect",
+	"AudioEffectSpectrumAnalyzer":              "AudioEffect",
+	"AudioEffectSpectrumAnalyzerInstance":      "AudioEffectInstance",
+	"AudioEffectStereoEnhance":                 "AudioEffect",
+	"AudioListener2D":                          "Node2D",
+	"AudioListener3D":                          "Node3D",
+	"AudioSample":                              "RefCounted",
+	"AudioSamplePlayback":                      "RefCounted",
+	"AudioServer":                              "Object",
+	"AudioStream":                              "Resource",
+	"AudioStreamGenerator":                     "AudioStream",
+	"AudioStreamGeneratorPlayback":             "AudioStreamPlaybackResampled",
+	"AudioStreamInteractive":                   "AudioStream",
+	"AudioStreamMP3":                           "AudioStream",
+	"AudioStreamMicrophone":                    "AudioStream",
+	"AudioStreamOggVorbis":                     "AudioStream",
+	"AudioStreamPlayback":                      "RefCounted",
+	"AudioStreamPlaybackInteractive":           "AudioStreamPlayback",
+	"AudioStreamPlaybackOggVorbis":             "AudioStreamPlaybackResampled",
+	"AudioStreamPlaybackPlaylist":              "AudioStreamPlayback",
+	"AudioStreamPlaybackPolyphonic":            "AudioStreamPlayback",
+	"AudioStreamPlaybackResampled":             "AudioStreamPlayback",
+	"AudioStreamPlaybackSynchronized":          "AudioStreamPlayback",
+	"AudioStreamPlayer2D":                      "Node2D",
+	"AudioStreamPlayer3D":                      "Node3D",
+	"AudioStreamPlayer":                        "Node",
+	"AudioStreamPlaylist":                      "AudioStream",
+	"AudioStreamPolyphonic":                    "AudioStream",
+	"AudioStreamRandomizer":                    "AudioStream",
+	"AudioStreamSynchronized":                  "AudioStream",
+	"AudioStreamWAV":                           "AudioStream",
+	"BackBufferCopy":                           "Node2D",
+	"BaseButton":                               "Control",
+	"BaseMaterial3D":                           "Material",
+	"BitMap":                                   "Resource",
+	"Bone2D":                                   "Node2D",
+	"BoneAttachment3D":                         "Node3D",
+	"BoneMap":                                  "Resource",
+	"BoxContainer":                             "Container",
+	"BoxMesh":                                  "PrimitiveMesh",
+	"BoxOccluder3D":                            "Occluder3D",
+	"BoxShape3D":                               "Shape3D",
+	"Button":                                   "BaseButton",
+	"ButtonGroup":                              "Resource",
+	"CPUParticles2D":                           "Node2D",
+	"CPUParticles3D":                           "GeometryInstance3D",
+	"CSGBox3D":                                 "CSGPrimitive3D",
+	"CSGCombiner3D":                            "CSGShape3D",
+	"CSGCylinder3D":                            "CSGPrimitive3D",
+	"CSGMesh3D":                                "CSGPrimitive3D",
+	"CSGPolygon3D":                             "CSGPrimitive3D",
+	"CSGPrimitive3D":                           "CSGShape3D",
+	"CSGShape3D":                               "GeometryInstance3D",
+	"CSGSphere3D":                              "CSGPrimitive3D",
+	"CSGTorus3D":                               "CSGPrimitive3D",
+	"CallbackTweener":                          "Tweener",
+	"Camera2D":                                 "Node2D",
+	"Camera3D":                                 "Node3D",
+	"CameraAttributes":                         "Resource",
+	"CameraAttributesPhysical":                 "CameraAttributes",
+	"CameraAttributesPractical":                "CameraAttributes",
+	"CameraFeed":                               "RefCounted",
+	"CameraServer":                             "Object",
+	"CameraTexture":                            "Texture2D",
+	"CanvasGroup":                              "Node2D",
+	"CanvasItem":                               "Node",
+	"CanvasItemMaterial":                       "Material",
+	"CanvasLayer":                              "Node",
+	"CanvasModulate":                           "Node2D",
+	"CanvasTexture":                            "Texture2D",
+	"CapsuleMesh":                              "PrimitiveMesh",
+	"CapsuleShape2D":                           "Shape2D",
+	"CapsuleShape3D":                           "Shape3D",
+	"CenterContainer":                          "Container",
+	"CharFXTransform":                          "RefCounted",
+	"CharacterBody2D":                          "PhysicsBody2D",
+	"CharacterBody3D":                          "PhysicsBody3D",
+	"CheckBox":                                 "Button",
+	"CheckButton":                              "Button",
+	"CircleShape2D":                            "Shape2D",
+	"ClassDB":                                  "Object",
+	"CodeEdit":                                 "TextEdit",
+	"CodeHighlighter":                          "SyntaxHighlighter",
+	"CollisionObject2D":                        "Node2D",
+	"CollisionObject3D":                        "Node3D",
+	"CollisionPolygon2D":                       "Node2D",
+	"CollisionPolygon3D":                       "Node3D",
+	"CollisionShape2D":                         "Node2D",
+	"CollisionShape3D":                         "Node3D",
+	"ColorPicker":                              "VBoxContainer",
+	"ColorPickerButton":                        "Button",
+	"ColorRect":                                "Control",
+	"Compositor":                               "Resource",
+	"CompositorEffect":                         "Resource",
+	"CompressedCubemap":                        "CompressedTextureLayered",
+	"CompressedCubemapArray":                   "CompressedTextureLayered",
+	"CompressedTexture2D":                      "Texture2D",
+	"CompressedTexture2DArray":                 "CompressedTextureLayered",
+	"CompressedTexture3D":                      "Texture3D",
+	"CompressedTextureLayered":                 "TextureLayered",
+	"ConcavePolygonShape2D":                    "Shape2D",
+	"ConcavePolygonShape3D":                    "Shape3D",
+	"ConeTwistJoint3D":                         "Joint3D",
+	"ConfigFile":                               "RefCounted",
+	"ConfirmationDialog":                       "AcceptDialog",
+	"Container":                                "Control",
+	"Control":                                  "CanvasItem",
+	"ConvexPolygonShape2D":                     "Shape2D",
+	"ConvexPolygonShape3D":                     "Shape3D",
+	"Crypto":                                   "RefCounted",
+	"CryptoKey":                                "Resource",
+	"Cubemap":                                  "ImageTextureLayered",
+	"CubemapArray":                             "ImageTextureLayered",
+	"Curve2D":                                  "Resource",
+	"Curve3D":                                  "Resource",
+	"Curve":                                    "Resource",
+	"CurveTexture":                             "Texture2D",
+	"CurveXYZTexture":                          "Texture2D",
+	"CylinderMesh":                             "PrimitiveMesh",
+	"CylinderShape3D":                          "Shape3D",
+	"DTLSServer":                               "RefCounted",
+	"DampedSpringJoint2D":                      "Joint2D",
+	"Decal":                                    "VisualInstance3D",
+	"DirAccess":                                "RefCounted",
+	"DirectionalLight2D":                       "Light2D",
+	"DirectionalLight3D":                       "Light3D",
+	"DisplayServer":                            "Object",
+	"ENetConnection":                           "RefCounted",
+	"ENetMultiplayerPeer":                      "MultiplayerPeer",
+	"ENetPacketPeer":                           "PacketPeer",
+	"EditorCommandPalette":                     "ConfirmationDialog",
+	"EditorDebuggerPlugin":                     "RefCounted",
+	"EditorDebuggerSession":                    "RefCounted",
+	"EditorExportPlatform":                     "RefCounted",
+	"EditorExportPlatformAndroid":              "EditorExportPlatform",
+	"EditorExportPlatformIOS":                  "EditorExportPlatform",
+	"EditorExportPlatformLinuxBSD":             "EditorExportPlatformPC",
+	"EditorExportPlatformMacOS":                "EditorExportPlatform",
+	"EditorExportPlatformPC":                   "EditorExportPlatform",
+	"EditorExportPlatformWeb":                  "EditorExportPlatform",
+	"EditorExportPlatformWindows":              "EditorExportPlatformPC",
+	"EditorExportPlugin":                       "RefCounted",
+	"EditorFeatureProfile":                     "RefCounted",
+	"EditorFileDialog":                         "ConfirmationDialog",
+	"EditorFileSystem":                         "Node",
+	"EditorFileSystemDirectory":                "Object",
+	"EditorFileSystemImportFormatSupportQuery": "RefCounted",
+	"EditorImportPlugin":                       "ResourceImporter",
+	"EditorInspector":                          "ScrollContainer",
+	"EditorInspectorPlugin":                    "RefCounted",
+	"EditorInterface":                          "Object",
+	"EditorNode3DGizmo":                        "Node3DGizmo",
+	"EditorNode3DGizmoPlugin":                  "Resource",
+	"EditorPaths":                              "Object",
+	"EditorPlugin":                             "Node",
+	"EditorProperty":                           "Container",
+	"EditorResourceConversionPlugin":           "RefCounted",
+	"EditorResourcePicker":                     "HBoxContainer",
+	"EditorResourcePreview":                    "Node",
+	"EditorResourcePreviewGenerator":           "RefCounted",
+	"EditorResourceTooltipPlugin":              "RefCounted",
+	"EditorSceneFormatImporter":                "RefCounted",
+	"EditorSceneFormatImporterBlend":           "EditorSceneFormatImporter",
+	"EditorSceneFormatImporterFBX2GLTF":        "EditorSceneFormatImporter",
+	"EditorSceneFormatImporterGLTF":            "EditorSceneFormatImporter",
+	"EditorSceneFormatImporterUFBX":            "EditorSceneFormatImporter",
+	"EditorScenePostImport":                    "RefCounted",
+	"EditorScenePostImportPlugin":              "RefCounted",
+	"EditorScript":                             "RefCounted",
+	"EditorScriptPicker":                       "EditorResourcePicker",
+	"EditorSelection":                          "Object",
+	"EditorSettings":                           "Resource",
+	"EditorSpinSlider":                         "Range",
+	"EditorSyntaxHighlighter":                  "SyntaxHighlighter",
+	"EditorTranslationParserPlugin":            "RefCounted",
+	"EditorUndoRedoManager":                    "Object",
+	"EditorVCSInterface":                       "Object",
+	"EncodedObjectAsID":                        "RefCounted",
+	"Engine":                                   "Object",
+	"EngineDebugger":                           "Object",
+	"EngineProfiler":                           "RefCounted",
+	"Environment":                              "Resource",
+	"Expression":                               "RefCounted",
+	"FBXDocument":                              "GLTFDocument",
+	"FBXState":                                 "GLTFState",
+	"FastNoiseLite":                            "Noise",
+	"FileAccess":                               "RefCounted",
+	"FileDialog":                               "ConfirmationDialog",
+	"FileSystemDock":                           "VBoxContainer",
+	"FlowContainer":                            "Container",
+	"FogMaterial":                              "Material",
+	"FogVolume":                                "VisualInstance3D",
+	"Font":                                     "Resource",
+	"FontFile":                                 "Font",
+	"FontVariation":                            "Font",
+	"FramebufferCacheRD":                       "Object",
+	"GDExtension":                              "Resource",
+	"GDExtensionManager":                       "Object",
+	"GDScript":                                 "Script",
+	"GLTFAccessor":                             "Resource",
+	"GLTFAnimation":                            "Resource",
+	"GLTFBufferView":                           "Resource",
+	"GLTFCamera":                               "Resource",
+	"GLTFDocument":                             "Resource",
+	"GLTFDocumentExtension":                    "Resource",
+	"GLTFDocumentExtensionConvertImporterMesh": "GLTFDocumentExtension",
+	"GLTFLight":                                "Resource",
+	"GLTFMesh":                                 "Resource",
+	"GLTFNode":                                 "Resource",
+	"GLTFPhysicsBody":                          "Resource",
+	"GLTFPhysicsShape":                         "Resource",
+	"GLTFSkeleton":                             "Resource",
+	"GLTFSkin":                                 "Resource",
+	"GLTFSpecGloss":                            "Resource",
+	"GLTFState":                                "Resource",
+	"GLTFTexture":                              "Resource",
+	"GLTFTextureSampler":                       "Resource",
+	"GPUParticles2D":                           "Node2D",
+	"GPUParticles3D":                           "GeometryInstance3D",
+	"GPUParticlesAttractor3D":                  "VisualInstance3D",
+	"GPUParticlesAttractorBox3D":               "GPUParticlesAttractor3D",
+	"GPUParticlesAttractorSphere3D":            "GPUParticlesAttractor3D",
+	"GPUParticlesAttractorVectorField3D":       "GPUParticlesAttractor3D",
+	"GPUParticlesCollision3D":                  "VisualInstance3D",
+	"GPUParticlesCollisionBox3D":               "GPUParticlesCollision3D",
+	"GPUParticlesCollisionHeightField3D":       "GPUParticlesCollision3D",
+	"GPUParticlesCollisionSDF3D":               "GPUParticlesCollision3D",
+	"GPUParticlesCollisionSphere3D":            "GPUParticlesCollision3D",
+	"Generic6DOFJoint3D":                       "Joint3D",
+	"Geometry2D":                               "Object",
+	"Geometry3D":                               "Object",
+	"GeometryInstance3D":                       "VisualInstance3D",
+	"Gradient":                                 "Resource",
+	"GradientTexture1D":                        "Texture2D",
+	"GradientTexture2D":                        "Texture2D",
+	"GraphEdit":                                "Control",
+	"GraphElement":                             "Container",
+	"GraphFrame":                               "GraphElement",
+	"GraphNode":                                "GraphElement",
+	"GridContainer":                            "Container",
+	"GridMap":                                  "Node3D",
+	"GrooveJoint2D":                            "Joint2D",
+	"HBoxContainer":                            "BoxContainer",
+	"HFlowContainer":                           "FlowContainer",
+	"HMACContext":                              "RefCounted",
+	"HScrollBar":                               "ScrollBar",
+	"HSeparator":                               "Separator",
+	"HSlider":                                  "Slider",
+	"HSplitContainer":                          "SplitContainer",
+	"HTTPClient":                               "RefCounted",
+	"HTTPRequest":                              "Node",
+	"HashingContext":                           "RefCounted",
+	"HeightMapShape3D":                         "Shape3D",
+	"HingeJoint3D":                             "Joint3D",
+	"IP":                                       "Object",
+	"Image":                                    "Resource",
+	"ImageFormatLoader":                        "RefCounted",
+	"ImageFormatLoaderExtension":               "ImageFormatLoader",
+	"ImageTexture3D":                           "Texture3D",
+	"ImageTexture":                             "Texture2D",
+	"ImageTextureLayered":                      "TextureLayered",
+	"ImmediateMesh":                            "Mesh",
+	"ImporterMesh":                             "Resource",
+	"ImporterMeshInstance3D":                   "Node3D",
+	"Input":                                    "Object",
+	"InputEvent":                               "Text",
+	"InputEventAction":                         "InputEvent",
+	"InputEventFromWindow":                     "InputEvent",
+	"InputEventGesture":                        "InputEventWithModifiers",
+	"InputEventJoypadButton":                   "InputEvent",
+	"InputEventJoypadMotion":                   "InputEvent",
+	"InputEventKey":                            "TextKeycode",
+	"InputEventMIDI":                           "InputEvent",
+	"InputEventMagnifyGesture":                 "InputEventGesture",
+	"InputEventMouse":                          "InputEventWithModifiers",
+	"InputEventMouseButton":                    "InputEventMouse",
+	"InputEventMouseMotion":                    "InputEventMouse",
+	"InputEventPanGesture":                     "InputEventGesture",
+	"InputEventScreenDrag":                     "InputEventFromWindow",
+	"InputEventScreenTouch":                    "InputEventFromWindow",
+	"InputEventShortcut":                       "InputEvent",
+	"InputEventWithModifiers":                  "InputEventFromWindow",
+	"InputMap":                                 "Object",
+	"InstancePlaceholder":                      "Node",
+	"IntervalTweener":                          "Tweener",
+	"ItemList":                                 "Control",
+	"JNISingleton":                             "Object",
+	"JSON":                                     "Resource",
+	"JSONRPC":                                  "Object",
+	"JavaClass":                                "RefCounted",
+	"JavaClassWrapper":                         "Object",
+	"JavaScriptBridge":                         "Object",
+	"JavaScriptObject":                         "RefCounted",
+	"Joint2D":                                  "Node2D",
+	"Joint3D":                                  "Node3D",
+	"KinematicCollision2D":                     "RefCounted",
+	"KinematicCollision3D":                     "RefCounted",
+	"Label3D":                                  "GeometryInstance3D",
+	"Label":                                    "Control",
+	"LabelSettings":                            "Resource",
+	"Light2D":                                  "Node2D",
+	"Light3D":                                  "VisualInstance3D",
+	"LightOccluder2D":                          "Node2D",
+	"LightmapGI":                               "VisualInstance3D",
+	"LightmapGIData":                           "Resource",
+	"LightmapProbe":                            "Node3D",
+	"Lightmapper":                              "RefCounted",
+	"LightmapperRD":                            "Lightmapper",
+	"Line2D":                                   "Node2D",
+	"LineEdit":                                 "Control",
+	"LinkButton":                               "BaseButton",
+	"MainLoop":                                 "Object",
+	"MarginContainer":                          "Container",
+	"Marker2D":                                 "Node2D",
+	"Marker3D":                                 "Node3D",
+	"Marshalls":                                "Object",
+	"Material":                                 "Resource",
+	"MenuBar":                                  "Control",
+	"MenuButton":                               "Button",
+	"Mesh":                                     "Resource",
+	"MeshConvexDecompositionSettings":          "RefCounted",
+	"MeshDataTool":                             "RefCounted",
+	"MeshInstance2D":                           "Node2D",
+	"MeshInstance3D":                           "GeometryInstance3D",
+	"MeshLibrary":                              "Resource",
+	"MeshTexture":                              "Texture2D",
+	"MethodTweener":                            "Tweener",
+	"MissingNode":                              "Node",
+	"MissingResource":                          "Resource",
+	"MobileVRInterface":                        "XRInterface",
+	"MovieWriter":                              "Object",
+	"MultiMesh":                                "Resource",
+	"MultiMeshInstance2D":                      "Node2D",
+	"MultiMeshInstance3D":                      "GeometryInstance3D",
+	"MultiplayerAPI":                           "RefCounted",
+	"MultiplayerAPIExtension":                  "MultiplayerAPI",
+	"MultiplayerPeer":                          "PacketPeer",
+	"MultiplayerPeerExtension":                 "MultiplayerPeer",
+	"MultiplayerSpawner":                       "Node",
+	"MultiplayerSynchronizer":                  "Node",
+	"Mutex":                                    "RefCounted",
+	"NativeMenu":                               "Object",
+	"NavigationAgent2D":                        "Node",
+	"NavigationAgent3D":                        "Node",
+	"NavigationLink2D":                         "Node2D",
+	"NavigationLink3D":                         "Node3D",
+	"NavigationMesh":                           "Resource",
+	"NavigationMeshGenerator":                  "Object",
+	"NavigationMeshSourceGeometryData2D":       "Resource",
+	"NavigationMeshSourceGeometryData3D":       "Resource",
+	"NavigationObstacle2D":                     "Node2D",
+	"NavigationObstacle3D":                     "Node3D",
+	"NavigationPathQueryParameters2D":          "RefCounted",
+	"NavigationPathQueryParameters3D":          "RefCounted",
+	"NavigationPathQueryResult2D":              "RefCounted",
+	"NavigationPathQueryResult3D":              "RefCounted",
+	"NavigationPolygon":                        "Resource",
+	"NavigationRegion2D":                       "Node2D",
+	"NavigationRegion3D":                       "Node3D",
+	"NavigationServer2D":                       "Object",
+	"NavigationServer3D":                       "Object",
+	"NinePatchRect":                            "Control",
+	"Node2D":                                   "CanvasItem",
+	"Node3D":                                   "Node",
+	"Node3DGizmo":                              "RefCounted",
+	"Node":                                     "Object",
+	"Noise":                                    "Resource",
+	"NoiseTexture2D":                           "NormalMap",
+	"NoiseTexture3D":                           "Texture3D",
+	"ORMMaterial3D":                            "BaseMaterial3D",
+	"OS":                                       "Object",
+	"Occluder3D":                               "Resource",
+	"OccluderInstance3D":                       "VisualInstance3D",
+	"OccluderPolygon2D":                        "Resource",
+	"OfflineMultiplayerPeer":                   "MultiplayerPeer",
+	"OggPacketSequence":                        "Resource",
+	"OggPacketSequencePlayback":                "RefCounted",
+	"OmniLight3D":                              "Light3D",
+	"OpenXRAPIExtension":                       "RefCounted",
+	"OpenXRAction":                             "Resource",
+	"OpenXRActionMap":                          "Resource",
+	"OpenXRActionSet":                          "Resource",
+	"OpenXRCompositionLayer":                   "Node3D",
+	"OpenXRCompositionLayerCylinder":           "pectRatio",
+	"OpenXRCompositionLayerEquirect":           "OpenXRCompositionLayer",
+	"OpenXRCompositionLayerQuad":               "OpenXRCompositionLayer",
+	"OpenXRExtensionWrapperExtension":          "Object",
+	"OpenXRHand":                               "Node3D",
+	"OpenXRIPBinding":                          "Resource",
+	"OpenXRInteractionProfile":                 "Resource",
+	"OpenXRInteractionProfileMetadata":         "Object",
+	"OpenXRInterface":                          "XRInterface",
+	"OptimizedTranslation":                     "Translation",
+	"OptionButton":                             "Button",
+	"PCKPacker":                                "RefCounted",
+	"PackedDataContainer":                      "Resource",
+	"PackedDataContainerRef":                   "RefCounted",
+	"PackedScene":                              "Resource",
+	"PacketPeer":                               "RefCounted",
+	"PacketPeerDTLS":                           "PacketPeer",
+	"PacketPeerExtension":                      "PacketPeer",
+	"PacketPeerStream":                         "PacketPeer",
+	"PacketPeerUDP":                            "PacketPeer",
+	"Panel":                                    "Control",
+	"PanelContainer":                           "Container",
+	"PanoramaSkyMaterial":                      "Material",
+	"Parallax2D":                               "Node2D",
+	"ParallaxBackground":                       "CanvasLayer",
+	"ParallaxLayer":                            "Node2D",
+	"ParticleProcessMaterial":                  "Material",
+	"Path2D":                                   "Node2D",
+	"Path3D":                                   "Node3D",
+	"PathFollow2D":                             "Node2D",
+	"PathFollow3D":                             "Node3D",
+	"Performance":                              "Object",
+	"PhysicalBone2D":                           "RigidBody2D",
+	"PhysicalBone3D":                           "PhysicsBody3D",
+	"PhysicalBoneSimulator3D":                  "SkeletonModifier3D",
+	"PhysicalSkyMaterial":                      "Material",
+	"PhysicsBody2D":                            "CollisionObject2D",
+	"PhysicsBody3D":                            "CollisionObject3D",
+	"PhysicsDirectBodyState2D":                 "Object",
+	"PhysicsDirectBodyState2DExtension":        "PhysicsDirectBodyState2D",
+	"PhysicsDirectBodyState3D":                 "Object",
+	"PhysicsDirectBodyState3DExtension":        "PhysicsDirectBodyState3D",
+	"PhysicsDirectSpaceState2D":                "Object",
+	"PhysicsDirectSpaceState2DExtension":       "PhysicsDirectSpaceState2D",
+	"PhysicsDirectSpaceState3D":                "Object",
+	"PhysicsDirectSpaceState3DExtension":       "PhysicsDirectSpaceState3D",
+	"PhysicsMaterial":                          "Resource",
+	"PhysicsPointQueryParameters2D":            "RefCounted",
+	"PhysicsPointQueryParameters3D":            "RefCounted",
+	"PhysicsRayQueryParameters2D":              "RefCounted",
+	"PhysicsRayQueryParameters3D":              "RefCounted",
+	"PhysicsServer2D":                          "Object",
+	"PhysicsServer2DExtension":                 "Object",
+	"PhysicsServer2DManager":                   "Object",
+	"PhysicsServer3D":                          "Object",
+	"PhysicsServer3DExtension":                 "Object",
+	"PhysicsServer3DManager":                   "Object",
+	"PhysicsServer3DRenderingServerHandler":    "Object",
+	"PhysicsShapeQueryParameters2D":            "RefCounted",
+	"PhysicsShapeQueryParameters3D":            "RefCounted",
+	"PhysicsTestMotionParameters2D":            "RefCounted",
+	"PhysicsTestMotionParameters3D":            "RefCounted",
+	"PhysicsTestMotionResult2D":                "RefCounted",
+	"PhysicsTestMotionResult3D":                "RefCounted",
+	"PinJoint2D":                               "Joint2D",
+	"PinJoint3D":                               "Joint3D",
+	"PlaceholderCubemap":                       "PlaceholderTextureLayered",
+	"PlaceholderCubemapArray":                  "PlaceholderTextureLayered",
+	"PlaceholderMaterial":                      "Material",
+	"PlaceholderMesh":                          "Mesh",
+	"PlaceholderTexture2D":                     "Texture2D",
+	"PlaceholderTexture2DArray":                "PlaceholderTextureLayered",
+	"PlaceholderTexture3D":                     "Texture3D",
+	"PlaceholderTextureLayered":                "TextureLayered",
+	"PlaneMesh":                                "PrimitiveMesh",
+	"PointLight2D":                             "Light2D",
+	"PointMesh":                                "PrimitiveMesh",
+	"Polygon2D":                                "Node2D",
+	"PolygonOccluder3D":                        "Occluder3D",
+	"PolygonPathFinder":                        "Resource",
+	"Popup":                                    "Window",
+	"PopupMenu":                                "Popup",
+	"PopupPanel":                               "Popup",
+	"PortableCompressedTexture2D":              "Texture2D",
+	"PrimitiveMesh":                            "Mesh",
+	"PrismMesh":                                "PrimitiveMesh",
+	"ProceduralSkyMaterial":                    "Material",
+	"ProgressBar":                              "Range",
+	"ProjectSettings":                          "Object",
+	"PropertyTweener":                          "Relative",
+	"QuadMesh":                                 "PlaneMesh",
+	"QuadOccluder3D":                           "Occluder3D",
+	"RDAttachmentFormat":                       "RefCounted",
+	"RDFramebufferPass":                        "RefCounted",
+	"RDPipelineColorBlendState":                "RefCounted",
+	"RDPipelineColorBlendStateAttachment":      "RefCounted",
+	"RDPipelineDepthStencilState":              "RefCounted",
+	"RDPipelineMultisampleState":               "RefCounted",
+	"RDPipelineRasterizationState":             "RefCounted",
+	"RDPipelineSpecializationConstant":         "RefCounted",
+	"RDSamplerState":                           "RefCounted",
+	"RDShaderFile":                             "Resource",
+	"RDShaderSPIRV":                            "Resource",
+	"RDShaderSource":                           "RefCounted",
+	"RDTextureFormat":                          "RefCounted",
+	"RDTextureView":                            "RefCounted",
+	"RDUniform":                                "RefCounted",
+	"RDVertexAttribute":                        "RefCounted",
+	"RandomNumberGenerator":                    "RefCounted",
+	"Range":                                    "Control",
+	"RayCast2D":                                "Node2D",
+	"RayCast3D":                                "Node3D",
+	"RectangleShape2D":                         "Shape2D",
+	"RefCounted":                               "Object",
+	"ReferenceRect":                            "Control",
+	"ReflectionProbe":                          "VisualInstance3D",
+	"RegEx":                                    "RefCounted",
+	"RegExMatch":                               "RefCounted",
+	"RemoteTransform2D":                        "Node2D",
+	"RemoteTransform3D":                        "Node3D",
+	"RenderData":                               "Object",
+	"RenderDataExtension":                      "RenderData",
+	"RenderDataRD":                             "RenderData",
+	"RenderSceneBuffers":                       "RefCounted",
+	"RenderSceneBuffersConfiguration":          "RefCounted",
+	"RenderSceneBuffersExtension":              "RenderSceneBuffers",
+	"RenderSceneBuffersRD":                     "RenderSceneBuffers",
+	"RenderSceneData":                          "Object",
+	"RenderSceneDataExtension":                 "RenderSceneData",
+	"RenderSceneDataRD":                        "RenderSceneData",
+	"RenderingDevice":                          "Object",
+	"RenderingServer":                          "Object",
+	"Resource":                                 "RefCounted",
+	"ResourceFormatLoader":                     "RefCounted",
+	"ResourceFormatSaver":                      "RefCounted",
+	"ResourceImporter":                         "RefCounted",
+	"ResourceImporterBMFont":                   "ResourceImporter",
+	"ResourceImporterBitMap":                   "ResourceImporter",
+	"ResourceImporterCSVTranslation":           "ResourceImporter",
+	"ResourceImporterDynamicFont":              "ResourceImporter",
+	"ResourceImporterImage":                    "ResourceImporter",
+	"ResourceImporterImageFont":                "ResourceImporter",
+	"ResourceImporterLayeredTexture":           "ResourceImporter",
+	"ResourceImporterMP3":                      "ResourceImporter",
+	"ResourceImporterOBJ":                      "ResourceImporter",
+	"ResourceImporterOggVorbis":                "ResourceImporter",
+	"ResourceImporterScene":                    "ResourceImporter",
+	"ResourceImporterShaderFile":               "ResourceImporter",
+	"ResourceImporterTexture":                  "ResourceImporter",
+	"ResourceImporterTextureAtlas":             "ResourceImporter",
+	"ResourceImporterWAV":                      "ResourceImporter",
+	"ResourceLoader":                           "Object",
+	"ResourcePreloader":                        "Node",
+	"ResourceSaver":                            "Object",
+	"ResourceUID":                              "Object",
+	"RibbonTrailMesh":                          "PrimitiveMesh",
+	"RichTextEffect":                           "Resource",
+	"RichTextLabel":                            "Control",
+	"RigidBody2D":                              "PhysicsBody2D",
+	"RigidBody3D":                              "PhysicsBody3D",
+	"RootMotionView":                           "VisualInstance3D",
+	"SceneMultiplayer":                         "MultiplayerAPI",
+	"SceneReplicationConfig":                   "Resource",
+	"SceneState":                               "RefCounted",
+	"SceneTree":                                "MainLoop",
+	"SceneTreeTimer":                           "RefCounted",
+	"Script":                                   "Resource",
+	"ScriptCreateDialog":                       "ConfirmationDialog",
+	"ScriptEditor":                             "PanelContainer",
+	"ScriptEditorBase":                         "VBoxContainer",
+	"ScriptExtension":                          "Script",
+	"ScriptLanguage":                           "Object",
+	"ScriptLanguageExtension":                  "ScriptLanguage",
+	"ScrollBar":                                "Range",
+	"ScrollContainer":                          "Container",
+	"SegmentShape2D":                           "Shape2D",
+	"Semaphore":                                "RefCounted",
+	"SeparationRayShape2D":                     "Shape2D",
+	"SeparationRayShape3D":                     "Shape3D",
+	"Separator":                                "Control",
+	"Shader":                                   "Resource",
+	"ShaderGlobalsOverride":                    "Node",
+	"ShaderInclude":                            "Resource",
+	"ShaderMaterial":                           "Material",
+	"Shape2D":                                  "Resource",
+	"Shape3D":                                  "Resource",
+	"ShapeCast2D":                              "Node2D",
+	"ShapeCast3D":                              "Node3D",
+	"Shortcut":                                 "Resource",
+	"Skeleton2D":                               "Node2D",
+	"Skeleton3D":                               "Node3D",
+	"SkeletonIK3D":                             "SkeletonModifier3D",
+	"SkeletonModification2D":                   "Resource",
+	"SkeletonModification2DCCDIK":              "SkeletonModification2D",
+	"SkeletonModification2DFABRIK":             "SkeletonModification2D",
+	"SkeletonModification2DJiggle":             "SkeletonModification2D",
+	"SkeletonModification2DLookAt":             "SkeletonModification2D",
+	"SkeletonModification2DPhysicalBones":      "SkeletonModification2D",
+	"SkeletonModification2DStackHolder":        "SkeletonModification2D",
+	"SkeletonModification2DTwoBoneIK":          "SkeletonModification2D",
+	"SkeletonModificationStack2D":              "Resource",
+	"SkeletonModifier3D":                       "Node3D",
+	"SkeletonProfile":                          "Resource",
+	"SkeletonProfileHumanoid":                  "SkeletonProfile",
+	"Skin":                                     "Resource",
+	"SkinReference":                            "RefCounted",
+	"Sky":                                      "Resource",
+	"Slider":                                   "Range",
+	"SliderJoint3D":                            "Joint3D",
+	"SoftBody3D":                               "MeshInstance3D",
+	"SphereMesh":                               "PrimitiveMesh",
+	"SphereOccluder3D":                         "Occluder3D",
+	"SphereShape3D":                            "Shape3D",
+	"SpinBox":                                  "Range",
+	"SplitContainer":                           "Container",
+	"SpotLight3D":                              "Light3D",
+	"SpringArm3D":                              "Node3D",
+	"Sprite2D":                                 "Node2D",
+	"Sprite3D":                                 "SpriteBase3D",
+	"SpriteBase3D":                             "GeometryInstance3D",
+	"SpriteFrames":                             "Resource",
+	"StandardMaterial3D":                       "BaseMaterial3D",
+	"StaticBody2D":                             "PhysicsBody2D",
+	"StaticBody3D":                             "PhysicsBody3D",
+	"StatusIndicator":                          "Node",
+	"StreamPeer":                               "RefCounted",
+	"StreamPeerBuffer":                         "StreamPeer",
+	"StreamPeerExtension":                      "StreamPeer",
+	"StreamPeerGZIP":                           "StreamPeer",
+	"StreamPeerTCP":                            "StreamPeer",
+	"StreamPeerTLS":                            "StreamPeer",
+	"StyleBox":                                 "Resource",
+	"StyleBoxEmpty":                            "StyleBox",
+	"StyleBoxFlat":                             "StyleBox",
+	"StyleBoxLine":                             "StyleBox",
+	"StyleBoxTexture":                          "StyleBox",
+	"SubViewport":                              "Viewport",
+	"SubViewportContainer":                     "Container",
+	"SurfaceTool":                              "RefCounted",
+	"SyntaxHighlighter":                        "Resource",
+	"SystemFont":                               "Font",
+	"TCPServer":                                "RefCounted",
+	"TLSOptions":                               "RefCounted",
+	"TabBar":                                   "Control",
+	"TabContainer":                             "Container",
+	"TextEdit":                                 "Control",
+	"TextLine":                                 "RefCounted",
+	"TextMesh":                                 "PrimitiveMesh",
+	"TextParagraph":                            "RefCounted",
+	"TextServer":                               "RefCounted",
+	"TextServerAdvanced":                       "TextServerExtension",
+	"TextServerDummy":                          "TextServerExtension",
+	"TextServerExtension":                      "TextServer",
+	"TextServerManager":                        "Object",
+	"Texture2D":                                "Texture",
+	"Texture2DArray":                           "ImageTextureLayered",
+	"Texture2DArrayRD":                         "TextureLayeredRD",
+	"Texture2DRD":                              "Texture2D",
+	"Texture3D":                                "Texture",
+	"Texture3DRD":                              "Texture3D",
+	"Texture":                                  "Resource",
+	"TextureButton":                            "BaseButton",
+	"TextureCubemapArrayRD":                    "TextureLayeredRD",
+	"TextureCubemapRD":                         "TextureLayeredRD",
+	"TextureLayered":                           "Texture",
+	"TextureLayeredRD":                         "TextureLayered",
+	"TextureProgressBar":                       "Range",
+	"TextureRect":                              "Control",
+	"Theme":                                    "Resource",
+	"ThemeDB":                                  "Object",
+	"Thread":                                   "RefCounted",
+	"TileData":                                 "Object",
+	"TileMap":                                  "Node2D",
+	"TileMapLayer":                             "Node2D",
+	"TileMapPattern":                           "Resource",
+	"TileSet":                                  "Resource",
+	"TileSetAtlasSource":                       "TileSetSource",
+	"TileSetScenesCollectionSource":            "TileSetSource",
+	"TileSetSource":                            "Resource",
+	"Time":                                     "Object",
+	"Timer":                                    "Node",
+	"TorusMesh":                                "PrimitiveMesh",
+	"TouchScreenButton":                        "Node2D",
+	"Translation":                              "Resource",
+	"TranslationServer":                        "Object",
+	"Tree":                                     "Control",
+	"TreeItem":                                 "Object",
+	"TriangleMesh":                             "RefCounted",
+	"TubeTrailMesh":                            "PrimitiveMesh",
+	"Tween":                                    "RefCounted",
+	"Tweener":                                  "RefCounted",
+	"UDPServer":                                "RefCounted",
+	"UPNP":                                     "RefCounted",
+	"UPNPDevice":                               "RefCounted",
+	"UndoRedo":                                 "Object",
+	"UniformSetCacheRD":                        "Object",
+	"VBoxContainer":                            "BoxContainer",
+	"VFlowContainer":                           "FlowContainer",
+	"VScrollBar":                               "ScrollBar",
+	"VSeparator":                               "Separator",
+	"VSlider":                                  "Slider",
+	"VSplitContainer":                          "SplitContainer",
+	"VehicleBody3D":                            "RigidBody3D",
+	"VehicleWheel3D":                           "Node3D",
+	"VideoStream":                              "Resource",
+	"VideoStreamPlayback":                      "Resource",
+	"VideoStreamPlayer":                        "Control",
+	"VideoStreamTheora":                        "VideoStream",
+	"Viewport":                                 "Node",
+	"ViewportTexture":                          "Texture2D",
+	"VisibleOnScreenEnabler2D":                 "VisibleOnScreenNotifier2D",
+	"VisibleOnScreenEnabler3D":                 "VisibleOnScreenNotifier3D",
+	"VisibleOnScreenNotifier2D":                "Node2D",
+	"VisibleOnScreenNotifier3D":                "VisualInstance3D",
+	"VisualInstance3D":                         "Node3D",
+	"VisualShader":                             "Shader",
+	"VisualShaderNode":                         "Resource",
+	"VisualShaderNodeBillboard":                "VisualShaderNode",
+	"VisualShaderNodeBooleanConstant":          "VisualShaderNodeConstant",
+	"VisualShaderNodeBooleanParameter":         "VisualShaderNodeParameter",
+	"VisualShaderNodeClamp":                    "VisualShaderNode",
+	"VisualShaderNodeColorConstant":            "VisualShaderNodeConstant",
+	"VisualShaderNodeColorFunc":                "VisualShaderNode",
+	"VisualShaderNodeColorOp":                  "VisualShaderNode",
+	"VisualShaderNodeColorParameter":           "VisualShaderNodeParameter",
+	"VisualShaderNodeComment":                  "VisualShaderNodeFrame",
+	"VisualShaderNodeCompare":                  "VisualShaderNode",
+	"VisualShaderNodeConstant":                 "VisualShaderNode",
+	"VisualShaderNodeCubemap":                  "VisualShaderNode",
+	"VisualShaderNodeCubemapParameter":         "VisualShaderNodeTextureParameter",
+	"VisualShaderNodeCurveTexture":             "VisualShaderNodeResizableBase",
+	"VisualShaderNodeCurveXYZTexture":          "VisualShaderNodeResizableBase",
+	"VisualShaderNodeCustom":                   "VisualShaderNode",
+	"VisualShaderNodeDerivativeFunc":           "VisualShaderNode",
+	"VisualShaderNodeDeterminant":              "VisualShaderNode",
+	"VisualShaderNodeDistanceFade":             "VisualShaderNode",
+	"VisualShaderNodeDotProduct":               "VisualShaderNode",
+	"VisualShaderNodeExpression":               "VisualShaderNodeGroupBase",
+	"VisualShaderNodeFaceForward":              "VisualShaderNodeVectorBase",
+	"VisualShaderNodeFloatConstant":            "VisualShaderNodeConstant",
+	"VisualShaderNodeFloatFunc":                "VisualShaderNode",
+	"VisualShaderNodeFloatOp":                  "VisualShaderNode",
+	"VisualShaderNodeFloatParameter":           "VisualShaderNodeParameter",
+	"VisualShaderNodeFrame":                    "VisualShaderNodeResizableBase",
+	"VisualShaderNodeFresnel":                  "VisualShaderNode",
+	"VisualShaderNodeGlobalExpression":         "VisualShaderNodeExpression",
+	"VisualShaderNodeGroupBase":                "VisualShaderNodeResizableBase",
+	"VisualShaderNodeIf":                       "VisualShaderNode",
+	"VisualShaderNodeInput":                    "VisualShaderNode",
+	"VisualShaderNodeIntConstant":              "VisualShaderNodeConstant",
+	"VisualShaderNodeIntFunc":                  "VisualShaderNode",
+	"VisualShaderNodeIntOp":                    "VisualShaderNode",
+	"VisualShaderNodeIntParameter":             "VisualShaderNodeParameter",
+	"VisualShaderNodeIs":                       "VisualShaderNode",
+	"VisualShaderNodeLinearSceneDepth":         "VisualShaderNode",
+	"VisualShaderNodeMix":                      "VisualShaderNode",
+	"VisualShaderNodeMultiplyAdd":              "VisualShaderNode",
+	"VisualShaderNodeOuterProduct":             "VisualShaderNode",
+	"VisualShaderNodeOutput":                   "VisualShaderNode",
+	"VisualShaderNodeParameter":                "VisualShaderNode",
+	"VisualShaderNodeParameterRef":             "VisualShaderNode",
+	"VisualShaderNodeParticleAccelerator":      "VisualShaderNode",
+	"VisualShaderNodeParticleBoxEmitter":       "VisualShaderNodeParticleEmitter",
+	"VisualShaderNodeParticleConeVelocity":     "VisualShaderNode",
+	"VisualShaderNodeParticleEmit":             "VisualShaderNode",
+	"VisualShaderNodeParticleEmitter":          "VisualShaderNode",
+	"VisualShaderNodeParticleMeshEmitter":      "VisualShaderNodeParticleEmitter",
+	"VisualShaderNodeParticleMultiplyByAxisAngle": "VisualShaderNode",
+	"VisualShaderNodeParticleOutput":              "VisualShaderNodeOutput",
+	"VisualShaderNodeParticleRandomness":          "VisualShaderNode",
+	"VisualShaderNodeParticleRingEmitter":         "VisualShaderNodeParticleEmitter",
+	"VisualShaderNodeParticleSphereEmitter":       "VisualShaderNodeParticleEmitter",
+	"VisualShaderNodeProximityFade":               "VisualShaderNode",
+	"VisualShaderNodeRandomRange":                 "VisualShaderNode",
+	"VisualShaderNodeRemap":                       "VisualShaderNode",
+	"VisualShaderNodeReroute":                     "VisualShaderNode",
+	"VisualShaderNodeResizableBase":               "VisualShaderNode",
+	"VisualShaderNodeRotationByAxis":              "VisualShaderNode",
+	"VisualShaderNodeSDFRaymarch":                 "VisualShaderNode",
+	"VisualShaderNodeSDFToScreenUV":               "VisualShaderNode",
+	"VisualShaderNodeSample3D":                    "VisualShaderNode",
+	"VisualShaderNodeScreenNormalWorldSpace":      "VisualShaderNode",
+	"VisualShaderNodeScreenUVToSDF":               "VisualShaderNode",
+	"VisualShaderNodeSmoothStep":                  "VisualShaderNode",
+	"VisualShaderNodeStep":                        "VisualShaderNode",
+	"VisualShaderNodeSwitch":                      "VisualShaderNode",
+	"VisualShaderNodeTexture2DArray":              "VisualShaderNodeSample3D",
+	"VisualShaderNodeTexture2DArrayParameter":     "VisualShaderNodeTextureParameter",
+	"VisualShaderNodeTexture2DParameter":          "VisualShaderNodeTextureParameter",
+	"VisualShaderNodeTexture3D":                   "VisualShaderNodeSample3D",
+	"VisualShaderNodeTexture3DParameter":          "VisualShaderNodeTextureParameter",
+	"VisualShaderNodeTexture":                     "VisualShaderNode",
+	"VisualShaderNodeTextureParameter":            "VisualShaderNodeParameter",
+	"VisualShaderNodeTextureParameterTriplanar":   "VisualShaderNodeTextureParameter",
+	"VisualShaderNodeTextureSDF":                  "VisualShaderNode",
+	"VisualShaderNodeTextureSDFNormal":            "VisualShaderNode",
+	"VisualShaderNodeTransformCompose":            "VisualShaderNode",
+	"VisualShaderNodeTransformConstant":           "VisualShaderNodeConstant",
+	"VisualShaderNodeTransformDecompose":          "VisualShaderNode",
+	"VisualShaderNodeTransformFunc":               "VisualShaderNode",
+	"VisualShaderNodeTransformOp":                 "VisualShaderNode",
+	"VisualShaderNodeTransformParameter":          "VisualShaderNodeParameter",
+	"VisualShaderNodeTransformVecMult":            "VisualShaderNode",
+	"VisualShaderNodeUIntConstant":                "VisualShaderNodeConstant",
+	"VisualShaderNodeUIntFunc":                    "VisualShaderNode",
+	"VisualShaderNodeUIntOp":                      "VisualShaderNode",
+	"VisualShaderNodeUIntParameter":               "VisualShaderNodeParameter",
+	"VisualShaderNodeUVFunc":                      "VisualShaderNode",
+	"VisualShaderNodeUVPolarCoord":                "VisualShaderNode",
+	"VisualShaderNodeVarying":                     "VisualShaderNode",
+	"VisualShaderNodeVaryingGetter":               "VisualShaderNodeVarying",
+	"VisualShaderNodeVaryingSetter":               "VisualShaderNodeVarying",
+	"VisualShaderNodeVec2Constant":                "VisualShaderNodeConstant",
+	"VisualShaderNodeVec2Parameter":               "VisualShaderNodeParameter",
+	"VisualShaderNodeVec3Constant":                "VisualShaderNodeConstant",
+	"VisualShaderNodeVec3Parameter":               "VisualShaderNodeParameter",
+	"VisualShaderNodeVec4Constant":                "VisualShaderNodeConstant",
+	"VisualShaderNodeVec4Parameter":               "VisualShaderNodeParameter",
+	"VisualShaderNodeVectorBase":                  "VisualShaderNode",
+	"VisualShaderNodeVectorCompose":               "VisualShaderNodeVectorBase",
+	"VisualShaderNodeVectorDecompose":             "VisualShaderNodeVectorBase",
+	"VisualShaderNodeVectorDistance":              "VisualShaderNodeVectorBase",
+	"VisualShaderNodeVectorFunc":                  "VisualShaderNodeVectorBase",
+	"VisualShaderNodeVectorLen":                   "VisualShaderNodeVectorBase",
+	"VisualShaderNodeVectorOp":                    "VisualShaderNodeVectorBase",
+	"VisualShaderNodeVectorRefract":               "VisualShaderNodeVectorBase",
+	"VisualShaderNodeWorldPositionFromDepth":      "VisualShaderNode",
+	"VoxelGI":                                     "VisualInstance3D",
+	"VoxelGIData":                                 "Resource",
+	"WeakRef":                                     "RefCounted",
+	"WebRTCDataChannel":                           "PacketPeer",
+	"WebRTCDataChannelExtension":                  "WebRTCDataChannel",
+	"WebRTCMultiplayerPeer":                       "MultiplayerPeer",
+	"WebRTCPeerConnection":                        "RefCounted",
+	"WebRTCPeerConnectionExtension":               "WebRTCPeerConnection",
+	"WebSocketMultiplayerPeer":                    "MultiplayerPeer",
+	"WebSocketPeer":                               "PacketPeer",
+	"WebXRInterface":                              "XRInterface",
+	"Window":                                      "Viewport",
+	"WorkerThreadPool":                            "Object",
+	"World2D":                                     "Resource",
+	"World3D":                                     "Resource",
+	"WorldBoundaryShape2D":                        "Shape2D",
+	"WorldBoundaryShape3D":                        "Shape3D",
+	"WorldEnvironment":                            "Node",
+	"X509Certificate":                             "Resource",
+	"XMLParser":                                   "RefCounted",
+	"XRAnchor3D":                                  "XRNode3D",
+	"XRBodyModifier3D":                            "SkeletonModifier3D",
+	"XRBodyTracker":                               "XRPositionalTracker",
+	"XRCamera3D":                                  "Camera3D",
+	"XRController3D":                              "XRNode3D",
+	"XRControllerTracker":                         "XRPositionalTracker",
+	"XRFaceModifier3D":                            "Node3D",
+	"XRFaceTracker":                               "XRTracker",
+	"XRHandModifier3D":                            "SkeletonModifier3D",
+	"XRHandTracker":                               "XRPositionalTracker",
+	"XRInterface":                                 "RefCounted",
+	"XRInterfaceExtension":                        "XRInterface",
+	"XRNode3D":                                    "Node3D",
+	"XROrigin3D":                                  "Node3D",
+	"XRPose":                                      "RefCounted",
+	"XRPositionalTracker":                         "XRTracker",
+	"XRServer":                                    "Object",
+	"XRTracker":                                   "RefCounted",
+	"XRVRS":                                       "Object",
+	"ZIPPacker":                                   "RefCounted",
+	"ZIPReader":                                   "RefCounted",
+}
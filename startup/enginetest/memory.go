@@ -0,0 +1,157 @@
+package enginetest
+
+import (
+	"reflect"
+	"unsafe"
+
+	gd "graphics.gd/internal"
+	"graphics.gd/internal/pointers"
+)
+
+// read the value of the given type from engine memory, gd.TypeNil reads a Variant. Arrays,
+// dictionaries and packed arrays are returned by reference.
+func read(vtype gd.VariantType, ptr unsafe.Pointer) any {
+	switch vtype {
+	case gd.TypeNil:
+		return readVariant(ptr)
+	case gd.TypeBool:
+		return *(*uint8)(ptr) != 0
+	case gd.TypeString:
+		s, _ := load(*(*uint64)(ptr)).(str)
+		return s
+	case gd.TypeStringName:
+		s, _ := load(*(*uint64)(ptr)).(name)
+		return s
+	case gd.TypeNodePath:
+		s, _ := load(*(*uint64)(ptr)).(path)
+		return s
+	case gd.TypeObject:
+		return objectOf(*(*uint64)(ptr))
+	case gd.TypeSignal:
+		s, _ := load(*(*uint64)(ptr)).(signal)
+		return s
+	}
+	if handled(vtype) {
+		if value := load(*(*uint64)(ptr)); value != nil {
+			return value
+		}
+		return zero(vtype)
+	}
+	return reflect.NewAt(valueTypes[vtype], ptr).Elem().Interface()
+}
+
+// write the value to engine memory, as the given type, gd.TypeNil writes a Variant. Any handles
+// written are owned by the memory.
+func write(vtype gd.VariantType, ptr unsafe.Pointer, value any) {
+	if ptr == nil {
+		return
+	}
+	if vtype != gd.TypeNil && value != nil && typeOf(value) != vtype {
+		value = convert(value, vtype)
+	}
+	switch vtype {
+	case gd.TypeNil:
+		writeVariant(ptr, value)
+	case gd.TypeBool:
+		b, _ := value.(bool)
+		*(*uint8)(ptr) = 0
+		if b {
+			*(*uint8)(ptr) = 1
+		}
+	case gd.TypeStringName:
+		s, _ := value.(name)
+		*(*uint64)(ptr) = intern(string(s))
+	case gd.TypeObject:
+		obj, _ := value.(*object)
+		*(*uint64)(ptr) = 0
+		if obj != nil {
+			*(*uint64)(ptr) = obj.id
+		}
+	case gd.TypeCallable, gd.TypeSignal:
+		*(*[2]uint64)(ptr) = [2]uint64{alloc(clone(orZero(value, vtype))), 0}
+	default:
+		if handled(vtype) {
+			if vtype >= gd.TypePackedByteArray {
+				*(*[2]uint64)(ptr) = [2]uint64{alloc(clone(orZero(value, vtype))), 0}
+			} else {
+				*(*uint64)(ptr) = alloc(orZero(value, vtype))
+			}
+			return
+		}
+		if value == nil {
+			value = zero(vtype)
+		}
+		reflect.NewAt(valueTypes[vtype], ptr).Elem().Set(reflect.ValueOf(value))
+	}
+}
+
+func orZero(value any, vtype gd.VariantType) any {
+	if value == nil {
+		return zero(vtype)
+	}
+	return value
+}
+
+// handled reports whether values of the type are referred to by handle.
+func handled(vtype gd.VariantType) bool {
+	switch vtype {
+	case gd.TypeString, gd.TypeStringName, gd.TypeNodePath, gd.TypeObject, gd.TypeCallable, gd.TypeSignal,
+		gd.TypeDictionary, gd.TypeArray:
+		return true
+	}
+	return vtype >= gd.TypePackedByteArray
+}
+
+// A Variant is laid out as its type, followed by the handle to its value.
+
+func readVariant(ptr unsafe.Pointer) any {
+	raw := (*[3]uint64)(ptr)
+	if gd.VariantType(raw[0]) == gd.TypeNil {
+		return nil
+	}
+	if gd.VariantType(raw[0]) == gd.TypeObject {
+		return objectOf(raw[1])
+	}
+	return load(raw[1])
+}
+
+func writeVariant(ptr unsafe.Pointer, value any) {
+	raw := (*[3]uint64)(ptr)
+	vtype := typeOf(value)
+	switch vtype {
+	case gd.TypeNil:
+		*raw = [3]uint64{}
+	case gd.TypeObject:
+		*raw = [3]uint64{uint64(vtype), value.(*object).id}
+	default:
+		*raw = [3]uint64{uint64(vtype), alloc(clone(value))}
+	}
+}
+
+func newVariant(value any) gd.Variant {
+	var raw [3]uint64
+	writeVariant(unsafe.Pointer(&raw), value)
+	return pointers.New[gd.Variant](raw)
+}
+
+func valueOf(variant gd.Variant) any {
+	raw := pointers.Get(variant)
+	return readVariant(unsafe.Pointer(&raw))
+}
+
+func destroyVariant(raw [3]uint64) {
+	if gd.VariantType(raw[0]) != gd.TypeNil && gd.VariantType(raw[0]) != gd.TypeObject {
+		free(raw[1])
+	}
+}
+
+// destroy frees the value of the given type in engine memory.
+func destroy(vtype gd.VariantType, ptr unsafe.Pointer) {
+	switch {
+	case vtype == gd.TypeNil:
+		destroyVariant(*(*[3]uint64)(ptr))
+	case vtype == gd.TypeObject, vtype == gd.TypeStringName:
+	case handled(vtype):
+		free(*(*uint64)(ptr))
+	}
+}
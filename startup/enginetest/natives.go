@@ -0,0 +1,398 @@
+package enginetest
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	gd "graphics.gd/internal"
+)
+
+// natives implements the engine methods supported by the fake engine, by class and method name,
+// the first parameter of each method is the object the method is called on.
+var natives map[string]map[string]*native
+
+func init() {
+	natives = map[string]map[string]*native{
+		"Object":      bindAll(objectMethods),
+		"RefCounted":  bindAll(refCountedMethods),
+		"Engine":      bindAll(engineMethods),
+		"Node":        bindAll(nodeMethods),
+		"SceneTree":   bindAll(sceneTreeMethods),
+		"OS":          bindAll(osMethods),
+		"Performance": bindAll(performanceMethods),
+
+		"DisplayServer": bindAll(displayServerMethods),
+
+		"ResourceSaver":  bindAll(resourceSaverMethods),
+		"ResourceLoader": bindAll(resourceLoaderMethods),
+		"FileAccess":     bindStatic(fileAccessMethods),
+
+		"EditorPlugin":          bindAll(editorPluginMethods),
+		"EditorInspectorPlugin": bindAll(inspectorPluginMethods),
+	}
+}
+
+func bindAll(methods map[string]any) map[string]*native {
+	bound := make(map[string]*native, len(methods))
+	for name, fn := range methods {
+		bound[name] = bind(fn)
+	}
+	return bound
+}
+
+// bindStatic binds static methods, which are called without an object.
+func bindStatic(methods map[string]any) map[string]*native {
+	bound := bindAll(methods)
+	for _, n := range bound {
+		n.static = true
+	}
+	return bound
+}
+
+type accessor struct{ getter, setter string }
+
+// nativeProperties are the engine properties supported by the fake engine.
+var nativeProperties = map[string]map[string]accessor{
+	"Node": {
+		"name":  {"get_name", "set_name"},
+		"owner": {"get_owner", "set_owner"},
+	},
+	"SceneTree": {
+		"root": {"get_root", ""},
+	},
+}
+
+func nativeProperty(c *class, property string) (accessor, bool) {
+	for ; c != nil; c = c.parent {
+		if a, ok := nativeProperties[c.name][property]; ok {
+			return a, true
+		}
+	}
+	return accessor{}, false
+}
+
+// nativeSignals are the engine signals supported by the fake engine.
+var nativeSignals = map[string][]string{
+	"Object":    {"script_changed", "property_list_changed"},
+	"Node":      {"ready", "renamed", "tree_entered", "tree_exiting", "tree_exited", "child_entered_tree", "child_exiting_tree", "child_order_changed"},
+	"SceneTree": {"node_added", "node_removed", "process_frame", "physics_frame", "tree_changed"},
+}
+
+func hasNativeSignal(c *class, signal string) bool {
+	for ; c != nil; c = c.parent {
+		if slices.Contains(nativeSignals[c.name], signal) {
+			return true
+		}
+	}
+	return false
+}
+
+// deferred calls, flushed at the end of each frame.
+var deferred struct {
+	sync.Mutex
+	calls []func()
+}
+
+func deferCall(fn func()) {
+	deferred.Lock()
+	defer deferred.Unlock()
+	deferred.calls = append(deferred.calls, fn)
+}
+
+// flush calls all deferred calls, including any that are deferred while flushing.
+func flush() {
+	for {
+		deferred.Lock()
+		calls := deferred.calls
+		deferred.calls = nil
+		deferred.Unlock()
+		if len(calls) == 0 {
+			return
+		}
+		for _, call := range calls {
+			call()
+		}
+	}
+}
+
+var objectMethods = map[string]any{
+	"get_class": func(self *object) str { return str(self.class.name) },
+	"is_class":  func(self *object, class str) bool { return self.class.is(string(class)) },
+	"get_instance_id": func(self *object) int64 {
+		return int64(self.id)
+	},
+	"set": func(self *object, property name, value any) {
+		self.set(string(property), value)
+	},
+	"get": func(self *object, property name) any {
+		value, _ := self.get(string(property))
+		return value
+	},
+	"get_property_list": func(self *object) *array {
+		list := newArray()
+		for c := self.class; c != nil; c = c.parent {
+			for _, p := range c.properties {
+				info := &dictionary{}
+				info.set(str("name"), str(p.name))
+				info.set(str("type"), int64(p.info.Type))
+				info.set(str("hint"), p.info.Hint)
+				info.set(str("usage"), p.info.Usage)
+				list.elems = append(list.elems, info)
+			}
+		}
+		return list
+	},
+	"notification": func(self *object, what int64, reversed bool) {
+		self.notify(int32(what))
+	},
+	"to_string": func(self *object) str { return str(stringOf(self)) },
+	"set_meta": func(self *object, key name, value any) {
+		if value == nil {
+			self.meta.erase(key)
+			return
+		}
+		self.meta.set(key, value)
+	},
+	"remove_meta": func(self *object, key name) { self.meta.erase(key) },
+	"get_meta": func(self *object, key name, fallback any) any {
+		if value, ok := self.meta.get(key); ok {
+			return value
+		}
+		if fallback == nil {
+			reportError(fmt.Sprintf("The object does not have any 'meta' values with the key '%s'.", key))
+		}
+		return fallback
+	},
+	"has_meta": func(self *object, key name) bool {
+		_, ok := self.meta.get(key)
+		return ok
+	},
+	"get_meta_list": func(self *object) *array {
+		return &array{elems: slices.Clone(self.meta.keys), typed: gd.TypeStringName}
+	},
+	"add_user_signal": func(self *object, signal str, arguments *array) {
+		if self.userSignals == nil {
+			self.userSignals = make(map[string]bool)
+		}
+		self.userSignals[string(signal)] = true
+	},
+	"has_user_signal":    func(self *object, signal name) bool { return self.userSignals[string(signal)] },
+	"remove_user_signal": func(self *object, signal name) { delete(self.userSignals, string(signal)) },
+	"emit_signal": func(self *object, signal name, args ...any) int64 {
+		return int64(self.emit(string(signal), args))
+	},
+	"call": func(self *object, method name, args ...any) any {
+		result, err := self.call(string(method), args)
+		if err != nil {
+			reportError(fmt.Sprintf("Error calling method '%s' on %s: %v.", method, stringOf(self), err))
+		}
+		return result
+	},
+	"call_deferred": func(self *object, method name, args ...any) any {
+		deferCall(func() {
+			if !self.freed {
+				self.call(string(method), args)
+			}
+		})
+		return nil
+	},
+	"set_deferred": func(self *object, property name, value any) {
+		deferCall(func() {
+			if !self.freed {
+				self.set(string(property), value)
+			}
+		})
+	},
+	"callv": func(self *object, method name, args *array) any {
+		result, err := self.call(string(method), slices.Clone(args.elems))
+		if err != nil {
+			reportError(fmt.Sprintf("Error calling method '%s' on %s: %v.", method, stringOf(self), err))
+		}
+		return result
+	},
+	"has_method": func(self *object, method name) bool { return self.hasMethod(string(method)) },
+	"has_signal": func(self *object, signal name) bool { return self.hasSignal(string(signal)) },
+	"connect": func(self *object, signal name, target *callable, flags int64) int64 {
+		return int64(self.connect(string(signal), target, flags))
+	},
+	"disconnect": func(self *object, signal name, target *callable) {
+		if !self.disconnect(string(signal), target) {
+			reportError(fmt.Sprintf("Attempt to disconnect a nonexistent connection from '%s'. Signal: '%s', callable: '%s'.", stringOf(self), signal, stringOf(target)))
+		}
+	},
+	"is_connected": func(self *object, signal name, target *callable) bool {
+		return self.isConnected(string(signal), target)
+	},
+	"set_block_signals":      func(self *object, enable bool) { self.blocked = enable },
+	"is_blocking_signals":    func(self *object) bool { return self.blocked },
+	"is_queued_for_deletion": func(self *object) bool { return self.queued },
+	"cancel_free":            func(self *object) { self.queued = false },
+	"get_script":             func(self *object) any { return nil },
+	"notify_property_list_changed": func(self *object) {
+		self.emit("property_list_changed", nil)
+	},
+	"can_translate_messages":  func(self *object) bool { return false },
+	"set_message_translation": func(self *object, enable bool) {},
+	"tr":                      func(self *object, message name, context name) str { return str(message) },
+	"property_can_revert":     func(self *object, property name) bool { return false },
+	"get_method_argument_count": func(self *object, method name) int64 {
+		if m := self.class.method(string(method)); m != nil {
+			return int64(len(m.Arguments))
+		}
+		if n := self.class.native(string(method)); n != nil {
+			return int64(len(n.in) - 1)
+		}
+		return 0
+	},
+}
+
+var refCountedMethods = map[string]any{
+	"init_ref": func(self *object) bool {
+		self.refcount++
+		return true
+	},
+	"reference": func(self *object) bool {
+		self.refcount++
+		if self.instance != nil {
+			self.instance.Reference()
+		}
+		return true
+	},
+	"unreference": func(self *object) bool {
+		self.refcount--
+		if self.instance != nil {
+			self.instance.Unreference()
+		}
+		return self.refcount <= 0
+	},
+	"get_reference_count": func(self *object) int64 { return self.refcount },
+}
+
+var engineMethods = map[string]any{
+	"is_editor_hint":      func(self *object) bool { return Editor },
+	"get_process_frames":  func(self *object) int64 { return tree.processFrames },
+	"get_physics_frames":  func(self *object) int64 { return tree.physicsFrames },
+	"get_frames_drawn":    func(self *object) int64 { return tree.processFrames },
+	"is_in_physics_frame": func(self *object) bool { return tree.inPhysics },
+	"get_physics_ticks_per_second": func(self *object) int64 {
+		return engine.physicsTicksPerSecond
+	},
+	"set_physics_ticks_per_second": func(self *object, ticks int64) {
+		if ticks <= 0 {
+			reportError("Condition \"p_ips <= 0\" is true.")
+			return
+		}
+		engine.physicsTicksPerSecond = ticks
+	},
+	"get_physics_jitter_fix": func(self *object) float64 { return engine.physicsJitterFix },
+	"set_physics_jitter_fix": func(self *object, fix float64) { engine.physicsJitterFix = fix },
+	"get_max_fps":            func(self *object) int64 { return engine.maxFPS },
+	"set_max_fps":            func(self *object, fps int64) { engine.maxFPS = max(fps, 0) },
+	"get_main_loop": func(self *object) *object {
+		if tree.mainLoop != nil {
+			return tree.mainLoop
+		}
+		return tree.object
+	},
+	"has_singleton": func(self *object, singleton name) bool {
+		return singletonOf(string(singleton)) != nil
+	},
+	"get_singleton": func(self *object, singleton name) *object {
+		return singletonOf(string(singleton))
+	},
+}
+
+var displayServerMethods = map[string]any{
+	"get_name": func(self *object) str { return "headless" },
+}
+
+var osMethods = map[string]any{
+	"is_debug_build": func(self *object) bool { return true },
+}
+
+// monitors added with Performance.add_custom_monitor, in the order that they were added.
+var monitors struct {
+	sync.Mutex
+	ids       []string
+	callables map[string]*callable
+}
+
+var performanceMethods = map[string]any{
+	"add_custom_monitor": func(self *object, id name, c *callable, args *array) {
+		monitors.Lock()
+		defer monitors.Unlock()
+		if _, ok := monitors.callables[string(id)]; ok {
+			reportError(fmt.Sprintf("Custom monitor with id '%s' already exists.", id))
+			return
+		}
+		if monitors.callables == nil {
+			monitors.callables = make(map[string]*callable)
+		}
+		monitors.ids = append(monitors.ids, string(id))
+		monitors.callables[string(id)] = c
+	},
+	"remove_custom_monitor": func(self *object, id name) {
+		monitors.Lock()
+		defer monitors.Unlock()
+		if _, ok := monitors.callables[string(id)]; !ok {
+			reportError(fmt.Sprintf("Custom monitor with id '%s' doesn't exists.", id))
+			return
+		}
+		delete(monitors.callables, string(id))
+		monitors.ids = slices.DeleteFunc(monitors.ids, func(existing string) bool { return existing == string(id) })
+	},
+	"has_custom_monitor": func(self *object, id name) bool {
+		monitors.Lock()
+		defer monitors.Unlock()
+		_, ok := monitors.callables[string(id)]
+		return ok
+	},
+	"get_custom_monitor": func(self *object, id name) any {
+		monitors.Lock()
+		c, ok := monitors.callables[string(id)]
+		monitors.Unlock()
+		if !ok {
+			reportError(fmt.Sprintf("Custom monitor with id '%s' doesn't exists.", id))
+			return nil
+		}
+		result, err := callCallable(c, nil)
+		if err != nil {
+			reportError(fmt.Sprintf("Error calling from custom monitor '%s' to callable: '%s': %v.", id, stringOf(c), err))
+		}
+		return result
+	},
+	"get_custom_monitor_names": func(self *object) *array {
+		monitors.Lock()
+		defer monitors.Unlock()
+		names := &array{typed: gd.TypeStringName}
+		for _, id := range monitors.ids {
+			names.elems = append(names.elems, name(id))
+		}
+		return names
+	},
+}
+
+// singletons by name, created on first use.
+var singletons struct {
+	sync.Mutex
+	byName map[string]*object
+}
+
+func singletonOf(className string) *object {
+	singletons.Lock()
+	defer singletons.Unlock()
+	if obj, ok := singletons.byName[className]; ok {
+		return obj
+	}
+	c := classNamed(className)
+	if c == nil {
+		return nil
+	}
+	obj := construct(c)
+	if singletons.byName == nil {
+		singletons.byName = make(map[string]*object)
+	}
+	singletons.byName[className] = obj
+	return obj
+}
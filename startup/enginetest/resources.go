@@ -0,0 +1,180 @@
+package enginetest
+
+import (
+	"fmt"
+	"sync"
+
+	gd "graphics.gd/internal"
+	"graphics.gd/variant/Error"
+)
+
+// files are the resources saved with ResourceSaver, by path. Instead of writing resource files, the
+// fake engine keeps the properties that the text resource format would write, in the same order, so
+// that loading a resource sets its properties as the engine would. Loaded resources are not cached.
+var files struct {
+	sync.Mutex
+	saved    map[string]*savedResource
+	defaults map[*class]map[string]any // default property values, by class.
+}
+
+// savedResource is a resource as written to a resource file, nested resources are saved as
+// sub-resources.
+type savedResource struct {
+	class      string
+	properties []savedProperty
+}
+
+type savedProperty struct {
+	name  string
+	value any
+}
+
+const propertyUsageStorage = 2
+
+// storedProperties returns the properties of the class that are saved, base classes first.
+func storedProperties(c *class) []property {
+	if c == nil {
+		return nil
+	}
+	stored := storedProperties(c.parent)
+	for _, p := range c.properties {
+		if p.info.Usage&propertyUsageStorage != 0 {
+			stored = append(stored, p)
+		}
+	}
+	return stored
+}
+
+// defaultsOf returns the default property values of the class, sampled from a new instance of
+// it, like the engine does.
+func defaultsOf(c *class) map[string]any {
+	files.Lock()
+	defaults, ok := files.defaults[c]
+	files.Unlock()
+	if ok {
+		return defaults
+	}
+	defaults = make(map[string]any)
+	if sample := construct(c); sample != nil {
+		for _, p := range storedProperties(c) {
+			if value, ok := sample.get(p.name); ok {
+				defaults[p.name] = value
+			}
+		}
+		sample.free()
+	}
+	files.Lock()
+	defer files.Unlock()
+	if files.defaults == nil {
+		files.defaults = make(map[*class]map[string]any)
+	}
+	files.defaults[c] = defaults
+	return defaults
+}
+
+// saveResource saves the stored properties of the resource that differ from the defaults of its
+// class.
+func saveResource(resource *object) *savedResource {
+	saved := &savedResource{class: resource.class.name}
+	defaults := defaultsOf(resource.class)
+	for _, p := range storedProperties(resource.class) {
+		value, ok := resource.get(p.name)
+		if !ok {
+			continue
+		}
+		if def, ok := defaults[p.name]; ok && def != nil && equal(value, def) {
+			continue
+		}
+		if value == nil && p.info.Type == gd.TypeObject {
+			continue
+		}
+		saved.properties = append(saved.properties, savedProperty{name: p.name, value: saveValue(value)})
+	}
+	return saved
+}
+
+func saveValue(value any) any {
+	switch v := value.(type) {
+	case *object:
+		if v != nil && v.class.is("Resource") {
+			return saveResource(v)
+		}
+		return v
+	case *array:
+		saved := &array{typed: v.typed, class: v.class, elems: make([]any, len(v.elems))}
+		for i, elem := range v.elems {
+			saved.elems[i] = saveValue(elem)
+		}
+		return saved
+	default:
+		return clone(value)
+	}
+}
+
+// loadResource creates a new resource from the saved one, setting its saved properties in order.
+func loadResource(saved *savedResource) *object {
+	resource := construct(classNamed(saved.class))
+	for _, p := range saved.properties {
+		if !resource.set(p.name, loadValue(p.value)) {
+			reportError(fmt.Sprintf("Resource property '%s' does not exist on %s.", p.name, saved.class))
+		}
+	}
+	return resource
+}
+
+func loadValue(value any) any {
+	switch v := value.(type) {
+	case *savedResource:
+		return loadResource(v)
+	case *array:
+		loaded := &array{typed: v.typed, class: v.class, elems: make([]any, len(v.elems))}
+		for i, elem := range v.elems {
+			loaded.elems[i] = loadValue(elem)
+		}
+		return loaded
+	default:
+		return clone(value)
+	}
+}
+
+var resourceSaverMethods = map[string]any{
+	"save": func(self *object, resource *object, path str, flags int64) int64 {
+		if resource == nil {
+			return int64(Error.InvalidParameter)
+		}
+		if path == "" {
+			return int64(Error.FileBadPath)
+		}
+		saved := saveResource(resource)
+		files.Lock()
+		defer files.Unlock()
+		if files.saved == nil {
+			files.saved = make(map[string]*savedResource)
+		}
+		files.saved[string(path)] = saved
+		return 0
+	},
+}
+
+var resourceLoaderMethods = map[string]any{
+	"load": func(self *object, path str, typeHint str, cacheMode int64) *object {
+		files.Lock()
+		saved, ok := files.saved[string(path)]
+		files.Unlock()
+		if !ok {
+			reportError(fmt.Sprintf("Resource file not found: %s (expected type: %s)", path, typeHint))
+			return nil
+		}
+		if typeHint != "" && !classNamed(saved.class).is(string(typeHint)) {
+			reportError(fmt.Sprintf("No loader found for resource: %s (expected type: %s)", path, typeHint))
+			return nil
+		}
+		return loadResource(saved)
+	},
+	"exists": func(self *object, path str, typeHint str) bool {
+		files.Lock()
+		defer files.Unlock()
+		_, ok := files.saved[string(path)]
+		return ok
+	},
+}
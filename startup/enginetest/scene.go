@@ -0,0 +1,670 @@
+package enginetest
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	gd "graphics.gd/internal"
+)
+
+// node state, for objects that inherit from Node.
+type node struct {
+	name     string
+	parent   *object
+	children []*object
+	owner    *object
+	internal int64 // internal mode, relative to the parent.
+
+	inside bool
+	ready  bool
+
+	process        bool
+	physicsProcess bool
+	processMode    int64
+
+	groups []string
+}
+
+// tree is the SceneTree, along with the root Window.
+var tree struct {
+	object *object
+	root   *object
+
+	processFrames int64
+	physicsFrames int64
+	inPhysics     bool
+
+	paused   bool
+	quit     bool
+	exitCode int64
+	current  *object
+
+	mainLoop *object // run by [run], instead of the SceneTree.
+}
+
+// engine settings, that can be changed through the Engine singleton.
+var engine = struct {
+	physicsTicksPerSecond int64
+	physicsJitterFix      float64
+	maxFPS                int64
+}{physicsTicksPerSecond: 60, physicsJitterFix: 0.5}
+
+// physicsDelta returns the duration of a physics tick, in seconds.
+func physicsDelta() float64 { return 1.0 / float64(engine.physicsTicksPerSecond) }
+
+// Process modes, for set_process_mode.
+const (
+	processModeInherit    = 0
+	processModePausable   = 1
+	processModeWhenPaused = 2
+	processModeAlways     = 3
+	processModeDisabled   = 4
+)
+
+// Internal modes, for add_child.
+const (
+	internalModeDisabled = 0
+	internalModeFront    = 1
+	internalModeBack     = 2
+)
+
+// newTree creates the SceneTree and its root Window.
+func newTree() {
+	tree.object = construct(classNamed("SceneTree"))
+	tree.root = construct(classNamed("Window"))
+	tree.root.node.name = "root"
+	tree.root.enterTree()
+	tree.root.propagateReady()
+}
+
+// children returns the node's children, in order, optionally including internal children.
+func (obj *object) children(includeInternal bool) []*object {
+	if includeInternal {
+		return obj.node.children
+	}
+	var children []*object
+	for _, child := range obj.node.children {
+		if child.node.internal == internalModeDisabled {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+func (obj *object) child(name string) *object {
+	for _, child := range obj.node.children {
+		if child.node.name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// validateName returns a name for the child, that is unique among its siblings.
+func (obj *object) validateName(child *object, name string, readable bool) string {
+	if name == "" {
+		name = "@" + child.class.name + "@" + strconv.FormatUint(child.id, 10)
+		if readable {
+			name = child.class.name
+		}
+	}
+	name = strings.NewReplacer(".", "_", ":", "_", "@", "_", "/", "_", "\"", "_", "%", "_").Replace(name)
+	if existing := obj.child(name); existing == nil || existing == child {
+		return name
+	}
+	base := strings.TrimRightFunc(name, func(r rune) bool { return r >= '0' && r <= '9' })
+	for i := 2; ; i++ {
+		candidate := base + strconv.Itoa(i)
+		if existing := obj.child(candidate); existing == nil || existing == child {
+			return candidate
+		}
+	}
+}
+
+func (obj *object) addChild(child *object, readable bool, internal int64) {
+	switch {
+	case child == obj:
+		reportError(fmt.Sprintf("Can't add child '%s' to itself.", child.node.name))
+		return
+	case child.node.parent != nil:
+		reportError(fmt.Sprintf("Can't add child '%s' to '%s', already has a parent '%s'.", child.node.name, obj.node.name, child.node.parent.node.name))
+		return
+	case child.isAncestorOf(obj):
+		reportError(fmt.Sprintf("Can't add child '%s' to '%s' as it would result in a cyclic dependency.", child.node.name, obj.node.name))
+		return
+	}
+	child.node.name = obj.validateName(child, child.node.name, readable)
+	child.node.parent = obj
+	child.node.internal = internal
+	switch internal {
+	case internalModeFront:
+		i := 0
+		for i < len(obj.node.children) && obj.node.children[i].node.internal == internalModeFront {
+			i++
+		}
+		obj.node.children = slices.Insert(obj.node.children, i, child)
+	case internalModeDisabled:
+		i := len(obj.node.children)
+		for i > 0 && obj.node.children[i-1].node.internal == internalModeBack {
+			i--
+		}
+		obj.node.children = slices.Insert(obj.node.children, i, child)
+	default:
+		obj.node.children = append(obj.node.children, child)
+	}
+	child.notify(notificationParented)
+	if obj.node.inside {
+		child.enterTree()
+	}
+	obj.emit("child_order_changed", nil)
+	if obj.node.inside {
+		child.propagateReady()
+	}
+}
+
+func (obj *object) removeChild(child *object) {
+	if child.node.parent != obj {
+		reportError(fmt.Sprintf("Cannot remove child node '%s' as it is not a child of this node.", child.node.name))
+		return
+	}
+	if child.node.inside {
+		child.exitTree()
+	}
+	obj.node.children = slices.DeleteFunc(obj.node.children, func(c *object) bool { return c == child })
+	child.node.parent = nil
+	child.node.internal = internalModeDisabled
+	if child.node.owner != nil && !child.node.owner.isAncestorOf(child) {
+		child.node.owner = nil
+	}
+	child.notify(notificationUnparented)
+	obj.emit("child_order_changed", nil)
+}
+
+func (obj *object) isAncestorOf(other *object) bool {
+	for parent := other.node.parent; parent != nil; parent = parent.node.parent {
+		if parent == obj {
+			return true
+		}
+	}
+	return false
+}
+
+// enterTree propagates ENTER_TREE from the node to all of its children, top-down.
+func (obj *object) enterTree() {
+	obj.node.inside = true
+	obj.notify(notificationEnterTree)
+	obj.callVirtual("_enter_tree", nil, nil)
+	obj.emit("tree_entered", nil)
+	if tree.object != nil {
+		tree.object.emit("node_added", []any{obj})
+	}
+	for _, child := range slices.Clone(obj.node.children) {
+		child.enterTree()
+	}
+	if parent := obj.node.parent; parent != nil {
+		parent.emit("child_entered_tree", []any{obj})
+	}
+}
+
+// propagateReady readies the node's children, bottom-up, before readying the node itself.
+func (obj *object) propagateReady() {
+	for _, child := range slices.Clone(obj.node.children) {
+		child.propagateReady()
+	}
+	if obj.node.ready || !obj.node.inside {
+		return
+	}
+	obj.node.ready = true
+	if obj.virtual("_process") != nil {
+		obj.node.process = true
+	}
+	if obj.virtual("_physics_process") != nil {
+		obj.node.physicsProcess = true
+	}
+	obj.notify(notificationReady)
+	obj.emit("ready", nil)
+}
+
+// exitTree propagates EXIT_TREE from the node to all of its children, bottom-up.
+func (obj *object) exitTree() {
+	if parent := obj.node.parent; parent != nil {
+		parent.emit("child_exiting_tree", []any{obj})
+	}
+	obj.emit("tree_exiting", nil)
+	for _, child := range slices.Backward(slices.Clone(obj.node.children)) {
+		child.exitTree()
+	}
+	obj.callVirtual("_exit_tree", nil, nil)
+	obj.notify(notificationExitTree)
+	obj.node.inside = false
+	if tree.object != nil {
+		tree.object.emit("node_removed", []any{obj})
+	}
+	obj.emit("tree_exited", nil)
+}
+
+// freeNode removes the node from its parent and frees its children.
+func (obj *object) freeNode() {
+	if parent := obj.node.parent; parent != nil {
+		parent.removeChild(obj)
+	}
+	for _, child := range slices.Backward(slices.Clone(obj.node.children)) {
+		child.free()
+	}
+	obj.node.children = nil
+	if tree.current == obj {
+		tree.current = nil
+	}
+}
+
+// path returns the absolute path of a node that is inside the tree, or its path relative to
+// the topmost ancestor.
+func (obj *object) path() path {
+	var names []string
+	top := obj
+	for n := obj; n != nil; n = n.node.parent {
+		names = append(names, n.node.name)
+		top = n
+	}
+	slices.Reverse(names)
+	if top == tree.root {
+		return path("/" + strings.Join(names, "/"))
+	}
+	return path(strings.Join(names[1:], "/"))
+}
+
+// lookup resolves the node path relative to the node.
+func (obj *object) lookup(p path) *object {
+	s := string(p)
+	if s == "" {
+		return nil
+	}
+	if s, _, _ = strings.Cut(s, ":"); s == "" {
+		return obj
+	}
+	current := obj
+	if strings.HasPrefix(s, "/") {
+		if !obj.node.inside {
+			return nil
+		}
+		current = nil
+		s = strings.TrimPrefix(s, "/")
+	}
+	for _, elem := range strings.Split(s, "/") {
+		switch {
+		case elem == "" || elem == ".":
+			continue
+		case current == nil:
+			if elem != tree.root.node.name {
+				return nil
+			}
+			current = tree.root
+		case elem == "..":
+			current = current.node.parent
+		case strings.HasPrefix(elem, "%"):
+			current = current.uniqueNamed(strings.TrimPrefix(elem, "%"))
+		default:
+			current = current.child(elem)
+		}
+		if current == nil {
+			return nil
+		}
+	}
+	return current
+}
+
+// uniqueNamed finds a node with the %unique name owned by the same owner as the node.
+func (obj *object) uniqueNamed(name string) *object {
+	owner := obj.node.owner
+	if owner == nil {
+		owner = obj
+	}
+	return owner.findChild(func(n *object) bool { return n.node.name == name && n.node.owner == owner }, true)
+}
+
+func (obj *object) findChild(match func(*object) bool, recursive bool) *object {
+	for _, child := range obj.node.children {
+		if match(child) {
+			return child
+		}
+	}
+	if recursive {
+		for _, child := range obj.node.children {
+			if found := child.findChild(match, true); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// walk the node and its descendants, in tree order.
+func (obj *object) walk(fn func(*object)) {
+	fn(obj)
+	for _, child := range slices.Clone(obj.node.children) {
+		if !child.freed {
+			child.walk(fn)
+		}
+	}
+}
+
+func (obj *object) inGroup(group string) bool { return slices.Contains(obj.node.groups, group) }
+
+func nodesInGroup(group string) []*object {
+	var nodes []*object
+	tree.root.walk(func(n *object) {
+		if n.inGroup(group) {
+			nodes = append(nodes, n)
+		}
+	})
+	return nodes
+}
+
+// processing reports whether the node processes, given its process mode, or the mode that
+// it inherits, and whether the tree is paused.
+func (obj *object) processing() bool {
+	mode := obj.node.processMode
+	for n := obj.node.parent; mode == processModeInherit && n != nil; n = n.node.parent {
+		mode = n.node.processMode
+	}
+	switch mode {
+	case processModeAlways:
+		return true
+	case processModeWhenPaused:
+		return tree.paused
+	case processModeDisabled:
+		return false
+	default:
+		return !tree.paused
+	}
+}
+
+// step advances the tree by one physics tick and one process frame.
+func step(delta float64) {
+	physicsFrame()
+	processFrame(delta)
+}
+
+// physicsFrame processes the nodes in the tree for one physics tick.
+func physicsFrame() {
+	tree.inPhysics = true
+	delta := physicsDelta()
+	tree.root.walk(func(n *object) {
+		if n.node.physicsProcess && n.node.inside && n.processing() {
+			n.notify(notificationPhysicsProcess)
+			n.callVirtual("_physics_process", []unsafe.Pointer{unsafe.Pointer(&delta)}, nil)
+		}
+	})
+	tree.physicsFrames++
+	tree.object.emit("physics_frame", nil)
+	tree.inPhysics = false
+}
+
+// processFrame processes the nodes in the tree for one frame, then makes any deferred calls and
+// frees the nodes that are queued for deletion.
+func processFrame(delta float64) {
+	tree.root.walk(func(n *object) {
+		if n.node.process && n.node.inside && n.processing() {
+			n.notify(notificationProcess)
+			n.callVirtual("_process", []unsafe.Pointer{unsafe.Pointer(&delta)}, nil)
+		}
+	})
+	tree.processFrames++
+	tree.object.emit("process_frame", nil)
+	flush()
+	tree.root.walk(func(n *object) {
+		if n.queued {
+			n.free()
+		}
+	})
+}
+
+func nodeOf(obj *object) *object {
+	if obj == nil || obj.node == nil {
+		return nil
+	}
+	return obj
+}
+
+var nodeMethods = map[string]any{
+	"set_name": func(self *object, name str) {
+		if name == "" {
+			reportError("Node name cannot be empty.")
+			return
+		}
+		if parent := self.node.parent; parent != nil {
+			self.node.name = parent.validateName(self, string(name), true)
+		} else {
+			self.node.name = string(name)
+		}
+		self.emit("renamed", nil)
+	},
+	"get_name": func(self *object) name { return name(self.node.name) },
+	"add_child": func(self *object, child *object, readable bool, internal int64) {
+		if nodeOf(child) == nil {
+			reportError("Parameter \"p_child\" is null.")
+			return
+		}
+		self.addChild(child, readable, internal)
+	},
+	"add_sibling": func(self *object, sibling *object, readable bool) {
+		parent := self.node.parent
+		if parent == nil || nodeOf(sibling) == nil {
+			reportError("Can't add a sibling to a node without a parent.")
+			return
+		}
+		parent.addChild(sibling, readable, self.node.internal)
+		i := slices.Index(parent.node.children, self)
+		parent.node.children = slices.DeleteFunc(parent.node.children, func(c *object) bool { return c == sibling })
+		parent.node.children = slices.Insert(parent.node.children, i+1, sibling)
+	},
+	"remove_child": func(self *object, child *object) {
+		if nodeOf(child) == nil {
+			reportError("Parameter \"p_child\" is null.")
+			return
+		}
+		self.removeChild(child)
+	},
+	"reparent": func(self *object, parent *object, keepGlobalTransform bool) {
+		if nodeOf(parent) == nil {
+			reportError("Parameter \"p_parent\" is null.")
+			return
+		}
+		if old := self.node.parent; old != nil {
+			old.removeChild(self)
+		}
+		parent.addChild(self, true, internalModeDisabled)
+	},
+	"move_child": func(self *object, child *object, index int64) {
+		if nodeOf(child) == nil || child.node.parent != self {
+			reportError("Parameter \"p_child\" is not a child of this node.")
+			return
+		}
+		children := slices.DeleteFunc(self.node.children, func(c *object) bool { return c == child })
+		if index < 0 {
+			index += int64(len(children)) + 1
+		}
+		index = max(0, min(index, int64(len(children))))
+		self.node.children = slices.Insert(children, int(index), child)
+		self.emit("child_order_changed", nil)
+	},
+	"get_child_count": func(self *object, includeInternal bool) int64 {
+		return int64(len(self.children(includeInternal)))
+	},
+	"get_child": func(self *object, index int64, includeInternal bool) *object {
+		children := self.children(includeInternal)
+		if index < 0 {
+			index += int64(len(children))
+		}
+		if index < 0 || index >= int64(len(children)) {
+			reportError(fmt.Sprintf("Index p_index = %d is out of bounds (%d).", index, len(children)))
+			return nil
+		}
+		return children[index]
+	},
+	"get_children": func(self *object, includeInternal bool) *array {
+		children := &array{typed: gd.TypeObject, class: "Node"}
+		for _, child := range self.children(includeInternal) {
+			children.elems = append(children.elems, child)
+		}
+		return children
+	},
+	"get_index": func(self *object, includeInternal bool) int64 {
+		if self.node.parent == nil {
+			return -1
+		}
+		return int64(slices.Index(self.node.parent.children(includeInternal), self))
+	},
+	"get_parent": func(self *object) *object { return self.node.parent },
+	"get_node": func(self *object, p path) *object {
+		found := self.lookup(p)
+		if found == nil {
+			reportError(fmt.Sprintf("Node not found: \"%s\" (relative to \"%s\").", p, self.path()))
+		}
+		return found
+	},
+	"get_node_or_null": func(self *object, p path) *object { return self.lookup(p) },
+	"has_node":         func(self *object, p path) bool { return self.lookup(p) != nil },
+	"find_child": func(self *object, pattern str, recursive, owned bool) *object {
+		return self.findChild(func(n *object) bool {
+			matched, _ := filepath.Match(string(pattern), n.node.name)
+			return matched && (!owned || n.node.owner != nil)
+		}, recursive)
+	},
+	"is_ancestor_of": func(self *object, other *object) bool {
+		return nodeOf(other) != nil && self.isAncestorOf(other)
+	},
+	"is_inside_tree": func(self *object) bool { return self.node.inside },
+	"is_node_ready":  func(self *object) bool { return self.node.ready },
+	"request_ready":  func(self *object) { self.node.ready = false },
+	"get_tree": func(self *object) *object {
+		if !self.node.inside {
+			reportError("Condition \"!data.tree\" is true. Returning: nullptr")
+			return nil
+		}
+		return tree.object
+	},
+	"get_path": func(self *object) path { return self.path() },
+	"get_path_to": func(self *object, other *object, useUniquePath bool) path {
+		if nodeOf(other) == nil {
+			return ""
+		}
+		from, to := strings.Split(string(self.path()), "/"), strings.Split(string(other.path()), "/")
+		common := 0
+		for common < len(from) && common < len(to) && from[common] == to[common] {
+			common++
+		}
+		var elems []string
+		for range from[common:] {
+			elems = append(elems, "..")
+		}
+		elems = append(elems, to[common:]...)
+		if len(elems) == 0 {
+			return "."
+		}
+		return path(strings.Join(elems, "/"))
+	},
+	"set_owner": func(self *object, owner *object) {
+		if owner != nil && !owner.isAncestorOf(self) {
+			reportError("Invalid owner. Owner must be an ancestor in the tree.")
+			return
+		}
+		self.node.owner = nodeOf(owner)
+	},
+	"get_owner": func(self *object) *object { return self.node.owner },
+	"queue_free": func(self *object) {
+		self.queued = true
+		if !self.node.inside {
+			deferCall(func() {
+				if !self.freed && self.queued {
+					self.free()
+				}
+			})
+		}
+	},
+	"set_process":                    func(self *object, enable bool) { self.node.process = enable },
+	"set_process_mode":               func(self *object, mode int64) { self.node.processMode = mode },
+	"get_process_mode":               func(self *object) int64 { return self.node.processMode },
+	"is_processing":                  func(self *object) bool { return self.node.process },
+	"set_physics_process":            func(self *object, enable bool) { self.node.physicsProcess = enable },
+	"is_physics_processing":          func(self *object) bool { return self.node.physicsProcess },
+	"get_process_delta_time":         func(self *object) float64 { return physicsDelta() },
+	"get_physics_process_delta_time": func(self *object) float64 { return physicsDelta() },
+	"add_to_group": func(self *object, group name, persistent bool) {
+		if !self.inGroup(string(group)) {
+			self.node.groups = append(self.node.groups, string(group))
+		}
+	},
+	"remove_from_group": func(self *object, group name) {
+		self.node.groups = slices.DeleteFunc(self.node.groups, func(g string) bool { return g == string(group) })
+	},
+	"is_in_group": func(self *object, group name) bool { return self.inGroup(string(group)) },
+	"get_groups": func(self *object) *array {
+		groups := &array{typed: gd.TypeStringName}
+		for _, group := range self.node.groups {
+			groups.elems = append(groups.elems, name(group))
+		}
+		return groups
+	},
+}
+
+var sceneTreeMethods = map[string]any{
+	"get_root": func(self *object) *object { return tree.root },
+	"quit": func(self *object, exitCode int64) {
+		tree.quit, tree.exitCode = true, exitCode
+	},
+	"get_frame": func(self *object) int64 { return tree.processFrames },
+	"is_paused": func(self *object) bool { return tree.paused },
+	"set_pause": func(self *object, paused bool) { tree.paused = paused },
+	"get_node_count": func(self *object) int64 {
+		var count int64
+		tree.root.walk(func(*object) { count++ })
+		return count
+	},
+	"get_current_scene": func(self *object) *object { return tree.current },
+	"set_current_scene": func(self *object, scene *object) {
+		if scene != nil && scene.node.parent != tree.root {
+			reportError("The new current scene must be a child of the root.")
+			return
+		}
+		tree.current = scene
+	},
+	"has_group": func(self *object, group name) bool { return len(nodesInGroup(string(group))) > 0 },
+	"get_nodes_in_group": func(self *object, group name) *array {
+		nodes := &array{typed: gd.TypeObject, class: "Node"}
+		for _, n := range nodesInGroup(string(group)) {
+			nodes.elems = append(nodes.elems, n)
+		}
+		return nodes
+	},
+	"get_first_node_in_group": func(self *object, group name) *object {
+		if nodes := nodesInGroup(string(group)); len(nodes) > 0 {
+			return nodes[0]
+		}
+		return nil
+	},
+	"get_node_count_in_group": func(self *object, group name) int64 {
+		return int64(len(nodesInGroup(string(group))))
+	},
+	"call_group": func(self *object, group name, method name, args ...any) {
+		for _, n := range nodesInGroup(string(group)) {
+			n.call(string(method), args)
+		}
+	},
+	"set_group": func(self *object, group name, property str, value any) {
+		for _, n := range nodesInGroup(string(group)) {
+			n.set(string(property), value)
+		}
+	},
+	"queue_delete": func(self *object, obj *object) {
+		deferCall(func() {
+			if !obj.freed {
+				obj.free()
+			}
+		})
+	},
+}
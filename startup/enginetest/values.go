@@ -0,0 +1,674 @@
+package enginetest
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	gd "graphics.gd/internal"
+	"graphics.gd/internal/stringify"
+)
+
+// The fake engine represents each engine value with a Go value of one of the following types, these
+// are stored in the heap and referred to by handle, wherever the engine would use a pointer.
+type (
+	str  string // String
+	name string // StringName
+	path string // NodePath
+
+	array struct {
+		elems    []any
+		typed    gd.VariantType
+		class    string
+		readonly bool
+	}
+	dictionary struct {
+		keys, vals []any
+		readonly   bool
+	}
+	packed[T any] struct{ elems []T }
+
+	callable struct {
+		fn     func(...gd.Variant) (gd.Variant, error) // custom callables, created by Go.
+		object *object                                 // standard callables, created from a method name.
+		method string
+		bound  []any
+	}
+	signal struct {
+		object *object
+		name   string
+	}
+)
+
+// heap of engine values, each handle is unique and is never zero, so that the zero value of each
+// engine type remains the null value.
+var heap struct {
+	sync.Mutex
+	next   uint64
+	values map[uint64]any
+	names  map[string]uint64
+}
+
+func alloc(value any) uint64 {
+	heap.Lock()
+	defer heap.Unlock()
+	if heap.values == nil {
+		heap.values = make(map[uint64]any)
+	}
+	heap.next++
+	heap.values[heap.next] = value
+	return heap.next
+}
+
+// intern returns the handle for the given StringName, StringNames are never freed, so that they can be
+// compared by handle, as the engine does.
+func intern(s string) uint64 {
+	heap.Lock()
+	handle, ok := heap.names[s]
+	heap.Unlock()
+	if ok {
+		return handle
+	}
+	handle = alloc(name(s))
+	heap.Lock()
+	defer heap.Unlock()
+	if heap.names == nil {
+		heap.names = make(map[string]uint64)
+	}
+	if existing, ok := heap.names[s]; ok {
+		return existing
+	}
+	heap.names[s] = handle
+	return handle
+}
+
+func load(handle uint64) any {
+	heap.Lock()
+	defer heap.Unlock()
+	return heap.values[handle]
+}
+
+func store(handle uint64, value any) {
+	heap.Lock()
+	defer heap.Unlock()
+	heap.values[handle] = value
+}
+
+func free(handle uint64) {
+	heap.Lock()
+	defer heap.Unlock()
+	if _, ok := heap.values[handle].(name); ok {
+		return
+	}
+	delete(heap.values, handle)
+}
+
+// live returns the number of values in the heap, excluding StringNames.
+func live() int {
+	heap.Lock()
+	defer heap.Unlock()
+	return len(heap.values) - len(heap.names)
+}
+
+func newArray(elems ...any) *array { return &array{elems: elems} }
+
+func (a *array) assign(i int, value any) {
+	if a.typed != gd.TypeNil {
+		value = convert(value, a.typed)
+	}
+	a.elems[i] = value
+}
+
+func (d *dictionary) find(key any) int {
+	for i, k := range d.keys {
+		if equal(k, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *dictionary) get(key any) (any, bool) {
+	if i := d.find(key); i >= 0 {
+		return d.vals[i], true
+	}
+	return nil, false
+}
+
+func (d *dictionary) set(key, value any) {
+	if i := d.find(key); i >= 0 {
+		d.vals[i] = value
+		return
+	}
+	d.keys = append(d.keys, key)
+	d.vals = append(d.vals, value)
+}
+
+func (d *dictionary) erase(key any) bool {
+	i := d.find(key)
+	if i < 0 {
+		return false
+	}
+	d.keys = slices.Delete(d.keys, i, i+1)
+	d.vals = slices.Delete(d.vals, i, i+1)
+	return true
+}
+
+// clone returns a copy of a value with value semantics, such that any later changes to either value
+// are not visible in the other (arrays, dictionaries and objects are shared by reference).
+func clone(value any) any {
+	switch v := value.(type) {
+	case *packed[byte]:
+		return &packed[byte]{slices.Clone(v.elems)}
+	case *packed[int32]:
+		return &packed[int32]{slices.Clone(v.elems)}
+	case *packed[int64]:
+		return &packed[int64]{slices.Clone(v.elems)}
+	case *packed[float32]:
+		return &packed[float32]{slices.Clone(v.elems)}
+	case *packed[float64]:
+		return &packed[float64]{slices.Clone(v.elems)}
+	case *packed[str]:
+		return &packed[str]{slices.Clone(v.elems)}
+	case *packed[gd.Vector2]:
+		return &packed[gd.Vector2]{slices.Clone(v.elems)}
+	case *packed[gd.Vector3]:
+		return &packed[gd.Vector3]{slices.Clone(v.elems)}
+	case *packed[gd.Vector4]:
+		return &packed[gd.Vector4]{slices.Clone(v.elems)}
+	case *packed[gd.Color]:
+		return &packed[gd.Color]{slices.Clone(v.elems)}
+	case *callable:
+		copied := *v
+		copied.bound = slices.Clone(v.bound)
+		return &copied
+	default:
+		return value
+	}
+}
+
+// duplicate implements the engine's duplicate method for arrays and dictionaries.
+func duplicate(value any, deep bool) any {
+	switch v := value.(type) {
+	case *array:
+		copied := &array{elems: slices.Clone(v.elems), typed: v.typed, class: v.class}
+		if deep {
+			for i, elem := range copied.elems {
+				copied.elems[i] = duplicate(elem, true)
+			}
+		}
+		return copied
+	case *dictionary:
+		copied := &dictionary{keys: slices.Clone(v.keys), vals: slices.Clone(v.vals)}
+		if deep {
+			for i, elem := range copied.vals {
+				copied.vals[i] = duplicate(elem, true)
+			}
+		}
+		return copied
+	default:
+		return clone(value)
+	}
+}
+
+func typeOf(value any) gd.VariantType {
+	switch v := value.(type) {
+	case nil:
+		return gd.TypeNil
+	case bool:
+		return gd.TypeBool
+	case int64:
+		return gd.TypeInt
+	case float64:
+		return gd.TypeFloat
+	case str:
+		return gd.TypeString
+	case gd.Vector2:
+		return gd.TypeVector2
+	case gd.Vector2i:
+		return gd.TypeVector2i
+	case gd.Rect2:
+		return gd.TypeRect2
+	case gd.Rect2i:
+		return gd.TypeRect2i
+	case gd.Vector3:
+		return gd.TypeVector3
+	case gd.Vector3i:
+		return gd.TypeVector3i
+	case gd.Transform2D:
+		return gd.TypeTransform2D
+	case gd.Vector4:
+		return gd.TypeVector4
+	case gd.Vector4i:
+		return gd.TypeVector4i
+	case gd.Plane:
+		return gd.TypePlane
+	case gd.Quaternion:
+		return gd.TypeQuaternion
+	case gd.AABB:
+		return gd.TypeAABB
+	case gd.Basis:
+		return gd.TypeBasis
+	case gd.Transform3D:
+		return gd.TypeTransform3D
+	case gd.Projection:
+		return gd.TypeProjection
+	case gd.Color:
+		return gd.TypeColor
+	case name:
+		return gd.TypeStringName
+	case path:
+		return gd.TypeNodePath
+	case gd.RID:
+		return gd.TypeRID
+	case *object:
+		if v == nil {
+			return gd.TypeNil
+		}
+		return gd.TypeObject
+	case *callable:
+		return gd.TypeCallable
+	case signal:
+		return gd.TypeSignal
+	case *dictionary:
+		return gd.TypeDictionary
+	case *array:
+		return gd.TypeArray
+	case *packed[byte]:
+		return gd.TypePackedByteArray
+	case *packed[int32]:
+		return gd.TypePackedInt32Array
+	case *packed[int64]:
+		return gd.TypePackedInt64Array
+	case *packed[float32]:
+		return gd.TypePackedFloat32Array
+	case *packed[float64]:
+		return gd.TypePackedFloat64Array
+	case *packed[str]:
+		return gd.TypePackedStringArray
+	case *packed[gd.Vector2]:
+		return gd.TypePackedVector2Array
+	case *packed[gd.Vector3]:
+		return gd.TypePackedVector3Array
+	case *packed[gd.Color]:
+		return gd.TypePackedColorArray
+	case *packed[gd.Vector4]:
+		return gd.TypePackedVector4Array
+	default:
+		panic(fmt.Sprintf("enginetest: unsupported value %T", value))
+	}
+}
+
+// zero returns the default value for the given variant type.
+func zero(vtype gd.VariantType) any {
+	switch vtype {
+	case gd.TypeDictionary:
+		return &dictionary{}
+	case gd.TypeArray:
+		return newArray()
+	case gd.TypeCallable:
+		return &callable{}
+	case gd.TypeObject:
+		return (*object)(nil)
+	}
+	if vtype >= gd.TypePackedByteArray {
+		return clone(reflect.New(valueTypes[vtype].Elem()).Interface())
+	}
+	return reflect.Zero(valueTypes[vtype]).Interface()
+}
+
+// valueTypes maps each variant type to the Go type used to represent it.
+var valueTypes = [gd.TypeMax]reflect.Type{
+	gd.TypeNil:                reflect.TypeFor[any](),
+	gd.TypeBool:               reflect.TypeFor[bool](),
+	gd.TypeInt:                reflect.TypeFor[int64](),
+	gd.TypeFloat:              reflect.TypeFor[float64](),
+	gd.TypeString:             reflect.TypeFor[str](),
+	gd.TypeVector2:            reflect.TypeFor[gd.Vector2](),
+	gd.TypeVector2i:           reflect.TypeFor[gd.Vector2i](),
+	gd.TypeRect2:              reflect.TypeFor[gd.Rect2](),
+	gd.TypeRect2i:             reflect.TypeFor[gd.Rect2i](),
+	gd.TypeVector3:            reflect.TypeFor[gd.Vector3](),
+	gd.TypeVector3i:           reflect.TypeFor[gd.Vector3i](),
+	gd.TypeTransform2D:        reflect.TypeFor[gd.Transform2D](),
+	gd.TypeVector4:            reflect.TypeFor[gd.Vector4](),
+	gd.TypeVector4i:           reflect.TypeFor[gd.Vector4i](),
+	gd.TypePlane:              reflect.TypeFor[gd.Plane](),
+	gd.TypeQuaternion:         reflect.TypeFor[gd.Quaternion](),
+	gd.TypeAABB:               reflect.TypeFor[gd.AABB](),
+	gd.TypeBasis:              reflect.TypeFor[gd.Basis](),
+	gd.TypeTransform3D:        reflect.TypeFor[gd.Transform3D](),
+	gd.TypeProjection:         reflect.TypeFor[gd.Projection](),
+	gd.TypeColor:              reflect.TypeFor[gd.Color](),
+	gd.TypeStringName:         reflect.TypeFor[name](),
+	gd.TypeNodePath:           reflect.TypeFor[path](),
+	gd.TypeRID:                reflect.TypeFor[gd.RID](),
+	gd.TypeObject:             reflect.TypeFor[*object](),
+	gd.TypeCallable:           reflect.TypeFor[*callable](),
+	gd.TypeSignal:             reflect.TypeFor[signal](),
+	gd.TypeDictionary:         reflect.TypeFor[*dictionary](),
+	gd.TypeArray:              reflect.TypeFor[*array](),
+	gd.TypePackedByteArray:    reflect.TypeFor[*packed[byte]](),
+	gd.TypePackedInt32Array:   reflect.TypeFor[*packed[int32]](),
+	gd.TypePackedInt64Array:   reflect.TypeFor[*packed[int64]](),
+	gd.TypePackedFloat32Array: reflect.TypeFor[*packed[float32]](),
+	gd.TypePackedFloat64Array: reflect.TypeFor[*packed[float64]](),
+	gd.TypePackedStringArray:  reflect.TypeFor[*packed[str]](),
+	gd.TypePackedVector2Array: reflect.TypeFor[*packed[gd.Vector2]](),
+	gd.TypePackedVector3Array: reflect.TypeFor[*packed[gd.Vector3]](),
+	gd.TypePackedColorArray:   reflect.TypeFor[*packed[gd.Color]](),
+	gd.TypePackedVector4Array: reflect.TypeFor[*packed[gd.Vector4]](),
+}
+
+// convertible reports whether values of one type are implicitly converted to the other when
+// passed as an argument.
+func convertible(from, to gd.VariantType) bool {
+	numeric := func(vtype gd.VariantType) bool {
+		return vtype == gd.TypeBool || vtype == gd.TypeInt || vtype == gd.TypeFloat
+	}
+	textual := func(vtype gd.VariantType) bool {
+		return vtype == gd.TypeString || vtype == gd.TypeStringName || vtype == gd.TypeNodePath
+	}
+	return from == to || to == gd.TypeNil || numeric(from) && numeric(to) || textual(from) && textual(to)
+}
+
+// convert the value to the given variant type, following the engine's implicit conversions between
+// numbers and between the string types, anything else converts to the zero value.
+func convert(value any, vtype gd.VariantType) any {
+	if vtype == gd.TypeNil || typeOf(value) == vtype {
+		return value
+	}
+	switch vtype {
+	case gd.TypeBool:
+		return truthy(value)
+	case gd.TypeInt:
+		switch v := value.(type) {
+		case bool:
+			if v {
+				return int64(1)
+			}
+			return int64(0)
+		case float64:
+			return int64(v)
+		case str:
+			i, _ := strconv.ParseInt(string(v), 10, 64)
+			return i
+		}
+	case gd.TypeFloat:
+		switch v := value.(type) {
+		case bool:
+			if v {
+				return 1.0
+			}
+			return 0.0
+		case int64:
+			return float64(v)
+		case str:
+			f, _ := strconv.ParseFloat(string(v), 64)
+			return f
+		}
+	case gd.TypeString:
+		return str(stringOf(value))
+	case gd.TypeStringName:
+		switch v := value.(type) {
+		case str:
+			return name(v)
+		}
+	case gd.TypeNodePath:
+		switch v := value.(type) {
+		case str:
+			return path(v)
+		case name:
+			return path(v)
+		}
+	}
+	return zero(vtype)
+}
+
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	case str:
+		return v != ""
+	case name:
+		return v != ""
+	case path:
+		return v != ""
+	case *object:
+		return v != nil && !v.freed
+	case *array:
+		return len(v.elems) > 0
+	case *dictionary:
+		return len(v.keys) > 0
+	case *callable:
+		return v.fn != nil || v.object != nil
+	case signal:
+		return v.object != nil
+	default:
+		return !reflect.ValueOf(value).IsZero()
+	}
+}
+
+// equal implements the engine's == operator.
+func equal(a, b any) bool {
+	switch x := a.(type) {
+	case int64:
+		switch y := b.(type) {
+		case int64:
+			return x == y
+		case float64:
+			return float64(x) == y
+		}
+		return false
+	case float64:
+		switch y := b.(type) {
+		case int64:
+			return x == float64(y)
+		case float64:
+			return x == y
+		}
+		return false
+	case str:
+		switch y := b.(type) {
+		case str:
+			return x == y
+		case name:
+			return string(x) == string(y)
+		}
+		return false
+	case name:
+		switch y := b.(type) {
+		case str:
+			return string(x) == string(y)
+		case name:
+			return x == y
+		}
+		return false
+	case *array:
+		y, ok := b.(*array)
+		if !ok || len(x.elems) != len(y.elems) {
+			return false
+		}
+		for i := range x.elems {
+			if !equal(x.elems[i], y.elems[i]) {
+				return false
+			}
+		}
+		return true
+	case *dictionary:
+		y, ok := b.(*dictionary)
+		if !ok || len(x.keys) != len(y.keys) {
+			return false
+		}
+		for i, key := range x.keys {
+			if value, ok := y.get(key); !ok || !equal(x.vals[i], value) {
+				return false
+			}
+		}
+		return true
+	case *callable:
+		y, ok := b.(*callable)
+		if !ok {
+			return false
+		}
+		if x.fn != nil || y.fn != nil {
+			return x == y
+		}
+		return x.object == y.object && x.method == y.method
+	}
+	if typeOf(a) != typeOf(b) {
+		return false
+	}
+	if typeOf(a) >= gd.TypePackedByteArray {
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
+
+// stringOf returns the value formatted in the same way as the engine's str.
+func stringOf(value any) string {
+	var b strings.Builder
+	appendString(&b, value, false)
+	return b.String()
+}
+
+func appendString(b *strings.Builder, value any, nested bool) {
+	switch v := value.(type) {
+	case nil:
+		b.WriteString("<null>")
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case int64:
+		b.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		switch {
+		case math.IsInf(v, 1):
+			b.WriteString("inf")
+		case math.IsInf(v, -1):
+			b.WriteString("-inf")
+		case math.IsNaN(v):
+			b.WriteString("nan")
+		default:
+			b.Write(stringify.AppendReal(nil, v, 64))
+		}
+	case str:
+		if nested {
+			b.WriteString(strconv.Quote(string(v)))
+		} else {
+			b.WriteString(string(v))
+		}
+	case name:
+		if nested {
+			b.WriteString("&" + strconv.Quote(string(v)))
+		} else {
+			b.WriteString(string(v))
+		}
+	case path:
+		if nested {
+			b.WriteString("^" + strconv.Quote(string(v)))
+		} else {
+			b.WriteString(string(v))
+		}
+	case gd.RID:
+		fmt.Fprintf(b, "RID(%d)", uint64(v))
+	case *object:
+		if v == nil || v.freed {
+			b.WriteString("<Object#null>")
+			return
+		}
+		if v.instance != nil {
+			if s, ok := v.instance.ToString(); ok {
+				b.WriteString(s.String())
+				return
+			}
+		}
+		if v.node != nil && v.node.name != "" {
+			fmt.Fprintf(b, "%s:<%s#%d>", v.node.name, v.class.name, v.id)
+			return
+		}
+		fmt.Fprintf(b, "<%s#%d>", v.class.name, v.id)
+	case *callable:
+		switch {
+		case v.object != nil:
+			fmt.Fprintf(b, "%s::%s", stringOf(v.object), v.method)
+		case v.fn != nil:
+			b.WriteString("<Go callable>")
+		default:
+			b.WriteString("null::null")
+		}
+	case signal:
+		if v.object == nil {
+			b.WriteString("null::null")
+			return
+		}
+		fmt.Fprintf(b, "%s::[signal]%s", stringOf(v.object), v.name)
+	case *array:
+		b.WriteByte('[')
+		for i, elem := range v.elems {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			appendString(b, elem, true)
+		}
+		b.WriteByte(']')
+	case *dictionary:
+		if len(v.keys) == 0 {
+			b.WriteString("{  }")
+			return
+		}
+		b.WriteString("{ ")
+		for i, key := range v.keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			appendString(b, key, true)
+			b.WriteString(": ")
+			appendString(b, v.vals[i], true)
+		}
+		b.WriteString(" }")
+	default:
+		if s, ok := stringify.Builtin(value); ok {
+			b.WriteString(s)
+			return
+		}
+		rvalue := reflect.ValueOf(value)
+		if rvalue.Kind() == reflect.Pointer && rvalue.Elem().Kind() == reflect.Struct {
+			elems := rvalue.Elem().Field(0)
+			b.WriteByte('[')
+			for i := range elems.Len() {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				appendString(b, normalize(elems.Index(i).Interface()), true)
+			}
+			b.WriteByte(']')
+			return
+		}
+		fmt.Fprint(b, value)
+	}
+}
+
+// normalize converts packed array elements into their equivalent variant value.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case byte:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	default:
+		return value
+	}
+}
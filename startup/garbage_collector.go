@@ -1,8 +1,17 @@
 package startup
 
 import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+
 	"graphics.gd/classdb"
 	NodeClass "graphics.gd/classdb/Node"
+	"graphics.gd/classdb/OS"
+	"graphics.gd/classdb/Performance"
 	SceneTreeClass "graphics.gd/classdb/SceneTree"
 	gd "graphics.gd/internal"
 	"graphics.gd/internal/pointers"
@@ -23,10 +32,162 @@ func (goRuntime) Ready() {
 func (gr goRuntime) AsNode() NodeClass.Instance { return gr.Super().AsNode() }
 
 func (goRuntime) Process(delta Float.X) {
-	gd.NewCallable(func() {
+	quit()
+	gd.NewCallable(cycle).CallDeferred()
+}
+
+// frame records what the Go runtime did during the last frame, so that it can be
+// reported by the Performance monitors and checked against the [GCPacing] budget, it
+// is only updated when monitoring or pacing.
+var frame struct {
+	measuring bool
+	samples   []metrics.Sample
+	cycles    uint64        // number of completed GC cycles.
+	pauses    uint64        // total time the GC has paused the program for, in nanoseconds.
+	pause     time.Duration // GC pause time during the last frame.
+	deferred  int           // calls made by the last callable cycle.
+	elapsed   time.Duration // time spent in the last callable and pointer cycle.
+	overruns  int           // number of frames that exceeded the [GCPacing] budget.
+}
+
+// pacing is the current [GCPacing] configuration, nil if garbage collection is not frame-paced.
+var pacing atomic.Pointer[GCPacing]
+
+// collecting is true while a frame-paced garbage collection is running.
+var collecting atomic.Bool
+
+// GCPacing configures frame-paced garbage collection, see [PaceGC].
+type GCPacing struct {
+	// MemoryLimit is a soft limit on the total memory used by Go, in bytes, see [debug.SetMemoryLimit].
+	// Zero leaves the limit unchanged.
+	MemoryLimit int64
+
+	// Percent is the garbage collection target percentage, see [debug.SetGCPercent]. Zero leaves the
+	// percentage unchanged, a negative value turns off automatic collections, such that Go only
+	// collects garbage at the end of a frame, or when the MemoryLimit is reached.
+	Percent int
+
+	// Budget is the time that Go may spend at the end of each frame, including any garbage collection
+	// pauses during the frame. Zero means there is no budget.
+	Budget time.Duration
+
+	// Exceeded is called at the end of each frame that exceeded the Budget, with the time that was
+	// spent. If nil, a warning is pushed to the engine instead.
+	Exceeded func(spent time.Duration)
+}
+
+// PaceGC opts into frame-paced garbage collection. Go starts its garbage collections at the end
+// of a frame, once the heap nears its goal, such that the collection overlaps with rendering and
+// idle time, instead of starting at an arbitrary point within the next frame.
+//
+// Go has no way to collect garbage incrementally in slices of idle time, so this starts a complete
+// collection with [runtime.GC] on a separate goroutine. The collection is concurrent, it only stops
+// the world for its short pauses, but if it takes longer than the idle time, the marking continues
+// alongside the next frame and may slow down goroutines that allocate during it.
+func PaceGC(gc GCPacing) {
+	if gc.MemoryLimit > 0 {
+		debug.SetMemoryLimit(gc.MemoryLimit)
+	}
+	if gc.Percent != 0 {
+		debug.SetGCPercent(gc.Percent)
+	}
+	pacing.Store(&gc)
+}
+
+// measure starts recording the [frame] statistics.
+func measure() {
+	if frame.measuring {
+		return
+	}
+	frame.samples = []metrics.Sample{
+		{Name: "/gc/cycles/total:gc-cycles"},
+		{Name: "/memory/classes/heap/objects:bytes"},
+		{Name: "/sched/goroutines:goroutines"},
+		{Name: "/gc/heap/goal:bytes"},
+		{Name: "/gc/heap/live:bytes"},
+	}
+	metrics.Read(frame.samples)
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	frame.cycles, frame.pauses = frame.samples[0].Value.Uint64(), stats.PauseTotalNs
+	frame.measuring = true
+}
+
+// cycle calls the deferred functions and frees expired pointers, it is called at the end of each frame.
+func cycle() {
+	paced := pacing.Load()
+	if paced != nil {
+		measure()
+	}
+	if !frame.measuring {
 		Callable.Cycle()
 		pointers.Cycle()
-	}).CallDeferred()
+		return
+	}
+	start := time.Now()
+	frame.deferred = Callable.Queued()
+	Callable.Cycle()
+	pointers.Cycle()
+	frame.elapsed = time.Since(start)
+	metrics.Read(frame.samples)
+	frame.pause = 0
+	if cycles := frame.samples[0].Value.Uint64(); cycles != frame.cycles {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats) // stops the world, so only read after a GC.
+		frame.pause = time.Duration(stats.PauseTotalNs - frame.pauses)
+		frame.cycles, frame.pauses = cycles, stats.PauseTotalNs
+	}
+	if paced != nil {
+		pace(paced)
+	}
+}
+
+// pace checks the frame against the budget and then starts a garbage collection in the
+// background if the heap is close to its goal.
+func pace(gc *GCPacing) {
+	if spent := frame.elapsed + frame.pause; gc.Budget > 0 && spent > gc.Budget {
+		frame.overruns++
+		if gc.Exceeded != nil {
+			gc.Exceeded(spent)
+		} else {
+			gd.PushWarning(gd.NewVariant(fmt.Sprintf("Go exceeded its frame budget of %v by %v (%v in garbage collection pauses)",
+				gc.Budget, spent-gc.Budget, frame.pause)))
+		}
+	}
+	heap, goal, live := frame.samples[1].Value.Uint64(), frame.samples[3].Value.Uint64(), frame.samples[4].Value.Uint64()
+	if gc.Percent < 0 && gc.MemoryLimit <= 0 {
+		goal = 2 * live // no goal, so collect at the same point as the default GC percent.
+	}
+	if heap >= goal/4*3 && collecting.CompareAndSwap(false, true) {
+		// runtime.GC blocks until the whole cycle is complete, so it must not be called from
+		// the main thread.
+		go func() {
+			defer collecting.Store(false)
+			runtime.GC()
+		}()
+	}
+}
+
+// monitor the Go runtime with Performance custom monitors, so that they show up in the
+// editor's Monitor tab, alongside the engine's own monitors.
+func monitor() {
+	measure()
+	for _, monitor := range []struct {
+		id string
+		fn any
+	}{
+		{"Go/Heap (MiB)", func() float64 { return float64(frame.samples[1].Value.Uint64()) / (1 << 20) }},
+		{"Go/GC Pause (ms)", func() float64 { return float64(frame.pause) / float64(time.Millisecond) }},
+		{"Go/Goroutines", func() int64 { return int64(frame.samples[2].Value.Uint64()) }},
+		{"Go/Live Pointers", func() int { return pointers.Count().Live }},
+		{"Go/Pinned Pointers", func() int { return pointers.Count().Pinned }},
+		{"Go/Static Pointers", func() int { return pointers.Count().Static }},
+		{"Go/Deferred Calls", func() int { return frame.deferred }},
+		{"Go/Cycle Time (ms)", func() float64 { return float64(frame.elapsed) / float64(time.Millisecond) }},
+		{"Go/Budget Overruns", func() int { return frame.overruns }},
+	} {
+		Performance.AddCustomMonitor(monitor.id, Callable.New(monitor.fn))
+	}
 }
 
 func init() {
@@ -35,7 +196,13 @@ func init() {
 	})
 	gd.PostStartupFunctions = append(gd.PostStartupFunctions, func() {
 		gd.NewCallable(func() {
-			SceneTreeClass.Add(new(goRuntime))
+			node := new(goRuntime)
+			// so that quit requests are handled while the SceneTree is paused.
+			node.Super().SetProcessMode(NodeClass.ProcessModeAlways)
+			SceneTreeClass.Add(node)
 		}).CallDeferred()
+		if OS.IsDebugBuild() || pointers.Debugging {
+			monitor()
+		}
 	})
 }
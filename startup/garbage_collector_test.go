@@ -0,0 +1,105 @@
+package startup_test
+
+import (
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Performance"
+	"graphics.gd/startup"
+	"graphics.gd/startup/enginetest"
+	"graphics.gd/variant/Callable"
+	"graphics.gd/variant/String"
+)
+
+func TestMain(m *testing.M) {
+	classdb.Register[stopper]()
+	enginetest.Main(m)
+}
+
+func TestPerformanceMonitors(t *testing.T) {
+	if goroutines := Performance.Advanced().GetCustomMonitor(String.Name(String.New("Go/Goroutines"))).Int(); goroutines < 1 {
+		t.Fatalf("expected the Go runtime to be monitored in debug builds, got %v goroutines", goroutines)
+	}
+	frames := 0
+	Performance.AddCustomMonitor("Test/Frames", Callable.New(func() int { frames++; return frames }))
+	defer Performance.RemoveCustomMonitor("Test/Frames")
+	if !Performance.HasCustomMonitor("Test/Frames") {
+		t.Fatal("expected the monitor to be added")
+	}
+	if value := Performance.Advanced().GetCustomMonitor(String.Name(String.New("Test/Frames"))).Int(); value != 1 {
+		t.Fatalf("expected 1, got %v", value)
+	}
+	if names := Performance.GetCustomMonitorNames(); !slices.Contains(names, "Go/Heap (MiB)") || names[len(names)-1] != "Test/Frames" {
+		t.Fatalf("unexpected monitor names %v", names)
+	}
+}
+
+// paceGC paces garbage collection for the duration of the test.
+func paceGC(t *testing.T, gc startup.GCPacing) {
+	percent := debug.SetGCPercent(100)
+	debug.SetGCPercent(percent)
+	startup.PaceGC(gc)
+	t.Cleanup(func() { startup.PaceGC(startup.GCPacing{Percent: percent}) })
+}
+
+func TestPaceGCBudget(t *testing.T) {
+	var overruns []time.Duration
+	paceGC(t, startup.GCPacing{Budget: time.Nanosecond, Exceeded: func(spent time.Duration) {
+		overruns = append(overruns, spent)
+	}})
+	for range 3 {
+		enginetest.Step(0)
+	}
+	// the end of frame cycle is deferred, so the last frame may only be checked during the next one.
+	if len(overruns) < 2 || overruns[0] <= time.Nanosecond {
+		t.Fatalf("expected every frame to exceed a budget of 1ns, got %v", overruns)
+	}
+	if overruns := Performance.Advanced().GetCustomMonitor(String.Name(String.New("Go/Budget Overruns"))).Int(); overruns < 2 {
+		t.Fatalf("expected the overruns to be monitored, got %v", overruns)
+	}
+}
+
+func TestPaceGCWarning(t *testing.T) {
+	output := enginetest.Capture(t)
+	paceGC(t, startup.GCPacing{Budget: time.Nanosecond})
+	enginetest.Step(0)
+	if !strings.Contains(output.String(), "WARNING: Go exceeded its frame budget of 1ns") {
+		t.Fatalf("expected a warning, got %q", output)
+	}
+	output.Reset()
+	paceGC(t, startup.GCPacing{Budget: time.Hour})
+	enginetest.Step(0)
+	if output.Len() != 0 {
+		t.Fatalf("expected no warning within the budget, got %q", output)
+	}
+}
+
+// garbage keeps the allocations of TestPaceGCTrigger from being optimized away.
+var garbage []byte
+
+func TestPaceGCTrigger(t *testing.T) {
+	paceGC(t, startup.GCPacing{Percent: -1})
+	runtime.GC()
+	var stats debug.GCStats
+	debug.ReadGCStats(&stats)
+	collections := stats.NumGC
+	enginetest.Step(0)
+	if debug.ReadGCStats(&stats); stats.NumGC != collections {
+		t.Fatal("expected no collection while the heap is far from its goal")
+	}
+	for range 64 {
+		garbage = make([]byte, 1<<20)
+	}
+	enginetest.Step(0)
+	for start := time.Now(); stats.NumGC == collections; debug.ReadGCStats(&stats) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("expected a collection once the heap neared its goal")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -433,6 +433,7 @@ import "C"
 import (
 	"errors"
 	"iter"
+	"os"
 	"runtime"
 	"runtime/cgo"
 	"unsafe"
@@ -482,6 +483,9 @@ func deinitialize(_ unsafe.Pointer, level initializationLevel) {
 		}
 		pointers.Cycle()
 		pointers.Cycle()
+		if pointers.Debugging {
+			pointers.Dump(os.Stderr)
+		}
 		if theMainFunctionIsWaitingForTheEngineToShutDown {
 			resume_main()
 		}
@@ -2542,6 +2546,24 @@ func get_rid_func(p_instance uintptr) C.uint64_t {
 	return C.uint64_t(cgo.Handle(p_instance).Value().(gd.ObjectInterface).GetRID())
 }
 
+// callError reports err to the engine, a [gd.CallError] is passed through as-is, any other error is
+// pushed to the debugger and the call is treated as successful, such that the engine reports it once.
+func callError(issue *C.GDExtensionCallError, err error) {
+	var call *gd.CallError
+	if !errors.As(err, &call) {
+		var value gd.CallError
+		if !errors.As(err, &value) {
+			gd.PushError(gd.NewVariant(err.Error()))
+			*issue = C.GDExtensionCallError{}
+			return
+		}
+		call = &value
+	}
+	issue.error = C.GDExtensionCallErrorType(call.ErrorType)
+	issue.argument = C.int32_t(call.Argument)
+	issue.expected = C.int32_t(call.Expected)
+}
+
 //export callable_call
 func callable_call(p_callable uintptr, p_args unsafe.Pointer, count C.GDExtensionInt, p_ret unsafe.Pointer, issue *C.GDExtensionCallError) {
 	fn := cgo.Handle(p_callable).Value().(func(...gd.Variant) (gd.Variant, error))
@@ -2554,7 +2576,7 @@ func callable_call(p_callable uintptr, p_args unsafe.Pointer, count C.GDExtensio
 	}
 	ret, err := fn(args...)
 	if err != nil {
-		issue.error = 7 // TODO no generic error>
+		callError(issue, err)
 		return
 	}
 	*(*[3]uint64)(p_ret) = pointers.Get(ret)
@@ -2570,7 +2592,7 @@ func method_call(p_method uintptr, p_instance uintptr, p_args unsafe.Pointer, co
 	}
 	result, err := method.Call(cgo.Handle(p_instance).Value(), variants...)
 	if err != nil {
-		issue.error = 7 // TODO no generic error>
+		callError(issue, err)
 		return
 	}
 	if result != (gd.Variant{}) {
@@ -0,0 +1,203 @@
+package startup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	EngineClass "graphics.gd/classdb/Engine"
+	MainLoopClass "graphics.gd/classdb/MainLoop"
+	"graphics.gd/classdb/SceneTree"
+	gd "graphics.gd/internal"
+	"graphics.gd/variant/Object"
+)
+
+// Options for [Run].
+type Options struct {
+	// MainLoop replaces the SceneTree as the engine's main loop, when non-nil.
+	MainLoop MainLoopClass.Interface
+
+	// Signals that request the engine to quit, see [signal.Notify]. When nil, the engine quits
+	// on [os.Interrupt] and [syscall.SIGTERM].
+	Signals []os.Signal
+}
+
+// ErrNotLoaded is returned by [Run] when the program was started directly, instead of being loaded
+// by the engine as a GDExtension, such that the engine can never start.
+var ErrNotLoaded = errors.New("startup: the program was not loaded by the engine, run it with the gd command")
+
+// ErrNotStarted is returned by [Run] when the engine shut down without starting the main loop,
+// such as when it failed to initialize, or the project's run/main_loop_type is not GoMainLoop.
+var ErrNotStarted = errors.New("startup: the engine shut down before starting the main loop")
+
+// ErrRunning is returned by [Run] when it is called while the engine is already running.
+var ErrRunning = errors.New("startup: the engine is already running")
+
+// ErrShutDown is returned by [Run] when it is called after the engine has shut down, the engine
+// can only be run once, unless it is hosted inside of the Go program, as it is in tests.
+var ErrShutDown = errors.New("startup: the engine has shut down and cannot be run again")
+
+// ExitError is returned by [Run] when the engine quit because of an OS signal, or with a non-zero
+// exit code passed to [Exit]. The engine does not expose the exit code passed to SceneTree.quit,
+// so quit with [Exit] for the code to be reported.
+type ExitError struct {
+	Code   int
+	Signal os.Signal // nil, unless the engine quit because of a signal.
+}
+
+func (err *ExitError) Error() string {
+	if err.Signal != nil {
+		return fmt.Sprintf("startup: engine quit on %v (exit code %d)", err.Signal, err.Code)
+	}
+	return fmt.Sprintf("startup: engine quit with exit code %d", err.Code)
+}
+
+// standalone reports whether the program was started directly, rather than by the engine.
+var standalone = func() bool { return false }
+
+// exit records a request for the engine to quit, it is handled on the main thread at the end of
+// the next frame.
+var exit struct {
+	sync.Mutex
+	running   bool
+	ran       bool // Run has returned.
+	started   bool // the main loop was initialized.
+	requested bool
+	handled   bool
+	code      int
+	signal    os.Signal
+	cause     error
+}
+
+// wake receives a value whenever the engine is requested to quit, so that
+// the main thread can stop waiting on Go.
+var wake = make(chan struct{}, 1)
+
+// Exit requests the engine to quit at the end of the current frame, with the given exit code.
+func Exit(code int) { request(code, nil, nil) }
+
+func request(code int, sig os.Signal, cause error) {
+	exit.Lock()
+	defer exit.Unlock()
+	if exit.requested {
+		return
+	}
+	exit.requested, exit.code, exit.signal, exit.cause = true, code, sig, cause
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+}
+
+// exiting returns the requested exit code and true, the first time it is called after a quit
+// request.
+func exiting() (int, bool) {
+	exit.Lock()
+	defer exit.Unlock()
+	if !exit.requested || exit.handled {
+		return 0, false
+	}
+	exit.handled = true
+	return exit.code, true
+}
+
+// started records that the engine initialized the main loop.
+func started() {
+	exit.Lock()
+	defer exit.Unlock()
+	exit.started = true
+}
+
+// quit the SceneTree, if there is a pending quit request.
+func quit() {
+	code, ok := exiting()
+	if !ok {
+		return
+	}
+	if tree, ok := Object.Is[SceneTree.Instance](MainLoopClass.Instance(EngineClass.GetMainLoop())); ok {
+		SceneTree.Advanced(tree).Quit(int64(code))
+	}
+}
+
+// Run starts the engine and blocks until it shuts down, either because the game quit, [Exit] was
+// called, one of the [Options.Signals] was received or the context was cancelled. The engine can
+// only be run once, except when it is hosted inside of the Go program, such as by enginetest.
+//
+//	func main() {
+//		if err := startup.Run(context.Background(), startup.Options{}); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func Run(ctx context.Context, opts Options) error {
+	if standalone() {
+		return ErrNotLoaded
+	}
+	exit.Lock()
+	if exit.running {
+		exit.Unlock()
+		return ErrRunning
+	}
+	if exit.ran && gd.HostedMainLoop == nil {
+		exit.Unlock()
+		return ErrShutDown
+	}
+	exit.running, exit.started, exit.requested, exit.handled = true, false, false, false
+	exit.code, exit.signal, exit.cause = 0, nil, nil
+	select {
+	case <-wake:
+	default:
+	}
+	exit.Unlock()
+	defer func() {
+		exit.Lock()
+		exit.running, exit.ran = false, true
+		exit.Unlock()
+	}()
+	signals := opts.Signals
+	if signals == nil {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	received := make(chan os.Signal, 1)
+	if len(signals) > 0 {
+		signal.Notify(received, signals...)
+		defer signal.Stop(received)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case sig := <-received:
+			code := 1
+			if number, ok := sig.(syscall.Signal); ok {
+				code = 128 + int(number)
+			}
+			request(code, sig, nil)
+		case <-ctx.Done():
+			request(0, nil, context.Cause(ctx))
+		case <-done:
+		}
+	}()
+	if opts.MainLoop != nil {
+		MainLoop(opts.MainLoop)
+	} else {
+		loadingSceneWasCalled = false
+		Scene()
+	}
+	exit.Lock()
+	defer exit.Unlock()
+	switch {
+	case !exit.started:
+		return ErrNotStarted
+	case !exit.requested:
+		return nil
+	case exit.cause != nil:
+		return exit.cause
+	case exit.signal != nil || exit.code != 0:
+		return &ExitError{Code: exit.code, Signal: exit.signal}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package startup_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"graphics.gd/classdb"
+	"graphics.gd/classdb/Node"
+	"graphics.gd/classdb/SceneTree"
+	"graphics.gd/startup"
+	"graphics.gd/startup/enginetest"
+	"graphics.gd/variant/Float"
+)
+
+// stopper stops the engine after a number of frames, with stop.
+type stopper struct {
+	classdb.Extension[stopper, Node.Instance]
+
+	frames int
+	stop   func()
+
+	started time.Time
+	expired bool
+}
+
+func (s *stopper) Process(delta Float.X) {
+	if s.started.IsZero() {
+		s.started = time.Now()
+	}
+	if s.frames--; s.frames == 0 {
+		s.stop()
+	}
+	if time.Since(s.started) > 5*time.Second && !s.expired {
+		s.expired = true
+		SceneTree.Instance(s.Super().GetTree()).Quit() // so that a failing test does not hang.
+	}
+}
+
+// stopAfter adds a stopper to the scene tree, for the duration of the test.
+func stopAfter(t *testing.T, frames int, stop func()) *stopper {
+	s := &stopper{frames: frames, stop: stop}
+	enginetest.Root().AddChild(s.Super())
+	t.Cleanup(func() {
+		if s.expired {
+			t.Error("the engine did not stop")
+		}
+		s.Super().QueueFree()
+		enginetest.Step(0)
+	})
+	return s
+}
+
+func TestRun(t *testing.T) {
+	for range 3 {
+		ctx, cancel := context.WithCancel(context.Background())
+		stopAfter(t, 2, cancel)
+		if err := startup.Run(ctx, startup.Options{}); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the context's error, got %v", err)
+		}
+	}
+	stopAfter(t, 2, func() { startup.Exit(0) })
+	if err := startup.Run(context.Background(), startup.Options{}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	stopAfter(t, 2, func() { startup.Exit(3) })
+	var exit *startup.ExitError
+	if err := startup.Run(context.Background(), startup.Options{}); !errors.As(err, &exit) || exit.Code != 3 {
+		t.Fatalf("expected exit code 3, got %v", err)
+	}
+}
+
+func TestRunPaused(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := stopAfter(t, 2, cancel)
+	s.Super().SetProcessMode(Node.ProcessModeAlways)
+	SceneTree.Instance(enginetest.Root().GetTree()).SetPaused(true)
+	defer func() { SceneTree.Instance(enginetest.Root().GetTree()).SetPaused(false) }()
+	if err := startup.Run(ctx, startup.Options{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the context to stop a paused game, got %v", err)
+	}
+}
+
+// counter is a main loop that exits after a number of frames.
+type counter struct {
+	frames, physics int
+	finalized       bool
+}
+
+func (c *counter) Initialize() {}
+
+func (c *counter) PhysicsProcess(delta Float.X) bool {
+	c.physics++
+	return false
+}
+
+func (c *counter) Process(delta Float.X) bool {
+	if c.frames--; c.frames == 0 {
+		startup.Exit(2)
+	}
+	return false
+}
+
+func (c *counter) Finalize() { c.finalized = true }
+
+func TestRunMainLoop(t *testing.T) {
+	for range 2 {
+		loop := &counter{frames: 3}
+		var exit *startup.ExitError
+		if err := startup.Run(context.Background(), startup.Options{MainLoop: loop}); !errors.As(err, &exit) || exit.Code != 2 {
+			t.Fatalf("expected exit code 2, got %v", err)
+		}
+		if loop.physics != 4 || !loop.finalized {
+			t.Fatalf("expected 4 physics frames and the main loop to be finalized, got %d", loop.physics)
+		}
+	}
+}
+
+func TestExitError(t *testing.T) {
+	var err error = &startup.ExitError{Code: 130, Signal: os.Interrupt}
+	var exit *startup.ExitError
+	if !errors.As(err, &exit) || exit.Code != 130 {
+		t.Fatal("expected an ExitError with code 130")
+	}
+	if err.Error() != "startup: engine quit on interrupt (exit code 130)" {
+		t.Fatal(err.Error())
+	}
+}
@@ -0,0 +1,154 @@
+package startup
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"graphics.gd/classdb/DisplayServer"
+	EngineClass "graphics.gd/classdb/Engine"
+	"graphics.gd/variant/Float"
+)
+
+// ErrStopped is returned by [Server.Step] when the server is not running.
+var ErrStopped = errors.New("startup: the server is not running")
+
+// Server runs the engine as a dedicated server, with a fixed-timestep main loop that only ticks
+// the simulation, there is no SceneTree, no rendering and no window. The engine should be started
+// with the --headless flag, which 'gd run -target server' does. As the server replaces the
+// SceneTree as the engine's main loop, nodes are never processed, so the simulation must be
+// advanced entirely by Tick.
+//
+//	func main() {
+//		server := startup.Server{TicksPerSecond: 30, Tick: world.Tick}
+//		if err := server.Run(context.Background()); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+type Server struct {
+	// TicksPerSecond is the fixed rate of the simulation, defaults to 60.
+	TicksPerSecond int
+
+	// Tick advances the simulation by one fixed timestep of 1/TicksPerSecond seconds.
+	Tick func(delta Float.X)
+
+	// Manual stops the engine from ticking the simulation in real time, instead, it is only
+	// ticked when [Server.Step] is called, as fast as the caller requests, which is useful for
+	// deterministic simulation and for integration tests. The engine advances one frame for
+	// each tick, so that deferred calls are made in between ticks.
+	Manual bool
+
+	mutex   sync.Mutex
+	running bool
+	steps   chan int
+	stepped chan struct{}
+	stopped chan struct{}
+	rate    int // TicksPerSecond, or its default.
+	pending int // ticks left to complete the current step, only used on the main thread.
+}
+
+// Run the server until it shuts down, see [Run].
+func (server *Server) Run(ctx context.Context) error {
+	server.mutex.Lock()
+	if server.running {
+		server.mutex.Unlock()
+		return ErrRunning
+	}
+	server.running = true
+	server.steps = make(chan int)
+	server.stepped = make(chan struct{})
+	server.stopped = make(chan struct{})
+	server.rate = server.TicksPerSecond
+	if server.rate <= 0 {
+		server.rate = 60
+	}
+	server.pending = 0
+	server.mutex.Unlock()
+	defer func() {
+		server.mutex.Lock()
+		server.running = false
+		close(server.stopped)
+		server.mutex.Unlock()
+	}()
+	return Run(ctx, Options{MainLoop: serverLoop{server}})
+}
+
+// Step ticks the simulation of a [Server.Manual] server, once per engine frame, blocking until the
+// given number of ticks have completed. Step must not be called from the main thread, nor from the
+// Tick function.
+func (server *Server) Step(ticks int) error {
+	server.mutex.Lock()
+	steps, stepped, stopped := server.steps, server.stepped, server.stopped
+	running := server.running
+	server.mutex.Unlock()
+	if !running {
+		return ErrStopped
+	}
+	select {
+	case steps <- ticks:
+	case <-stopped:
+		return ErrStopped
+	}
+	select {
+	case <-stepped:
+		return nil
+	case <-stopped:
+		return ErrStopped
+	}
+}
+
+func (server *Server) delta() Float.X { return 1 / Float.X(server.rate) }
+
+// serverLoop is the [MainLoopClass.Interface] for a [Server].
+type serverLoop struct {
+	server *Server
+}
+
+func (loop serverLoop) Initialize() {
+	if DisplayServer.GetName() != "headless" {
+		EngineClass.RaiseWarning("startup: the server should be run by the engine with --headless (gd run -target server)")
+	}
+	EngineClass.SetPhysicsTicksPerSecond(loop.server.rate)
+	EngineClass.SetPhysicsJitterFix(0)
+	if loop.server.Manual {
+		EngineClass.SetMaxFps(0)
+	} else {
+		EngineClass.SetMaxFps(loop.server.rate)
+	}
+}
+
+func (loop serverLoop) PhysicsProcess(delta Float.X) bool {
+	if !loop.server.Manual && loop.server.Tick != nil {
+		loop.server.Tick(loop.server.delta())
+	}
+	return false
+}
+
+// Process ticks a manual server once per frame, until the current [Server.Step] has completed,
+// then waits for the next one, so that the engine only advances when Go asks it to.
+func (loop serverLoop) Process(delta Float.X) bool {
+	server := loop.server
+	if !server.Manual {
+		return false
+	}
+	if server.pending == 0 {
+		select {
+		case server.pending = <-server.steps:
+		case <-wake:
+			return true
+		}
+	}
+	if server.pending > 0 {
+		server.pending--
+		if server.Tick != nil {
+			server.Tick(server.delta())
+		}
+	}
+	if server.pending <= 0 {
+		server.pending = 0
+		server.stepped <- struct{}{}
+	}
+	return false
+}
+
+func (loop serverLoop) Finalize() {}
@@ -0,0 +1,83 @@
+package startup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"graphics.gd/classdb/Engine"
+	"graphics.gd/startup"
+	"graphics.gd/variant/Float"
+)
+
+func TestServerStopped(t *testing.T) {
+	server := startup.Server{Manual: true, Tick: func(Float.X) {}}
+	if err := server.Step(1); !errors.Is(err, startup.ErrStopped) {
+		t.Fatalf("expected ErrStopped, got %v", err)
+	}
+}
+
+func TestServerManual(t *testing.T) {
+	defer Engine.SetPhysicsTicksPerSecond(60)
+	var frames []int
+	server := &startup.Server{TicksPerSecond: 30, Manual: true}
+	server.Tick = func(delta Float.X) {
+		if ticks := Engine.PhysicsTicksPerSecond(); delta != Float.X(1)/30 || ticks != 30 {
+			t.Errorf("expected a fixed timestep of 1/30 at 30 ticks per second, got %v at %d", delta, ticks)
+		}
+		frames = append(frames, Engine.GetProcessFrames())
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- server.Run(ctx) }()
+	for start := time.Now(); server.Step(0) != nil; time.Sleep(time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("expected the server to start")
+		}
+	}
+	for _, ticks := range []int{3, 2} {
+		if err := server.Step(ticks); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(frames) != 5 {
+		t.Fatalf("expected 5 ticks, got %d", len(frames))
+	}
+	for i := 1; i < len(frames); i++ {
+		if frames[i] != frames[i-1]+1 {
+			t.Fatalf("expected one tick per engine frame, ticked on frames %v", frames)
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the context's error, got %v", err)
+	}
+	if err := server.Step(1); !errors.Is(err, startup.ErrStopped) {
+		t.Fatalf("expected ErrStopped after Run returned, got %v", err)
+	}
+}
+
+func TestServer(t *testing.T) {
+	defer Engine.SetPhysicsTicksPerSecond(60)
+	defer Engine.SetMaxFps(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var ticks int
+	server := &startup.Server{}
+	server.Tick = func(delta Float.X) {
+		if delta != Float.X(1)/60 || Engine.MaxFps() != 60 {
+			t.Errorf("expected a fixed timestep of 1/60 at 60 frames per second, got %v at %d", delta, Engine.MaxFps())
+		}
+		if ticks++; ticks == 3 {
+			cancel()
+		}
+	}
+	if err := server.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the context's error, got %v", err)
+	}
+	if ticks < 3 || server.TicksPerSecond != 0 {
+		t.Fatalf("expected at least 3 ticks without changing TicksPerSecond, got %d ticks at %d", ticks, server.TicksPerSecond)
+	}
+}
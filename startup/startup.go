@@ -3,15 +3,12 @@ package startup
 
 import (
 	"iter"
-	"time"
 
 	"graphics.gd/classdb"
 	EngineClass "graphics.gd/classdb/Engine"
 	MainLoopClass "graphics.gd/classdb/MainLoop"
 	"graphics.gd/classdb/SceneTree"
 	gd "graphics.gd/internal"
-	"graphics.gd/internal/pointers"
-	"graphics.gd/variant/Callable"
 	"graphics.gd/variant/Float"
 )
 
@@ -28,14 +25,17 @@ func MainLoop(loop MainLoopClass.Interface) {
 			stop_main()
 		}
 		theMainFunctionIsWaitingForTheEngineToShutDown = true
-	} else {
+	} else if gd.HostedMainLoop == nil {
 		<-intialized
 	}
 	classdb.Register[goMainLoop]()
 	mainloop = loop
-	if pause_main != nil {
+	switch {
+	case pause_main != nil:
 		pause_main(false) // We pause here until the engine has fully started up.
-	} else {
+	case gd.HostedMainLoop != nil:
+		gd.HostedMainLoop()
+	default:
 		<-shutdown
 	}
 }
@@ -53,10 +53,13 @@ func Scene() {
 	if !loadingSceneWasCalled {
 		LoadingScene()
 	}
-	if pause_main != nil {
+	switch {
+	case pause_main != nil:
 		theMainFunctionIsWaitingForTheEngineToShutDown = true
 		pause_main(false)
-	} else {
+	case gd.HostedMainLoop != nil:
+		gd.HostedMainLoop()
+	default:
 		<-shutdown
 	}
 }
@@ -82,7 +85,7 @@ func LoadingScene() {
 		if EngineClass.IsEditorHint() {
 			stop_main()
 		}
-	} else {
+	} else if gd.HostedMainLoop == nil {
 		<-intialized
 		var loaded = make(chan struct{})
 		gd.NewCallable(func() {
@@ -104,8 +107,12 @@ type goSceneTree struct {
 
 var main_loop_initialized = make(chan struct{})
 
+// Initialize is called once the SceneTree has been initialized.
+func (tree goSceneTree) Initialize() { started() }
+
 // Called once during initialization.
 func (loop goMainLoop) Initialize() {
+	started()
 	if mainloop != nil {
 		mainloop.Initialize()
 	} else if pause_main != nil {
@@ -129,8 +136,10 @@ var frame_ready = make(chan bool)
 // Called each process (idle) frame with the time since the last process frame as argument (in seconds). Equivalent to [method Node._process].
 // If implemented, the method must return a boolean value. [code]true[/code] ends the main loop, while [code]false[/code] lets it proceed to the next frame.
 func (loop goMainLoop) Process(delta Float.X) bool {
-	defer Callable.Cycle()
-	defer pointers.Cycle()
+	defer cycle()
+	if _, ok := exiting(); ok {
+		return true
+	}
 	if mainloop != nil {
 		return mainloop.Process(delta)
 	}
@@ -185,12 +194,14 @@ func Rendering() iter.Seq[Float.X] {
 			pause_main(true) // we pause here until the engine has fully shut down.
 		}
 	} else {
-		<-frame_ready
-		time.Sleep(5 * time.Second)
+		<-intialized
 		return func(yield func(Float.X) bool) {
-			frame_ready <- true
 			for {
-				<-frame_ready // we pause here until the next frame is ready (next Process callback).
+				select {
+				case <-frame_ready: // we pause here until the next frame is ready (next Process callback).
+				case <-main_loop_shutdown:
+					return
+				}
 				if !yield(dt) {
 					frame_ready <- true
 					break
@@ -8,8 +8,11 @@ import (
 	"reflect"
 	"sort"
 
+	"graphics.gd/internal/stringify"
 	"graphics.gd/variant"
 	"graphics.gd/variant/Int"
+	"graphics.gd/variant/Random"
+	"graphics.gd/variant/internal/containers"
 )
 
 // Contains is an array data structure that can contain a sequence of elements of T. Elements
@@ -41,6 +44,17 @@ type Any = Contains[variant.Any]
 // Nil reference array.
 var Nil Any
 
+func init() {
+	containers.NewArray = func(elements any) any { return New(elements.([]variant.Any)...) }
+	containers.NewTypedArray = newTyped
+	containers.Unwrap = append(containers.Unwrap, func(value any) (any, bool) {
+		if array, ok := value.(Interface); ok {
+			return array.Any().Slice(), true
+		}
+		return nil, false
+	})
+}
+
 // New creates a new array with the given elements.
 func New[T any](elements ...T) Contains[T] {
 	return Contains[T]{
@@ -384,6 +398,16 @@ func PickRandom[T any](array Contains[T]) T { //gd:Array.pick_random
 	return array.Index(rand.Intn(array.Len()))
 }
 
+// PickRandomWith returns a random element from the array, using the given source of random
+// numbers, the element picked matches the engine's pick_random for the same sequence of
+// random numbers. Returns the zero value for T if the array is empty.
+func PickRandomWith[T any](src Random.Source, array Contains[T]) T {
+	if array.Len() == 0 {
+		return [1]T{}[0]
+	}
+	return array.Index(int(uint32(src.Randi()) % uint32(array.Len())))
+}
+
 // PopAt removes and returns the element of the array at index position. If negative,
 // position is considered relative to the end of the array. Returns the zero value for
 // T if the array is empty. If position is out of bounds, an error message is also generated.
@@ -497,6 +521,18 @@ func Shuffle[T any](array Contains[T]) { //gd:Array.shuffle
 	}
 }
 
+// ShuffleWith shuffles all elements of the array in a random order, using the given source of
+// random numbers, such that the result matches the engine's shuffle for the same sequence of
+// random numbers.
+func ShuffleWith[T any](src Random.Source, array Contains[T]) {
+	for i := array.Len() - 1; i > 0; i-- {
+		j := int(uint32(src.Randi()) % uint32(i+1))
+		v, w := array.Index(i), array.Index(j)
+		array.SetIndex(i, w)
+		array.SetIndex(j, v)
+	}
+}
+
 // Slice returns a new Array containing this array's elements, from index begin (inclusive) to end (exclusive).
 // If either begin or end are negative, their value is relative to the end of the array.
 func Slice[T any](array Contains[T], from, upto int) Contains[T] { //gd:Array.slice
@@ -563,3 +599,18 @@ func (a Contains[T]) Iter() iter.Seq2[int, T] {
 		}
 	}
 }
+
+// MarshalJSON implements [encoding/json.Marshaler], the array is encoded in the same way as JSON.stringify.
+func (a Contains[T]) MarshalJSON() ([]byte, error) {
+	return stringify.Marshal(a.Slice())
+}
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler], replacing the array with the decoded elements.
+func (a *Contains[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+	if err := stringify.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	*a = New(elements...)
+	return nil
+}
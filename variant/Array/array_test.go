@@ -5,6 +5,7 @@ import (
 
 	"graphics.gd/internal/gdtests"
 	"graphics.gd/variant/Array"
+	"graphics.gd/variant/Random"
 )
 
 func TestAll(t *testing.T) {
@@ -110,3 +111,16 @@ func TestSortFunc(t *testing.T) {
 	Array.SortFunc(sort_ascending, my_items)
 	gdtests.Print(t, `[["Rice", 4], ["Tomato", 5], ["Apple", 9]]`, my_items) // Prints [["Rice", 4], ["Tomato", 5], ["Apple", 9]]
 }
+
+func TestShuffleWith(t *testing.T) {
+	var a, b Random.Generator
+	a.SetSeed(42)
+	b.SetSeed(42)
+	x, y := Array.New(1, 2, 3, 4, 5, 6, 7, 8), Array.New(1, 2, 3, 4, 5, 6, 7, 8)
+	Array.ShuffleWith(&a, x)
+	Array.ShuffleWith(&b, y)
+	gdtests.Print(t, gdtests.Sprint(x), y)
+	if Array.PickRandomWith(&a, x) != Array.PickRandomWith(&b, y) {
+		t.Fatal("expected the same pick for the same seed")
+	}
+}
@@ -0,0 +1,94 @@
+package Array
+
+import (
+	"reflect"
+
+	"graphics.gd/variant"
+	"graphics.gd/variant/AABB"
+	"graphics.gd/variant/Basis"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Dictionary"
+	"graphics.gd/variant/Path"
+	"graphics.gd/variant/Plane"
+	"graphics.gd/variant/Projection"
+	"graphics.gd/variant/Quaternion"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Rect2i"
+	"graphics.gd/variant/String"
+	"graphics.gd/variant/Transform2D"
+	"graphics.gd/variant/Transform3D"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector3i"
+	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/Vector4i"
+)
+
+// newTyped returns a typed array of the elements (a []variant.Any), when elem is the Go type of a
+// builtin variant type.
+func newTyped(elem reflect.Type, elements any) (any, bool) {
+	values := elements.([]variant.Any)
+	switch elem {
+	case reflect.TypeFor[bool]():
+		return typed[bool](values), true
+	case reflect.TypeFor[int64]():
+		return typed[int64](values), true
+	case reflect.TypeFor[float64]():
+		return typed[float64](values), true
+	case reflect.TypeFor[string]():
+		return typed[string](values), true
+	case reflect.TypeFor[String.Name]():
+		return typed[String.Name](values), true
+	case reflect.TypeFor[Path.ToNode]():
+		return typed[Path.ToNode](values), true
+	case reflect.TypeFor[RID.Any]():
+		return typed[RID.Any](values), true
+	case reflect.TypeFor[Vector2.XY]():
+		return typed[Vector2.XY](values), true
+	case reflect.TypeFor[Vector2i.XY]():
+		return typed[Vector2i.XY](values), true
+	case reflect.TypeFor[Rect2.PositionSize]():
+		return typed[Rect2.PositionSize](values), true
+	case reflect.TypeFor[Rect2i.PositionSize]():
+		return typed[Rect2i.PositionSize](values), true
+	case reflect.TypeFor[Vector3.XYZ]():
+		return typed[Vector3.XYZ](values), true
+	case reflect.TypeFor[Vector3i.XYZ]():
+		return typed[Vector3i.XYZ](values), true
+	case reflect.TypeFor[Transform2D.OriginXY]():
+		return typed[Transform2D.OriginXY](values), true
+	case reflect.TypeFor[Vector4.XYZW]():
+		return typed[Vector4.XYZW](values), true
+	case reflect.TypeFor[Vector4i.XYZW]():
+		return typed[Vector4i.XYZW](values), true
+	case reflect.TypeFor[Plane.NormalD]():
+		return typed[Plane.NormalD](values), true
+	case reflect.TypeFor[Quaternion.IJKX]():
+		return typed[Quaternion.IJKX](values), true
+	case reflect.TypeFor[AABB.PositionSize]():
+		return typed[AABB.PositionSize](values), true
+	case reflect.TypeFor[Basis.XYZ]():
+		return typed[Basis.XYZ](values), true
+	case reflect.TypeFor[Transform3D.BasisOrigin]():
+		return typed[Transform3D.BasisOrigin](values), true
+	case reflect.TypeFor[Projection.XYZW]():
+		return typed[Projection.XYZW](values), true
+	case reflect.TypeFor[Color.RGBA]():
+		return typed[Color.RGBA](values), true
+	case reflect.TypeFor[Any]():
+		return typed[Any](values), true
+	case reflect.TypeFor[Dictionary.Any]():
+		return typed[Dictionary.Any](values), true
+	}
+	return nil, false
+}
+
+func typed[T any](values []variant.Any) Contains[T] {
+	elements := make([]T, len(values))
+	for i, value := range values {
+		elements[i] = value.Interface().(T)
+	}
+	return New(elements...)
+}
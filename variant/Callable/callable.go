@@ -37,10 +37,17 @@ func Cycle() {
 	Array.Clear(queue)
 }
 
+// Queued returns the number of functions in the defer queue, that will be called by the next [Cycle].
+func Queued() int { return queue.Len() }
+
 // New returns a new [Func] from the given value, if the value is not a Go func
 // then it will be wrapped as if it were a function without any arguments that
-// returns the specified value.
+// returns the specified value. If the value is already a [Function], it is
+// returned as-is.
 func New(value any) Function {
+	if fn, ok := value.(Function); ok {
+		return fn
+	}
 	return Function{
 		proxy: &local{
 			value: value,
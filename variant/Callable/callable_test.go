@@ -15,6 +15,23 @@ func TestCallable(t *testing.T) {
 	}
 }
 
+func TestNewFunction(t *testing.T) {
+	var fn = Callable.New(Callable.New(func() int {
+		return 1
+	}))
+	if fn.Call().Int() != 1 {
+		t.Fatal("expected New to return an existing Function as-is")
+	}
+	Callable.Defer(fn)
+	if Callable.Queued() != 1 {
+		t.Fatal("expected one queued call")
+	}
+	Callable.Cycle()
+	if Callable.Queued() != 0 {
+		t.Fatal("expected the queue to be empty after Cycle")
+	}
+}
+
 func BenchmarkAllocations(b *testing.B) {
 	b.ReportAllocs()
 	var fn = Callable.New(func() string {
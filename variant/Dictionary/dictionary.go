@@ -2,10 +2,13 @@
 package Dictionary
 
 import (
+	"bytes"
 	"iter"
 	"reflect"
 
+	"graphics.gd/internal/stringify"
 	"graphics.gd/variant"
+	"graphics.gd/variant/internal/containers"
 )
 
 // Map is an associative container that contain values referenced by unique keys.
@@ -21,6 +24,23 @@ type Any = Map[variant.Any, variant.Any]
 
 var Nil Any
 
+func init() {
+	containers.NewDictionary = func(keys, values any) any {
+		dictionary := New[variant.Any, variant.Any]()
+		for i, key := range keys.([]variant.Any) {
+			dictionary.SetIndex(key, values.([]variant.Any)[i])
+		}
+		return dictionary
+	}
+	containers.Unwrap = append(containers.Unwrap, func(value any) (any, bool) {
+		if dictionary, ok := value.(Interface); ok {
+			entries := dictionary.Any()
+			return entries.Iter(), true
+		}
+		return nil, false
+	})
+}
+
 func New[K comparable, V any]() Map[K, V] {
 	return Map[K, V]{
 		proxy: &localFirst[K, V]{
@@ -245,3 +265,44 @@ func (m *Map[K, V]) Iter() iter.Seq2[K, V] {
 	}
 	return m.proxy.Iter(m.state)
 }
+
+// MarshalJSON implements [encoding/json.Marshaler], the dictionary is encoded in the same way as
+// JSON.stringify, keys are converted to strings and sorted.
+func (m Map[K, V]) MarshalJSON() ([]byte, error) {
+	object := make(map[string]V, m.Len())
+	for key, value := range m.Iter() {
+		name, err := stringify.Key(reflect.ValueOf(key))
+		if err != nil {
+			return nil, err
+		}
+		object[name] = value
+	}
+	return stringify.Marshal(object)
+}
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler], replacing the dictionary with the decoded entries,
+// in the order that they appear in the JSON object.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		*m = Map[K, V]{}
+		return nil
+	}
+	names, values, err := stringify.Object(data)
+	if err != nil {
+		return err
+	}
+	result := New[K, V]()
+	for i, name := range names {
+		var key K
+		if err := stringify.Assign(reflect.ValueOf(&key).Elem(), stringify.ParseKey(name, reflect.TypeFor[K]())); err != nil {
+			return err
+		}
+		var value V
+		if err := stringify.Unmarshal(values[i], &value); err != nil {
+			return err
+		}
+		result.SetIndex(key, value)
+	}
+	*m = result
+	return nil
+}
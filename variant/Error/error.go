@@ -1,13 +1,40 @@
 // Package Error provides generic or codes for use as error return values that do not allocate.
 package Error
 
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+)
+
 // Code is an error code.
 type Code uint8
 
+// New returns the [Code] that best describes err, a Code anywhere in the chain of err is returned as-is,
+// and the standard library's sentinel errors are mapped to their closest Code. Any other error is [Failed].
+// Since Code implements [error], errors.Is(err, Error.FileNotFound) can be used to check for codes.
 func New(err error) Code {
 	if err == nil {
 		return 0
 	}
+	var code Code
+	switch {
+	case errors.As(err, &code):
+		return code
+	case errors.Is(err, fs.ErrNotExist):
+		return FileNotFound
+	case errors.Is(err, fs.ErrPermission):
+		return Unauthorized
+	case errors.Is(err, fs.ErrExist):
+		return AlreadyExists
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return FileEof
+	case errors.Is(err, context.DeadlineExceeded):
+		return Timeout
+	case errors.Is(err, errors.ErrUnsupported):
+		return Unavailable
+	}
 	return Failed
 }
 
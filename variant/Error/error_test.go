@@ -0,0 +1,37 @@
+package Error_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"testing"
+
+	"graphics.gd/variant/Error"
+)
+
+func TestNew(t *testing.T) {
+	for _, test := range []struct {
+		err  error
+		code Error.Code
+	}{
+		{nil, 0},
+		{Error.Busy, Error.Busy},
+		{fmt.Errorf("loading save: %w", Error.FileCorrupt), Error.FileCorrupt},
+		{&fs.PathError{Op: "open", Path: "save.dat", Err: fs.ErrNotExist}, Error.FileNotFound},
+		{fs.ErrPermission, Error.Unauthorized},
+		{fs.ErrExist, Error.AlreadyExists},
+		{io.ErrUnexpectedEOF, Error.FileEof},
+		{context.DeadlineExceeded, Error.Timeout},
+		{errors.ErrUnsupported, Error.Unavailable},
+		{errors.New("something else"), Error.Failed},
+	} {
+		if code := Error.New(test.err); code != test.code {
+			t.Errorf("New(%v) = %v, want %v", test.err, code, test.code)
+		}
+	}
+	if err := fmt.Errorf("wrapped: %w", Error.Timeout); !errors.Is(err, Error.Timeout) {
+		t.Errorf("errors.Is(%v, Timeout) = false", err)
+	}
+}
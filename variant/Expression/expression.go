@@ -0,0 +1,312 @@
+// Package Expression evaluates the same expression syntax as the engine's Expression class, without
+// requiring the engine.
+//
+// An expression can use the GDScript operators, array and dictionary literals, builtin type
+// constructors such as Vector2(1, 2), the global math functions (sqrt, lerp, clamp...) and named
+// inputs.
+//
+//	expr, err := Expression.Parse("base_damage * pow(1.1, level)", "base_damage", "level")
+//	if err != nil {
+//		return err
+//	}
+//	damage, err := expr.Execute(10, 3)
+package Expression
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"graphics.gd/variant"
+	"graphics.gd/variant/AABB"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/Dictionary"
+	"graphics.gd/variant/Plane"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Rect2i"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+)
+
+// Parsed expression, ready to be executed any number of times with different inputs.
+type Parsed struct {
+	root   node
+	inputs []string
+}
+
+// Parse the expression, the names of the inputs that the expression may refer to are given
+// in the same order as their values will be passed to [Parsed.Execute].
+func Parse(expression string, inputs ...string) (Parsed, error) { //gd:Expression.parse
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return Parsed{}, fmt.Errorf("Expression: %w", err)
+	}
+	p := parser{tokens: tokens, inputs: make(map[string]int, len(inputs))}
+	for i, name := range inputs {
+		p.inputs[name] = i
+	}
+	root, err := p.expression(priorityLast)
+	if err != nil {
+		return Parsed{}, fmt.Errorf("Expression: %w", err)
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return Parsed{}, fmt.Errorf("Expression: %w", p.unexpected(t, "expected end of expression"))
+	}
+	return Parsed{root: root, inputs: inputs}, nil
+}
+
+// Execute the expression with the given input values, which are converted with [variant.Encode], so
+// Go structs, maps and slices can be passed as inputs.
+func (expr Parsed) Execute(values ...any) (variant.Any, error) { //gd:Expression.execute
+	if expr.root == nil {
+		return variant.Nil, fmt.Errorf("Expression: not parsed")
+	}
+	if len(values) != len(expr.inputs) {
+		return variant.Nil, fmt.Errorf("Expression: expected %d inputs, not %d", len(expr.inputs), len(values))
+	}
+	inputs := make([]any, len(values))
+	for i, value := range values {
+		encoded, err := variant.Encode(value)
+		if err != nil {
+			return variant.Nil, fmt.Errorf("Expression: input '%s': %w", expr.inputs[i], err)
+		}
+		inputs[i] = normalize(encoded)
+	}
+	result, err := expr.root.eval(inputs)
+	if err != nil {
+		return variant.Nil, fmt.Errorf("Expression: %w", err)
+	}
+	return variant.New(result), nil
+}
+
+// Evaluate parses and executes an expression that has no inputs.
+func Evaluate(expression string) (variant.Any, error) {
+	expr, err := Parse(expression)
+	if err != nil {
+		return variant.Nil, err
+	}
+	return expr.Execute()
+}
+
+// node of the parsed expression tree.
+type node interface {
+	eval(inputs []any) (any, error)
+}
+
+type (
+	constant struct{ value any }
+	input    int
+	unary    struct {
+		op      string
+		operand node
+	}
+	binary struct {
+		op          string
+		left, right node
+	}
+	indexed struct{ value, index node }
+	named   struct {
+		value node
+		name  string
+	}
+	array      []node
+	dictionary [][2]node
+	call       struct {
+		name string
+		fn   function
+		args []node
+	}
+)
+
+func (n constant) eval([]any) (any, error)     { return n.value, nil }
+func (n input) eval(inputs []any) (any, error) { return inputs[n], nil }
+
+func (n unary) eval(inputs []any) (any, error) {
+	operand, err := n.operand.eval(inputs)
+	if err != nil {
+		return nil, err
+	}
+	return unaryOp(n.op, operand)
+}
+
+func (n binary) eval(inputs []any) (any, error) {
+	left, err := n.left.eval(inputs)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(inputs)
+	if err != nil {
+		return nil, err
+	}
+	return binaryOp(n.op, left, right)
+}
+
+func (n indexed) eval(inputs []any) (any, error) {
+	value, err := n.value.eval(inputs)
+	if err != nil {
+		return nil, err
+	}
+	index, err := n.index.eval(inputs)
+	if err != nil {
+		return nil, err
+	}
+	return indexOf(value, index)
+}
+
+func (n named) eval(inputs []any) (any, error) {
+	value, err := n.value.eval(inputs)
+	if err != nil {
+		return nil, err
+	}
+	return member(value, n.name)
+}
+
+func (n array) eval(inputs []any) (any, error) {
+	elements := make([]variant.Any, len(n))
+	for i, element := range n {
+		value, err := element.eval(inputs)
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = variant.New(value)
+	}
+	return Array.New(elements...), nil
+}
+
+func (n dictionary) eval(inputs []any) (any, error) {
+	result := Dictionary.New[variant.Any, variant.Any]()
+	for _, entry := range n {
+		key, err := entry[0].eval(inputs)
+		if err != nil {
+			return nil, err
+		}
+		value, err := entry[1].eval(inputs)
+		if err != nil {
+			return nil, err
+		}
+		result.SetIndex(variant.New(key), variant.New(value))
+	}
+	return result, nil
+}
+
+func (n call) eval(inputs []any) (any, error) {
+	args := make([]any, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(inputs)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	result, err := n.fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return result, nil
+}
+
+// indexOf implements value[index].
+func indexOf(value, index any) (any, error) {
+	if name, ok := text(index); ok {
+		if dictionary, ok := value.(Dictionary.Any); ok {
+			if result, ok := lookup(dictionary, index); ok {
+				return result, nil
+			}
+			return nil, fmt.Errorf("Invalid index '%s' on base 'Dictionary'", name)
+		}
+		return member(value, name)
+	}
+	if dictionary, ok := value.(Dictionary.Any); ok {
+		if result, ok := lookup(dictionary, index); ok {
+			return result, nil
+		}
+		return nil, fmt.Errorf("Invalid index of type '%s' on base 'Dictionary'", typeName(index))
+	}
+	i, ok := index.(int64)
+	if !ok {
+		return nil, fmt.Errorf("Invalid index of type '%s' on base '%s'", typeName(index), typeName(value))
+	}
+	var length int
+	switch v := value.(type) {
+	case string:
+		length = len([]rune(v))
+	case Array.Any:
+		length = v.Len()
+	default:
+		if vec, ok := vectorOf(value); ok {
+			length = vec.n
+		} else if rvalue := reflect.ValueOf(value); rvalue.Kind() == reflect.Slice {
+			length = rvalue.Len()
+		} else {
+			return nil, fmt.Errorf("Cannot index a value of type '%s'", typeName(value))
+		}
+	}
+	if i < 0 {
+		i += int64(length)
+	}
+	if i < 0 || i >= int64(length) {
+		return nil, fmt.Errorf("Out of bounds index '%d' on base '%s' of size %d", index, typeName(value), length)
+	}
+	switch v := value.(type) {
+	case string:
+		return string([]rune(v)[i]), nil
+	case Array.Any:
+		return normalize(v.Index(int(i))), nil
+	}
+	if vec, ok := vectorOf(value); ok {
+		if vec.integer {
+			return int64(vec.i[i]), nil
+		}
+		return vec.f[i], nil
+	}
+	return normalize(reflect.ValueOf(value).Index(int(i)).Interface()), nil
+}
+
+// member implements value.name, using the engine's names for the fields of the builtin types.
+func member(value any, name string) (any, error) {
+	if dictionary, ok := value.(Dictionary.Any); ok {
+		if result, ok := lookup(dictionary, name); ok {
+			return result, nil
+		}
+		return nil, fmt.Errorf("Invalid named index '%s' for base type Dictionary", name)
+	}
+	if vec, ok := vectorOf(value); ok {
+		components := "xyzw"
+		if vec.kind == variant.TypeColor {
+			components = "rgba"
+		}
+		if k := strings.Index(components[:vec.n], name); len(name) == 1 && k >= 0 {
+			if vec.integer {
+				return int64(vec.i[k]), nil
+			}
+			return vec.f[k], nil
+		}
+	}
+	switch v := value.(type) {
+	case Rect2.PositionSize:
+		if name == "end" {
+			return Vector2.Add(v.Position, v.Size), nil
+		}
+	case Rect2i.PositionSize:
+		if name == "end" {
+			return Vector2i.Add(v.Position, v.Size), nil
+		}
+	case AABB.PositionSize:
+		if name == "end" {
+			return Vector3.Add(v.Position, v.Size), nil
+		}
+	case Plane.NormalD:
+		switch name {
+		case "x", "y", "z":
+			return member(v.Normal, name)
+		}
+	}
+	if rvalue := reflect.ValueOf(value); rvalue.Kind() == reflect.Struct && name != "" && strings.ToLower(name) == name {
+		field := rvalue.FieldByNameFunc(func(field string) bool { return strings.EqualFold(field, name) })
+		if field.IsValid() && field.CanInterface() {
+			return normalize(field.Interface()), nil
+		}
+	}
+	return nil, fmt.Errorf("Invalid named index '%s' for base type %s", name, typeName(value))
+}
@@ -0,0 +1,175 @@
+package Expression_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"graphics.gd/variant"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Expression"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+)
+
+func TestEvaluate(t *testing.T) {
+	for _, test := range []struct {
+		expression string
+		expect     any
+	}{
+		{"2 + 3 * 4", int64(14)},
+		{"(2 + 3) * 4", int64(20)},
+		{"7 / 2", int64(3)},
+		{"-7 / 2", int64(-3)},
+		{"-7 % 3", int64(-1)},
+		{"7 / 2.0", 3.5},
+		{"2 ** 10", int64(1024)},
+		{"-2 ** 2", int64(-4)},
+		{"2 ** 3 ** 2", int64(64)},
+		{"2 ** 0.5 * 2 ** 0.5", 2.0000000000000004},
+		{"0x1F + 0b101 + 1_000", int64(1036)},
+		{"1e3", 1000.0},
+		{"~5 & 0xF", int64(10)},
+		{"1 << 4 | 1", int64(17)},
+		{"6 ^ 3", int64(5)},
+		{"1 < 2 and not false", true},
+		{"1 == 1.0", true},
+		{"1 > 2 || !true", false},
+		{"2 in [1, 2, 3]", true},
+		{"'b' in {'a': 1, 'b': 2}", true},
+		{"'ell' in 'hello'", true},
+		{"'a' + \"b\\n\"", "ab\n"},
+		{"&'name' == 'name'", true},
+		{"[1, 2] + [3]", []int64{1, 2, 3}},
+		{"[1, [2, 3]][1][0]", int64(2)},
+		{"[1, 2, 3][-1]", int64(3)},
+		{"{'a': {'b': 5}}.a.b", int64(5)},
+		{"'hello'[1]", "e"},
+		{"sqrt(pow(3, 2) + pow(4, 2))", 5.0},
+		{"abs(-3)", int64(3)},
+		{"abs(-3.5)", 3.5},
+		{"floor(2.5)", 2.0},
+		{"floori(2.5)", int64(2)},
+		{"round(-2.5)", -3.0},
+		{"clamp(5, 0, 3)", int64(3)},
+		{"clamp(5, 0, 2.5)", 2.5},
+		{"max(1, 5, 3)", int64(5)},
+		{"min(1, 0.5)", 0.5},
+		{"lerp(0, 10, 0.25)", 2.5},
+		{"snapped(7, 5)", int64(5)},
+		{"wrapi(-1, 0, 3)", int64(2)},
+		{"posmod(-7, 3)", int64(2)},
+		{"fmod(-7, 3)", -1.0},
+		{"fposmod(-7, 3)", 2.0},
+		{"sign(-2)", int64(-1)},
+		{"deg_to_rad(180) == PI", true},
+		{"TAU / 2 == PI", true},
+		{"is_nan(NAN)", true},
+		{"is_inf(-INF)", true},
+		{"int('12') + int(2.9)", int64(14)},
+		{"float(3)", 3.0},
+		{"str(1, ' ', 2.0, ' ', true, ' ', null)", "1 2.0 true <null>"},
+		{"str([1, 'a', &'b'])", `[1, "a", &"b"]`},
+		{"str({'a': 1})", `{ "a": 1 }`},
+		{"str(Vector2(1, 2.5))", "(1.0, 2.5)"},
+		{"typeof(1.0)", int64(variant.TypeFloat)},
+		{"typeof([])", int64(variant.TypeArray)},
+		{"Vector2(1, 2) * 2", Vector2.New(2, 4)},
+		{"2 * Vector2(1, 2)", Vector2.New(2, 4)},
+		{"Vector2(1, 2) + Vector2(3, 4)", Vector2.New(4, 6)},
+		{"Vector2(1, 2) / Vector2(2, 4)", Vector2.New(0.5, 0.5)},
+		{"-Vector2(1, 2)", Vector2.New(-1, -2)},
+		{"Vector2(1, 2).y", 2.0},
+		{"Vector2(1, 2)[0]", 1.0},
+		{"Vector2(1, 2) < Vector2(1, 3)", true},
+		{"Vector2i(3, 4) * 2", Vector2i.New(6, 8)},
+		{"Vector2i(3, 4) * 0.5", Vector2.New(1.5, 2)},
+		{"Vector2i(7, -7) / 2", Vector2i.New(3, -3)},
+		{"Vector2i(7, -7) % 4", Vector2i.New(3, -3)},
+		{"Vector2i(Vector2(1.7, -1.7))", Vector2i.New(1, -1)},
+		{"abs(Vector2i(-1, 2))", Vector2i.New(1, 2)},
+		{"floor(Vector2(1.5, -1.5))", Vector2.New(1, -2)},
+		{"lerp(Vector2(0, 0), Vector2(10, 20), 0.5)", Vector2.New(5, 10)},
+		{"Vector3(1, 2, 3).z", 3.0},
+		{"Color(1, 0.5, 0) * 0.5", Color.RGBA{R: 0.5, G: 0.25, B: 0, A: 0.5}},
+		{"Color('#ff0000').r", 1.0},
+		{"-Color(1, 0, 0, 1)", Color.RGBA{R: 0, G: 1, B: 1, A: 0}},
+		{"Rect2(1, 2, 3, 4).end", Vector2.New(4, 6)},
+		{"Rect2(Vector2(1, 2), Vector2(3, 4)).size.x", 3.0},
+		{"Transform2D(0, Vector2(5, 6)) * Vector2(1, 1)", Vector2.New(6, 7)},
+		{"Basis(Vector3(1, 0, 0), Vector3(0, 2, 0), Vector3(0, 0, 3)) * Vector3(1, 1, 1)", Vector3.New(1, 2, 3)},
+		{"Transform3D().origin", Vector3.Zero},
+	} {
+		result, err := Expression.Evaluate(test.expression)
+		if err != nil {
+			t.Errorf("%s: %v", test.expression, err)
+			continue
+		}
+		got := result.Interface()
+		if array, ok := got.(Array.Any); ok {
+			var ints []int64
+			for _, element := range array.Slice() {
+				ints = append(ints, element.Interface().(int64))
+			}
+			got = ints
+		}
+		if fmt.Sprintf("%T %v", got, got) != fmt.Sprintf("%T %v", test.expect, test.expect) {
+			t.Errorf("%s = %#v, expected %#v", test.expression, got, test.expect)
+		}
+	}
+}
+
+func TestInputs(t *testing.T) {
+	type Weapon struct {
+		Damage int     `gd:"damage"`
+		Scale  float64 `gd:"scale"`
+	}
+	expr, err := Expression.Parse("weapon.damage * pow(weapon.scale, level) + bonus[level - 1]", "weapon", "level", "bonus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for level, expect := range map[int]float64{1: 25, 2: 43} {
+		result, err := expr.Execute(Weapon{Damage: 10, Scale: 2}, level, []int{5, 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := result.Interface(); got != expect {
+			t.Fatalf("level %d: expected %v, got %#v", level, expect, got)
+		}
+	}
+	if _, err := expr.Execute(Weapon{}); err == nil {
+		t.Fatal("expected an error for a missing input")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for _, expression := range []string{
+		"1 / 0",
+		"1 % 0",
+		"7.5 % 2",
+		"Vector2i(1, 1) / 0",
+		"1 + 'a'",
+		"~1.5",
+		"1 +",
+		"(1",
+		"unknown",
+		"unknown(1)",
+		"sqrt(1, 2)",
+		"sqrt('a')",
+		"[1][2]",
+		"{'a': 1}.b",
+		"Vector2(1, 2, 3)",
+		"Vector2(1, 2).length()",
+		"'unterminated",
+		"1 $ 2",
+	} {
+		if _, err := Expression.Evaluate(expression); err == nil {
+			t.Errorf("%s: expected an error", expression)
+		}
+	}
+	if got, err := Expression.Evaluate("1 / 0.0"); err != nil || !math.IsInf(got.Interface().(float64), 1) {
+		t.Errorf("1 / 0.0 = %v, %v, expected +inf", got, err)
+	}
+}
@@ -0,0 +1,646 @@
+package Expression
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"graphics.gd/internal/stringify"
+	"graphics.gd/variant"
+	"graphics.gd/variant/AABB"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/Basis"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Dictionary"
+	"graphics.gd/variant/Float"
+	"graphics.gd/variant/Int"
+	"graphics.gd/variant/Plane"
+	"graphics.gd/variant/Projection"
+	"graphics.gd/variant/Quaternion"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Rect2i"
+	"graphics.gd/variant/Transform2D"
+	"graphics.gd/variant/Transform3D"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector3i"
+	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/Vector4i"
+)
+
+// function called from an expression, with normalized arguments.
+type function func(args []any) (any, error)
+
+// functions available to expressions, the global scope functions and the builtin constructors.
+var functions map[string]function
+
+func init() {
+	functions = map[string]function{
+		"sin":   float1(math.Sin),
+		"cos":   float1(math.Cos),
+		"tan":   float1(math.Tan),
+		"sinh":  float1(math.Sinh),
+		"cosh":  float1(math.Cosh),
+		"tanh":  float1(math.Tanh),
+		"asin":  float1(func(x float64) float64 { return math.Asin(Float.Clamp(x, -1, 1)) }),
+		"acos":  float1(func(x float64) float64 { return math.Acos(Float.Clamp(x, -1, 1)) }),
+		"atan":  float1(math.Atan),
+		"asinh": float1(math.Asinh),
+		"acosh": float1(func(x float64) float64 { return math.Acosh(max(x, 1)) }),
+		"atanh": float1(func(x float64) float64 { return math.Atanh(Float.Clamp(x, -1, 1)) }),
+		"atan2": float2(math.Atan2),
+		"sqrt":  float1(Float.Sqrt[float64]),
+		"exp":   float1(Float.Exp[float64]),
+		"log":   float1(Float.Log[float64]),
+		"fmod":  float2(Float.Mod[float64]),
+		"pow":   float2(Float.Pow[float64]),
+		"ease":  float2(Float.Ease[float64]),
+
+		"fposmod":      float2(Float.Posmod[float64]),
+		"absf":         float1(Float.Abs[float64]),
+		"signf":        float1(Float.Sign[float64]),
+		"floorf":       float1(Float.Floor[float64]),
+		"ceilf":        float1(Float.Ceil[float64]),
+		"roundf":       float1(Float.Round[float64]),
+		"snappedf":     float2(Float.Snapped[float64]),
+		"maxf":         float2(Float.Max[float64]),
+		"minf":         float2(Float.Min[float64]),
+		"clampf":       float3(Float.Clamp[float64]),
+		"wrapf":        float3(Float.Wrap[float64]),
+		"lerpf":        float3(Float.Lerp[float64]),
+		"inverse_lerp": float3(Float.InverseLerp[float64]),
+		"smoothstep":   float3(Float.Smoothstep[float64]),
+		"move_toward":  float3(Float.MoveToward[float64]),
+		"pingpong":     float2(Float.PingPong[float64]),
+		"linear_to_db": float1(Float.LinearToDecibels[float64]),
+		"db_to_linear": float1(Float.DecibelsToLinear[float64]),
+		"deg_to_rad":   float1(func(x float64) float64 { return x * (math.Pi / 180) }),
+		"rad_to_deg":   float1(func(x float64) float64 { return x * (180 / math.Pi) }),
+
+		"angle_difference": float2(angleDifference),
+		"lerp_angle": float3(func(from, to, weight float64) float64 {
+			return from + angleDifference(from, to)*weight
+		}),
+		"rotate_toward": float3(func(from, to, delta float64) float64 {
+			difference := angleDifference(from, to)
+			abs := math.Abs(difference)
+			return from + Float.Clamp(delta, abs-math.Pi, abs)*Float.Sign(difference)
+		}),
+		"remap": floats(5, func(f []float64) any { return Float.Remap(f[0], f[1], f[2], f[3], f[4]) }),
+		"cubic_interpolate": floats(5, func(f []float64) any {
+			return Float.CubicInterpolate(f[0], f[1], f[2], f[3], f[4])
+		}),
+		"bezier_interpolate": floats(5, func(f []float64) any {
+			return Float.BezierInterpolate(f[0], f[1], f[2], f[3], f[4])
+		}),
+		"bezier_derivative": floats(5, func(f []float64) any {
+			return Float.BezierDerivative(f[0], f[1], f[2], f[3], f[4])
+		}),
+		"is_nan":          floats(1, func(f []float64) any { return Float.IsNaN(f[0]) }),
+		"is_inf":          floats(1, func(f []float64) any { return Float.IsInf(f[0]) }),
+		"is_finite":       floats(1, func(f []float64) any { return Float.IsFinite(f[0]) }),
+		"is_zero_approx":  floats(1, func(f []float64) any { return Float.IsApproximatelyZero(f[0]) }),
+		"is_equal_approx": floats(2, func(f []float64) any { return Float.IsApproximatelyEqual(f[0], f[1]) }),
+		"step_decimals":   floats(1, func(f []float64) any { return int64(Float.StepDecimals(f[0])) }),
+		"floori":          floats(1, func(f []float64) any { return int64(Int.Floor(f[0])) }),
+		"ceili":           floats(1, func(f []float64) any { return int64(Int.Ceil(f[0])) }),
+		"roundi":          floats(1, func(f []float64) any { return int64(Int.Round(f[0])) }),
+		"snappedi":        floats(2, func(f []float64) any { return int64(Float.Snapped(f[0], f[1])) }),
+		"absi":            int1(Int.Abs[int64]),
+		"signi":           int1(Int.Sign[int64]),
+		"nearest_po2":     int1(Int.NearestPowerOfTwo[int64]),
+		"posmod":          int2(Int.Posmod[int64]),
+		"maxi":            int2(Int.Max[int64]),
+		"mini":            int2(Int.Min[int64]),
+		"clampi":          int3(Int.Clamp[int64]),
+		"wrapi":           int3(Int.Wrap[int64]),
+		"abs":             generic1(Int.Abs[int64], Float.Abs[float64]),
+		"sign":            generic1(Int.Sign[int64], Float.Sign[float64]),
+		"floor":           generic1(nil, Float.Floor[float64]),
+		"ceil":            generic1(nil, Float.Ceil[float64]),
+		"round":           generic1(nil, Float.Round[float64]),
+		"snapped":         generic2(Int.Snapped[int64], Float.Snapped[float64]),
+		"clamp":           generic3(Int.Clamp[int64], Float.Clamp[float64]),
+		"wrap":            generic3(Int.Wrap[int64], Float.Wrap[float64]),
+		"max":             extremum(Int.Max[int64], Float.Max[float64]),
+		"min":             extremum(Int.Min[int64], Float.Min[float64]),
+		"lerp":            lerp,
+		"str":             func(args []any) (any, error) { return str(args...), nil },
+		"typeof":          fixed(1, func(args []any) (any, error) { return int64(typeOf(args[0])), nil }),
+		"is_same":         fixed(2, isSame),
+		"Vector2":         vectorConstructor(Vector2.XY{}),
+		"Vector2i":        vectorConstructor(Vector2i.XY{}),
+		"Vector3":         vectorConstructor(Vector3.XYZ{}),
+		"Vector3i":        vectorConstructor(Vector3i.XYZ{}),
+		"Vector4":         vectorConstructor(Vector4.XYZW{}),
+		"Vector4i":        vectorConstructor(Vector4i.XYZW{}),
+		"Color":           colorConstructor,
+		"Quaternion":      builtinConstructor(Quaternion.Identity),
+		"Rect2":           builtinConstructor(Rect2.PositionSize{}),
+		"Rect2i":          builtinConstructor(Rect2i.PositionSize{}),
+		"AABB":            builtinConstructor(AABB.PositionSize{}),
+		"Plane":           builtinConstructor(Plane.NormalD{}),
+		"Basis":           builtinConstructor(Basis.Identity),
+		"Transform2D":     transform2DConstructor,
+		"Transform3D":     builtinConstructor(Transform3D.Identity),
+		"Projection":      builtinConstructor(Projection.Identity),
+		"int":             fixed(1, toInt),
+		"float":           fixed(1, toFloat),
+		"bool":            fixed(1, toBool),
+		"String":          fixed(1, func(args []any) (any, error) { return str(args[0]), nil }),
+		"StringName":      fixed(1, func(args []any) (any, error) { return newStringName(str(args[0])), nil }),
+		"NodePath":        fixed(1, func(args []any) (any, error) { return newNodePath(str(args[0])), nil }),
+		"Array":           arrayConstructor,
+		"Dictionary":      dictionaryConstructor,
+	}
+}
+
+// angleDifference returns the difference between two angles in radians, in the range [-PI, PI].
+func angleDifference(from, to float64) float64 {
+	difference := math.Mod(to-from, math.Pi*2)
+	return math.Mod(2*difference, math.Pi*2) - difference
+}
+
+func fixed(n int, fn function) function {
+	return func(args []any) (any, error) {
+		if len(args) != n {
+			return nil, fmt.Errorf("expected %d arguments, not %d", n, len(args))
+		}
+		return fn(args)
+	}
+}
+
+func argument(args []any, i int) (float64, error) {
+	f, _, ok := number(args[i])
+	if !ok {
+		return 0, fmt.Errorf("cannot convert argument %d from %s to float", i+1, typeName(args[i]))
+	}
+	return f, nil
+}
+
+func floats(n int, fn func([]float64) any) function {
+	return fixed(n, func(args []any) (any, error) {
+		f := make([]float64, n)
+		for i := range args {
+			var err error
+			if f[i], err = argument(args, i); err != nil {
+				return nil, err
+			}
+		}
+		return fn(f), nil
+	})
+}
+
+func float1(fn func(float64) float64) function {
+	return floats(1, func(f []float64) any { return fn(f[0]) })
+}
+
+func float2(fn func(float64, float64) float64) function {
+	return floats(2, func(f []float64) any { return fn(f[0], f[1]) })
+}
+
+func float3(fn func(float64, float64, float64) float64) function {
+	return floats(3, func(f []float64) any { return fn(f[0], f[1], f[2]) })
+}
+
+func ints(n int, fn func([]int64) int64) function {
+	return fixed(n, func(args []any) (any, error) {
+		v := make([]int64, n)
+		for i, arg := range args {
+			switch x := arg.(type) {
+			case int64:
+				v[i] = x
+			case float64:
+				v[i] = int64(x)
+			default:
+				return nil, fmt.Errorf("cannot convert argument %d from %s to int", i+1, typeName(arg))
+			}
+		}
+		return fn(v), nil
+	})
+}
+
+func int1(fn func(int64) int64) function {
+	return ints(1, func(v []int64) int64 { return fn(v[0]) })
+}
+
+func int2(fn func(int64, int64) int64) function {
+	return ints(2, func(v []int64) int64 { return fn(v[0], v[1]) })
+}
+
+func int3(fn func(int64, int64, int64) int64) function {
+	return ints(3, func(v []int64) int64 { return fn(v[0], v[1], v[2]) })
+}
+
+// allInts reports whether every argument is an int.
+func allInts(args []any) bool {
+	for _, arg := range args {
+		if _, ok := arg.(int64); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// generic1 returns a function that accepts an int, a float or a vector, the int function is
+// optional and the value is returned unchanged without it.
+func generic1(i func(int64) int64, f func(float64) float64) function {
+	return fixed(1, func(args []any) (any, error) {
+		switch x := args[0].(type) {
+		case int64:
+			if i == nil {
+				return x, nil
+			}
+			return i(x), nil
+		case float64:
+			return f(x), nil
+		}
+		vec, ok := vectorOf(args[0])
+		if !ok || vec.kind == variant.TypeQuaternion || vec.kind == variant.TypeColor || vec.integer && i == nil {
+			return nil, fmt.Errorf("invalid argument of type %s", typeName(args[0]))
+		}
+		for k := range vec.n {
+			if vec.integer {
+				vec.i[k] = int32(i(int64(vec.i[k])))
+			} else {
+				vec.f[k] = f(vec.f[k])
+			}
+		}
+		return vec.value(), nil
+	})
+}
+
+func generic2(i func(int64, int64) int64, f func(float64, float64) float64) function {
+	return func(args []any) (any, error) {
+		if len(args) == 2 && allInts(args) {
+			return i(args[0].(int64), args[1].(int64)), nil
+		}
+		return float2(f)(args)
+	}
+}
+
+func generic3(i func(int64, int64, int64) int64, f func(float64, float64, float64) float64) function {
+	return func(args []any) (any, error) {
+		if len(args) == 3 && allInts(args) {
+			return i(args[0].(int64), args[1].(int64), args[2].(int64)), nil
+		}
+		return float3(f)(args)
+	}
+}
+
+// extremum returns a function that accepts two or more numbers, the result is an int if all of the
+// arguments are ints.
+func extremum(i func(int64, int64) int64, f func(float64, float64) float64) function {
+	return func(args []any) (any, error) {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("expected at least 2 arguments, not %d", len(args))
+		}
+		if allInts(args) {
+			result := args[0].(int64)
+			for _, arg := range args[1:] {
+				result = i(result, arg.(int64))
+			}
+			return result, nil
+		}
+		result, err := argument(args, 0)
+		if err != nil {
+			return nil, err
+		}
+		for n := range args[1:] {
+			x, err := argument(args, n+1)
+			if err != nil {
+				return nil, err
+			}
+			result = f(result, x)
+		}
+		return result, nil
+	}
+}
+
+// lerp interpolates numbers as floats, and vectors component-wise in [Float.X] precision.
+func lerp(args []any) (any, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("expected 3 arguments, not %d", len(args))
+	}
+	weight, err := argument(args, 2)
+	if err != nil {
+		return nil, err
+	}
+	from, fok := vectorOf(args[0])
+	to, tok := vectorOf(args[1])
+	if !fok || !tok {
+		return float3(Float.Lerp[float64])(args)
+	}
+	if from.kind != to.kind || from.integer || from.kind == variant.TypeQuaternion {
+		return nil, fmt.Errorf("cannot interpolate between %s and %s", typeName(args[0]), typeName(args[1]))
+	}
+	weight = toReal(weight)
+	for k := range from.n {
+		from.f[k] = toReal(from.f[k] + toReal(toReal(to.f[k]-from.f[k])*weight))
+	}
+	return from.value(), nil
+}
+
+func isSame(args []any) (any, error) {
+	if typeOf(args[0]) != typeOf(args[1]) {
+		return false, nil
+	}
+	switch a := args[0].(type) {
+	case Array.Any, Dictionary.Any:
+		return reflect.ValueOf(a).Equal(reflect.ValueOf(args[1])), nil
+	}
+	return equal(args[0], args[1])
+}
+
+func toInt(args []any) (any, error) {
+	switch x := args[0].(type) {
+	case nil:
+		return int64(0), nil
+	case bool:
+		if x {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	case int64:
+		return x, nil
+	case float64:
+		return int64(x), nil
+	case string:
+		n, _ := strconv.ParseInt(strings.TrimSpace(x), 10, 64)
+		return n, nil
+	}
+	return nil, fmt.Errorf("cannot convert %s to int", typeName(args[0]))
+}
+
+func toFloat(args []any) (any, error) {
+	switch x := args[0].(type) {
+	case nil:
+		return 0.0, nil
+	case bool:
+		if x {
+			return 1.0, nil
+		}
+		return 0.0, nil
+	case int64:
+		return float64(x), nil
+	case float64:
+		return x, nil
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		return f, nil
+	}
+	return nil, fmt.Errorf("cannot convert %s to float", typeName(args[0]))
+}
+
+func toBool(args []any) (any, error) {
+	switch args[0].(type) {
+	case nil, bool, int64, float64:
+		return truthy(args[0]), nil
+	}
+	return nil, fmt.Errorf("cannot convert %s to bool", typeName(args[0]))
+}
+
+// vectorConstructor returns the constructor for a vector type, which accepts no arguments, a
+// vector with the same number of components, or one number for each component.
+func vectorConstructor(zero any) function {
+	like, _ := vectorOf(zero)
+	return func(args []any) (any, error) {
+		result := like
+		switch {
+		case len(args) == 0:
+		case len(args) == 1:
+			from, ok := vectorOf(args[0])
+			special := func(v vector) bool { return v.kind == variant.TypeColor || v.kind == variant.TypeQuaternion }
+			if !ok || from.n != like.n || (special(from) || special(like)) && from.kind != like.kind {
+				return nil, fmt.Errorf("cannot construct %s from %s", typeName(zero), typeName(args[0]))
+			}
+			for k := range like.n {
+				if from.integer {
+					result.f[k], result.i[k] = float64(from.i[k]), from.i[k]
+				} else {
+					result.f[k], result.i[k] = from.f[k], int32(from.f[k])
+				}
+			}
+		case len(args) == like.n:
+			for k := range like.n {
+				switch x := args[k].(type) {
+				case int64:
+					result.f[k], result.i[k] = float64(x), int32(x)
+				case float64:
+					result.f[k], result.i[k] = x, int32(x)
+				default:
+					return nil, fmt.Errorf("cannot convert argument %d from %s to float", k+1, typeName(args[k]))
+				}
+			}
+		default:
+			return nil, fmt.Errorf("no constructor of %s takes %d arguments", typeName(zero), len(args))
+		}
+		return result.value(), nil
+	}
+}
+
+// colorConstructor accepts the same arguments as the engine's Color constructors.
+func colorConstructor(args []any) (any, error) {
+	if len(args) > 0 {
+		if s, ok := text(args[0]); ok {
+			color := Color.String(s)
+			if len(args) == 2 {
+				alpha, err := argument(args, 1)
+				if err != nil {
+					return nil, err
+				}
+				color.A = Float.X(alpha)
+			}
+			return color, nil
+		}
+	}
+	if len(args) == 2 {
+		if color, ok := args[0].(Color.RGBA); ok {
+			alpha, err := argument(args, 1)
+			if err != nil {
+				return nil, err
+			}
+			color.A = Float.X(alpha)
+			return color, nil
+		}
+	}
+	if len(args) == 3 {
+		args = append(args[:3:3], 1.0)
+	}
+	return vectorConstructor(Color.RGBA{})(args)
+}
+
+// transform2DConstructor adds the engine's rotation and position constructor to those accepted by
+// [builtinConstructor].
+func transform2DConstructor(args []any) (any, error) {
+	if len(args) == 2 {
+		if _, _, ok := number(args[0]); ok {
+			rotation, _ := argument(args, 0)
+			position, ok := args[1].(Vector2.XY)
+			if !ok {
+				return nil, fmt.Errorf("cannot convert argument 2 from %s to Vector2", typeName(args[1]))
+			}
+			sin, cos := math.Sincos(rotation)
+			return Transform2D.OriginXY{
+				X:      Vector2.XY{Float.X(cos), Float.X(sin)},
+				Y:      Vector2.XY{Float.X(-sin), Float.X(cos)},
+				Origin: position,
+			}, nil
+		}
+	}
+	return builtinConstructor(Transform2D.Identity)(args)
+}
+
+// builtinConstructor returns a constructor for a builtin math type, the arguments are the numbers
+// or vectors that make up the value, in the same order as its fields.
+func builtinConstructor(zero any) function {
+	return func(args []any) (any, error) {
+		if len(args) == 0 {
+			return zero, nil
+		}
+		if len(args) == 1 && typeOf(args[0]) == typeOf(zero) {
+			return args[0], nil
+		}
+		var components []float64
+		for i, arg := range args {
+			if f, _, ok := number(arg); ok {
+				components = append(components, f)
+				continue
+			}
+			if vec, ok := vectorOf(arg); ok {
+				for k := range vec.n {
+					components = append(components, vec.real().f[k])
+				}
+				continue
+			}
+			f, ok := stringify.Components(arg)
+			if !ok {
+				return nil, fmt.Errorf("cannot convert argument %d from %s", i+1, typeName(arg))
+			}
+			components = append(components, f...)
+		}
+		// a basis is constructed from its columns, but its components are listed by row.
+		if _, ok := args[0].(Vector3.XYZ); ok && (typeOf(zero) == variant.TypeBasis || typeOf(zero) == variant.TypeTransform3D) && len(components) >= 9 {
+			for r := range 3 {
+				for c := r + 1; c < 3; c++ {
+					components[r*3+c], components[c*3+r] = components[c*3+r], components[r*3+c]
+				}
+			}
+		}
+		ptr := reflect.New(reflect.TypeOf(zero))
+		if err := stringify.SetComponents(ptr.Interface(), components); err != nil {
+			return nil, fmt.Errorf("no constructor of %s takes these arguments: %w", typeName(zero), err)
+		}
+		return ptr.Elem().Interface(), nil
+	}
+}
+
+func arrayConstructor(args []any) (any, error) {
+	switch len(args) {
+	case 0:
+		return Array.New[variant.Any](), nil
+	case 1:
+		if array, ok := args[0].(Array.Any); ok {
+			return Array.New(array.Slice()...), nil
+		}
+		if rvalue := reflect.ValueOf(args[0]); rvalue.Kind() == reflect.Slice {
+			elements := make([]variant.Any, rvalue.Len())
+			for i := range elements {
+				elements[i] = variant.New(rvalue.Index(i).Interface())
+			}
+			return Array.New(elements...), nil
+		}
+		return nil, fmt.Errorf("cannot construct Array from %s", typeName(args[0]))
+	}
+	return nil, fmt.Errorf("no constructor of Array takes %d arguments", len(args))
+}
+
+func dictionaryConstructor(args []any) (any, error) {
+	switch len(args) {
+	case 0:
+		return Dictionary.New[variant.Any, variant.Any](), nil
+	case 1:
+		if dictionary, ok := args[0].(Dictionary.Any); ok {
+			return Dictionary.Duplicate(dictionary), nil
+		}
+		return nil, fmt.Errorf("cannot construct Dictionary from %s", typeName(args[0]))
+	}
+	return nil, fmt.Errorf("no constructor of Dictionary takes %d arguments", len(args))
+}
+
+// str converts the values to strings and concatenates them, in the same way as the engine's str.
+func str(values ...any) string {
+	var buf []byte
+	for _, value := range values {
+		buf = appendString(buf, value, false)
+	}
+	return string(buf)
+}
+
+// appendString appends the engine's String form of the value, nested strings are quoted.
+func appendString(buf []byte, value any, nested bool) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, "<null>"...)
+	case bool:
+		return strconv.AppendBool(buf, v)
+	case int64:
+		return strconv.AppendInt(buf, v, 10)
+	case float64:
+		return stringify.AppendReal(buf, v, 64)
+	case string:
+		if nested {
+			return strconv.AppendQuote(buf, v)
+		}
+		return append(buf, v...)
+	case stringName:
+		if nested {
+			return strconv.AppendQuote(append(buf, '&'), v.String())
+		}
+		return append(buf, v.String()...)
+	case nodePath:
+		if nested {
+			return strconv.AppendQuote(append(buf, '^'), v.String())
+		}
+		return append(buf, v.String()...)
+	case Array.Any:
+		buf = append(buf, '[')
+		for i := range v.Len() {
+			if i > 0 {
+				buf = append(buf, ", "...)
+			}
+			buf = appendString(buf, normalize(v.Index(i)), true)
+		}
+		return append(buf, ']')
+	case Dictionary.Any:
+		buf = append(buf, "{ "...)
+		first := true
+		for key, value := range v.Iter() {
+			if !first {
+				buf = append(buf, ", "...)
+			}
+			first = false
+			buf = appendString(buf, normalize(key), true)
+			buf = append(buf, ": "...)
+			buf = appendString(buf, normalize(value), true)
+		}
+		return append(buf, " }"...)
+	}
+	if s, ok := stringify.Builtin(value); ok {
+		return append(buf, s...)
+	}
+	if rvalue := reflect.ValueOf(value); rvalue.Kind() == reflect.Slice {
+		buf = append(buf, '[')
+		for i := range rvalue.Len() {
+			if i > 0 {
+				buf = append(buf, ", "...)
+			}
+			buf = appendString(buf, normalize(rvalue.Index(i).Interface()), true)
+		}
+		return append(buf, ']')
+	}
+	return fmt.Append(buf, value)
+}
@@ -0,0 +1,634 @@
+package Expression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	"graphics.gd/variant"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/Basis"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Dictionary"
+	"graphics.gd/variant/Float"
+	"graphics.gd/variant/Path"
+	"graphics.gd/variant/Quaternion"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/String"
+	"graphics.gd/variant/Transform2D"
+	"graphics.gd/variant/Transform3D"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector3i"
+	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/Vector4i"
+)
+
+var errDivisionByZero = errors.New("Division by zero error")
+
+type stringName = String.Name
+type nodePath = Path.ToNode
+
+func newStringName(s string) stringName { return stringName(String.New(s)) }
+func newNodePath(s string) nodePath     { return nodePath(String.New(s)) }
+
+// normalize converts a Go value into the representation used during evaluation: nil, bool,
+// int64, float64, string, [String.Name], [Path.ToNode], the builtin math types, [Array.Any],
+// [Dictionary.Any] or a packed Go slice.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case nil, bool, int64, float64, string, stringName, nodePath, Array.Any, Dictionary.Any:
+		return v
+	case variant.Any:
+		return normalize(v.Interface())
+	case String.Readable:
+		return v.String()
+	case Array.Interface:
+		return v.Any()
+	case Dictionary.Interface:
+		return v.Any()
+	}
+	rvalue := reflect.ValueOf(value)
+	switch rvalue.Kind() {
+	case reflect.Bool:
+		return rvalue.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rvalue.Int()
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return int64(rvalue.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rvalue.Float()
+	case reflect.String:
+		return rvalue.String()
+	}
+	return value
+}
+
+// typeOf returns the variant type of a normalized value.
+func typeOf(value any) variant.Type {
+	switch value.(type) {
+	case nil:
+		return variant.TypeNil
+	case bool:
+		return variant.TypeBool
+	case int64:
+		return variant.TypeInt
+	case float64:
+		return variant.TypeFloat
+	case string:
+		return variant.TypeString
+	case stringName:
+		return variant.TypeStringName
+	case nodePath:
+		return variant.TypeNodePath
+	case RID.Any:
+		return variant.TypeRID
+	case Array.Any:
+		return variant.TypeArray
+	case Dictionary.Any:
+		return variant.TypeDictionary
+	}
+	return variant.New(value).Type()
+}
+
+// typeName returns the name of the value's type, as it is written in GDScript.
+func typeName(value any) string {
+	switch t := typeOf(value); t {
+	case variant.TypeBool:
+		return "bool"
+	case variant.TypeInt:
+		return "int"
+	case variant.TypeFloat:
+		return "float"
+	default:
+		return t.String()
+	}
+}
+
+func invalidOperands(op string, a, b any) error {
+	return fmt.Errorf("Invalid operands '%s' and '%s' in operator '%s'", typeName(a), typeName(b), op)
+}
+
+func invalidOperand(op string, a any) error {
+	return fmt.Errorf("Invalid operand '%s' in unary operator '%s'", typeName(a), op)
+}
+
+// vector is the common representation of the vector-like builtins, integer vectors keep their
+// components as int32 so that arithmetic wraps in the same way as the engine.
+type vector struct {
+	kind    variant.Type
+	n       int
+	integer bool
+	f       [4]float64
+	i       [4]int32
+}
+
+func vectorOf(value any) (vector, bool) {
+	switch v := value.(type) {
+	case Vector2.XY:
+		return vector{kind: variant.TypeVector2, n: 2, f: [4]float64{float64(v.X), float64(v.Y)}}, true
+	case Vector3.XYZ:
+		return vector{kind: variant.TypeVector3, n: 3, f: [4]float64{float64(v.X), float64(v.Y), float64(v.Z)}}, true
+	case Vector4.XYZW:
+		return vector{kind: variant.TypeVector4, n: 4, f: [4]float64{float64(v.X), float64(v.Y), float64(v.Z), float64(v.W)}}, true
+	case Color.RGBA:
+		return vector{kind: variant.TypeColor, n: 4, f: [4]float64{float64(v.R), float64(v.G), float64(v.B), float64(v.A)}}, true
+	case Quaternion.IJKX:
+		return vector{kind: variant.TypeQuaternion, n: 4, f: [4]float64{float64(v.I), float64(v.J), float64(v.K), float64(v.X)}}, true
+	case Vector2i.XY:
+		return vector{kind: variant.TypeVector2i, n: 2, integer: true, i: [4]int32{v.X, v.Y}}, true
+	case Vector3i.XYZ:
+		return vector{kind: variant.TypeVector3i, n: 3, integer: true, i: [4]int32{v.X, v.Y, v.Z}}, true
+	case Vector4i.XYZW:
+		return vector{kind: variant.TypeVector4i, n: 4, integer: true, i: [4]int32{v.X, v.Y, v.Z, v.W}}, true
+	}
+	return vector{}, false
+}
+
+// real returns the floating point vector type with the same number of components.
+func (v vector) real() vector {
+	if !v.integer {
+		return v
+	}
+	r := vector{kind: map[int]variant.Type{2: variant.TypeVector2, 3: variant.TypeVector3, 4: variant.TypeVector4}[v.n], n: v.n}
+	for k := range v.n {
+		r.f[k] = float64(v.i[k])
+	}
+	return r
+}
+
+// value converts the vector back into its builtin type, rounding components to [Float.X].
+func (v vector) value() any {
+	r := func(k int) Float.X { return Float.X(v.f[k]) }
+	switch v.kind {
+	case variant.TypeVector2:
+		return Vector2.XY{r(0), r(1)}
+	case variant.TypeVector3:
+		return Vector3.XYZ{r(0), r(1), r(2)}
+	case variant.TypeVector4:
+		return Vector4.XYZW{r(0), r(1), r(2), r(3)}
+	case variant.TypeColor:
+		return Color.RGBA{r(0), r(1), r(2), r(3)}
+	case variant.TypeQuaternion:
+		return Quaternion.IJKX{r(0), r(1), r(2), r(3)}
+	case variant.TypeVector2i:
+		return Vector2i.XY{v.i[0], v.i[1]}
+	case variant.TypeVector3i:
+		return Vector3i.XYZ{v.i[0], v.i[1], v.i[2]}
+	case variant.TypeVector4i:
+		return Vector4i.XYZW{v.i[0], v.i[1], v.i[2], v.i[3]}
+	}
+	return nil
+}
+
+// toReal rounds the scalar to [Float.X], as the engine does before combining it with a vector.
+func toReal(f float64) float64 { return float64(Float.X(f)) }
+
+// number returns the value of an int or float, reporting whether it is an int.
+func number(value any) (f float64, integer, ok bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true, true
+	case float64:
+		return v, false, true
+	}
+	return 0, false, false
+}
+
+// truthy converts the value to a bool, in the same way as the engine's Variant::booleanize.
+func truthy(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case int64:
+		return v != 0
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	case stringName:
+		return v.String() != ""
+	case nodePath:
+		return v.String() != ""
+	case Array.Any:
+		return v.Len() != 0
+	case Dictionary.Any:
+		return v.Len() != 0
+	}
+	if vec, ok := vectorOf(value); ok {
+		for k := range vec.n {
+			if vec.f[k] != 0 || vec.i[k] != 0 {
+				return true
+			}
+		}
+		return false
+	}
+	rvalue := reflect.ValueOf(value)
+	if rvalue.Kind() == reflect.Slice {
+		return rvalue.Len() != 0
+	}
+	return !rvalue.IsZero()
+}
+
+// text returns the Go string for String and StringName values.
+func text(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case stringName:
+		return v.String(), true
+	}
+	return "", false
+}
+
+// equal reports whether the values are equal, numbers of different types are compared by value,
+// and containers are compared element by element.
+func equal(a, b any) (bool, error) {
+	if a == nil || b == nil {
+		return a == nil && b == nil, nil
+	}
+	if x, _, ok := number(a); ok {
+		if y, _, ok := number(b); ok {
+			return x == y, nil
+		}
+	}
+	if x, ok := text(a); ok {
+		if y, ok := text(b); ok {
+			return x == y, nil
+		}
+	}
+	if typeOf(a) != typeOf(b) {
+		return false, invalidOperands("==", a, b)
+	}
+	switch x := a.(type) {
+	case nodePath:
+		return x.String() == b.(nodePath).String(), nil
+	case Array.Any:
+		y := b.(Array.Any)
+		if x.Len() != y.Len() {
+			return false, nil
+		}
+		for i := range x.Len() {
+			if eq, err := equal(normalize(x.Index(i)), normalize(y.Index(i))); err != nil || !eq {
+				return false, nil
+			}
+		}
+		return true, nil
+	case Dictionary.Any:
+		y := b.(Dictionary.Any)
+		if x.Len() != y.Len() {
+			return false, nil
+		}
+		for key, value := range x.Iter() {
+			other, ok := lookup(y, normalize(key))
+			if !ok {
+				return false, nil
+			}
+			if eq, err := equal(normalize(value), other); err != nil || !eq {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+	ra, rb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if ra.Kind() == reflect.Slice {
+		return reflect.DeepEqual(a, b), nil
+	}
+	if ra.Comparable() && rb.Comparable() {
+		return ra.Equal(rb), nil
+	}
+	return false, invalidOperands("==", a, b)
+}
+
+// less reports whether a sorts before b, vectors are compared component by component.
+func less(a, b any) (bool, error) {
+	if x, _, ok := number(a); ok {
+		if y, _, ok := number(b); ok {
+			return x < y, nil
+		}
+	}
+	if x, ok := a.(bool); ok {
+		if y, ok := b.(bool); ok {
+			return !x && y, nil
+		}
+	}
+	if x, ok := text(a); ok {
+		if y, ok := text(b); ok && typeOf(a) == typeOf(b) {
+			return x < y, nil
+		}
+	}
+	x, ok1 := vectorOf(a)
+	y, ok2 := vectorOf(b)
+	if ok1 && ok2 && x.kind == y.kind && x.kind != variant.TypeQuaternion {
+		for k := range x.n {
+			if x.integer && x.i[k] != y.i[k] {
+				return x.i[k] < y.i[k], nil
+			}
+			if !x.integer && x.f[k] != y.f[k] {
+				return x.f[k] < y.f[k], nil
+			}
+		}
+		return false, nil
+	}
+	return false, invalidOperands("<", a, b)
+}
+
+// lookup finds the value for key in the dictionary, keys are compared with [equal] so that a
+// String key can be found with a StringName.
+func lookup(dictionary Dictionary.Any, key any) (any, bool) {
+	for k, v := range dictionary.Iter() {
+		if eq, err := equal(normalize(k), key); err == nil && eq {
+			return normalize(v), true
+		}
+	}
+	return nil, false
+}
+
+// contains implements the 'in' operator.
+func contains(op string, needle, haystack any) (bool, error) {
+	switch h := haystack.(type) {
+	case string, stringName:
+		s, _ := text(h)
+		n, ok := text(needle)
+		if !ok {
+			return false, invalidOperands(op, needle, haystack)
+		}
+		return strings.Contains(s, n), nil
+	case Array.Any:
+		for i := range h.Len() {
+			if eq, err := equal(needle, normalize(h.Index(i))); err == nil && eq {
+				return true, nil
+			}
+		}
+		return false, nil
+	case Dictionary.Any:
+		_, ok := lookup(h, needle)
+		return ok, nil
+	}
+	if rvalue := reflect.ValueOf(haystack); rvalue.Kind() == reflect.Slice {
+		for i := range rvalue.Len() {
+			if eq, err := equal(needle, normalize(rvalue.Index(i).Interface())); err == nil && eq {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, invalidOperands(op, needle, haystack)
+}
+
+// unaryOp evaluates a unary operator.
+func unaryOp(op string, a any) (any, error) {
+	switch op {
+	case "not":
+		return !truthy(a), nil
+	case "~":
+		if x, ok := a.(int64); ok {
+			return ^x, nil
+		}
+		return nil, invalidOperand(op, a)
+	}
+	switch x := a.(type) {
+	case int64:
+		return -x, nil
+	case float64:
+		return -x, nil
+	}
+	if v, ok := vectorOf(a); ok {
+		for k := range v.n {
+			if v.kind == variant.TypeColor {
+				v.f[k] = 1 - v.f[k]
+			} else {
+				v.f[k], v.i[k] = -v.f[k], -v.i[k]
+			}
+		}
+		return v.value(), nil
+	}
+	return nil, invalidOperand(op, a)
+}
+
+// binaryOp evaluates a binary operator, following the result types and errors of the engine's
+// Variant::evaluate.
+func binaryOp(op string, a, b any) (any, error) {
+	switch op {
+	case "and":
+		return truthy(a) && truthy(b), nil
+	case "or":
+		return truthy(a) || truthy(b), nil
+	case "==":
+		return equal(a, b)
+	case "!=":
+		eq, err := equal(a, b)
+		return !eq, err
+	case "<":
+		return less(a, b)
+	case ">":
+		return less(b, a)
+	case "<=":
+		lt, err := less(b, a)
+		return !lt, err
+	case ">=":
+		lt, err := less(a, b)
+		return !lt, err
+	case "in":
+		return contains(op, a, b)
+	}
+	x, xint, xok := number(a)
+	y, yint, yok := number(b)
+	switch {
+	case xint && yint:
+		return intOp(op, a.(int64), b.(int64))
+	case xok && yok:
+		return floatOp(op, a, b, x, y)
+	}
+	switch op {
+	case "+":
+		if x, ok := text(a); ok {
+			if y, ok := text(b); ok {
+				return x + y, nil
+			}
+		}
+		if x, ok := a.(Array.Any); ok {
+			if y, ok := b.(Array.Any); ok {
+				sum := Array.New(x.Slice()...)
+				for _, value := range y.Slice() {
+					sum.Append(value)
+				}
+				return sum, nil
+			}
+		}
+		ra, rb := reflect.ValueOf(a), reflect.ValueOf(b)
+		if ra.Kind() == reflect.Slice && ra.Type() == rb.Type() {
+			sum := reflect.MakeSlice(ra.Type(), 0, ra.Len()+rb.Len())
+			return reflect.AppendSlice(reflect.AppendSlice(sum, ra), rb).Interface(), nil
+		}
+	case "*":
+		switch x := a.(type) {
+		case Quaternion.IJKX:
+			if y, ok := b.(Quaternion.IJKX); ok {
+				return Quaternion.Mul(x, y), nil
+			}
+		case Transform2D.OriginXY:
+			switch y := b.(type) {
+			case Transform2D.OriginXY:
+				return Transform2D.Mul(x, y), nil
+			case Vector2.XY:
+				return Transform2D.Vector(y, x), nil
+			}
+		case Basis.XYZ:
+			switch y := b.(type) {
+			case Basis.XYZ:
+				return Basis.Mul(x, y), nil
+			case Vector3.XYZ:
+				return Basis.Transform(y, x), nil
+			}
+		case Transform3D.BasisOrigin:
+			switch y := b.(type) {
+			case Transform3D.BasisOrigin:
+				return Transform3D.Mul(x, y), nil
+			case Vector3.XYZ:
+				return Transform3D.Transform(y, x), nil
+			}
+		}
+	}
+	if result, ok, err := vectorOp(op, a, b); ok {
+		return result, err
+	}
+	return nil, invalidOperands(op, a, b)
+}
+
+func intOp(op string, x, y int64) (any, error) {
+	switch op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/":
+		if y == 0 {
+			return nil, errDivisionByZero
+		}
+		return x / y, nil
+	case "%":
+		if y == 0 {
+			return nil, errors.New("Modulo by zero error")
+		}
+		return x % y, nil
+	case "**":
+		return int64(math.Pow(float64(x), float64(y))), nil
+	case "&":
+		return x & y, nil
+	case "|":
+		return x | y, nil
+	case "^":
+		return x ^ y, nil
+	case "<<", ">>":
+		if y < 0 || y > 63 {
+			return nil, fmt.Errorf("Invalid shift amount %d in operator '%s'", y, op)
+		}
+		if op == "<<" {
+			return x << y, nil
+		}
+		return x >> y, nil
+	}
+	return nil, invalidOperands(op, x, y)
+}
+
+func floatOp(op string, a, b any, x, y float64) (any, error) {
+	switch op {
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/":
+		return x / y, nil
+	case "**":
+		return math.Pow(x, y), nil
+	}
+	return nil, invalidOperands(op, a, b)
+}
+
+// vectorOp evaluates arithmetic on vectors and colors, either component-wise between two values of
+// the same type, or between a vector and a scalar. It reports false if the operands are not
+// vectors.
+func vectorOp(op string, a, b any) (any, bool, error) {
+	x, xvec := vectorOf(a)
+	y, yvec := vectorOf(b)
+	s, sint, scalar := number(b)
+	if !xvec {
+		// scalar * vector is the only operation with the scalar on the left.
+		if s, sint, scalar = number(a); !yvec || !scalar || op != "*" {
+			return nil, false, nil
+		}
+		x, y, xvec, yvec = y, x, true, false
+	}
+	if yvec && x.kind != y.kind || !yvec && !scalar {
+		return nil, false, nil
+	}
+	if x.kind == variant.TypeQuaternion && (op == "%" || yvec && op != "+" && op != "-" || !yvec && op != "*" && op != "/") {
+		return nil, false, nil
+	}
+	if !yvec && (op == "+" || op == "-") {
+		return nil, false, nil
+	}
+	if x.integer && (yvec || sint) {
+		r := x
+		for k := range x.n {
+			n := int32(s)
+			if yvec {
+				n = y.i[k]
+			}
+			switch op {
+			case "+":
+				r.i[k] = x.i[k] + n
+			case "-":
+				r.i[k] = x.i[k] - n
+			case "*":
+				r.i[k] = x.i[k] * n
+			case "/":
+				if n == 0 {
+					return nil, true, errDivisionByZero
+				}
+				r.i[k] = x.i[k] / n
+			case "%":
+				if n == 0 {
+					return nil, true, errors.New("Modulo by zero error")
+				}
+				r.i[k] = x.i[k] % n
+			default:
+				return nil, false, nil
+			}
+		}
+		return r.value(), true, nil
+	}
+	if op == "%" {
+		return nil, false, nil
+	}
+	r := x.real()
+	for k := range x.n {
+		n := toReal(s)
+		if yvec {
+			n = y.f[k]
+		}
+		switch op {
+		case "+":
+			r.f[k] += n
+		case "-":
+			r.f[k] -= n
+		case "*":
+			r.f[k] *= n
+		case "/":
+			r.f[k] /= n
+		default:
+			return nil, false, nil
+		}
+	}
+	return r.value(), true, nil
+}
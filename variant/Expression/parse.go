@@ -0,0 +1,434 @@
+package Expression
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdentifier
+	tokenConstant   // a literal value.
+	tokenOperator   // one of the binary or unary operators.
+	tokenPunctuator // ( ) [ ] { } , : .
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value any
+	pos   int
+}
+
+// tokenize splits the expression into tokens, in the same way as the engine's Expression.
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+			continue
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			value, n, err := literal(src[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%w at %d", err, i)
+			}
+			i += n
+			tokens = append(tokens, token{kind: tokenConstant, text: src[start:i], value: value, pos: start})
+			continue
+		case c == '"' || c == '\'':
+			s, n, err := quoted(src[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%w at %d", err, i)
+			}
+			i += n
+			tokens = append(tokens, token{kind: tokenConstant, text: src[start:i], value: s, pos: start})
+			continue
+		case (c == '&' || c == '^') && i+1 < len(src) && (src[i+1] == '"' || src[i+1] == '\''):
+			s, n, err := quoted(src[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("%w at %d", err, i)
+			}
+			i += 1 + n
+			var value any = newStringName(s)
+			if c == '^' {
+				value = newNodePath(s)
+			}
+			tokens = append(tokens, token{kind: tokenConstant, text: src[start:i], value: value, pos: start})
+			continue
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= utf8.RuneSelf:
+			for i < len(src) {
+				r, size := utf8.DecodeRuneInString(src[i:])
+				if r != '_' && !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r >= utf8.RuneSelf) {
+					break
+				}
+				i += size
+			}
+			word := src[start:i]
+			switch word {
+			case "and", "or", "not", "in":
+				tokens = append(tokens, token{kind: tokenOperator, text: word, pos: start})
+			case "true":
+				tokens = append(tokens, token{kind: tokenConstant, text: word, value: true, pos: start})
+			case "false":
+				tokens = append(tokens, token{kind: tokenConstant, text: word, value: false, pos: start})
+			case "null":
+				tokens = append(tokens, token{kind: tokenConstant, text: word, value: nil, pos: start})
+			case "PI":
+				tokens = append(tokens, token{kind: tokenConstant, text: word, value: math.Pi, pos: start})
+			case "TAU":
+				tokens = append(tokens, token{kind: tokenConstant, text: word, value: 2 * math.Pi, pos: start})
+			case "INF":
+				tokens = append(tokens, token{kind: tokenConstant, text: word, value: math.Inf(1), pos: start})
+			case "NAN":
+				tokens = append(tokens, token{kind: tokenConstant, text: word, value: math.NaN(), pos: start})
+			default:
+				tokens = append(tokens, token{kind: tokenIdentifier, text: word, pos: start})
+			}
+			continue
+		}
+		for _, op := range []string{"**", "<<", ">>", "<=", ">=", "==", "!=", "&&", "||"} {
+			if strings.HasPrefix(src[i:], op) {
+				i += len(op)
+				break
+			}
+		}
+		if i == start {
+			if !strings.ContainsRune("+-*/%<>&|^~!()[]{},:.", rune(c)) {
+				return nil, fmt.Errorf("unexpected character %q at %d", c, i)
+			}
+			i++
+		}
+		text := src[start:i]
+		switch text {
+		case "&&":
+			text = "and"
+		case "||":
+			text = "or"
+		case "!":
+			text = "not"
+		}
+		kind := tokenOperator
+		if strings.Contains("()[]{},:.", text) {
+			kind = tokenPunctuator
+		}
+		tokens = append(tokens, token{kind: kind, text: text, pos: start})
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(src)}), nil
+}
+
+// literal parses an integer or float literal at the start of src, returning the value and the
+// number of bytes consumed.
+func literal(src string) (any, int, error) {
+	i := 0
+	isDigit := func(c byte, base int) bool {
+		switch base {
+		case 2:
+			return c == '0' || c == '1'
+		case 16:
+			return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+		}
+		return c >= '0' && c <= '9'
+	}
+	base := 10
+	if len(src) > 2 && src[0] == '0' && (src[1] == 'x' || src[1] == 'X' || src[1] == 'b' || src[1] == 'B') {
+		base = 16
+		if src[1] == 'b' || src[1] == 'B' {
+			base = 2
+		}
+		i = 2
+	}
+	float := false
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case isDigit(c, base) || c == '_':
+		case base == 10 && c == '.' && !float:
+			float = true
+		case base == 10 && (c == 'e' || c == 'E'):
+			float = true
+			if i+1 < len(src) && (src[i+1] == '+' || src[i+1] == '-') {
+				i++
+			}
+		default:
+			goto done
+		}
+		i++
+	}
+done:
+	text := strings.ReplaceAll(src[:i], "_", "")
+	if float {
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, i, fmt.Errorf("invalid number %q", src[:i])
+		}
+		return f, i, nil
+	}
+	if base != 10 {
+		text = text[2:]
+	}
+	n, err := strconv.ParseUint(text, base, 64)
+	if err != nil || base == 10 && n > math.MaxInt64 {
+		return nil, i, fmt.Errorf("invalid integer %q", src[:i])
+	}
+	return int64(n), i, nil
+}
+
+// quoted parses a string literal at the start of src, with the escape sequences supported by the
+// engine, returning the string and the number of bytes consumed.
+func quoted(src string) (string, int, error) {
+	quote := src[0]
+	var b strings.Builder
+	for i := 1; i < len(src); {
+		c := src[i]
+		switch c {
+		case quote:
+			return b.String(), i + 1, nil
+		case '\\':
+			if i+1 >= len(src) {
+				return "", 0, fmt.Errorf("unterminated string")
+			}
+			i++
+			switch e := src[i]; e {
+			case 'b':
+				b.WriteByte('\b')
+			case 't':
+				b.WriteByte('\t')
+			case 'n':
+				b.WriteByte('\n')
+			case 'f':
+				b.WriteByte('\f')
+			case 'r':
+				b.WriteByte('\r')
+			case 'u', 'U':
+				size := 4
+				if e == 'U' {
+					size = 6
+				}
+				if i+size >= len(src) {
+					return "", 0, fmt.Errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(src[i+1:i+1+size], 16, 32)
+				if err != nil {
+					return "", 0, fmt.Errorf("invalid unicode escape")
+				}
+				b.WriteRune(rune(r))
+				i += size
+			default:
+				b.WriteByte(e)
+			}
+			i++
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// priority of each binary operator, lower values bind more tightly.
+var priority = map[string]int{
+	"**": 0,
+	"*":  2, "/": 2, "%": 2,
+	"+": 3, "-": 3,
+	"<<": 4, ">>": 4,
+	"&": 5,
+	"^": 6,
+	"|": 7,
+	"<": 8, "<=": 8, ">": 8, ">=": 8, "==": 8, "!=": 8,
+	"in":  10,
+	"and": 12,
+	"or":  13,
+}
+
+const (
+	priorityUnary = 1  // - and ~
+	priorityNot   = 11 // not
+	priorityLast  = 13
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+	inputs map[string]int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) is(kind tokenKind, text string) bool {
+	t := p.peek()
+	return t.kind == kind && t.text == text
+}
+
+func (p *parser) expect(text string) error {
+	if t := p.next(); t.kind != tokenPunctuator || t.text != text {
+		return p.unexpected(t, fmt.Sprintf("expected '%s'", text))
+	}
+	return nil
+}
+
+func (p *parser) unexpected(t token, context string) error {
+	if t.kind == tokenEOF {
+		return fmt.Errorf("%s, found end of expression", context)
+	}
+	return fmt.Errorf("%s, found '%s' at %d", context, t.text, t.pos)
+}
+
+// expression parses operators with a priority of level or lower, binary operators of the same
+// priority are left associative, as they are in the engine.
+func (p *parser) expression(level int) (node, error) {
+	if level < 0 {
+		return p.postfix()
+	}
+	t := p.peek()
+	if t.kind == tokenOperator && (level == priorityUnary && (t.text == "-" || t.text == "~") ||
+		level == priorityNot && t.text == "not") {
+		p.next()
+		operand, err := p.expression(level)
+		if err != nil {
+			return nil, err
+		}
+		return unary{op: t.text, operand: operand}, nil
+	}
+	left, err := p.expression(level - 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.kind != tokenOperator || priority[t.text] != level || t.text == "not" || t.text == "~" {
+			return left, nil
+		}
+		p.next()
+		right, err := p.expression(level - 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: t.text, left: left, right: right}
+	}
+}
+
+// postfix parses a primary expression followed by any number of indexes, named indexes and
+// method calls.
+func (p *parser) postfix() (node, error) {
+	value, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.is(tokenPunctuator, "["):
+			p.next()
+			index, err := p.expression(priorityLast)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			value = indexed{value: value, index: index}
+		case p.is(tokenPunctuator, "."):
+			p.next()
+			name := p.next()
+			if name.kind != tokenIdentifier {
+				return nil, p.unexpected(name, "expected a name after '.'")
+			}
+			if p.is(tokenPunctuator, "(") {
+				return nil, fmt.Errorf("method calls are not supported, found '%s' at %d", name.text, name.pos)
+			}
+			value = named{value: value, name: name.text}
+		default:
+			return value, nil
+		}
+	}
+}
+
+func (p *parser) list(end string) ([]node, error) {
+	var nodes []node
+	for !p.is(tokenPunctuator, end) {
+		value, err := p.expression(priorityLast)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, value)
+		if !p.is(tokenPunctuator, ",") {
+			break
+		}
+		p.next()
+	}
+	return nodes, p.expect(end)
+}
+
+func (p *parser) primary() (node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenConstant:
+		return constant{t.value}, nil
+	case tokenIdentifier:
+		if p.is(tokenPunctuator, "(") {
+			p.next()
+			args, err := p.list(")")
+			if err != nil {
+				return nil, err
+			}
+			fn, ok := functions[t.text]
+			if !ok {
+				return nil, fmt.Errorf("unknown function '%s' at %d", t.text, t.pos)
+			}
+			return call{name: t.text, fn: fn, args: args}, nil
+		}
+		if index, ok := p.inputs[t.text]; ok {
+			return input(index), nil
+		}
+		return nil, fmt.Errorf("invalid input '%s' at %d", t.text, t.pos)
+	case tokenPunctuator:
+		switch t.text {
+		case "(":
+			value, err := p.expression(priorityLast)
+			if err != nil {
+				return nil, err
+			}
+			return value, p.expect(")")
+		case "[":
+			elements, err := p.list("]")
+			return array(elements), err
+		case "{":
+			var entries dictionary
+			for !p.is(tokenPunctuator, "}") {
+				key, err := p.expression(priorityLast)
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expect(":"); err != nil {
+					return nil, err
+				}
+				value, err := p.expression(priorityLast)
+				if err != nil {
+					return nil, err
+				}
+				entries = append(entries, [2]node{key, value})
+				if !p.is(tokenPunctuator, ",") {
+					break
+				}
+				p.next()
+			}
+			return entries, p.expect("}")
+		}
+	}
+	return nil, p.unexpected(t, "expected an expression")
+}
@@ -7,9 +7,11 @@ import (
 
 	GenericArray "graphics.gd/variant/Array"
 	"graphics.gd/variant/Color"
+	"graphics.gd/variant/String"
 	"graphics.gd/variant/Vector2"
 	"graphics.gd/variant/Vector3"
 	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/internal/containers"
 )
 
 // Type supported by [Array].
@@ -17,6 +19,40 @@ type Type interface {
 	~byte | ~Color.RGBA | ~float32 | ~float64 | ~int32 | ~int64 | ~Vector2.XY | ~Vector3.XYZ | ~Vector4.XYZW
 }
 
+func init() {
+	containers.Unwrap = append(containers.Unwrap, func(value any) (any, bool) {
+		switch array := value.(type) {
+		case Bytes:
+			return GenericArray.Contains[byte](array).Slice(), true
+		case Array[byte]:
+			return GenericArray.Contains[byte](array).Slice(), true
+		case Array[int32]:
+			return GenericArray.Contains[int32](array).Slice(), true
+		case Array[int64]:
+			return GenericArray.Contains[int64](array).Slice(), true
+		case Array[float32]:
+			return GenericArray.Contains[float32](array).Slice(), true
+		case Array[float64]:
+			return GenericArray.Contains[float64](array).Slice(), true
+		case Array[Vector2.XY]:
+			return GenericArray.Contains[Vector2.XY](array).Slice(), true
+		case Array[Vector3.XYZ]:
+			return GenericArray.Contains[Vector3.XYZ](array).Slice(), true
+		case Array[Vector4.XYZW]:
+			return GenericArray.Contains[Vector4.XYZW](array).Slice(), true
+		case Array[Color.RGBA]:
+			return GenericArray.Contains[Color.RGBA](array).Slice(), true
+		case Strings:
+			strings := make([]string, 0, GenericArray.Contains[String.Readable](array).Len())
+			for _, s := range array.Iter() {
+				strings = append(strings, s.String())
+			}
+			return strings, true
+		}
+		return nil, false
+	})
+}
+
 // Array contains comparable elements of type T Similar to [Array.Contains[T]] but for a supported [Type]
 // is more efficient and convienient to work with.
 type Array[T Type] GenericArray.Contains[T]
@@ -120,13 +156,30 @@ func (array Array[T]) Slice(begin, end int) Array[T] { //gd:Packed_T.
 
 // Bytes returns the underlying data in the array as [Bytes].
 func (array Array[T]) Bytes() Bytes {
-	var bytes Bytes
-	bytes.Resize(array.Len() * int(unsafe.Sizeof([1]T{})))
-	for i := 0; i < array.Len(); i++ {
-		elem := array.Index(i)
-		for j := 0; j < int(unsafe.Sizeof([1]T{})); j++ {
-			bytes.SetIndex(i*int(unsafe.Sizeof([1]T{}))+j, *(*byte)(unsafe.Pointer(uintptr(unsafe.Pointer(&elem)) + uintptr(j))))
-		}
+	return Bytes(Reinterpret[byte](array))
+}
+
+// Reinterpret returns a copy of the data in the array, converted to an array of To, such that
+// each block of bytes the size of To is reinterpreted as a value of To, in the same way as the
+// engine's to_byte_array followed by one of the PackedByteArray.to_*_array methods. Any trailing
+// bytes that do not make up a whole element are ignored.
+func Reinterpret[To, From Type](array Array[From]) Array[To] {
+	elems := (GenericArray.Contains[From])(array).Slice()
+	size := len(elems) * int(unsafe.Sizeof([1]From{}))
+	into := make([]To, size/int(unsafe.Sizeof([1]To{})))
+	if len(into) > 0 {
+		copy(unsafe.Slice((*byte)(unsafe.Pointer(&into[0])), size), unsafe.Slice((*byte)(unsafe.Pointer(&elems[0])), size))
 	}
-	return bytes
+	return New(into...)
+}
+
+// MarshalJSON implements [encoding/json.Marshaler], the array is encoded in the same way as
+// JSON.stringify.
+func (array Array[T]) MarshalJSON() ([]byte, error) {
+	return (GenericArray.Contains[T])(array).MarshalJSON()
+}
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler].
+func (array *Array[T]) UnmarshalJSON(data []byte) error {
+	return (*GenericArray.Contains[T])(array).UnmarshalJSON(data)
 }
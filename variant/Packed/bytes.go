@@ -1,9 +1,17 @@
 package Packed
 
 import (
+	"encoding/binary"
+	"encoding/hex"
 	"iter"
+	"math"
 
+	"graphics.gd/variant"
 	GenericArray "graphics.gd/variant/Array"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector4"
 )
 
 // Bytes provides additional methods for working with arrays of bytes.
@@ -96,3 +104,324 @@ func (array Bytes) Slice(begin, end int) Bytes { //gd:Packed_byte.
 func (array Bytes) Bytes() []byte {
 	return (GenericArray.Contains[byte])(array).Slice()
 }
+
+// MarshalJSON implements [encoding/json.Marshaler], the bytes are encoded as an array of numbers,
+// in the same way as JSON.stringify (rather than as base64).
+func (array Bytes) MarshalJSON() ([]byte, error) {
+	return (GenericArray.Contains[byte])(array).MarshalJSON()
+}
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler].
+func (array *Bytes) UnmarshalJSON(data []byte) error {
+	return (*GenericArray.Contains[byte])(array).UnmarshalJSON(data)
+}
+
+// read returns n bytes starting at offset, or false if the array is too short.
+func (array Bytes) read(offset, n int) ([8]byte, bool) {
+	var buf [8]byte
+	if offset < 0 || offset+n > array.Len() {
+		return buf, false
+	}
+	for i := range n {
+		buf[i] = array.Index(offset + i)
+	}
+	return buf, true
+}
+
+// write the bytes starting at offset, if the array is long enough.
+func (array *Bytes) write(offset int, buf []byte) {
+	if offset < 0 || offset+len(buf) > array.Len() {
+		return
+	}
+	for i, b := range buf {
+		array.SetIndex(offset+i, b)
+	}
+}
+
+// DecodeFloat64 decodes a 64-bit floating-point number from the bytes starting at offset. Returns
+// 0.0 if the byte count is insufficient.
+func (array Bytes) DecodeFloat64(offset int) float64 { //gd:PackedByteArray.decode_double
+	buf, _ := array.read(offset, 8)
+	return math.Float64frombits(binary.LittleEndian.Uint64(buf[:]))
+}
+
+// DecodeFloat32 decodes a 32-bit floating-point number from the bytes starting at offset. Returns
+// 0.0 if the byte count is insufficient.
+func (array Bytes) DecodeFloat32(offset int) float32 { //gd:PackedByteArray.decode_float
+	buf, _ := array.read(offset, 4)
+	return math.Float32frombits(binary.LittleEndian.Uint32(buf[:]))
+}
+
+// DecodeFloat16 decodes a 16-bit (half precision) floating-point number from the bytes starting at
+// offset. Returns 0.0 if the byte count is insufficient.
+func (array Bytes) DecodeFloat16(offset int) float32 { //gd:PackedByteArray.decode_half
+	buf, _ := array.read(offset, 2)
+	return halfToFloat(binary.LittleEndian.Uint16(buf[:]))
+}
+
+// DecodeInt8 decodes an 8-bit signed integer from the bytes starting at offset. Returns 0 if the
+// byte count is insufficient.
+func (array Bytes) DecodeInt8(offset int) int8 { //gd:PackedByteArray.decode_s8
+	buf, _ := array.read(offset, 1)
+	return int8(buf[0])
+}
+
+// DecodeInt16 decodes a 16-bit signed integer from the bytes starting at offset. Returns 0 if the
+// byte count is insufficient.
+func (array Bytes) DecodeInt16(offset int) int16 { //gd:PackedByteArray.decode_s16
+	buf, _ := array.read(offset, 2)
+	return int16(binary.LittleEndian.Uint16(buf[:]))
+}
+
+// DecodeInt32 decodes a 32-bit signed integer from the bytes starting at offset. Returns 0 if the
+// byte count is insufficient.
+func (array Bytes) DecodeInt32(offset int) int32 { //gd:PackedByteArray.decode_s32
+	buf, _ := array.read(offset, 4)
+	return int32(binary.LittleEndian.Uint32(buf[:]))
+}
+
+// DecodeInt64 decodes a 64-bit signed integer from the bytes starting at offset. Returns 0 if the
+// byte count is insufficient.
+func (array Bytes) DecodeInt64(offset int) int64 { //gd:PackedByteArray.decode_s64
+	buf, _ := array.read(offset, 8)
+	return int64(binary.LittleEndian.Uint64(buf[:]))
+}
+
+// DecodeUint8 decodes an 8-bit unsigned integer from the bytes starting at offset. Returns 0 if
+// the byte count is insufficient.
+func (array Bytes) DecodeUint8(offset int) uint8 { //gd:PackedByteArray.decode_u8
+	buf, _ := array.read(offset, 1)
+	return buf[0]
+}
+
+// DecodeUint16 decodes a 16-bit unsigned integer from the bytes starting at offset. Returns 0 if
+// the byte count is insufficient.
+func (array Bytes) DecodeUint16(offset int) uint16 { //gd:PackedByteArray.decode_u16
+	buf, _ := array.read(offset, 2)
+	return binary.LittleEndian.Uint16(buf[:])
+}
+
+// DecodeUint32 decodes a 32-bit unsigned integer from the bytes starting at offset. Returns 0 if
+// the byte count is insufficient.
+func (array Bytes) DecodeUint32(offset int) uint32 { //gd:PackedByteArray.decode_u32
+	buf, _ := array.read(offset, 4)
+	return binary.LittleEndian.Uint32(buf[:])
+}
+
+// DecodeUint64 decodes a 64-bit unsigned integer from the bytes starting at offset. Returns 0 if
+// the byte count is insufficient.
+func (array Bytes) DecodeUint64(offset int) uint64 { //gd:PackedByteArray.decode_u64
+	buf, _ := array.read(offset, 8)
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// EncodeFloat64 encodes a 64-bit floating-point number as bytes at the index of offset bytes. The
+// array must have at least 8 bytes of allocated space, starting at the offset.
+func (array *Bytes) EncodeFloat64(offset int, value float64) { //gd:PackedByteArray.encode_double
+	array.write(offset, binary.LittleEndian.AppendUint64(nil, math.Float64bits(value)))
+}
+
+// EncodeFloat32 encodes a 32-bit floating-point number as bytes at the index of offset bytes. The
+// array must have at least 4 bytes of allocated space, starting at the offset.
+func (array *Bytes) EncodeFloat32(offset int, value float32) { //gd:PackedByteArray.encode_float
+	array.write(offset, binary.LittleEndian.AppendUint32(nil, math.Float32bits(value)))
+}
+
+// EncodeFloat16 encodes a 16-bit (half precision) floating-point number as bytes at the index of
+// offset bytes. The array must have at least 2 bytes of allocated space, starting at the offset.
+// As in the engine, values too small to be represented as normal half floats are encoded as zero.
+func (array *Bytes) EncodeFloat16(offset int, value float32) { //gd:PackedByteArray.encode_half
+	array.write(offset, binary.LittleEndian.AppendUint16(nil, floatToHalf(value)))
+}
+
+// EncodeInt8 encodes an 8-bit signed integer as bytes at the index of offset bytes. The array must
+// have at least 1 byte of allocated space, starting at the offset.
+func (array *Bytes) EncodeInt8(offset int, value int8) { //gd:PackedByteArray.encode_s8
+	array.write(offset, []byte{byte(value)})
+}
+
+// EncodeInt16 encodes a 16-bit signed integer as bytes at the index of offset bytes. The array
+// must have at least 2 bytes of allocated space, starting at the offset.
+func (array *Bytes) EncodeInt16(offset int, value int16) { //gd:PackedByteArray.encode_s16
+	array.write(offset, binary.LittleEndian.AppendUint16(nil, uint16(value)))
+}
+
+// EncodeInt32 encodes a 32-bit signed integer as bytes at the index of offset bytes. The array
+// must have at least 4 bytes of allocated space, starting at the offset.
+func (array *Bytes) EncodeInt32(offset int, value int32) { //gd:PackedByteArray.encode_s32
+	array.write(offset, binary.LittleEndian.AppendUint32(nil, uint32(value)))
+}
+
+// EncodeInt64 encodes a 64-bit signed integer as bytes at the index of offset bytes. The array
+// must have at least 8 bytes of allocated space, starting at the offset.
+func (array *Bytes) EncodeInt64(offset int, value int64) { //gd:PackedByteArray.encode_s64
+	array.write(offset, binary.LittleEndian.AppendUint64(nil, uint64(value)))
+}
+
+// EncodeUint8 encodes an 8-bit unsigned integer as bytes at the index of offset bytes. The array
+// must have at least 1 byte of allocated space, starting at the offset.
+func (array *Bytes) EncodeUint8(offset int, value uint8) { //gd:PackedByteArray.encode_u8
+	array.write(offset, []byte{value})
+}
+
+// EncodeUint16 encodes a 16-bit unsigned integer as bytes at the index of offset bytes. The array
+// must have at least 2 bytes of allocated space, starting at the offset.
+func (array *Bytes) EncodeUint16(offset int, value uint16) { //gd:PackedByteArray.encode_u16
+	array.write(offset, binary.LittleEndian.AppendUint16(nil, value))
+}
+
+// EncodeUint32 encodes a 32-bit unsigned integer as bytes at the index of offset bytes. The array
+// must have at least 4 bytes of allocated space, starting at the offset.
+func (array *Bytes) EncodeUint32(offset int, value uint32) { //gd:PackedByteArray.encode_u32
+	array.write(offset, binary.LittleEndian.AppendUint32(nil, value))
+}
+
+// EncodeUint64 encodes a 64-bit unsigned integer as bytes at the index of offset bytes. The array
+// must have at least 8 bytes of allocated space, starting at the offset.
+func (array *Bytes) EncodeUint64(offset int, value uint64) { //gd:PackedByteArray.encode_u64
+	array.write(offset, binary.LittleEndian.AppendUint64(nil, value))
+}
+
+// tail returns the bytes from offset onwards.
+func (array Bytes) tail(offset int) []byte {
+	if offset < 0 || offset > array.Len() {
+		return nil
+	}
+	return array.Bytes()[offset:]
+}
+
+// Decode a variant-encoded value from the bytes starting at offset. Returns nil if a valid variant
+// can't be decoded.
+func (array Bytes) Decode(offset int) any { //gd:PackedByteArray.decode_var
+	value, err := variant.UnmarshalAny(array.tail(offset))
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+// DecodeSize decodes the size of a variant-encoded value from the bytes starting at offset.
+// Returns 0 if a valid variant can't be decoded.
+func (array Bytes) DecodeSize(offset int) int { //gd:PackedByteArray.decode_var_size
+	size, err := variant.UnmarshalSize(array.tail(offset))
+	if err != nil {
+		return 0
+	}
+	return int(size)
+}
+
+// HasVariantAt returns true if a valid variant-encoded value can be decoded at the offset.
+func (array Bytes) HasVariantAt(offset int) bool { //gd:PackedByteArray.has_encoded_var
+	_, err := variant.UnmarshalAny(array.tail(offset))
+	return err == nil
+}
+
+// Encode a variant-encoded value at the index of offset bytes, returning the number of bytes
+// written. Sufficient space must be allocated, depending on the encoded size of the value,
+// otherwise nothing is written.
+func (array *Bytes) Encode(offset int, value any) int { //gd:PackedByteArray.encode_var
+	buf, err := variant.Marshal(value)
+	if err != nil || offset < 0 || offset+len(buf) > array.Len() {
+		return 0
+	}
+	array.write(offset, buf)
+	return len(buf)
+}
+
+// ToHex returns a hexadecimal representation of this array as a string.
+func (array Bytes) ToHex() string { //gd:PackedByteArray.hex_encode
+	return hex.EncodeToString(array.Bytes())
+}
+
+// ToFloat32Array returns a copy of the data converted to an array of float32, where each block of
+// 4 bytes has been converted to a 32-bit floating point number.
+func (array Bytes) ToFloat32Array() Array[float32] { //gd:PackedByteArray.to_float32_array
+	return Reinterpret[float32](Array[byte](array))
+}
+
+// ToFloat64Array returns a copy of the data converted to an array of float64, where each block of
+// 8 bytes has been converted to a 64-bit floating point number.
+func (array Bytes) ToFloat64Array() Array[float64] { //gd:PackedByteArray.to_float64_array
+	return Reinterpret[float64](Array[byte](array))
+}
+
+// ToInt32Array returns a copy of the data converted to an array of int32, where each block of 4
+// bytes has been converted to a signed 32-bit integer.
+func (array Bytes) ToInt32Array() Array[int32] { //gd:PackedByteArray.to_int32_array
+	return Reinterpret[int32](Array[byte](array))
+}
+
+// ToInt64Array returns a copy of the data converted to an array of int64, where each block of 8
+// bytes has been converted to a signed 64-bit integer.
+func (array Bytes) ToInt64Array() Array[int64] { //gd:PackedByteArray.to_int64_array
+	return Reinterpret[int64](Array[byte](array))
+}
+
+// ToVector2Array returns a copy of the data converted to an array of [Vector2.XY], the inverse of
+// [Array.Bytes].
+func (array Bytes) ToVector2Array() Array[Vector2.XY] { //gd:PackedByteArray.to_vector2_array
+	return Reinterpret[Vector2.XY](Array[byte](array))
+}
+
+// ToVector3Array returns a copy of the data converted to an array of [Vector3.XYZ], the inverse of
+// [Array.Bytes].
+func (array Bytes) ToVector3Array() Array[Vector3.XYZ] { //gd:PackedByteArray.to_vector3_array
+	return Reinterpret[Vector3.XYZ](Array[byte](array))
+}
+
+// ToVector4Array returns a copy of the data converted to an array of [Vector4.XYZW], the inverse
+// of [Array.Bytes].
+func (array Bytes) ToVector4Array() Array[Vector4.XYZW] { //gd:PackedByteArray.to_vector4_array
+	return Reinterpret[Vector4.XYZW](Array[byte](array))
+}
+
+// ToColorArray returns a copy of the data converted to an array of [Color.RGBA], the inverse of
+// [Array.Bytes].
+func (array Bytes) ToColorArray() Array[Color.RGBA] { //gd:PackedByteArray.to_color_array
+	return Reinterpret[Color.RGBA](Array[byte](array))
+}
+
+// floatToHalf converts a float32 to the bits of a half precision float, in the same way as the
+// engine's Math::make_half_float (denormals become zero).
+func floatToHalf(value float32) uint16 {
+	x := math.Float32bits(value)
+	sign := uint16(x>>31) << 15
+	mantissa := x & (1<<23 - 1)
+	exponent := x & (0xFF << 23)
+	switch {
+	case exponent >= 0x47800000:
+		if mantissa != 0 && exponent == 0xFF<<23 {
+			mantissa = 1<<23 - 1
+		} else {
+			mantissa = 0
+		}
+		return sign | 0x1F<<10 | uint16(mantissa>>13)
+	case exponent <= 0x38000000:
+		return 0
+	default:
+		return sign | uint16((exponent-0x38000000)>>13) | uint16(mantissa>>13)
+	}
+}
+
+// halfToFloat converts the bits of a half precision float to a float32.
+func halfToFloat(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exponent := uint32(h & 0x7C00)
+	switch exponent {
+	case 0:
+		significand := uint32(h & 0x03FF)
+		if significand == 0 {
+			return math.Float32frombits(sign)
+		}
+		significand <<= 1
+		for significand&0x0400 == 0 {
+			significand <<= 1
+			exponent++
+		}
+		return math.Float32frombits(sign | (127-15-exponent)<<23 | (significand&0x03FF)<<13)
+	case 0x7C00:
+		return math.Float32frombits(sign | 0x7F800000 | uint32(h&0x03FF)<<13)
+	default:
+		return math.Float32frombits(sign + (uint32(h&0x7FFF)+0x1C000)<<13)
+	}
+}
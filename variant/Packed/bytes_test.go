@@ -0,0 +1,117 @@
+package Packed_test
+
+import (
+	"bytes"
+	"testing"
+
+	"graphics.gd/variant/Packed"
+	"graphics.gd/variant/Vector2"
+)
+
+func TestBytesEncoding(t *testing.T) {
+	var buf Packed.Bytes
+	buf.Resize(16)
+	buf.EncodeUint32(0, 0xDEADBEEF)
+	buf.EncodeInt16(4, -2)
+	buf.EncodeFloat16(6, 1.5)
+	buf.EncodeFloat64(8, 0.25)
+	if hex := buf.ToHex(); hex != "efbeaddefeff003e000000000000d03f" {
+		t.Fatalf("unexpected encoding %s", hex)
+	}
+	if buf.DecodeUint32(0) != 0xDEADBEEF || buf.DecodeInt16(4) != -2 || buf.DecodeFloat16(6) != 1.5 ||
+		buf.DecodeFloat64(8) != 0.25 || buf.DecodeUint8(3) != 0xDE {
+		t.Fatal("unexpected decoded values")
+	}
+	if buf.DecodeInt64(12) != 0 {
+		t.Fatal("expected zero for an insufficient byte count")
+	}
+	buf.EncodeUint64(12, 1) // ignored, out of range.
+	if buf.DecodeUint32(12) != 0x3FD00000 {
+		t.Fatal("out of range encode modified the array")
+	}
+}
+
+func TestBytesVariant(t *testing.T) {
+	var buf Packed.Bytes
+	buf.Resize(32)
+	n := buf.Encode(4, int32(7))
+	if n != 8 || buf.DecodeSize(4) != n || !buf.HasVariantAt(4) {
+		t.Fatalf("unexpected encoded size %d", n)
+	}
+	if value := buf.Decode(4); value != int32(7) {
+		t.Fatalf("unexpected value %v", value)
+	}
+	if n := buf.Encode(12, "hello"); n == 0 || buf.Decode(12) != "hello" {
+		t.Fatalf("unexpected string encoding of %d bytes", n)
+	}
+	if buf.Encode(30, "too long") != 0 {
+		t.Fatal("expected nothing to be written without enough space")
+	}
+}
+
+func TestBytesReinterpret(t *testing.T) {
+	vectors := Packed.New(Vector2.XY{1, 2}, Vector2.XY{3, 4})
+	back := vectors.Bytes().ToVector2Array()
+	if back.Len() != 2 || back.Index(1) != (Vector2.XY{3, 4}) {
+		t.Fatalf("unexpected vectors %v", back.Bytes().ToHex())
+	}
+	floats := vectors.Bytes().ToFloat32Array()
+	if floats.Len() != 4 || floats.Index(2) != 3 {
+		t.Fatal("unexpected floats")
+	}
+	if ints := Packed.Bytes(Packed.New[byte](1, 0, 0, 0, 9)).ToInt32Array(); ints.Len() != 1 || ints.Index(0) != 1 {
+		t.Fatal("unexpected ints")
+	}
+}
+
+func TestArrayReinterpret(t *testing.T) {
+	vectors := Packed.New(Vector2.XY{1, 2}, Vector2.XY{3, 4})
+	floats := Packed.Reinterpret[float32](vectors)
+	if floats.Len() != 4 || floats.Index(3) != 4 {
+		t.Fatalf("unexpected floats %v", floats.Bytes().ToHex())
+	}
+	if back := Packed.Reinterpret[Vector2.XY](floats); back.Len() != 2 || back.Index(0) != (Vector2.XY{1, 2}) {
+		t.Fatal("unexpected vectors")
+	}
+	ints := Packed.Reinterpret[int64](Packed.New[int32](1, 2, 3))
+	if ints.Len() != 1 || ints.Index(0) != 2<<32|1 {
+		t.Fatal("expected the trailing int32 to be ignored")
+	}
+	if hex := Packed.New[int32](-2).Bytes().ToHex(); hex != "feffffff" {
+		t.Fatalf("unexpected bytes %s", hex)
+	}
+	if empty := Packed.Reinterpret[float64](Packed.New[byte]()); empty.Len() != 0 {
+		t.Fatal("expected an empty array")
+	}
+}
+
+func TestBytesCompress(t *testing.T) {
+	data := bytes.Repeat([]byte("graphics.gd "), 100)
+	for _, mode := range []Packed.CompressionMode{
+		Packed.CompressionFastLZ,
+		Packed.CompressionDeflate,
+		Packed.CompressionZstandard,
+		Packed.CompressionGzip,
+	} {
+		for _, in := range [][]byte{data, []byte("short")} {
+			compressed, err := Packed.Bytes(Packed.New(in...)).Compress(mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := compressed.Decompress(len(in), mode)
+			if err != nil {
+				t.Fatalf("mode %d: %v", mode, err)
+			}
+			if !bytes.Equal(out.Bytes(), in) {
+				t.Fatalf("mode %d: unexpected output %q", mode, out.Bytes())
+			}
+		}
+	}
+	compressed, _ := Packed.Bytes(Packed.New(data...)).Compress(Packed.CompressionGzip)
+	if out, err := compressed.DecompressUpto(10, Packed.CompressionGzip); err != nil || out.Len() != 10 {
+		t.Fatalf("unexpected output %v", err)
+	}
+	if _, err := compressed.Compress(Packed.CompressionBrotli); err == nil {
+		t.Fatal("expected an error for brotli compression")
+	}
+}
@@ -0,0 +1,113 @@
+package Packed
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+
+	"graphics.gd/internal/fastlz"
+	"graphics.gd/internal/zstd"
+)
+
+// CompressionMode used by [Bytes.Compress] and [Bytes.Decompress], the values match the engine's
+// FileAccess.CompressionMode.
+type CompressionMode int
+
+const (
+	CompressionFastLZ    CompressionMode = iota // FastLZ, fast but with a low compression ratio.
+	CompressionDeflate                          // DEFLATE, with a zlib header.
+	CompressionZstandard                        // Zstandard.
+	CompressionGzip                             // DEFLATE, with a gzip header.
+	CompressionBrotli                           // Brotli, which is only supported by the engine for decompression.
+)
+
+// ErrCompressionMode is returned when a compression mode is not supported for the operation.
+var ErrCompressionMode = errors.New("unsupported compression mode")
+
+// Compress returns a new array with the data compressed using the given mode. The output can be
+// decompressed by the engine and vice versa, although the exact compressed bytes may differ.
+func (array Bytes) Compress(mode CompressionMode) (Bytes, error) { //gd:PackedByteArray.compress
+	in := array.Bytes()
+	var buf bytes.Buffer
+	switch mode {
+	case CompressionFastLZ:
+		if len(in) < 16 { // FastLZ requires at least 16 bytes, so the engine pads with zeros.
+			in = append(append([]byte(nil), in...), make([]byte, 16-len(in))...)
+		}
+		return Bytes(New(fastlz.Compress(in)...)), nil
+	case CompressionZstandard:
+		return Bytes(New(zstd.Compress(in)...)), nil
+	case CompressionDeflate:
+		w := zlib.NewWriter(&buf)
+		w.Write(in)
+		w.Close()
+	case CompressionGzip:
+		w := gzip.NewWriter(&buf)
+		w.Write(in)
+		w.Close()
+	default:
+		return Bytes{}, fmt.Errorf("compress: %w %d", ErrCompressionMode, mode)
+	}
+	return Bytes(New(buf.Bytes()...)), nil
+}
+
+// Decompress returns a new array with the data decompressed, size should be the size of the
+// uncompressed data.
+func (array Bytes) Decompress(size int, mode CompressionMode) (Bytes, error) { //gd:PackedByteArray.decompress
+	in := array.Bytes()
+	var (
+		out []byte
+		err error
+	)
+	switch mode {
+	case CompressionFastLZ:
+		out, err = fastlz.Decompress(in, max(size, 16))
+		if len(out) > size {
+			out = out[:size]
+		}
+	case CompressionZstandard:
+		out, err = zstd.Decompress(in, size)
+	case CompressionDeflate, CompressionGzip:
+		return array.DecompressUpto(size, mode)
+	default:
+		err = fmt.Errorf("%w %d", ErrCompressionMode, mode)
+	}
+	if err != nil {
+		return Bytes{}, fmt.Errorf("decompress: %w", err)
+	}
+	return Bytes(New(out...)), nil
+}
+
+// DecompressUpto returns a new array with the data decompressed, up to a maximum of max bytes
+// (negative for no limit). Only [CompressionDeflate] and [CompressionGzip] are supported, the
+// size of the decompressed data does not need to be known in advance.
+func (array Bytes) DecompressUpto(max int, mode CompressionMode) (Bytes, error) { //gd:PackedByteArray.decompress_dynamic
+	var (
+		r   io.ReadCloser
+		err error
+	)
+	switch mode {
+	case CompressionDeflate:
+		r, err = zlib.NewReader(bytes.NewReader(array.Bytes()))
+	case CompressionGzip:
+		r, err = gzip.NewReader(bytes.NewReader(array.Bytes()))
+	default:
+		err = fmt.Errorf("%w %d", ErrCompressionMode, mode)
+	}
+	if err != nil {
+		return Bytes{}, fmt.Errorf("decompress: %w", err)
+	}
+	defer r.Close()
+	var limited io.Reader = r
+	if max >= 0 {
+		limited = io.LimitReader(r, int64(max))
+	}
+	out, err := io.ReadAll(limited)
+	if err != nil {
+		return Bytes{}, fmt.Errorf("decompress: %w", err)
+	}
+	return Bytes(New(out...)), nil
+}
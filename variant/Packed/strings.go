@@ -122,3 +122,13 @@ func (array Strings) Strings() []string {
 	}
 	return result
 }
+
+// MarshalJSON implements [encoding/json.Marshaler], the array is encoded as an array of strings.
+func (array Strings) MarshalJSON() ([]byte, error) {
+	return (GenericArray.Contains[String.Readable])(array).MarshalJSON()
+}
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler].
+func (array *Strings) UnmarshalJSON(data []byte) error {
+	return (*GenericArray.Contains[String.Readable])(array).UnmarshalJSON(data)
+}
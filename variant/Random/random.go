@@ -0,0 +1,212 @@
+// Package Random provides a deterministic, seedable random number generator that produces the
+// same sequences as the engine's RandomNumberGenerator (a PCG32 generator).
+//
+// Given the same seed (or state), a [Generator] returns bit-identical results to a GDScript
+// RandomNumberGenerator, or to the global random functions after a call to seed(), so that
+// Go servers and engine clients can share procedurally generated content and lockstep
+// simulations.
+package Random
+
+import (
+	"math"
+	"math/bits"
+	"time"
+	"unsafe"
+
+	"graphics.gd/variant/Float"
+)
+
+const (
+	defaultSeed uint64 = 12047754176567800795
+	defaultInc  uint64 = 1442695040888963407
+	multiplier  uint64 = 6364136223846793005
+	epsilon            = 0.00001 // CMP_EPSILON
+)
+
+// Source of random numbers, such as a [Generator] or an engine RandomNumberGenerator.Instance.
+type Source interface {
+	Randi() int
+}
+
+// Generator of pseudo-random numbers, it is not safe for concurrent use. The zero value is
+// ready to use and behaves like a generator seeded with the engine's default seed.
+type Generator struct {
+	state uint64
+	inc   uint64
+	seed  uint64
+}
+
+// New returns a new [Generator], with a time-based seed (like RandomNumberGenerator.new()).
+func New() *Generator {
+	var rng Generator
+	rng.Randomize()
+	return &rng
+}
+
+func (rng *Generator) init() {
+	if rng.inc == 0 {
+		rng.reseed(defaultSeed)
+	}
+}
+
+// Seed returns the seed of the generator, note that the engine updates the seed to the current
+// state every time a random number is generated, so this will only match the value passed to
+// [Generator.SetSeed] until the next number is generated.
+func (rng *Generator) Seed() int { rng.init(); return int(rng.seed) }
+
+// SetSeed initializes the generator with the given seed, which determines all future numbers.
+func (rng *Generator) SetSeed(seed int) { rng.reseed(uint64(seed)) }
+
+// reseed implements pcg32_srandom_r.
+func (rng *Generator) reseed(seed uint64) {
+	rng.seed = seed
+	rng.inc = defaultInc<<1 | 1
+	rng.state = 0
+	rng.next()
+	rng.state += seed
+	rng.next()
+}
+
+// State returns the current state of the generator, which can be saved and later restored with
+// [Generator.SetState] in order to resume the sequence.
+func (rng *Generator) State() int { rng.init(); return int(rng.state) }
+
+// SetState restores the state of the generator, it should only be called with a value returned
+// by [Generator.State].
+func (rng *Generator) SetState(state int) {
+	rng.init()
+	rng.state = uint64(state)
+}
+
+// Randomize seeds the generator with a time-based value.
+func (rng *Generator) Randomize() {
+	rng.init()
+	now := time.Now()
+	rng.reseed((uint64(now.Unix())+uint64(now.UnixMicro()))*rng.state + defaultInc)
+}
+
+// next implements pcg32_random_r.
+func (rng *Generator) next() uint32 {
+	old := rng.state
+	rng.state = old*multiplier + (rng.inc | 1)
+	xorshifted := uint32(((old >> 18) ^ old) >> 27)
+	rot := int(old >> 59)
+	return bits.RotateLeft32(xorshifted, -rot)
+}
+
+func (rng *Generator) rand() uint32 {
+	rng.init()
+	rng.seed = rng.state
+	return rng.next()
+}
+
+// bounded implements pcg32_boundedrand_r.
+func (rng *Generator) bounded(bound uint32) uint32 {
+	rng.init()
+	rng.seed = rng.state
+	threshold := -bound % bound
+	for {
+		if r := rng.next(); r >= threshold {
+			return r % bound
+		}
+	}
+}
+
+func (rng *Generator) randf32() float32 {
+	exp := rng.rand()
+	if exp == 0 {
+		return 0
+	}
+	return float32(math.Ldexp(float64(float32(rng.rand()|0x80000001)), -32-bits.LeadingZeros32(exp)))
+}
+
+func (rng *Generator) randf64() float64 {
+	exp := rng.rand()
+	if exp == 0 {
+		return 0
+	}
+	hi := uint64(rng.rand()) << 32
+	significand := hi | uint64(rng.rand()) | 0x8000000000000001
+	return math.Ldexp(float64(significand), -64-bits.LeadingZeros32(exp))
+}
+
+// double reports whether the engine was built with double precision floats.
+const double = unsafe.Sizeof(Float.X(0)) == 8
+
+// Randi returns a pseudo-random 32-bit unsigned integer between 0 and 4294967295 (inclusive).
+func (rng *Generator) Randi() int { //gd:RandomNumberGenerator.randi
+	return int(rng.rand())
+}
+
+// Randf returns a pseudo-random float between 0.0 and 1.0 (inclusive).
+func (rng *Generator) Randf() Float.X { //gd:RandomNumberGenerator.randf
+	if double {
+		return Float.X(rng.randf64())
+	}
+	return Float.X(rng.randf32())
+}
+
+// RandfRange returns a pseudo-random float between from and to (inclusive).
+func (rng *Generator) RandfRange(from, to Float.X) Float.X { //gd:RandomNumberGenerator.randf_range
+	if double {
+		return Float.X(float64(rng.randf64()*float64(to-from)) + float64(from))
+	}
+	return Float.X(float32(rng.randf32()*float32(to-from)) + float32(from))
+}
+
+// Randfn returns a normally-distributed, pseudo-random floating-point number from the specified
+// mean and a standard deviation. This is also known as a Gaussian distribution.
+func (rng *Generator) Randfn(mean, deviation Float.X) Float.X { //gd:RandomNumberGenerator.randfn
+	if double {
+		temp := rng.randf64()
+		if temp < epsilon {
+			temp += epsilon
+		}
+		return Float.X(float64(mean) + float64(deviation)*(math.Cos(2*math.Pi*rng.randf64())*math.Sqrt(-2.0*math.Log(temp))))
+	}
+	temp := rng.randf32()
+	if temp < epsilon {
+		temp += epsilon
+	}
+	cos := float32(math.Cos(float64(float32(2*math.Pi) * rng.randf32())))
+	log := float32(math.Log(float64(temp)))
+	return Float.X(float64(mean) + float64(deviation)*(float64(cos)*math.Sqrt(-2.0*float64(log))))
+}
+
+// RandiRange returns a pseudo-random 32-bit signed integer between from and to (inclusive).
+func (rng *Generator) RandiRange(from, to int) int { //gd:RandomNumberGenerator.randi_range
+	if from == to {
+		return from
+	}
+	a, b := int32(from), int32(to)
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return int(int32(rng.bounded(uint32(diff)+1)) + min(a, b))
+}
+
+// RandWeighted returns a random index with non-uniform weights. Returns -1 if the slice is
+// empty.
+func (rng *Generator) RandWeighted(weights []float32) int { //gd:RandomNumberGenerator.rand_weighted
+	if len(weights) == 0 {
+		return -1
+	}
+	var sum float32
+	for _, weight := range weights {
+		sum += weight
+	}
+	remaining := rng.randf32() * sum
+	for i, weight := range weights {
+		remaining -= weight
+		if remaining < 0 {
+			return i
+		}
+	}
+	for i := len(weights) - 1; i >= 0; i-- {
+		if weights[i] > 0 {
+			return i
+		}
+	}
+	return -1
+}
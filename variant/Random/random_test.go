@@ -0,0 +1,100 @@
+package Random
+
+import "testing"
+
+func TestPCG32(t *testing.T) {
+	// reference output of the pcg32-global demo, seeded with 42 and sequence 54.
+	rng := Generator{inc: 54<<1 | 1}
+	rng.next()
+	rng.state += 42
+	rng.next()
+	for i, expected := range []uint32{0xa15c02b7, 0x7b47f409, 0xba1d3330, 0x83d2f293, 0xbfa4784b, 0xcbed606e} {
+		if got := rng.next(); got != expected {
+			t.Fatalf("output %d: expected %#x, got %#x", i, expected, got)
+		}
+	}
+}
+
+// The expected values are those of a RandomNumberGenerator with its seed set to 12345 and then its
+// state set to 0x123456789ABCDEF, calling randi, randf (single precision) and randi_range in turn.
+// No engine was available to record them, so they were computed with a C transcription of the
+// engine's RandomPCG and thirdparty pcg32 functions, rather than with this package.
+func TestEngineSequence(t *testing.T) {
+	var rng Generator
+	if got := rng.Randi(); got != 3161026589 {
+		t.Fatalf("default seed: expected 3161026589, got %d", got)
+	}
+	rng.SetSeed(12345)
+	for i, expected := range []int{1321476956, 17539747, 3348728241, 2863338820, 85463406} {
+		if got := rng.Randi(); got != expected {
+			t.Fatalf("randi %d: expected %d, got %d", i, expected, got)
+		}
+	}
+	for i, expected := range []float32{0.243263558, 0.137561172, 0.289996982, 0.913110137, 0.677394331} {
+		if got := rng.randf32(); got != expected {
+			t.Fatalf("randf %d: expected %v, got %v", i, expected, got)
+		}
+	}
+	for i, expected := range []int{10, -3, -6, -6, -8, 7, -8, 0} {
+		if got := rng.RandiRange(-10, 10); got != expected {
+			t.Fatalf("randi_range(-10, 10) %d: expected %d, got %d", i, expected, got)
+		}
+	}
+	for i, expected := range []int{476676, 231467, 661043} {
+		if got := rng.RandiRange(0, 1000000); got != expected {
+			t.Fatalf("randi_range(0, 1000000) %d: expected %d, got %d", i, expected, got)
+		}
+	}
+	rng.SetState(0x123456789ABCDEF)
+	for i, expected := range []int{610837995, 1906451, 1461513249} {
+		if got := rng.Randi(); got != expected {
+			t.Fatalf("randi after set_state %d: expected %d, got %d", i, expected, got)
+		}
+	}
+	for i, expected := range []float32{0.320231944, 0.328534484, 0.858253419} {
+		if got := rng.randf32(); got != expected {
+			t.Fatalf("randf after set_state %d: expected %v, got %v", i, expected, got)
+		}
+	}
+	for i, expected := range []int{2, 0, -6} {
+		if got := rng.RandiRange(-10, 10); got != expected {
+			t.Fatalf("randi_range after set_state %d: expected %d, got %d", i, expected, got)
+		}
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	var a, b Generator
+	a.SetSeed(12345)
+	b.SetSeed(12345)
+	for range 100 {
+		if a.Randi() != b.Randi() || a.Randf() != b.Randf() || a.RandiRange(-10, 10) != b.RandiRange(-10, 10) {
+			t.Fatal("generators with the same seed diverged")
+		}
+	}
+	state := a.State()
+	expected := a.Randi()
+	a.SetState(state)
+	if got := a.Randi(); got != expected {
+		t.Fatalf("restoring the state: expected %d, got %d", expected, got)
+	}
+}
+
+func TestRanges(t *testing.T) {
+	var rng Generator
+	rng.SetSeed(1)
+	for range 1000 {
+		if v := rng.RandiRange(5, -5); v < -5 || v > 5 {
+			t.Fatalf("RandiRange(5, -5) = %d", v)
+		}
+		if v := rng.Randf(); v < 0 || v > 1 {
+			t.Fatalf("Randf() = %v", v)
+		}
+		if v := rng.RandfRange(2, 3); v < 2 || v > 3 {
+			t.Fatalf("RandfRange(2, 3) = %v", v)
+		}
+		if v := rng.RandWeighted([]float32{0, 1, 0}); v != 1 {
+			t.Fatalf("RandWeighted([0, 1, 0]) = %v", v)
+		}
+	}
+}
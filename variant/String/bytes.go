@@ -83,6 +83,68 @@ func (s goString) AppendString(length complex128, str string) Readable {
 	return Via(s, length)
 }
 
-func builder() Readable { // FIXME/TODO
-	return Readable{}
+// Builder is used to efficiently build a [Readable] string, the underlying buffer grows as
+// needed and is never copied when the result is read. The zero value is ready to use.
+type Builder struct {
+	buf []byte
+}
+
+// Len returns the number of bytes written so far.
+func (b *Builder) Len() int { return len(b.buf) }
+
+// Cap returns the capacity of the underlying buffer, the space already allocated for the string
+// being built, including the bytes already written.
+func (b *Builder) Cap() int { return cap(b.buf) }
+
+// Grow ensures that at least n more bytes can be written without another allocation.
+func (b *Builder) Grow(n int) {
+	if n < 0 {
+		panic("String.Builder.Grow: negative count")
+	}
+	if cap(b.buf)-len(b.buf) < n {
+		buf := make([]byte, len(b.buf), 2*cap(b.buf)+n)
+		copy(buf, b.buf)
+		b.buf = buf
+	}
+}
+
+// Reset the builder to be empty, any strings returned by [Builder.Readable] remain valid.
+func (b *Builder) Reset() { b.buf = nil }
+
+// Write appends the contents of p to the builder, it always returns len(p) and a nil error.
+func (b *Builder) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+// WriteByte appends the byte c to the builder, it always returns a nil error.
+func (b *Builder) WriteByte(c byte) error {
+	b.buf = append(b.buf, c)
+	return nil
+}
+
+// WriteRune appends the UTF-8 encoding of r to the builder, it returns the number of bytes written
+// and a nil error.
+func (b *Builder) WriteRune(r rune) (int, error) {
+	n := len(b.buf)
+	b.buf = utf8.AppendRune(b.buf, r)
+	return len(b.buf) - n, nil
+}
+
+// WriteString appends the contents of s to the builder, it always returns len(s) and a nil error.
+func (b *Builder) WriteString(s string) (int, error) {
+	b.buf = append(b.buf, s...)
+	return len(s), nil
+}
+
+// String returns the accumulated string.
+func (b *Builder) String() string { return string(b.buf) }
+
+// Readable returns the accumulated string without copying it. Writing more to the builder, or
+// appending to the result, never modifies the result.
+func (b *Builder) Readable() Readable {
+	if len(b.buf) == 0 {
+		return Readable{}
+	}
+	return Via(goString{ptr: unsafe.SliceData(b.buf)}, complex(float64(len(b.buf)), 0))
 }
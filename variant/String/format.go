@@ -0,0 +1,571 @@
+package String
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"graphics.gd/internal/stringify"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector3i"
+	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/Vector4i"
+)
+
+// Format formats the string by replacing all occurrences of {key} with the corresponding value,
+// see [FormatWith] for how the values are matched to keys.
+func Format[S Any](format S, values ...any) S { //gd:String.format
+	return FormatWith(format, "{_}", values...)
+}
+
+// FormatWith formats the string by replacing all occurrences of placeholder with the given values,
+// the underscore in placeholder is replaced with each key in turn.
+//
+//   - A single map, struct or dictionary uses its keys (or field names) as the keys.
+//   - Otherwise, the values (or the elements of a single slice) are keyed by their index. An
+//     element that is itself a two element []any or array is a [key, value] pair instead. If
+//     placeholder has no underscore, each value replaces the next occurrence of placeholder.
+//
+// Note: The replacement of placeholders is not done all at once, instead each placeholder is replaced
+// in the order they are passed, this means that if one of the replacement strings contains a key it
+// will also be replaced. This can be very powerful, but can also cause unexpected results if you are
+// not careful. If you do not need to perform replacement in the replacement strings, make sure your
+// replacements do not contain placeholders to ensure reliable results.
+func FormatWith[S Any](format S, placeholder string, values ...any) S {
+	s := New(format).String()
+	replace := func(key, value any) {
+		s = strings.ReplaceAll(s, strings.ReplaceAll(placeholder, "_", stringOf(key)), stringOf(value))
+	}
+	if len(values) == 1 {
+		if keys, vals, ok := entries(values[0]); ok {
+			for i := range keys {
+				replace(keys[i], vals[i])
+			}
+			return As[S](s)
+		}
+		if elements, ok := elementsOf(values[0], false); ok {
+			values = elements
+		}
+	}
+	for i, value := range values {
+		if pair, ok := elementsOf(value, true); ok {
+			if len(pair) == 2 {
+				replace(pair[0], pair[1])
+			}
+			continue
+		}
+		if strings.Contains(placeholder, "_") {
+			replace(i, value)
+		} else {
+			s = strings.Replace(s, placeholder, stringOf(value), 1)
+		}
+	}
+	return As[S](s)
+}
+
+// elementsOf returns the elements of an array value, if generic is true, only arrays that can hold
+// values of any type are considered, as packed arrays are not arrays to the engine.
+func elementsOf(value any, generic bool) ([]any, bool) {
+	rvalue := reflect.ValueOf(value)
+	if !rvalue.IsValid() {
+		return nil, false
+	}
+	switch rvalue.Kind() {
+	case reflect.Slice, reflect.Array:
+		if elem := rvalue.Type().Elem(); elem.Kind() == reflect.Uint8 || generic && !isVariant(elem) {
+			return nil, false
+		}
+		elements := make([]any, rvalue.Len())
+		for i := range elements {
+			elements[i] = rvalue.Index(i).Interface()
+		}
+		return elements, true
+	}
+	length, index := rvalue.MethodByName("Len"), rvalue.MethodByName("Index")
+	if rvalue.Kind() == reflect.Struct && length.IsValid() && index.IsValid() && !isDictionary(rvalue.Type()) &&
+		length.Type().NumIn() == 0 && index.Type().NumIn() == 1 && index.Type().In(0).Kind() == reflect.Int {
+		if generic && !isVariant(index.Type().Out(0)) {
+			return nil, false
+		}
+		elements := make([]any, length.Call(nil)[0].Int())
+		for i := range elements {
+			elements[i] = index.Call([]reflect.Value{reflect.ValueOf(i)})[0].Interface()
+		}
+		return elements, true
+	}
+	return nil, false
+}
+
+// isDictionary reports whether the type is a Dictionary (without importing Dictionary).
+func isDictionary(rtype reflect.Type) bool {
+	_, keys := reflect.PointerTo(rtype).MethodByName("Keys")
+	_, values := rtype.MethodByName("Values")
+	return keys && values
+}
+
+// isVariant reports whether the type is a variant.Any (without importing variant).
+func isVariant(rtype reflect.Type) bool {
+	return rtype.Kind() == reflect.Interface || rtype.PkgPath() == "graphics.gd/variant" && rtype.Name() == "Any"
+}
+
+// entries returns the keys and values of a map, struct or dictionary value. Map entries are sorted
+// by key, dictionaries keep their order and struct fields are named by their gd tag, if any.
+func entries(value any) (keys, values []any, ok bool) {
+	rvalue := reflect.ValueOf(value)
+	for rvalue.Kind() == reflect.Pointer && !rvalue.IsNil() {
+		rvalue = rvalue.Elem()
+	}
+	switch rvalue.Kind() {
+	case reflect.Map:
+		for iter := rvalue.MapRange(); iter.Next(); {
+			keys, values = append(keys, iter.Key().Interface()), append(values, iter.Value().Interface())
+		}
+		order := make([]int, len(keys))
+		for i := range order {
+			order[i] = i
+		}
+		slices.SortFunc(order, func(a, b int) int { return cmp.Compare(stringOf(keys[a]), stringOf(keys[b])) })
+		sortedKeys, sortedValues := make([]any, len(keys)), make([]any, len(keys))
+		for i, j := range order {
+			sortedKeys[i], sortedValues[i] = keys[j], values[j]
+		}
+		return sortedKeys, sortedValues, true
+	case reflect.Struct:
+		if isDictionary(rvalue.Type()) {
+			return dictionary(rvalue)
+		}
+		if _, ok := stringify.Builtin(rvalue.Interface()); ok || rvalue.MethodByName("Len").IsValid() ||
+			isVariantPackage(rvalue.Type().PkgPath()) {
+			return nil, nil, false
+		}
+		for i := range rvalue.NumField() {
+			field := rvalue.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			if tag := field.Tag.Get("gd"); tag == "-" {
+				continue
+			} else if tag != "" {
+				name = tag
+			}
+			keys, values = append(keys, name), append(values, rvalue.Field(i).Interface())
+		}
+		return keys, values, true
+	}
+	return nil, nil, false
+}
+
+// isVariantPackage reports whether path is the variant package or one of its builtin type packages.
+func isVariantPackage(path string) bool {
+	return path == "graphics.gd/variant" || strings.HasPrefix(path, "graphics.gd/variant/") && !strings.HasSuffix(path, "_test")
+}
+
+// dictionary returns the keys and values of a Dictionary (without importing Dictionary).
+func dictionary(rvalue reflect.Value) (keys, values []any, ok bool) {
+	ptr := reflect.New(rvalue.Type())
+	ptr.Elem().Set(rvalue)
+	k, v := ptr.MethodByName("Keys").Call(nil)[0], ptr.MethodByName("Values").Call(nil)[0]
+	if k.Kind() != reflect.Slice || v.Kind() != reflect.Slice || k.Len() != v.Len() {
+		return nil, nil, false
+	}
+	for i := range k.Len() {
+		keys, values = append(keys, k.Index(i).Interface()), append(values, v.Index(i).Interface())
+	}
+	return keys, values, true
+}
+
+// stringOf returns the value converted to a string, in the same way as the engine's str.
+func stringOf(value any) string { return string(appendValue(nil, value, false)) }
+
+// appendValue appends the engine's String form of the value, strings nested inside of containers
+// are quoted.
+func appendValue(buf []byte, value any, nested bool) []byte {
+	switch v := value.(type) {
+	case nil:
+		return append(buf, "<null>"...)
+	case string:
+		if nested {
+			return strconv.AppendQuote(buf, v)
+		}
+		return append(buf, v...)
+	case Readable:
+		return appendValue(buf, v.String(), nested)
+	case Name:
+		if nested {
+			return strconv.AppendQuote(append(buf, '&'), v.String())
+		}
+		return append(buf, v.String()...)
+	case Rune:
+		return utf8.AppendRune(buf, rune(v))
+	case interface{ Interface() any }:
+		return appendValue(buf, v.Interface(), nested)
+	}
+	if s, ok := stringify.Builtin(value); ok {
+		return append(buf, s...)
+	}
+	rvalue := reflect.ValueOf(value)
+	switch rvalue.Kind() {
+	case reflect.Bool:
+		return strconv.AppendBool(buf, rvalue.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(buf, rvalue.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.AppendUint(buf, rvalue.Uint(), 10)
+	case reflect.Float32:
+		return stringify.AppendReal(buf, rvalue.Float(), 32)
+	case reflect.Float64:
+		return stringify.AppendReal(buf, rvalue.Float(), 64)
+	case reflect.String:
+		return appendValue(buf, rvalue.String(), nested)
+	case reflect.Pointer:
+		if rvalue.IsNil() {
+			return append(buf, "<null>"...)
+		}
+	}
+	if keys, values, ok := entries(value); ok && (rvalue.Kind() == reflect.Map || isDictionary(rvalue.Type())) {
+		buf = append(buf, "{ "...)
+		for i := range keys {
+			if i > 0 {
+				buf = append(buf, ", "...)
+			}
+			buf = appendValue(buf, keys[i], true)
+			buf = append(buf, ": "...)
+			buf = appendValue(buf, values[i], true)
+		}
+		return append(buf, " }"...)
+	}
+	if elements, ok := elementsOf(value, false); ok || rvalue.Kind() == reflect.Slice {
+		buf = append(buf, '[')
+		for i, element := range elements {
+			if i > 0 {
+				buf = append(buf, ", "...)
+			}
+			buf = appendValue(buf, element, true)
+		}
+		return append(buf, ']')
+	}
+	if stringer, ok := value.(fmt.Stringer); ok {
+		return append(buf, stringer.String()...)
+	}
+	return fmt.Append(buf, value)
+}
+
+// Sprintf formats the values according to the format string, in the same way as GDScript's %
+// operator. Each placeholder starts with % and is followed by optional flags and ends with one of
+// the conversions:
+//
+//	s	the value converted to a String.
+//	c	a single character, from a number (Unicode code point) or single-character string.
+//	d	an integer, in decimal.
+//	o	an integer, in octal.
+//	x X	an integer, in lowercase or uppercase hexadecimal.
+//	f	a number, in decimal with 6 decimal places by default.
+//	v	a vector, each component formatted as with f.
+//	%	a literal %.
+//
+// The flags are + (always show the sign), - (pad to the right), 0 (pad with zeros), a width, a
+// precision after a period and * (use the next value as the width or precision). Just like the engine, an error is returned if the format and values do not match.
+func Sprintf[S Any](format S, values ...any) (S, error) { //gd:String%(right:Variant)
+	var (
+		b         Builder
+		index     int
+		inFormat  bool
+		minChars  int
+		decimals  int
+		inDecimal bool
+		zeros     bool
+		left      bool
+		sign      bool
+	)
+	fail := func(message string) (S, error) {
+		return [1]S{}[0], errors.New(message)
+	}
+	pad := func(s string, count int, padding string) string {
+		if n := count - utf8.RuneCountInString(s); n > 0 {
+			if left {
+				return s + strings.Repeat(padding, n)
+			}
+			return strings.Repeat(padding, n) + s
+		}
+		return s
+	}
+	// number pads the digits of a number and adds the sign, the sign is placed before zero padding
+	// and after space padding.
+	number := func(digits string, negative, showSign, finite bool) string {
+		padding := " "
+		if zeros && finite {
+			padding = "0"
+		}
+		count := minChars
+		if negative || showSign {
+			count--
+		}
+		s := pad(digits, count, padding)
+		if negative || showSign {
+			symbol := "+"
+			if negative {
+				symbol = "-"
+			}
+			switch {
+			case left || zeros:
+				s = symbol + s
+			default:
+				at := len(s) - len(digits)
+				s = s[:at] + symbol + s[at:]
+			}
+		}
+		return s
+	}
+	for _, c := range New(format).String() {
+		if !inFormat {
+			if c == '%' {
+				inFormat = true
+				minChars, decimals, inDecimal, zeros, left, sign = 0, 6, false, false, false, false
+				continue
+			}
+			b.WriteRune(c)
+			continue
+		}
+		switch c {
+		case '%':
+			b.WriteByte('%')
+			inFormat = false
+		case 'd', 'o', 'x', 'X':
+			if index >= len(values) {
+				return fail("not enough arguments for format string")
+			}
+			f, isInt, ok := numeric(values[index])
+			if !ok {
+				return fail("a number is required")
+			}
+			value := int64(f)
+			if isInt {
+				value = integer(values[index])
+			}
+			base := map[rune]int{'d': 10, 'o': 8, 'x': 16, 'X': 16}[c]
+			magnitude := uint64(value)
+			if value < 0 {
+				magnitude = -magnitude
+			}
+			digits := strconv.FormatUint(magnitude, base)
+			if c == 'X' {
+				digits = strings.ToUpper(digits)
+			}
+			b.WriteString(number(digits, value < 0, sign, true))
+			index++
+			inFormat = false
+		case 'f':
+			if index >= len(values) {
+				return fail("not enough arguments for format string")
+			}
+			f, _, ok := numeric(values[index])
+			if !ok {
+				return fail("a number is required")
+			}
+			finite := !math.IsInf(f, 0) && !math.IsNaN(f)
+			digits := num(math.Abs(f), decimals)
+			if finite {
+				digits = padDecimals(digits, decimals)
+			}
+			b.WriteString(number(digits, math.Signbit(f), sign, finite))
+			index++
+			inFormat = false
+		case 'v':
+			if index >= len(values) {
+				return fail("not enough arguments for format string")
+			}
+			components, ok := vectorComponents(values[index])
+			if !ok {
+				return fail("%v requires a vector type (Vector2/3/4/2i/3i/4i)")
+			}
+			b.WriteByte('(')
+			for i, f := range components {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				finite := !math.IsInf(f, 0) && !math.IsNaN(f)
+				digits := num(math.Abs(f), decimals)
+				if finite {
+					digits = padDecimals(digits, decimals)
+				}
+				b.WriteString(number(digits, f < 0, false, finite))
+			}
+			b.WriteByte(')')
+			index++
+			inFormat = false
+		case 's':
+			if index >= len(values) {
+				return fail("not enough arguments for format string")
+			}
+			b.WriteString(pad(stringOf(values[index]), minChars, " "))
+			index++
+			inFormat = false
+		case 'c':
+			if index >= len(values) {
+				return fail("not enough arguments for format string")
+			}
+			var s string
+			if f, isInt, ok := numeric(values[index]); ok {
+				value := int32(f)
+				if isInt {
+					value = int32(integer(values[index]))
+				}
+				switch {
+				case value < 0:
+					return fail("unsigned integer is lower than minimum")
+				case value >= 0xd800 && value <= 0xdfff:
+					return fail("unsigned integer is invalid Unicode character")
+				case value > 0x10ffff:
+					return fail("unsigned integer is greater than maximum")
+				}
+				s = string(rune(value))
+			} else if text, ok := values[index].(string); ok {
+				s = text
+			} else if text, ok := values[index].(Readable); ok {
+				s = text.String()
+			} else {
+				return fail("%c requires number or single-character string")
+			}
+			if utf8.RuneCountInString(s) != 1 {
+				return fail("%c requires number or single-character string")
+			}
+			b.WriteString(pad(s, minChars, " "))
+			index++
+			inFormat = false
+		case '-':
+			left = true
+		case '+':
+			sign = true
+		case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			n := int(c - '0')
+			switch {
+			case inDecimal:
+				decimals = decimals*10 + n
+			case c == '0' && minChars == 0:
+				zeros = !left
+			default:
+				minChars = minChars*10 + n
+			}
+		case '.':
+			if inDecimal {
+				return fail("too many decimal points in format")
+			}
+			inDecimal = true
+			decimals = 0
+		case '*':
+			if index >= len(values) {
+				return fail("not enough arguments for format string")
+			}
+			f, isInt, ok := numeric(values[index])
+			if _, vector := vectorComponents(values[index]); !ok && !vector {
+				return fail("* wants number or vector")
+			}
+			size := int(int32(f))
+			if isInt {
+				size = int(int32(integer(values[index])))
+			}
+			if inDecimal {
+				decimals = size
+			} else {
+				minChars = size
+			}
+			index++
+		default:
+			return fail("unsupported format character")
+		}
+	}
+	if inFormat {
+		return fail("incomplete format")
+	}
+	if index != len(values) {
+		return fail("not all arguments converted during string formatting")
+	}
+	return As[S](b.Readable()), nil
+}
+
+// numeric returns the value of an integer or floating point number.
+func numeric(value any) (f float64, isInt, ok bool) {
+	if v, ok := value.(interface{ Interface() any }); ok {
+		value = v.Interface()
+	}
+	rvalue := reflect.ValueOf(value)
+	switch rvalue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(integer(value)), true, true
+	case reflect.Float32, reflect.Float64:
+		return rvalue.Float(), false, true
+	}
+	return 0, false, false
+}
+
+// integer returns the value of an integer, as an int64.
+func integer(value any) int64 {
+	if v, ok := value.(interface{ Interface() any }); ok {
+		value = v.Interface()
+	}
+	rvalue := reflect.ValueOf(value)
+	if rvalue.CanInt() {
+		return rvalue.Int()
+	}
+	return int64(rvalue.Uint())
+}
+
+// vectorComponents returns the components of a vector value.
+func vectorComponents(value any) ([]float64, bool) {
+	if v, ok := value.(interface{ Interface() any }); ok {
+		value = v.Interface()
+	}
+	switch v := value.(type) {
+	case Vector2.XY:
+		return []float64{float64(v.X), float64(v.Y)}, true
+	case Vector2i.XY:
+		return []float64{float64(v.X), float64(v.Y)}, true
+	case Vector3.XYZ:
+		return []float64{float64(v.X), float64(v.Y), float64(v.Z)}, true
+	case Vector3i.XYZ:
+		return []float64{float64(v.X), float64(v.Y), float64(v.Z)}, true
+	case Vector4.XYZW:
+		return []float64{float64(v.X), float64(v.Y), float64(v.Z), float64(v.W)}, true
+	case Vector4i.XYZW:
+		return []float64{float64(v.X), float64(v.Y), float64(v.Z), float64(v.W)}, true
+	}
+	return nil, false
+}
+
+// num converts the number to a string with at most the given number of decimals, without trailing
+// zeros, in the same way as the engine's String::num.
+func num(f float64, decimals int) string {
+	switch {
+	case math.IsNaN(f):
+		return "nan"
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	}
+	if decimals < 0 {
+		decimals = 14
+		if f > 10 {
+			decimals -= int(math.Floor(math.Log10(f)))
+		}
+	}
+	s := strconv.FormatFloat(f, 'f', min(decimals, 32), 64)
+	if decimals < 0 {
+		s = strconv.FormatFloat(f, 'f', 6, 64)
+	}
+	if strings.IndexByte(s, '.') >= 0 {
+		s = strings.TrimRight(s, "0")
+		s = strings.TrimSuffix(s, ".")
+	}
+	return s
+}
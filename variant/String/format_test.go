@@ -0,0 +1,162 @@
+package String_test
+
+import (
+	"testing"
+
+	"graphics.gd/internal/gdtests"
+	"graphics.gd/variant/String"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+)
+
+// The expected outputs follow the engine's String::sprintf, they were not recorded from a
+// running engine. The "fish" cases are those of the engine's own sprintf tests.
+func TestSprintf(t *testing.T) {
+	for _, test := range []struct {
+		format string
+		values []any
+		expect string
+	}{
+		{"fish %s frog", []any{"cheese"}, "fish cheese frog"},
+		{"fish %d frog", []any{5}, "fish 5 frog"},
+		{"fish %d frog", []any{-5}, "fish -5 frog"},
+		{"fish %05d frog", []any{5}, "fish 00005 frog"},
+		{"fish %5d frog", []any{5}, "fish     5 frog"},
+		{"fish %-5d frog", []any{5}, "fish 5     frog"},
+		{"fish %+d frog", []any{5}, "fish +5 frog"},
+		{"fish %x frog", []any{45}, "fish 2d frog"},
+		{"fish %X frog", []any{45}, "fish 2D frog"},
+		{"fish %o frog", []any{99}, "fish 143 frog"},
+		{"fish %f frog", []any{99.99}, "fish 99.990000 frog"},
+		{"fish %11f frog", []any{99.99}, "fish   99.990000 frog"},
+		{"fish %-11f frog", []any{99.99}, "fish 99.990000   frog"},
+		{"fish %f frog", []any{-99.99}, "fish -99.990000 frog"},
+		{"fish %+f frog", []any{99.99}, "fish +99.990000 frog"},
+		{"fish %c frog", []any{65}, "fish A frog"},
+		{"fish %c frog", []any{"A"}, "fish A frog"},
+		{"fish %*d frog", []any{5, 12}, "fish    12 frog"},
+		{"fish %-*d frog", []any{5, 12}, "fish 12    frog"},
+		{"fish %v frog", []any{Vector2.New(19.99, 1)}, "fish (19.990000, 1.000000) frog"},
+		{"fish %.2v frog", []any{Vector2.New(19.99, 1)}, "fish (19.99, 1.00) frog"},
+		{"fish %v frog", []any{Vector3.New(19.99, 1, -2.05)}, "fish (19.990000, 1.000000, -2.050000) frog"},
+
+		{"%s", []any{"text"}, "text"},
+		{"Remaining fuel: %5.1f", []any{12.3}, "Remaining fuel:  12.3"},
+		{"%d%%", []any{50}, "50%"},
+		{"%10d", []any{12345}, "     12345"},
+		{"%-10d|", []any{12345}, "12345     |"},
+		{"%+d", []any{12345}, "+12345"},
+		{"%010d", []any{12345}, "0000012345"},
+		{"%5d", []any{-3}, "   -3"},
+		{"%05d", []any{-3}, "-0003"},
+		{"%-+5d|", []any{3}, "+3   |"},
+		{"%d", []any{3.9}, "3"},
+		{"%x %X %o", []any{255, 255, 8}, "ff FF 10"},
+		{"%x", []any{-255}, "-ff"},
+		{"%f", []any{1.0 / 3}, "0.333333"},
+		{"%f", []any{2}, "2.000000"},
+		{"%.3f", []any{3.14159}, "3.142"},
+		{"%.0f", []any{2.5}, "2"},
+		{"%.f", []any{3.5}, "4"},
+		{"%10.3f", []any{10000.5555}, " 10000.556"},
+		{"%-10.3f|", []any{10000.5555}, "10000.556 |"},
+		{"%5.1f", []any{-3.14}, " -3.1"},
+		{"%05.1f", []any{-3.14}, "-03.1"},
+		{"%+.2f", []any{1.5}, "+1.50"},
+		{"%*.*f", []any{7, 3, 8.8888}, "  8.889"},
+		{"%f", []any{float32(0.1)}, "0.100000"},
+		{"%c%c", []any{65, "b"}, "Ab"},
+		{"%3c|", []any{0x1F916}, "  🤖|"},
+		{"%v", []any{Vector2.New(1.5, -2)}, "(1.500000, -2.000000)"},
+		{"%.1v", []any{Vector3.New(0.5, 1, 2)}, "(0.5, 1.0, 2.0)"},
+		{"%v", []any{Vector2i.New(1, 2)}, "(1.000000, 2.000000)"},
+		{"%5.1v", []any{Vector2.New(1, -1)}, "(  1.0,  -1.0)"},
+		{"%s, %s and %s", []any{1, 2.5, true}, "1, 2.5 and true"},
+		{"%s", []any{nil}, "<null>"},
+		{"%s", []any{[]any{1, "a", 1.0}}, `[1, "a", 1.0]`},
+		{"%s", []any{map[string]int{"b": 2, "a": 1}}, `{ "a": 1, "b": 2 }`},
+		{"%s", []any{Vector2.New(1, 2)}, "(1.0, 2.0)"},
+		{"%8s|%-8s|", []any{"right", "left"}, "   right|left    |"},
+	} {
+		got, err := String.Sprintf(test.format, test.values...)
+		if err != nil {
+			t.Errorf("%q %% %v: %v", test.format, test.values, err)
+			continue
+		}
+		if got != test.expect {
+			t.Errorf("%q %% %v = %q, expected %q", test.format, test.values, got, test.expect)
+		}
+	}
+}
+
+func TestSprintfErrors(t *testing.T) {
+	for _, test := range []struct {
+		format string
+		values []any
+		expect string
+	}{
+		{"%d %d", []any{1}, "not enough arguments for format string"},
+		{"%d", []any{1, 2}, "not all arguments converted during string formatting"},
+		{"%d", []any{"a"}, "a number is required"},
+		{"%f", []any{true}, "a number is required"},
+		{"%v", []any{1}, "%v requires a vector type (Vector2/3/4/2i/3i/4i)"},
+		{"%c", []any{"ab"}, "%c requires number or single-character string"},
+		{"%c", []any{-1}, "unsigned integer is lower than minimum"},
+		{"%c", []any{0xd800}, "unsigned integer is invalid Unicode character"},
+		{"%1.2.3f", []any{1}, "too many decimal points in format"},
+		{"%*d", []any{"a", 1}, "* wants number or vector"},
+		{"%q", nil, "unsupported format character"},
+		{"%ud", []any{1}, "unsupported format character"},
+		{"100%", nil, "incomplete format"},
+	} {
+		_, err := String.Sprintf(test.format, test.values...)
+		if err == nil || err.Error() != test.expect {
+			t.Errorf("%q %% %v: expected error %q, got %v", test.format, test.values, test.expect, err)
+		}
+	}
+}
+
+func TestFormatWith(t *testing.T) {
+	type Player struct {
+		Name  string `gd:"name"`
+		Level int    `gd:"level"`
+	}
+	gdtests.That(t, String.Format("{name} is {age}", map[string]any{"name": "Godot", "age": 10}), "Godot is 10")
+	gdtests.That(t, String.Format("{name} is level {level}", Player{"Godot", 3}), "Godot is level 3")
+	gdtests.That(t, String.Format("{0}, {1}", []string{"a", "b"}), "a, b")
+	gdtests.That(t, String.Format("{name}: {1}", []any{"name", "Godot"}, "second"), "Godot: second")
+	gdtests.That(t, String.Format("{0} {1}", 1.0, Vector2.New(1, 2)), "1.0 (1.0, 2.0)")
+	gdtests.That(t, String.FormatWith("$name is $0", "$_", map[string]string{"name": "Godot", "0": "free"}), "Godot is free")
+	gdtests.That(t, String.FormatWith("{} {}", "{}", "Godot", "Engine"), "Godot Engine")
+}
+
+func TestPadding(t *testing.T) {
+	gdtests.That(t, String.PadDecimals("1.5", 3), "1.500")
+	gdtests.That(t, String.PadDecimals("1.23456", 2), "1.23")
+	gdtests.That(t, String.PadDecimals("10", 1), "10.0")
+	gdtests.That(t, String.PadDecimals("1.5", 0), "1")
+	gdtests.That(t, String.PadZeros("-5.3", 3), "-005.3")
+	gdtests.That(t, String.PadZeros("123", 2), "123")
+}
+
+func TestBuilder(t *testing.T) {
+	var b String.Builder
+	b.WriteString("Hello")
+	b.WriteByte(',')
+	b.WriteRune(' ')
+	hello := b.Readable()
+	b.WriteString("World")
+	gdtests.That(t, hello.String(), "Hello, ")
+	gdtests.That(t, b.String(), "Hello, World")
+	appended := String.Append(hello, "Gopher")
+	gdtests.That(t, appended.String(), "Hello, Gopher")
+	gdtests.That(t, b.String(), "Hello, World")
+	b.Reset()
+	b.Grow(64)
+	if b.Len() != 0 || b.Cap() < 64 {
+		t.Fatalf("unexpected builder length %d and capacity %d", b.Len(), b.Cap())
+	}
+	gdtests.That(t, String.Map(func(r String.Rune) String.Rune { return -1 }, "abc"), "")
+	gdtests.That(t, String.StripFilename(""), "")
+}
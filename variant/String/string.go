@@ -80,6 +80,17 @@ func (name Name) String() string {
 	return Readable(name).String()
 }
 
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (name Name) MarshalText() ([]byte, error) {
+	return []byte(name.String()), nil
+}
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (name *Name) UnmarshalText(text []byte) error {
+	*name = Name(New(string(text)))
+	return nil
+}
+
 func MakeComparable[T Any](s T) Comparable {
 	panic("not implemented")
 }
@@ -162,10 +173,10 @@ func As[T, S Any](s S) T {
 	rtype := reflect.TypeFor[T]()
 	switch {
 	case rtype == reflect.TypeFor[string]():
-		c := uni.api.String(uni.buf)
+		c := uni.String()
 		return *(*T)(unsafe.Pointer(&c))
 	case rtype.Kind() == reflect.Slice && rtype.Elem().Kind() == reflect.Uint8:
-		b := []byte(uni.api.String(uni.buf))
+		b := []byte(uni.String())
 		return *(*T)(unsafe.Pointer(&b))
 	case rtype.ConvertibleTo(reflect.TypeOf(Readable{})):
 		u := uni
@@ -305,7 +316,8 @@ func Title[S Any](s S) S {
 // dropped from the string with no replacement.
 func Map[S Any](mapping func(Rune) Rune, s S) S {
 	var (
-		buf Readable = builder()
+		buf     Builder
+		changed bool
 	)
 	for i, c := range Runes(s) {
 		r := mapping(c)
@@ -313,23 +325,24 @@ func Map[S Any](mapping func(Rune) Rune, s S) S {
 			continue
 		}
 		width := utf8.RuneLen(rune(c))
-		buf = Append(buf, Slice(s, 0, i))
+		buf.WriteString(New(Slice(s, 0, i)).String())
 		if r >= 0 {
-			buf = Append(buf, Rune(r))
+			buf.WriteRune(rune(r))
 		}
 		s = Slice(s, i+width, Length(s))
+		changed = true
 		break
 	}
-	if buf.api == nil {
+	if !changed {
 		return s // Fast path for unchanged input
 	}
 	for _, c := range Runes(s) {
 		r := mapping(c)
 		if r >= 0 {
-			buf = Append(buf, New(r))
+			buf.WriteRune(rune(r))
 		}
 	}
-	return As[S](buf)
+	return As[S](buf.Readable())
 }
 
 // Capitalize changes the appearance of the string: replaces underscores (_) with spaces, adds spaces
@@ -622,64 +635,6 @@ func Scientific[F Float.Any](number F) string { //gd:String.num_scientific
 	return strconv.FormatFloat(float64(number), 'e', -1, 64)
 }
 
-// Format formats the string by replacing all occurrences of placeholder with the elements of values.
-//
-// Any underscores in placeholder will be replaced with the corresponding keys in advance. Array
-// elements use their index as keys.
-//
-// Note: The replacement of placeholders is not done all at once, instead each placeholder is replaced
-// in the order they are passed, this means that if one of the replacement strings contains a key it
-// will also be replaced. This can be very powerful, but can also cause unexpected results if you are
-// not careful. If you do not need to perform replacement in the replacement strings, make sure your
-// replacements do not contain placeholders to ensure reliable results.
-func Format[S Any](format S, args ...any) S { //gd:String.format
-	var result strings.Builder
-	var search strings.Builder
-	var braces bool
-	for i := 0; i < Length(format); i++ {
-		v := Index(format, i)
-		switch v {
-		case '{':
-			braces = true
-			continue
-		case '}':
-			if braces {
-				key, err := strconv.Atoi(search.String())
-				if err == nil {
-					result.WriteString(fmt.Sprint(args[key]))
-				} else {
-					for _, arg := range args {
-						rvalue := reflect.ValueOf(arg)
-						switch rvalue.Kind() {
-						case reflect.Map:
-							if rvalue.Type().Key().Kind() == reflect.String {
-								if rvalue.MapIndex(reflect.ValueOf(search.String()).Convert(rvalue.Type().Key())).IsValid() {
-									result.WriteString(fmt.Sprint(rvalue.MapIndex(reflect.ValueOf(search.String())).Interface()))
-								}
-							}
-						case reflect.Struct:
-							if rvalue.FieldByName(search.String()).IsValid() {
-								result.WriteString(fmt.Sprint(rvalue.FieldByName(search.String()).Interface()))
-							}
-						}
-					}
-				}
-				search.Reset()
-				braces = false
-				continue
-			}
-			fallthrough
-		default:
-			if braces {
-				search.WriteByte(v)
-			} else {
-				result.WriteByte(v)
-			}
-		}
-	}
-	return As[S](result.String())
-}
-
 // FileExtension returns the file extension without the leading period (.) if the string is a
 // valid file name or path. Otherwise, returns an empty string.
 func FileExtension[S Any](path S) S { //gd:String.get_extension
@@ -905,13 +860,13 @@ func JoinedWith[S, D Any](d D, s ...S) S { //gd:String.join
 	if len(s) == 0 {
 		return [1]S{}[0]
 	}
-	var b Readable = builder()
-	b = Append(b, New(fmt.Sprint(s[0])))
+	var b Builder
+	b.WriteString(New(s[0]).String())
 	for _, v := range s[1:] {
-		b = Append(b, d)
-		b = Append(b, v)
+		b.WriteString(New(d).String())
+		b.WriteString(New(v).String())
 	}
-	return As[S](b)
+	return As[S](b.Readable())
 }
 
 // First, returns the first n characters from the beginning of the string. If length is
@@ -971,25 +926,39 @@ func MD5[S Any](s S) []byte { //gd:String.md5_buffer String.md5_text
 // PadDecimals formats the string representing a number to have an exact number of digits
 // after the decimal point.
 func PadDecimals[S Any](s S, digits int) S { //gd:String.pad_decimals
-	if !IsValidFloat(s) {
-		return s
+	return As[S](padDecimals(New(s).String(), digits))
+}
+
+func padDecimals(s string, digits int) string {
+	dot := strings.IndexByte(s, '.')
+	if dot == -1 {
+		if digits <= 0 {
+			return s
+		}
+		s += "."
+		dot = len(s) - 1
+	} else if digits <= 0 {
+		return s[:dot]
 	}
-	var result Readable = builder()
-	result = Append(result, New(s))
-	result = Append(result, New(strings.Repeat("0", Length(s)-FindIndex(s, ".")+digits+1)))
-	return As[S](result)
+	if len(s)-(dot+1) > digits {
+		return s[:dot+digits+1]
+	}
+	return s + strings.Repeat("0", digits-len(s)+dot+1)
 }
 
 // PadZeros formats the string representing a number to have an exact number of digits before
 // the decimal point.
 func PadZeros[S Any](s S, digits int) S { //gd:String.pad_zeros
-	if !IsValidFloat(s) {
+	str := New(s).String()
+	end := strings.IndexByte(str, '.')
+	if end == -1 {
+		end = len(str)
+	}
+	begin := strings.IndexFunc(str[:end], func(r rune) bool { return r >= '0' && r <= '9' })
+	if begin == -1 || end-begin >= digits {
 		return s
 	}
-	var result Readable = builder()
-	result = Append(result, New(strings.Repeat("0", FindIndex(s, ".")-digits)))
-	result = Append(result, New(s))
-	return As[S](result)
+	return As[S](str[:begin] + strings.Repeat("0", digits-(end-begin)) + str[begin:])
 }
 
 // AddPathElement concatenates file at the end of the string as a subpath, adding / if necessary.
@@ -1028,8 +997,8 @@ func Replace[S Any, T Any](s S, old, new T) S { //gd:String.replacen
 		n = m
 	}
 	// Apply replacements to buffer.
-	var b = builder()
-	//b.Grow(len(s) + n*(len(new)-len(old)))
+	var b Builder
+	b.Grow(Length(s) + n*(Length(new)-Length(old)))
 	start := 0
 	for i := 0; i < n; i++ {
 		j := start
@@ -1041,12 +1010,12 @@ func Replace[S Any, T Any](s S, old, new T) S { //gd:String.replacen
 		} else {
 			j += FindIndex(Slice(s, start, Length(s)), old)
 		}
-		b = Append(b, New(Slice(s, start, j)))
-		b = Append(b, New(new))
+		b.WriteString(New(Slice(s, start, j)).String())
+		b.WriteString(New(new).String())
 		start = j + Length(old)
 	}
-	b = Append(b, New(Slice(s, start, Length(s))))
-	return As[S](b)
+	b.WriteString(New(Slice(s, start, Length(s))).String())
+	return As[S](b.Readable())
 }
 
 // Reverse returns the copy of this string in reverse order. This operation works on
@@ -1386,15 +1355,15 @@ func UnicodeAt[S Any](s S, index int) Rune { //gd:String.unicode_at
 
 // StripFilename returns a copy of the string with all characters that are not allowed in [IsValidFilename] replaced with underscores.
 func StripFilename[S Any](s S) S { //gd:String.validate_filename
-	var result = builder()
+	var result Builder
 	for _, r := range Runes(s) {
 		if IsValidFilename(string(r)) {
-			result = Append(result, New(r))
+			result.WriteRune(rune(r))
 		} else {
-			result = Append(result, New(Rune('_')))
+			result.WriteByte('_')
 		}
 	}
-	return As[S](result)
+	return As[S](result.Readable())
 }
 
 // StripNodeName returns a copy of the string with all characters that are not allowed in Node.name (. : @ / " %) replaced with underscores.
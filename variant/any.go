@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"unsafe"
 
+	"graphics.gd/internal/stringify"
 	"graphics.gd/variant/AABB"
 	"graphics.gd/variant/Basis"
 	"graphics.gd/variant/Color"
@@ -352,3 +353,33 @@ func (a Any) Interface() interface{} {
 		return a.value
 	}
 }
+
+// MarshalJSON implements [encoding/json.Marshaler], the variant is encoded in the same way as
+// JSON.stringify.
+func (a Any) MarshalJSON() ([]byte, error) {
+	return stringify.Marshal(a.Interface())
+}
+
+// UnmarshalJSON implements [encoding/json.Unmarshaler], in the same way as JSON.parse, numbers
+// are decoded as float64, arrays as []any and objects as map[string]any.
+func (a *Any) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := stringify.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	*a = New(value)
+	return nil
+}
+
+// MarshalText implements [encoding.TextMarshaler], so that variants can be used as the keys of
+// JSON objects, the text is the same as the engine's String conversion.
+func (a Any) MarshalText() ([]byte, error) {
+	s, err := stringify.Key(reflect.ValueOf(a.Interface()))
+	return []byte(s), err
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], the variant becomes a string.
+func (a *Any) UnmarshalText(text []byte) error {
+	*a = New(string(text))
+	return nil
+}
@@ -1,424 +1,404 @@
 package variant
 
 import (
+	"encoding/binary"
 	"fmt"
-	"unsafe"
+	"math"
+	"reflect"
+	"strings"
 
 	"graphics.gd/variant/AABB"
 	"graphics.gd/variant/Basis"
 	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Float"
 	"graphics.gd/variant/Path"
 	"graphics.gd/variant/Plane"
+	"graphics.gd/variant/Projection"
 	"graphics.gd/variant/Quaternion"
+	"graphics.gd/variant/RID"
 	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Rect2i"
 	"graphics.gd/variant/String"
 	"graphics.gd/variant/Transform2D"
 	"graphics.gd/variant/Transform3D"
 	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
 	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector3i"
+	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/Vector4i"
+	"graphics.gd/variant/internal/containers"
 )
 
-type decodable interface {
-	uint8 | uint16 | uint32 | uint64 | int8 | int16 | int32 | int64 | float32 | float64 |
-		Vector2.XY | Rect2.PositionSize | Vector3.XYZ | Transform2D.OriginXY | Plane.NormalD | Quaternion.IJKX |
-		AABB.PositionSize | Basis.XYZ | Transform3D.BasisOrigin | Color.RGBA
+// UnmarshalAny a variant-encoded value from the byte slice, as encoded by var_to_bytes. Integers and
+// floats are decoded as int32 and float32, unless they were encoded with 64 bits. Arrays are decoded
+// as []any, dictionaries as map[any]any, packed arrays as Go slices and objects as an [ObjectID].
+// Callables and signals cannot be represented and are decoded as nil.
+func UnmarshalAny(data []byte) (any, error) { //gd:bytes_to_var bytes_to_var_with_objects
+	d := decoder{data: data, maxDepth: DefaultMaxDepth}
+	value := d.value(0)
+	return value, d.err
 }
 
-func decode[T decodable](data []byte) (T, error) {
-	if unsafe.Sizeof([1]T{}[0]) > uintptr(len(data)) {
-		return [1]T{}[0], fmt.Errorf("decode: data too short for type %T", [1]T{}[0])
+// UnmarshalSize returns the size in bytes of the variant-encoded value at the start of the data.
+func UnmarshalSize(data []byte) (uintptr, error) {
+	d := decoder{data: data, objects: true, maxDepth: DefaultMaxDepth}
+	d.value(0)
+	if d.err != nil {
+		return 0, d.err
 	}
-	return *(*T)(unsafe.Pointer(&data[0])), nil
+	return uintptr(len(data) - len(d.data)), nil
+}
+
+// decoder consumes data as it is read, the first error is kept and every read after an error
+// returns zero values.
+type decoder struct {
+	data     []byte
+	err      error
+	objects  bool
+	variants bool // decode as a [Decoder] does: int64, float64, Array.Any and Dictionary.Any.
+	maxDepth int
 }
 
-func decodePacked[T decodable](data []byte) ([]T, error) {
-	if len(data) < 4 {
-		return nil, fmt.Errorf("decodePacked: data too short for length")
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
 	}
-	length, err := decode[uint32](data)
-	if err != nil {
-		return nil, err
+}
+
+func (d *decoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
 	}
-	if uint32(len(data)) < 4+length*uint32(unsafe.Sizeof([1]T{}[0])) {
-		return nil, fmt.Errorf("decodePacked: data too short for packed array of type %T", [1]T{}[0])
+	if n < 0 || n > len(d.data) {
+		d.fail(fmt.Errorf("%w: unexpected end of data", ErrFormat))
+		return nil
 	}
-	return *(*[]T)(unsafe.Pointer(&data[4])), nil
+	b := d.data[:n:n]
+	d.data = d.data[n:]
+	return b
 }
 
-// DecodeString decodes a length-prefixed string from the bytes starting at offset. Fails if the byte count is
-// insufficient. Returns an empty string if a valid string can't be decoded.
-func decodeString(data []byte) (string, error) {
-	length, err := decode[uint32](data)
-	if err != nil {
-		return "", err
+func (d *decoder) u32() uint32 {
+	if b := d.bytes(4); b != nil {
+		return binary.LittleEndian.Uint32(b)
 	}
-	if length > uint32(len(data)) {
-		return "", fmt.Errorf("decodeString: data too short for string of length %d", length)
+	return 0
+}
+
+func (d *decoder) u64() uint64 {
+	if b := d.bytes(8); b != nil {
+		return binary.LittleEndian.Uint64(b)
 	}
-	return string(data[4 : 4+length]), nil
+	return 0
 }
 
-const (
-	typeNull = iota
-	typeBool
-	typeInteger
-	typeFloat
-	typeString
-	typeVector2
-	typeRect2
-	typeVector3
-	typeTransform2D
-	typePlane
-	typeQuaternion
-	typeAABB
-	typeBasis
-	typeTransform3D
-	typeColor
-	typeNodePath
-	typeRID
-	typeObject
-	typeDictionary
-	typeArray
-	typeRawArray
-	typeInt32Array
-	typeInt64Array
-	typeFloat32Array
-	typeFloat64Array
-	typeStringArray
-	typeVector2Array
-	typeVector3Array
-	typeColorArray
-)
+func (d *decoder) f32() Float.X { return Float.X(math.Float32frombits(d.u32())) }
 
-const (
-	encodeFlag64 = 1 << 0
-)
+func (d *decoder) real(wide bool) Float.X {
+	if wide {
+		return Float.X(math.Float64frombits(d.u64()))
+	}
+	return d.f32()
+}
 
-// UnmarshalAny a variant-encoded value from the byte slice.
-func UnmarshalAny(data []byte) (any, error) { //gd:bytes_to_var bytes_to_var_with_objects
-	header, err := decode[uint32](data)
-	if err != nil {
-		return nil, err
+func (d *decoder) str() string {
+	n := int(d.u32())
+	s := string(d.bytes(n))
+	d.bytes((4 - n%4) % 4)
+	return s
+}
+
+// count validates that there is enough data left for n elements of at least size bytes, so that
+// hostile input cannot cause large allocations.
+func (d *decoder) count(n uint32, size int) int {
+	if d.err == nil && uint64(n)*uint64(size) > uint64(len(d.data)) {
+		d.fail(fmt.Errorf("%w: %d elements exceed the remaining data", ErrFormat, n))
+	}
+	if d.err != nil {
+		return 0
 	}
-	vtype := header & 0xFF
-	flags := header >> 16
-	data = data[4:]
-	switch vtype {
-	case typeNull:
-		return nil, nil
-	case typeBool:
-		b, err := decode[uint32](data)
-		if err != nil {
-			return nil, err
+	return int(n)
+}
+
+func (d *decoder) nest(depth int) bool {
+	if depth >= d.maxDepth {
+		d.fail(ErrDepth)
+	}
+	return d.err == nil
+}
+
+func (d *decoder) vector2(wide bool) Vector2.XY { return Vector2.XY{d.real(wide), d.real(wide)} }
+func (d *decoder) vector3(wide bool) Vector3.XYZ {
+	return Vector3.XYZ{d.real(wide), d.real(wide), d.real(wide)}
+}
+func (d *decoder) vector4(wide bool) Vector4.XYZW {
+	return Vector4.XYZW{d.real(wide), d.real(wide), d.real(wide), d.real(wide)}
+}
+func (d *decoder) vector2i() Vector2i.XY { return Vector2i.XY{int32(d.u32()), int32(d.u32())} }
+func (d *decoder) vector3i() Vector3i.XYZ {
+	return Vector3i.XYZ{int32(d.u32()), int32(d.u32()), int32(d.u32())}
+}
+func (d *decoder) color() Color.RGBA { return Color.RGBA{d.f32(), d.f32(), d.f32(), d.f32()} }
+
+func (d *decoder) basis(wide bool) Basis.XYZ {
+	return Basis.XYZ{X: d.vector3(wide), Y: d.vector3(wide), Z: d.vector3(wide)}
+}
+
+func (d *decoder) nodePath() Path.ToNode {
+	names := d.u32()
+	if d.err == nil && names&0x80000000 == 0 {
+		d.fail(fmt.Errorf("%w: unsupported NodePath encoding", ErrFormat))
+	}
+	subnames := d.u32()
+	flags := d.u32()
+	if flags&2 != 0 { // older encodings stored a property separately.
+		subnames++
+	}
+	n := d.count(names&0x7FFFFFFF, 4)
+	m := d.count(subnames, 4)
+	var path strings.Builder
+	if flags&1 != 0 {
+		path.WriteByte('/')
+	}
+	for i := range n {
+		if i > 0 {
+			path.WriteByte('/')
+		}
+		path.WriteString(d.str())
+	}
+	for range m {
+		path.WriteByte(':')
+		path.WriteString(d.str())
+	}
+	return Path.ToNode(String.New(path.String()))
+}
+
+// containerType skips over the element type of a typed Array or Dictionary, as the values are
+// decoded regardless of it.
+func (d *decoder) containerType(kind uint32) {
+	switch kind {
+	case 0:
+	case 1:
+		d.u32()
+	case 2, 3:
+		d.str()
+	default:
+		d.fail(fmt.Errorf("%w: invalid container type", ErrFormat))
+	}
+}
+
+// value decodes the next value, nested values are decoded at depth+1.
+func (d *decoder) value(depth int) any {
+	header := d.u32()
+	if d.err != nil {
+		return nil
+	}
+	wide := header&encodeFlag64 != 0
+	switch t := Type(header & 0xFF); t {
+	case TypeNil:
+		return nil
+	case TypeBool:
+		return d.u32() != 0
+	case TypeInt:
+		if wide {
+			return int64(d.u64())
 		}
-		return b != 0, nil
-	case typeInteger:
-		if flags&encodeFlag64 != 0 {
-			return decode[int64](data)
+		if !d.variants {
+			return int32(d.u32())
 		}
-		return decode[int32](data)
-	case typeFloat:
-		if flags&encodeFlag64 != 0 {
-			return decode[float64](data)
+		return int64(int32(d.u32()))
+	case TypeFloat:
+		if wide {
+			return math.Float64frombits(d.u64())
 		}
-		return decode[float32](data)
-	case typeString:
-		return decodeString(data)
-	case typeVector2:
-		return decode[Vector2.XY](data)
-	case typeRect2:
-		return decode[Rect2.PositionSize](data)
-	case typeVector3:
-		return decode[Vector3.XYZ](data)
-	case typeTransform2D:
-		return decode[Transform2D.OriginXY](data)
-	case typePlane:
-		return decode[Plane.NormalD](data)
-	case typeQuaternion:
-		return decode[Quaternion.IJKX](data)
-	case typeAABB:
-		return decode[AABB.PositionSize](data)
-	case typeBasis:
-		return decode[Basis.XYZ](data)
-	case typeTransform3D:
-		return decode[Transform3D.BasisOrigin](data)
-	case typeColor:
-		return decode[Color.RGBA](data)
-	case typeNodePath:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return nil, err
+		if !d.variants {
+			return math.Float32frombits(d.u32())
 		}
-		data = data[4:]
-		if length&0x80000000 != 0 {
-			count := length & 0x7FFFFFFF
-			flags, err := decode[uint32](data)
-			if err != nil {
-				return nil, err
-			}
-			absolute := flags&1 != 0
-			var path Path.ToNode
-			if absolute {
-				path = Path.ToNode(String.New("/"))
-			}
-			for i := uintptr(0); i < uintptr(count); i++ {
-				s, err := decodeString(data)
-				if err != nil {
-					return nil, err
+		return float64(math.Float32frombits(d.u32()))
+	case TypeString:
+		return d.str()
+	case TypeStringName:
+		return String.Name(String.New(d.str()))
+	case TypeNodePath:
+		return d.nodePath()
+	case TypeVector2:
+		return d.vector2(wide)
+	case TypeVector2i:
+		return d.vector2i()
+	case TypeRect2:
+		return Rect2.PositionSize{Position: d.vector2(wide), Size: d.vector2(wide)}
+	case TypeRect2i:
+		return Rect2i.PositionSize{Position: d.vector2i(), Size: d.vector2i()}
+	case TypeVector3:
+		return d.vector3(wide)
+	case TypeVector3i:
+		return d.vector3i()
+	case TypeTransform2D:
+		return Transform2D.OriginXY{X: d.vector2(wide), Y: d.vector2(wide), Origin: d.vector2(wide)}
+	case TypeVector4:
+		return d.vector4(wide)
+	case TypeVector4i:
+		return Vector4i.XYZW{int32(d.u32()), int32(d.u32()), int32(d.u32()), int32(d.u32())}
+	case TypePlane:
+		return Plane.NormalD{Normal: d.vector3(wide), D: d.real(wide)}
+	case TypeQuaternion:
+		q := d.vector4(wide)
+		return Quaternion.IJKX{I: q.X, J: q.Y, K: q.Z, X: q.W}
+	case TypeAABB:
+		return AABB.PositionSize{Position: d.vector3(wide), Size: d.vector3(wide)}
+	case TypeBasis:
+		return d.basis(wide)
+	case TypeTransform3D:
+		return Transform3D.BasisOrigin{Basis: d.basis(wide), Origin: d.vector3(wide)}
+	case TypeProjection:
+		return Projection.XYZW{X: d.vector4(wide), Y: d.vector4(wide), Z: d.vector4(wide), W: d.vector4(wide)}
+	case TypeColor:
+		return d.color()
+	case TypeRID:
+		return RID.Any(d.u64())
+	case TypeObject:
+		return d.object(header, depth)
+	case TypeCallable:
+		return nil
+	case TypeSignal:
+		d.str()
+		d.u64()
+		return nil
+	case TypeDictionary:
+		if !d.nest(depth) {
+			return nil
+		}
+		d.containerType(header >> 16 & 3)
+		d.containerType(header >> 18 & 3)
+		n := d.count(d.u32()&0x7FFFFFFF, 8)
+		if !d.variants {
+			dictionary := make(map[any]any, n)
+			for range n {
+				key, value := d.value(depth+1), d.value(depth+1)
+				if d.err == nil && !reflect.TypeOf(key).Comparable() {
+					d.fail(fmt.Errorf("%w: %T dictionary key", ErrFormat, key))
+				}
+				if d.err != nil {
+					return nil
 				}
-				path = String.Append(path, Path.ToNode(String.New(s)))
-				data = data[(len(s)+3)&^3:] // must be multiple of 4 bytes
+				dictionary[key] = value
 			}
-			return path, nil
+			return dictionary
 		}
-		s, err := decodeString(data)
-		if err != nil {
-			return nil, err
+		keys, values := make([]Any, n), make([]Any, n)
+		for i := range n {
+			keys[i] = New(d.value(depth + 1))
+			values[i] = New(d.value(depth + 1))
 		}
-		return Path.ToNode(String.New(s)), nil
-	case typeRID:
-		return decode[uint64](data)
-	case typeObject:
-		return nil, nil
-	case typeDictionary:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return nil, err
+		if d.err != nil {
+			return nil
 		}
-		var dictionary = make(map[any]any)
-		for i := uintptr(0); i < uintptr(length)&0x7FFFFFFF; i++ {
-			keySize, err := UnmarshalSize(data)
-			if err != nil {
-				return nil, err
-			}
-			valSize, err := UnmarshalSize(data[keySize:])
-			if err != nil {
-				return nil, err
-			}
-			key, err := UnmarshalAny(data)
-			if err != nil {
-				return nil, err
-			}
-			value, err := UnmarshalAny(data[keySize:])
-			if err != nil {
-				return nil, err
-			}
-			dictionary[key] = value
-			data = data[keySize+valSize:]
+		if containers.NewDictionary == nil {
+			d.fail(fmt.Errorf("%w: graphics.gd/variant/Dictionary is not imported", ErrContainers))
+			return nil
 		}
-		return dictionary, nil
-	case typeArray:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return nil, err
+		return containers.NewDictionary(keys, values)
+	case TypeArray:
+		if !d.nest(depth) {
+			return nil
 		}
-		var result = make([]any, length&0x7FFFFFFF)
-		for i := range result {
-			size, err := UnmarshalSize(data)
-			if err != nil {
-				return nil, err
+		d.containerType(header >> 16 & 3)
+		n := d.count(d.u32(), 4)
+		if !d.variants {
+			elements := make([]any, n)
+			for i := range elements {
+				elements[i] = d.value(depth + 1)
 			}
-			result[i], err = UnmarshalAny(data)
-			if err != nil {
-				return nil, err
+			if d.err != nil {
+				return nil
 			}
-			data = data[size:]
+			return elements
 		}
-		return result, nil
-	case typeRawArray:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return nil, err
+		elements := make([]Any, n)
+		for i := range elements {
+			elements[i] = New(d.value(depth + 1))
 		}
-		return data[4 : 4+uintptr(length)], nil
-	case typeInt32Array:
-		return decodePacked[int32](data)
-	case typeInt64Array:
-		return decodePacked[int64](data)
-	case typeFloat32Array:
-		return decodePacked[float32](data)
-	case typeFloat64Array:
-		return decodePacked[float64](data)
-	case typeStringArray:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return nil, err
+		if d.err != nil {
+			return nil
 		}
-		result := make([]string, length)
-		for i := uint32(0); i < length; i++ {
-			result[i], err = decodeString(data)
-			if err != nil {
-				return nil, err
-			}
-			data = data[(len(result[i])+3)&^3:] // must be multiple of 4 bytes
+		if containers.NewArray == nil {
+			d.fail(fmt.Errorf("%w: graphics.gd/variant/Array is not imported", ErrContainers))
+			return nil
 		}
-		return result, nil
-	case typeVector2Array:
-		return decodePacked[Vector2.XY](data)
-	case typeVector3Array:
-		return decodePacked[Vector3.XYZ](data)
-	case typeColorArray:
-		return decodePacked[Color.RGBA](data)
+		return containers.NewArray(elements)
+	case TypePackedByteArray:
+		n := d.count(d.u32(), 1)
+		data := append([]byte{}, d.bytes(n)...)
+		d.bytes((4 - n%4) % 4)
+		return data
+	case TypePackedInt32Array:
+		return decodePacked(d, 4, func() int32 { return int32(d.u32()) })
+	case TypePackedInt64Array:
+		return decodePacked(d, 8, func() int64 { return int64(d.u64()) })
+	case TypePackedFloat32Array:
+		return decodePacked(d, 4, func() float32 { return math.Float32frombits(d.u32()) })
+	case TypePackedFloat64Array:
+		return decodePacked(d, 8, func() float64 { return math.Float64frombits(d.u64()) })
+	case TypePackedStringArray:
+		return decodePacked(d, 4, d.str)
+	case TypePackedVector2Array:
+		return decodePacked(d, realSize(wide)*2, func() Vector2.XY { return d.vector2(wide) })
+	case TypePackedVector3Array:
+		return decodePacked(d, realSize(wide)*3, func() Vector3.XYZ { return d.vector3(wide) })
+	case TypePackedVector4Array:
+		return decodePacked(d, realSize(wide)*4, func() Vector4.XYZW { return d.vector4(wide) })
+	case TypePackedColorArray:
+		return decodePacked(d, 16, d.color)
+	default:
+		d.fail(fmt.Errorf("%w: unknown type %d", ErrFormat, t))
+		return nil
 	}
-	return nil, fmt.Errorf("unsupported variant type %d", vtype)
 }
 
-// Decodes a size of a Variant from the bytes starting at offset. Requires at least 4 bytes of data
-// starting at the offset, otherwise fails.
-func UnmarshalSize(data []byte) (uintptr, error) {
-	header, err := decode[uint32](data)
-	if err != nil {
-		return 0, err
+func realSize(wide bool) int {
+	if wide {
+		return 8
 	}
-	vtype := header & 0xFF
-	flags := header >> 16
-	data = data[4:]
-	switch vtype {
-	case typeNull:
-		return 4, nil
-	case typeBool:
-		return 4 + 4, nil
-	case typeInteger, typeFloat:
-		if flags&encodeFlag64 != 0 {
-			return 4 + 8, nil
-		}
-		return 4 + 4, nil
-	case typeString:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return 0, err
-		}
-		return 4 + uintptr(length), nil
-	case typeVector2:
-		return 4 + unsafe.Sizeof(Vector2.XY{}), nil
-	case typeRect2:
-		return 4 + unsafe.Sizeof(Rect2.PositionSize{}), nil
-	case typeVector3:
-		return 4 + unsafe.Sizeof(Vector3.XYZ{}), nil
-	case typeTransform2D:
-		return 4 + unsafe.Sizeof(Transform2D.OriginXY{}), nil
-	case typePlane:
-		return 4 + unsafe.Sizeof(Plane.NormalD{}), nil
-	case typeQuaternion:
-		return 4 + unsafe.Sizeof(Quaternion.IJKX{}), nil
-	case typeAABB:
-		return 4 + unsafe.Sizeof(AABB.PositionSize{}), nil
-	case typeBasis:
-		return 4 + unsafe.Sizeof(Basis.XYZ{}), nil
-	case typeTransform3D:
-		return 4 + unsafe.Sizeof(Transform3D.BasisOrigin{}), nil
-	case typeColor:
-		return 4 + unsafe.Sizeof(Color.RGBA{}), nil
-	case typeNodePath:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return 0, err
-		}
-		if length&0x80000000 != 0 {
-			count := length & 0x7FFFFFFF
-			var size uintptr = 4
-			for i := uintptr(0); i < uintptr(count); i++ {
-				length, err := decode[uint32](data[size:])
-				if err != nil {
-					return 0, err
-				}
-				size += 4 + uintptr(length)
-				data = data[size:]
-			}
-			return size, nil
-		}
-		return 4 + uintptr(length), nil
-	case typeRID:
-		return 4 + 8, nil
-	case typeObject:
-		return 4, nil
-	case typeDictionary:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return 0, err
-		}
-		size := uintptr(4)
-		for i := uint32(0); i < length; i++ {
-			s, err := UnmarshalSize(data[size:])
-			if err != nil {
-				return 0, err
-			}
-			size += s
-			data = data[size:]
-		}
-		return size, nil
-	case typeArray:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return 0, err
-		}
-		size := uintptr(4)
-		for i := uint32(0); i < length; i++ {
-			s, err := UnmarshalSize(data[size:])
-			if err != nil {
-				return 0, err
-			}
-			size += s
-			data = data[size:]
-		}
-		return size, nil
-	case typeInt32Array:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return 0, err
-		}
-		return 4 + 4 + uintptr(length)*unsafe.Sizeof(int32(0)), nil
-	case typeInt64Array:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return 0, err
-		}
-		return 4 + 4 + uintptr(length)*unsafe.Sizeof(int64(0)), nil
-	case typeFloat32Array:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return 0, err
-		}
-		return 4 + 4 + uintptr(length)*unsafe.Sizeof(float32(0)), nil
-	case typeFloat64Array:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return 0, err
-		}
-		return 4 + 4 + uintptr(length)*unsafe.Sizeof(float64(0)), nil
-	case typeStringArray:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return 0, err
-		}
-		size := uintptr(4)
-		for i := uint32(0); i < length; i++ {
-			length, err := decode[uint32](data[size:])
-			if err != nil {
-				return 0, err
-			}
-			size += 4 + uintptr(length)
-			data = data[size:]
-		}
-		return size, nil
-	case typeVector2Array:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return 0, err
-		}
-		return 4 + 4 + uintptr(length)*unsafe.Sizeof(Vector2.XY{}), nil
-	case typeVector3Array:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return 0, err
-		}
-		return 4 + 4 + uintptr(length)*unsafe.Sizeof(Vector3.XYZ{}), nil
-	case typeColorArray:
-		length, err := decode[uint32](data)
-		if err != nil {
-			return 0, err
+	return 4
+}
+
+func decodePacked[T any](d *decoder, size int, read func() T) []T {
+	elements := make([]T, d.count(d.u32(), size))
+	for i := range elements {
+		elements[i] = read()
+	}
+	if d.err != nil {
+		return nil
+	}
+	return elements
+}
+
+func (d *decoder) object(header uint32, depth int) any {
+	if header&encodeFlagObjectAsID != 0 {
+		if id := d.u64(); id != 0 {
+			return ObjectID(id)
 		}
-		return 4 + 4 + uintptr(length)*unsafe.Sizeof(Color.RGBA{}), nil
+		return nil
+	}
+	if !d.objects {
+		d.fail(ErrObjects)
+		return nil
+	}
+	if !d.nest(depth) {
+		return nil
+	}
+	class := d.str()
+	if class == "" {
+		return nil
+	}
+	object := EncodedObject{Class: class}
+	object.Properties = make([]ObjectProperty, d.count(d.u32(), 8))
+	for i := range object.Properties {
+		object.Properties[i] = ObjectProperty{Name: d.str(), Value: d.value(depth + 1)}
+	}
+	if d.err != nil {
+		return nil
 	}
-	return 0, fmt.Errorf("unknown type %d", vtype)
+	return object
 }
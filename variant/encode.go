@@ -1,240 +1,465 @@
 package variant
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
+	"iter"
 	"math"
 	"reflect"
+	"slices"
+	"strings"
 	"unsafe"
 
 	"graphics.gd/variant/AABB"
 	"graphics.gd/variant/Basis"
 	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Float"
 	"graphics.gd/variant/Path"
 	"graphics.gd/variant/Plane"
+	"graphics.gd/variant/Projection"
 	"graphics.gd/variant/Quaternion"
 	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Rect2i"
 	"graphics.gd/variant/String"
 	"graphics.gd/variant/Transform2D"
 	"graphics.gd/variant/Transform3D"
 	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
 	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector3i"
+	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/Vector4i"
+	"graphics.gd/variant/internal/containers"
 )
 
-// Marshal a variant-encoded value.
+// header flags of the engine's binary encoding.
+const (
+	encodeFlag64         = 1 << 16 // 64-bit integers, floats and real_t based types.
+	encodeFlagObjectAsID = 1 << 16 // objects encoded as an instance ID.
+)
+
+// double is true when [Float.X] is a float64, real_t based types then use 64-bit floats.
+const double = unsafe.Sizeof(Float.X(0)) == 8
+
+// Marshal returns the engine's binary encoding of the value, as used by var_to_bytes. Objects
+// can only be encoded as an [ObjectID], use an [Encoder] with AllowObjects to encode an
+// [EncodedObject] in full.
 func Marshal(value interface{}) ([]byte, error) { //gd:var_to_bytes var_to_bytes_with_objects
-	var buf []byte
-	switch value := value.(type) {
+	e := encoder{maxDepth: DefaultMaxDepth}
+	if err := e.value(value, 0); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+type encoder struct {
+	buf      []byte
+	objects  bool
+	maxDepth int
+}
+
+func (e *encoder) header(t Type, flags uint32) { e.u32(uint32(t) | flags) }
+func (e *encoder) u32(v uint32)                { e.buf = binary.LittleEndian.AppendUint32(e.buf, v) }
+func (e *encoder) u64(v uint64)                { e.buf = binary.LittleEndian.AppendUint64(e.buf, v) }
+func (e *encoder) f32(v Float.X)               { e.u32(math.Float32bits(float32(v))) }
+
+func (e *encoder) real(v Float.X) {
+	if double {
+		e.u64(math.Float64bits(float64(v)))
+	} else {
+		e.f32(v)
+	}
+}
+
+// realFlags returns the header flags for a real_t based type.
+func realFlags() uint32 {
+	if double {
+		return encodeFlag64
+	}
+	return 0
+}
+
+// pad the buffer after n bytes of data, to a multiple of 4 bytes.
+func (e *encoder) pad(n int) { e.buf = append(e.buf, make([]byte, (4-n%4)%4)...) }
+
+func (e *encoder) str(s string) {
+	e.u32(uint32(len(s)))
+	e.buf = append(e.buf, s...)
+	e.pad(len(s))
+}
+
+func (e *encoder) vector2(v Vector2.XY) {
+	e.real(v.X)
+	e.real(v.Y)
+}
+
+func (e *encoder) vector3(v Vector3.XYZ) {
+	e.real(v.X)
+	e.real(v.Y)
+	e.real(v.Z)
+}
+
+func (e *encoder) vector4(v Vector4.XYZW) {
+	e.real(v.X)
+	e.real(v.Y)
+	e.real(v.Z)
+	e.real(v.W)
+}
+
+func (e *encoder) vector2i(v Vector2i.XY) {
+	e.u32(uint32(v.X))
+	e.u32(uint32(v.Y))
+}
+
+func (e *encoder) vector3i(v Vector3i.XYZ) {
+	e.u32(uint32(v.X))
+	e.u32(uint32(v.Y))
+	e.u32(uint32(v.Z))
+}
+
+func (e *encoder) basis(b Basis.XYZ) {
+	e.vector3(b.X)
+	e.vector3(b.Y)
+	e.vector3(b.Z)
+}
+
+func (e *encoder) int(v int64) {
+	if v == int64(int32(v)) {
+		e.header(TypeInt, 0)
+		e.u32(uint32(v))
+		return
+	}
+	e.header(TypeInt, encodeFlag64)
+	e.u64(uint64(v))
+}
+
+func (e *encoder) float(v float64) {
+	if float64(float32(v)) == v {
+		e.header(TypeFloat, 0)
+		e.u32(math.Float32bits(float32(v)))
+		return
+	}
+	e.header(TypeFloat, encodeFlag64)
+	e.u64(math.Float64bits(v))
+}
+
+// nodePath encodes a path like "/root/Node:position:x", with names and subnames stored separately.
+func (e *encoder) nodePath(path string) {
+	var flags uint32
+	if strings.HasPrefix(path, "/") {
+		flags |= 1
+	}
+	path, subpath, _ := strings.Cut(path, ":")
+	names := slices.DeleteFunc(strings.Split(path, "/"), func(s string) bool { return s == "" })
+	subnames := slices.DeleteFunc(strings.Split(subpath, ":"), func(s string) bool { return s == "" })
+	e.header(TypeNodePath, 0)
+	e.u32(uint32(len(names)) | 0x80000000)
+	e.u32(uint32(len(subnames)))
+	e.u32(flags)
+	for _, name := range append(names, subnames...) {
+		e.str(name)
+	}
+}
+
+func (e *encoder) nest(depth int) error {
+	if depth >= e.maxDepth {
+		return ErrDepth
+	}
+	return nil
+}
+
+func (e *encoder) object(object EncodedObject, depth int) error {
+	if !e.objects {
+		return ErrObjects
+	}
+	if err := e.nest(depth); err != nil {
+		return err
+	}
+	e.header(TypeObject, 0)
+	e.str(object.Class)
+	if object.Class == "" {
+		return nil
+	}
+	e.u32(uint32(len(object.Properties)))
+	for _, property := range object.Properties {
+		e.str(property.Name)
+		if err := e.value(property.Value, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *encoder) dictionary(entries iter.Seq2[Any, Any], depth int) error {
+	if err := e.nest(depth); err != nil {
+		return err
+	}
+	e.header(TypeDictionary, 0)
+	count := len(e.buf)
+	e.u32(0)
+	var n uint32
+	for key, value := range entries {
+		if err := e.value(key, depth+1); err != nil {
+			return err
+		}
+		if err := e.value(value, depth+1); err != nil {
+			return err
+		}
+		n++
+	}
+	binary.LittleEndian.PutUint32(e.buf[count:], n)
+	return nil
+}
+
+// value appends the encoding of value to the buffer.
+func (e *encoder) value(value any, depth int) error {
+	switch v := value.(type) {
+	case nil:
+		e.header(TypeNil, 0)
+	case Any:
+		return e.value(v.Interface(), depth)
 	case bool:
-		buf = binary.LittleEndian.AppendUint32(buf, typeBool)
-		if value {
-			buf = binary.LittleEndian.AppendUint32(buf, 1)
-		} else {
-			buf = binary.LittleEndian.AppendUint32(buf, 0)
-		}
-	case int8:
-		var i32 int32 = int32(value)
-		buf = binary.LittleEndian.AppendUint32(buf, typeInteger)
-		buf = binary.LittleEndian.AppendUint32(buf, *(*uint32)(unsafe.Pointer(&i32)))
-	case int16:
-		var i32 int32 = int32(value)
-		buf = binary.LittleEndian.AppendUint32(buf, typeInteger)
-		buf = binary.LittleEndian.AppendUint32(buf, *(*uint32)(unsafe.Pointer(&i32)))
-	case int32:
-		var i32 int32 = int32(value)
-		buf = binary.LittleEndian.AppendUint32(buf, typeInteger)
-		buf = binary.LittleEndian.AppendUint32(buf, *(*uint32)(unsafe.Pointer(&i32)))
-	case int64:
-		buf = binary.LittleEndian.AppendUint32(buf, typeInteger&encodeFlag64)
-		buf = binary.LittleEndian.AppendUint64(buf, *(*uint64)(unsafe.Pointer(&value)))
-	case int:
-		if reflect.TypeOf(int(0)).Size() == 4 {
-			var i32 int32 = int32(value)
-			buf = binary.LittleEndian.AppendUint32(buf, typeInteger)
-			buf = binary.LittleEndian.AppendUint32(buf, *(*uint32)(unsafe.Pointer(&i32)))
+		e.header(TypeBool, 0)
+		if v {
+			e.u32(1)
 		} else {
-			buf = binary.LittleEndian.AppendUint32(buf, typeInteger&encodeFlag64)
-			buf = binary.LittleEndian.AppendUint64(buf, *(*uint64)(unsafe.Pointer(&value)))
-		}
-	case uint8:
-		buf = binary.LittleEndian.AppendUint32(buf, typeInteger)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(value))
-	case uint16:
-		buf = binary.LittleEndian.AppendUint32(buf, typeInteger)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(value))
-	case uint32:
-		buf = binary.LittleEndian.AppendUint32(buf, typeInteger&encodeFlag64)
-		buf = binary.LittleEndian.AppendUint64(buf, uint64(value))
-	case uint64:
-		buf = binary.LittleEndian.AppendUint32(buf, typeRID)
-		buf = binary.LittleEndian.AppendUint64(buf, value)
-	case uint:
-		if reflect.TypeOf(uint(0)).Size() == 4 {
-			buf = binary.LittleEndian.AppendUint32(buf, typeInteger&encodeFlag64)
-			buf = binary.LittleEndian.AppendUint64(buf, uint64(value))
-		} else {
-			buf = binary.LittleEndian.AppendUint32(buf, typeRID)
-			buf = binary.LittleEndian.AppendUint64(buf, uint64(value))
-		}
-	case float32:
-		buf = binary.LittleEndian.AppendUint32(buf, typeFloat)
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value))
-	case float64:
-		buf = binary.LittleEndian.AppendUint32(buf, typeFloat)
-		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(value))
-	case string:
-		buf = binary.LittleEndian.AppendUint32(buf, typeString)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
-		buf = append(buf, value...)
-		for i := len(value); i%4 != 0; i++ {
-			buf = append(buf, 0)
-		}
-	case []byte:
-		buf = binary.LittleEndian.AppendUint32(buf, typeRawArray)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
-		buf = append(buf, value...)
-		for i := len(value); i%4 != 0; i++ {
-			buf = append(buf, 0)
+			e.u32(0)
 		}
+	case ObjectID:
+		e.header(TypeObject, encodeFlagObjectAsID)
+		e.u64(uint64(v))
+	case EncodedObject:
+		return e.object(v, depth)
+	case String.Readable:
+		e.header(TypeString, 0)
+		e.str(v.String())
+	case String.Name:
+		e.header(TypeStringName, 0)
+		e.str(v.String())
+	case Path.ToNode:
+		e.nodePath(v.String())
 	case Vector2.XY:
-		buf = binary.LittleEndian.AppendUint32(buf, typeVector2)
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Y))
+		e.header(TypeVector2, realFlags())
+		e.vector2(v)
+	case Vector2i.XY:
+		e.header(TypeVector2i, 0)
+		e.vector2i(v)
 	case Rect2.PositionSize:
-		buf = binary.LittleEndian.AppendUint32(buf, typeRect2)
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Position.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Position.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Size.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Size.Y))
+		e.header(TypeRect2, realFlags())
+		e.vector2(v.Position)
+		e.vector2(v.Size)
+	case Rect2i.PositionSize:
+		e.header(TypeRect2i, 0)
+		e.vector2i(v.Position)
+		e.vector2i(v.Size)
 	case Vector3.XYZ:
-		buf = binary.LittleEndian.AppendUint32(buf, typeVector3)
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Z))
+		e.header(TypeVector3, realFlags())
+		e.vector3(v)
+	case Vector3i.XYZ:
+		e.header(TypeVector3i, 0)
+		e.vector3i(v)
 	case Transform2D.OriginXY:
-		buf = binary.LittleEndian.AppendUint32(buf, typeTransform2D)
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.X.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.X.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Y.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Y.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Origin.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Origin.Y))
+		e.header(TypeTransform2D, realFlags())
+		e.vector2(v.X)
+		e.vector2(v.Y)
+		e.vector2(v.Origin)
+	case Vector4.XYZW:
+		e.header(TypeVector4, realFlags())
+		e.vector4(v)
+	case Vector4i.XYZW:
+		e.header(TypeVector4i, 0)
+		e.u32(uint32(v.X))
+		e.u32(uint32(v.Y))
+		e.u32(uint32(v.Z))
+		e.u32(uint32(v.W))
 	case Plane.NormalD:
-		buf = binary.LittleEndian.AppendUint32(buf, typePlane)
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Normal.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Normal.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Normal.Z))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.D))
+		e.header(TypePlane, realFlags())
+		e.vector3(v.Normal)
+		e.real(v.D)
 	case Quaternion.IJKX:
-		buf = binary.LittleEndian.AppendUint32(buf, typeQuaternion)
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.I))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.J))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.K))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.X))
+		e.header(TypeQuaternion, realFlags())
+		e.vector4(Vector4.XYZW{v.I, v.J, v.K, v.X})
 	case AABB.PositionSize:
-		buf = binary.LittleEndian.AppendUint32(buf, typeAABB)
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Position.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Position.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Position.Z))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Size.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Size.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Size.Z))
+		e.header(TypeAABB, realFlags())
+		e.vector3(v.Position)
+		e.vector3(v.Size)
 	case Basis.XYZ:
-		buf = binary.LittleEndian.AppendUint32(buf, typeBasis)
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.X.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.X.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.X.Z))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Y.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Y.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Y.Z))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Z.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Z.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Z.Z))
+		e.header(TypeBasis, realFlags())
+		e.basis(v)
 	case Transform3D.BasisOrigin:
-		buf = binary.LittleEndian.AppendUint32(buf, typeTransform3D)
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Basis.X.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Basis.X.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Basis.X.Z))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Basis.Y.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Basis.Y.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Basis.Y.Z))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Basis.Z.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Basis.Z.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Basis.Z.Z))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Origin.X))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Origin.Y))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.Origin.Z))
+		e.header(TypeTransform3D, realFlags())
+		e.basis(v.Basis)
+		e.vector3(v.Origin)
+	case Projection.XYZW:
+		e.header(TypeProjection, realFlags())
+		e.vector4(v.X)
+		e.vector4(v.Y)
+		e.vector4(v.Z)
+		e.vector4(v.W)
 	case Color.RGBA:
-		buf = binary.LittleEndian.AppendUint32(buf, typeColor)
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.R))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.G))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.B))
-		buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(value.A))
-	case Path.ToNode:
-		buf = binary.LittleEndian.AppendUint32(buf, typeNodePath)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(String.Length(value)))
-		buf = append(buf, value.String()...)
+		e.header(TypeColor, 0)
+		e.f32(v.R)
+		e.f32(v.G)
+		e.f32(v.B)
+		e.f32(v.A)
+	case []byte:
+		e.header(TypePackedByteArray, 0)
+		e.u32(uint32(len(v)))
+		e.buf = append(e.buf, v...)
+		e.pad(len(v))
 	case []int32:
-		buf = binary.LittleEndian.AppendUint32(buf, typeInt32Array)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
-		for _, v := range value {
-			var i32 int32 = int32(v)
-			buf = binary.LittleEndian.AppendUint32(buf, *(*uint32)(unsafe.Pointer(&i32)))
+		e.header(TypePackedInt32Array, 0)
+		e.u32(uint32(len(v)))
+		for _, x := range v {
+			e.u32(uint32(x))
 		}
 	case []int64:
-		buf = binary.LittleEndian.AppendUint32(buf, typeInt64Array)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
-		for _, v := range value {
-			buf = binary.LittleEndian.AppendUint64(buf, *(*uint64)(unsafe.Pointer(&v)))
+		e.header(TypePackedInt64Array, 0)
+		e.u32(uint32(len(v)))
+		for _, x := range v {
+			e.u64(uint64(x))
 		}
 	case []float32:
-		buf = binary.LittleEndian.AppendUint32(buf, typeFloat32Array)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
-		for _, v := range value {
-			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v))
+		e.header(TypePackedFloat32Array, 0)
+		e.u32(uint32(len(v)))
+		for _, x := range v {
+			e.u32(math.Float32bits(x))
 		}
 	case []float64:
-		buf = binary.LittleEndian.AppendUint32(buf, typeFloat64Array)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
-		for _, v := range value {
-			buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+		e.header(TypePackedFloat64Array, 0)
+		e.u32(uint32(len(v)))
+		for _, x := range v {
+			e.u64(math.Float64bits(x))
 		}
 	case []string:
-		buf = binary.LittleEndian.AppendUint32(buf, typeStringArray)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
-		for _, v := range value {
-			buf = binary.LittleEndian.AppendUint32(buf, uint32(len(v)))
-			buf = append(buf, v...)
-			for i := len(v); i%4 != 0; i++ {
-				buf = append(buf, 0)
-			}
+		e.header(TypePackedStringArray, 0)
+		e.u32(uint32(len(v)))
+		for _, s := range v {
+			e.str(s)
 		}
 	case []Vector2.XY:
-		buf = binary.LittleEndian.AppendUint32(buf, typeVector2Array)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
-		for _, v := range value {
-			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v.X))
-			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v.Y))
+		e.header(TypePackedVector2Array, realFlags())
+		e.u32(uint32(len(v)))
+		for _, x := range v {
+			e.vector2(x)
 		}
 	case []Vector3.XYZ:
-		buf = binary.LittleEndian.AppendUint32(buf, typeVector3Array)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
-		for _, v := range value {
-			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v.X))
-			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v.Y))
-			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v.Z))
+		e.header(TypePackedVector3Array, realFlags())
+		e.u32(uint32(len(v)))
+		for _, x := range v {
+			e.vector3(x)
+		}
+	case []Vector4.XYZW:
+		e.header(TypePackedVector4Array, realFlags())
+		e.u32(uint32(len(v)))
+		for _, x := range v {
+			e.vector4(x)
 		}
 	case []Color.RGBA:
-		buf = binary.LittleEndian.AppendUint32(buf, typeColorArray)
-		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
-		for _, v := range value {
-			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v.R))
-			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v.G))
-			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v.B))
-			buf = binary.LittleEndian.AppendUint32(buf, math.Float32bits(v.A))
+		e.header(TypePackedColorArray, 0)
+		e.u32(uint32(len(v)))
+		for _, x := range v {
+			e.f32(x.R)
+			e.f32(x.G)
+			e.f32(x.B)
+			e.f32(x.A)
+		}
+	case iter.Seq2[Any, Any]:
+		return e.dictionary(v, depth)
+	default:
+		for _, unwrap := range containers.Unwrap {
+			if unwrapped, ok := unwrap(value); ok {
+				return e.value(unwrapped, depth)
+			}
+		}
+		return e.reflect(reflect.ValueOf(value), depth)
+	}
+	return nil
+}
+
+// packedTypes that are encoded as packed arrays when they are the element type of a slice.
+var packedTypes = []reflect.Type{
+	reflect.TypeFor[byte](), reflect.TypeFor[int32](), reflect.TypeFor[int64](), reflect.TypeFor[float32](),
+	reflect.TypeFor[float64](), reflect.TypeFor[string](), reflect.TypeFor[Vector2.XY](),
+	reflect.TypeFor[Vector3.XYZ](), reflect.TypeFor[Vector4.XYZW](), reflect.TypeFor[Color.RGBA](),
+}
+
+// reflect encodes the value by its kind, named types are encoded as their underlying type.
+func (e *encoder) reflect(rvalue reflect.Value, depth int) error {
+	rtype := rvalue.Type()
+	switch rtype.Kind() {
+	case reflect.Bool:
+		return e.value(rvalue.Bool(), depth)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.int(rvalue.Int())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		e.int(int64(rvalue.Uint()))
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		e.header(TypeRID, 0)
+		e.u64(rvalue.Uint())
+	case reflect.Float32, reflect.Float64:
+		e.float(rvalue.Float())
+	case reflect.String:
+		e.header(TypeString, 0)
+		e.str(rvalue.String())
+	case reflect.Pointer, reflect.Interface:
+		if rvalue.IsNil() {
+			e.header(TypeNil, 0)
+			return nil
+		}
+		return e.value(rvalue.Elem().Interface(), depth)
+	case reflect.Func:
+		e.header(TypeCallable, 0)
+	case reflect.Slice, reflect.Array:
+		if rtype.Kind() == reflect.Slice && slices.Contains(packedTypes, rtype.Elem()) {
+			return e.value(rvalue.Convert(reflect.SliceOf(rtype.Elem())).Interface(), depth)
+		}
+		if err := e.nest(depth); err != nil {
+			return err
+		}
+		e.header(TypeArray, 0)
+		e.u32(uint32(rvalue.Len()))
+		for i := range rvalue.Len() {
+			if err := e.value(rvalue.Index(i).Interface(), depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if err := e.nest(depth); err != nil {
+			return err
+		}
+		// map keys are sorted by their encoding, so that the output is deterministic.
+		type entry struct {
+			key   []byte
+			value reflect.Value
+		}
+		entries := make([]entry, 0, rvalue.Len())
+		for iter := rvalue.MapRange(); iter.Next(); {
+			key := encoder{objects: e.objects, maxDepth: e.maxDepth}
+			if err := key.value(iter.Key().Interface(), depth+1); err != nil {
+				return err
+			}
+			entries = append(entries, entry{key.buf, iter.Value()})
+		}
+		slices.SortFunc(entries, func(a, b entry) int { return bytes.Compare(a.key, b.key) })
+		e.header(TypeDictionary, 0)
+		e.u32(uint32(len(entries)))
+		for _, entry := range entries {
+			e.buf = append(e.buf, entry.key...)
+			if err := e.value(entry.value.Interface(), depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		// structs are encoded in the same way as the Dictionary returned by [Encode].
+		encoded, err := encodeValue(rvalue, depth)
+		if err != nil {
+			return err
 		}
+		return e.value(encoded, depth)
 	default:
-		return nil, fmt.Errorf("unsupported type %T", value)
+		return fmt.Errorf("variant: cannot encode %s", rtype)
 	}
-	return buf, nil
+	return nil
 }
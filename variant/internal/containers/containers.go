@@ -0,0 +1,23 @@
+// Package containers lets the Array, Dictionary and Packed packages register themselves with the
+// variant package, which cannot import them directly.
+package containers
+
+import "reflect"
+
+var (
+	// NewArray returns an Array.Any with the given elements, which are a []variant.Any.
+	NewArray func(elements any) any
+
+	// NewTypedArray returns an Array.Contains[T] with the given elements, which are a []variant.Any
+	// with values of type T, reports false if there is no typed array for T.
+	NewTypedArray func(elem reflect.Type, elements any) (any, bool)
+
+	// NewDictionary returns a Dictionary.Any with the given entries, keys and values are both a
+	// []variant.Any of the same length.
+	NewDictionary func(keys, values any) any
+
+	// Unwrap functions convert an Array, Dictionary or Packed array into a Go value that the
+	// variant package understands: a []variant.Any for arrays, an iter.Seq2[variant.Any, variant.Any]
+	// for dictionaries and a slice of the element type for packed arrays.
+	Unwrap []func(value any) (any, bool)
+)
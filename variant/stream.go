@@ -0,0 +1,132 @@
+package variant
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+var (
+	ErrFormat  = errors.New("variant: invalid encoding")                   // the data is not a valid encoding.
+	ErrObjects = errors.New("variant: objects are not allowed")            // objects are only encoded in full when allowed.
+	ErrDepth   = errors.New("variant: maximum nesting depth exceeded")     // too many nested arrays, dictionaries or objects.
+	ErrSize    = errors.New("variant: encoded value exceeds maximum size") // the length prefix is larger than the limit.
+
+	// ErrContainers is returned by a [Decoder] for an Array or Dictionary when the
+	// graphics.gd/variant/Array or graphics.gd/variant/Dictionary package is not linked in.
+	ErrContainers = errors.New("variant: decoding containers requires the Array and Dictionary packages")
+)
+
+const (
+	DefaultMaxDepth = 1024     // nesting limit of arrays, dictionaries and objects, the same as the engine.
+	DefaultMaxSize  = 16 << 20 // size limit of an encoded value read by a [Decoder], in bytes.
+)
+
+// ObjectID identifies an Object instance by its instance ID, which is how objects are encoded
+// unless they are encoded in full as an [EncodedObject].
+type ObjectID uint64
+
+// EncodedObject is an Object encoded in full, with its class name and property values. These are
+// only accepted by an [Encoder] or [Decoder] with AllowObjects set, as decoding them in the engine
+// can instantiate arbitrary scripts.
+type EncodedObject struct {
+	Class      string
+	Properties []ObjectProperty
+}
+
+// ObjectProperty is the name and value of a property of an [EncodedObject].
+type ObjectProperty struct {
+	Name  string
+	Value any
+}
+
+// Encoder writes values to a stream in the same format as StreamPeer.put_var, each value is
+// prefixed with its length in bytes.
+type Encoder struct {
+	w   io.Writer
+	buf []byte
+
+	AllowObjects bool // encode [EncodedObject] values in full, like put_var with full_objects.
+	MaxDepth     int  // maximum nesting of arrays, dictionaries and objects.
+}
+
+// NewEncoder returns a new encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, MaxDepth: DefaultMaxDepth}
+}
+
+// Encode writes the length-prefixed encoding of value to the stream.
+func (enc *Encoder) Encode(value any) error {
+	e := encoder{buf: append(enc.buf[:0], 0, 0, 0, 0), objects: enc.AllowObjects, maxDepth: enc.MaxDepth}
+	if err := e.value(value, 0); err != nil {
+		return err
+	}
+	enc.buf = e.buf
+	if len(e.buf)-4 > math.MaxUint32 {
+		return ErrSize
+	}
+	binary.LittleEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+	_, err := enc.w.Write(e.buf)
+	return err
+}
+
+// Decoder reads values from a stream in the same format as StreamPeer.get_var. Values are read in
+// full before they are decoded, so the size of each value is limited by MaxSize and no allocation
+// can exceed the size of the encoded data.
+//
+// The variant package cannot import the Array and Dictionary packages, as they depend on it, so
+// they register themselves when they are imported. A program that decodes arrays or dictionaries
+// without importing them must do so for their side effects, otherwise [Decoder.Decode] returns
+// [ErrContainers]:
+//
+//	import (
+//		_ "graphics.gd/variant/Array"
+//		_ "graphics.gd/variant/Dictionary"
+//	)
+type Decoder struct {
+	r   io.Reader
+	buf []byte
+
+	AllowObjects bool // decode objects in full as [EncodedObject], like get_var with allow_objects.
+	MaxDepth     int  // maximum nesting of arrays, dictionaries and objects.
+	MaxSize      int  // maximum length of an encoded value, in bytes.
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r, MaxDepth: DefaultMaxDepth, MaxSize: DefaultMaxSize}
+}
+
+// Decode reads the next length-prefixed value from the stream. Integers are decoded as int64,
+// floats as float64, arrays as an Array.Any, dictionaries as a Dictionary.Any (see [Decoder] for the
+// packages this requires), packed arrays as Go slices and objects as either an
+// [ObjectID] or an [EncodedObject]. Callables and signals cannot be represented and are decoded
+// as nil. Returns [io.EOF] if the stream ends before the next value.
+func (dec *Decoder) Decode() (Any, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(dec.r, prefix[:]); err != nil {
+		return Nil, err
+	}
+	size := binary.LittleEndian.Uint32(prefix[:])
+	if uint64(size) > uint64(max(dec.MaxSize, 0)) {
+		return Nil, fmt.Errorf("%w: %d bytes", ErrSize, size)
+	}
+	if cap(dec.buf) < int(size) {
+		dec.buf = make([]byte, size)
+	}
+	data := dec.buf[:size]
+	if _, err := io.ReadFull(dec.r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Nil, err
+	}
+	d := decoder{data: data, objects: dec.AllowObjects, variants: true, maxDepth: dec.MaxDepth}
+	value := d.value(0)
+	if d.err != nil {
+		return Nil, d.err
+	}
+	return New(value), nil
+}
@@ -0,0 +1,214 @@
+package variant_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"graphics.gd/variant"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/Basis"
+	"graphics.gd/variant/Dictionary"
+	"graphics.gd/variant/Packed"
+	"graphics.gd/variant/Path"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/String"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/internal/containers"
+)
+
+func TestStreamWireFormat(t *testing.T) {
+	for _, test := range []struct {
+		value any
+		hex   string
+	}{
+		{nil, "0400000000000000"},
+		{int32(1), "080000000200000001000000"},
+		{int64(1) << 40, "0c000000020001000000000000010000"},
+		{0.5, "08000000030000000000003f"},
+		{"hi", "0c000000040000000200000068690000"},
+		{Vector2.New(1, 2), "0c000000050000000000803f00000040"},
+		{[]byte{1, 2, 3}, "0c0000001d0000000300000001020300"},
+		{Array.New(variant.New(1)), "100000001c000000010000000200000001000000"},
+		{variant.ObjectID(9), "0c000000180001000900000000000000"},
+		{Basis.XYZ{X: Vector3.New(1, 2, 3), Y: Vector3.New(4, 5, 6), Z: Vector3.New(7, 8, 9)},
+			"28000000110000000000803f000000400000404000008040" + "0000a0400000c0400000e0400000004100001041"},
+	} {
+		var buf bytes.Buffer
+		if err := variant.NewEncoder(&buf).Encode(test.value); err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(buf.Bytes()); got != test.hex {
+			t.Errorf("%v: expected %s, got %s", test.value, test.hex, got)
+		}
+	}
+}
+
+func TestStreamRoundTrip(t *testing.T) {
+	basis := Basis.XYZ{X: Vector3.New(1, 2, 3), Y: Vector3.New(4, 5, 6), Z: Vector3.New(7, 8, 9)}
+	values := []any{
+		nil, true, int64(-5), int64(1) << 40, 1.25, 0.1, "hello",
+		String.Name(String.New("name")),
+		Path.ToNode(String.New("/root/Node:position:x")),
+		basis,
+		RID.Any(3),
+		variant.ObjectID(7),
+		[]int32{1, -2},
+		[]string{"a", "bc", ""},
+		[]Vector3.XYZ{Vector3.New(1, 2, 3)},
+	}
+	var buf bytes.Buffer
+	enc := variant.NewEncoder(&buf)
+	for _, value := range values {
+		if err := enc.Encode(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dec := variant.NewDecoder(&buf)
+	for _, value := range values {
+		decoded, err := dec.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, want := variant.New(decoded).Interface(), variant.New(value).Interface()
+		if hex.EncodeToString(mustMarshal(t, got)) != hex.EncodeToString(mustMarshal(t, want)) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	}
+	if _, err := dec.Decode(); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestStreamContainers(t *testing.T) {
+	var buf bytes.Buffer
+	enc := variant.NewEncoder(&buf)
+	if err := enc.Encode(map[string]any{"b": []any{1, "two"}, "a": Packed.New[int32](3)}); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := variant.NewDecoder(&buf).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dictionary, ok := decoded.Interface().(Dictionary.Any)
+	if !ok || dictionary.Len() != 2 {
+		t.Fatalf("expected a dictionary, got %T", decoded.Interface())
+	}
+	keys, values := dictionary.Keys(), dictionary.Values()
+	if keys[0].String() != "a" || keys[1].String() != "b" {
+		t.Fatalf("expected sorted keys, got %v", keys)
+	}
+	if packed, ok := values[0].Interface().([]int32); !ok || len(packed) != 1 || packed[0] != 3 {
+		t.Fatalf("unexpected packed array %v", values[0].Interface())
+	}
+	array, ok := values[1].Interface().(Array.Any)
+	if !ok || array.Len() != 2 || array.Index(0).Int() != 1 || array.Index(1).String() != "two" {
+		t.Fatalf("unexpected array %v", values[1].Interface())
+	}
+}
+
+func TestStreamWithoutContainers(t *testing.T) {
+	var buf bytes.Buffer
+	if err := variant.NewEncoder(&buf).Encode([]any{1}); err != nil {
+		t.Fatal(err)
+	}
+	newArray := containers.NewArray
+	containers.NewArray = nil // as if the Array package was not imported.
+	defer func() { containers.NewArray = newArray }()
+	if _, err := variant.NewDecoder(&buf).Decode(); !errors.Is(err, variant.ErrContainers) {
+		t.Fatalf("expected ErrContainers, got %v", err)
+	}
+}
+
+func TestUnmarshalAny(t *testing.T) {
+	data := mustMarshal(t, []any{int32(1), float32(0.5), map[string]any{"a": []any{}}})
+	value, err := variant.UnmarshalAny(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	array, ok := value.([]any)
+	if !ok || len(array) != 3 || array[0] != int32(1) || array[1] != float32(0.5) {
+		t.Fatalf("unexpected value %#v", value)
+	}
+	if dictionary, ok := array[2].(map[any]any); !ok || len(dictionary) != 1 {
+		t.Fatalf("unexpected dictionary %#v", array[2])
+	}
+	object := variant.EncodedObject{Class: "Resource"}
+	if _, err := variant.Marshal(object); !errors.Is(err, variant.ErrObjects) {
+		t.Fatalf("expected ErrObjects, got %v", err)
+	}
+}
+
+func TestStreamLimits(t *testing.T) {
+	// a length prefix larger than the limit is rejected before anything is allocated.
+	dec := variant.NewDecoder(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0x7f}))
+	if _, err := dec.Decode(); !errors.Is(err, variant.ErrSize) {
+		t.Fatalf("expected ErrSize, got %v", err)
+	}
+	// an array that claims more elements than there is data for.
+	hostile, _ := hex.DecodeString("080000001c000000ffffff0f")
+	if _, err := variant.NewDecoder(bytes.NewReader(hostile)).Decode(); !errors.Is(err, variant.ErrFormat) {
+		t.Fatalf("expected ErrFormat, got %v", err)
+	}
+	var nested any = []any{}
+	for range 10 {
+		nested = []any{nested}
+	}
+	var buf bytes.Buffer
+	enc := variant.NewEncoder(&buf)
+	enc.MaxDepth = 5
+	if err := enc.Encode(nested); !errors.Is(err, variant.ErrDepth) {
+		t.Fatalf("expected ErrDepth, got %v", err)
+	}
+	buf.Reset()
+	if err := variant.NewEncoder(&buf).Encode(nested); err != nil {
+		t.Fatal(err)
+	}
+	dec = variant.NewDecoder(&buf)
+	dec.MaxDepth = 5
+	if _, err := dec.Decode(); !errors.Is(err, variant.ErrDepth) {
+		t.Fatalf("expected ErrDepth, got %v", err)
+	}
+}
+
+func TestStreamObjects(t *testing.T) {
+	object := variant.EncodedObject{Class: "Resource", Properties: []variant.ObjectProperty{{Name: "name", Value: "x"}}}
+	var buf bytes.Buffer
+	enc := variant.NewEncoder(&buf)
+	if err := enc.Encode(object); !errors.Is(err, variant.ErrObjects) {
+		t.Fatalf("expected ErrObjects, got %v", err)
+	}
+	enc.AllowObjects = true
+	if err := enc.Encode(object); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Clone(buf.Bytes())
+	if _, err := variant.NewDecoder(bytes.NewReader(data)).Decode(); !errors.Is(err, variant.ErrObjects) {
+		t.Fatalf("expected ErrObjects, got %v", err)
+	}
+	dec := variant.NewDecoder(bytes.NewReader(data))
+	dec.AllowObjects = true
+	decoded, err := dec.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := decoded.Interface().(variant.EncodedObject)
+	if !ok || got.Class != "Resource" || len(got.Properties) != 1 || got.Properties[0].Value != "x" {
+		t.Fatalf("unexpected object %#v", decoded.Interface())
+	}
+	if decoded.Type() != variant.TypeObject {
+		t.Fatalf("unexpected type %v", decoded.Type())
+	}
+}
+
+func mustMarshal(t *testing.T, value any) []byte {
+	t.Helper()
+	data, err := variant.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,433 @@
+package variant
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"reflect"
+	"slices"
+
+	"graphics.gd/variant/AABB"
+	"graphics.gd/variant/Basis"
+	"graphics.gd/variant/Color"
+	"graphics.gd/variant/Path"
+	"graphics.gd/variant/Plane"
+	"graphics.gd/variant/Projection"
+	"graphics.gd/variant/Quaternion"
+	"graphics.gd/variant/RID"
+	"graphics.gd/variant/Rect2"
+	"graphics.gd/variant/Rect2i"
+	"graphics.gd/variant/String"
+	"graphics.gd/variant/Transform2D"
+	"graphics.gd/variant/Transform3D"
+	"graphics.gd/variant/Vector2"
+	"graphics.gd/variant/Vector2i"
+	"graphics.gd/variant/Vector3"
+	"graphics.gd/variant/Vector3i"
+	"graphics.gd/variant/Vector4"
+	"graphics.gd/variant/Vector4i"
+	"graphics.gd/variant/internal/containers"
+)
+
+// Encode converts a Go value into a variant, recursing through structs, maps, slices and arrays:
+//
+//   - structs become a Dictionary, keyed by field name.
+//   - maps become a Dictionary, with the entries sorted by key.
+//   - slices of numbers, strings, vectors and colors become packed arrays.
+//   - other slices and arrays become a typed Array, when their elements encode to the same builtin
+//     type, otherwise an untyped Array.
+//
+// Field names follow the gd tag, in the same way as the fields of a registered class, fields tagged
+// with "-" are skipped and embedded structs without a tag have their fields inlined. Integers are
+// encoded as int64 and floats as float64. The Array and Dictionary packages must be imported.
+func Encode(value any) (Any, error) {
+	encoded, err := encodeValue(reflect.ValueOf(value), 0)
+	if err != nil {
+		return Nil, err
+	}
+	return New(encoded), nil
+}
+
+// Decode stores the variant in the value pointed to by target, reversing [Encode]. Dictionary
+// entries are matched to struct fields by name, entries without a matching field are ignored, as
+// are fields without a matching entry.
+func Decode(value Any, target any) error {
+	rvalue := reflect.ValueOf(target)
+	if rvalue.Kind() != reflect.Pointer || rvalue.IsNil() {
+		return fmt.Errorf("variant: cannot decode into %T, a non-nil pointer is required", target)
+	}
+	return decodeValue(value.Interface(), rvalue.Elem(), 0)
+}
+
+type structField struct {
+	name  string
+	index []int
+}
+
+// structFields returns the fields of a struct that are mapped to dictionary entries.
+func structFields(rtype reflect.Type) []structField {
+	var fields []structField
+	for i := range rtype.NumField() {
+		field := rtype.Field(i)
+		tag := field.Tag.Get("gd")
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			for _, inner := range structFields(field.Type) {
+				inner.index = append([]int{i}, inner.index...)
+				fields = append(fields, inner)
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag != "" {
+			name = tag
+		}
+		fields = append(fields, structField{name: name, index: field.Index})
+	}
+	return fields
+}
+
+// isBuiltin reports whether the value is already a builtin variant type, that is encoded as-is.
+func isBuiltin(value any) bool {
+	switch value.(type) {
+	case Any, ObjectID, EncodedObject, String.Readable, String.Name, Path.ToNode, RID.Any,
+		Vector2.XY, Vector2i.XY, Rect2.PositionSize, Rect2i.PositionSize, Vector3.XYZ, Vector3i.XYZ,
+		Transform2D.OriginXY, Vector4.XYZW, Vector4i.XYZW, Plane.NormalD, Quaternion.IJKX, AABB.PositionSize,
+		Basis.XYZ, Transform3D.BasisOrigin, Projection.XYZW, Color.RGBA:
+		return true
+	}
+	for _, unwrap := range containers.Unwrap {
+		if _, ok := unwrap(value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// packedKind returns the packed slice type for slices with elements of the given kind.
+func packedKind(elem reflect.Type) (reflect.Type, bool) {
+	if slices.Contains(packedTypes, elem) {
+		return reflect.SliceOf(elem), true
+	}
+	switch elem.Kind() {
+	case reflect.Uint8:
+		return reflect.TypeFor[[]byte](), true
+	case reflect.Int32:
+		return reflect.TypeFor[[]int32](), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int64, reflect.Uint16, reflect.Uint32:
+		return reflect.TypeFor[[]int64](), true
+	case reflect.Float32:
+		return reflect.TypeFor[[]float32](), true
+	case reflect.Float64:
+		return reflect.TypeFor[[]float64](), true
+	case reflect.String:
+		return reflect.TypeFor[[]string](), true
+	}
+	return nil, false
+}
+
+// encodeValue returns a Go value that represents the given value as a variant.
+func encodeValue(rvalue reflect.Value, depth int) (any, error) {
+	if !rvalue.IsValid() {
+		return nil, nil
+	}
+	if depth >= DefaultMaxDepth {
+		return nil, ErrDepth
+	}
+	rtype := rvalue.Type()
+	if rvalue.CanInterface() && isBuiltin(rvalue.Interface()) {
+		return rvalue.Interface(), nil
+	}
+	switch rtype.Kind() {
+	case reflect.Bool:
+		return rvalue.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rvalue.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if rvalue.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("variant: %d overflows int64", rvalue.Uint())
+		}
+		return int64(rvalue.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rvalue.Float(), nil
+	case reflect.String:
+		return rvalue.String(), nil
+	case reflect.Func:
+		return rvalue.Interface(), nil
+	case reflect.Pointer, reflect.Interface:
+		if rvalue.IsNil() {
+			return nil, nil
+		}
+		return encodeValue(rvalue.Elem(), depth)
+	case reflect.Slice, reflect.Array:
+		if packed, ok := packedKind(rtype.Elem()); ok {
+			slice := reflect.MakeSlice(packed, rvalue.Len(), rvalue.Len())
+			for i := range rvalue.Len() {
+				slice.Index(i).Set(rvalue.Index(i).Convert(packed.Elem()))
+			}
+			return slice.Interface(), nil
+		}
+		return encodeArray(rvalue, depth)
+	case reflect.Map:
+		keys, values := make([]Any, 0, rvalue.Len()), make([]Any, 0, rvalue.Len())
+		var order [][]byte
+		for iter := rvalue.MapRange(); iter.Next(); {
+			key, err := encodeValue(iter.Key(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			value, err := encodeValue(iter.Value(), depth+1)
+			if err != nil {
+				return nil, err
+			}
+			encoded, err := Marshal(key)
+			if err != nil {
+				return nil, err
+			}
+			i, _ := slices.BinarySearchFunc(order, encoded, bytes.Compare)
+			order = slices.Insert(order, i, encoded)
+			keys = slices.Insert(keys, i, New(key))
+			values = slices.Insert(values, i, New(value))
+		}
+		return newDictionary(keys, values)
+	case reflect.Struct:
+		fields := structFields(rtype)
+		keys, values := make([]Any, 0, len(fields)), make([]Any, 0, len(fields))
+		for _, field := range fields {
+			value, err := encodeValue(rvalue.FieldByIndex(field.index), depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.name, err)
+			}
+			keys = append(keys, New(field.name))
+			values = append(values, New(value))
+		}
+		return newDictionary(keys, values)
+	default:
+		return nil, fmt.Errorf("variant: cannot encode %s", rtype)
+	}
+}
+
+// encodeArray returns a typed array if every element encodes to the same builtin type.
+func encodeArray(rvalue reflect.Value, depth int) (any, error) {
+	if containers.NewArray == nil {
+		return nil, errors.New("variant: encoding an Array requires the graphics.gd/variant/Array package")
+	}
+	var (
+		elements = make([]Any, rvalue.Len())
+		elem     reflect.Type
+		typed    = rvalue.Type().Elem().Kind() != reflect.Interface
+	)
+	for i := range elements {
+		value, err := encodeValue(rvalue.Index(i), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case value == nil:
+			typed = false
+		case elem == nil:
+			elem = reflect.TypeOf(value)
+		case elem != reflect.TypeOf(value):
+			typed = false
+		}
+		elements[i] = New(value)
+	}
+	if typed && elem != nil && containers.NewTypedArray != nil {
+		if array, ok := containers.NewTypedArray(elem, elements); ok {
+			return array, nil
+		}
+	}
+	return containers.NewArray(elements), nil
+}
+
+func newDictionary(keys, values []Any) (any, error) {
+	if containers.NewDictionary == nil {
+		return nil, errors.New("variant: encoding a Dictionary requires the graphics.gd/variant/Dictionary package")
+	}
+	return containers.NewDictionary(keys, values), nil
+}
+
+// unwrapValue returns the Go representation of Array, Dictionary and Packed values.
+func unwrapValue(value any) any {
+	for _, unwrap := range containers.Unwrap {
+		if unwrapped, ok := unwrap(value); ok {
+			return unwrapped
+		}
+	}
+	return value
+}
+
+// decodeValue stores value into rvalue, converting between compatible types.
+func decodeValue(value any, rvalue reflect.Value, depth int) error {
+	if v, ok := value.(Any); ok {
+		value = v.Interface()
+	}
+	if depth >= DefaultMaxDepth {
+		return ErrDepth
+	}
+	rtype := rvalue.Type()
+	if value == nil {
+		rvalue.SetZero()
+		return nil
+	}
+	if rtype == reflect.TypeFor[Any]() {
+		rvalue.Set(reflect.ValueOf(New(value)))
+		return nil
+	}
+	if reflect.TypeOf(value).AssignableTo(rtype) {
+		rvalue.Set(reflect.ValueOf(value))
+		return nil
+	}
+	mismatch := fmt.Errorf("variant: cannot decode %T into %s", value, rtype)
+	source := reflect.ValueOf(unwrapValue(value))
+	switch rtype.Kind() {
+	case reflect.Bool:
+		if source.Kind() != reflect.Bool {
+			return mismatch
+		}
+		rvalue.SetBool(source.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch source.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			i = source.Int()
+		case reflect.Float32, reflect.Float64:
+			if f := source.Float(); f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+				i = int64(f)
+			} else {
+				return mismatch
+			}
+		default:
+			return mismatch
+		}
+		if rvalue.OverflowInt(i) {
+			return fmt.Errorf("variant: %d overflows %s", i, rtype)
+		}
+		rvalue.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		switch source.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if source.Int() < 0 {
+				return fmt.Errorf("variant: %d overflows %s", source.Int(), rtype)
+			}
+			u = uint64(source.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			u = source.Uint()
+		case reflect.Float32, reflect.Float64:
+			if f := source.Float(); f == math.Trunc(f) && f >= 0 && f < math.MaxUint64 {
+				u = uint64(f)
+			} else {
+				return mismatch
+			}
+		default:
+			return mismatch
+		}
+		if rvalue.OverflowUint(u) {
+			return fmt.Errorf("variant: %d overflows %s", u, rtype)
+		}
+		rvalue.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		switch source.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			rvalue.SetFloat(float64(source.Int()))
+		case reflect.Float32, reflect.Float64:
+			rvalue.SetFloat(source.Float())
+		default:
+			return mismatch
+		}
+	case reflect.String:
+		switch s := value.(type) {
+		case string:
+			rvalue.SetString(s)
+		case String.Readable:
+			rvalue.SetString(s.String())
+		case String.Name:
+			rvalue.SetString(s.String())
+		case Path.ToNode:
+			rvalue.SetString(s.String())
+		default:
+			if source.Kind() != reflect.String {
+				return mismatch
+			}
+			rvalue.SetString(source.String())
+		}
+	case reflect.Pointer:
+		if rvalue.IsNil() {
+			rvalue.Set(reflect.New(rtype.Elem()))
+		}
+		return decodeValue(value, rvalue.Elem(), depth)
+	case reflect.Slice:
+		if source.Kind() != reflect.Slice {
+			return mismatch
+		}
+		slice := reflect.MakeSlice(rtype, source.Len(), source.Len())
+		for i := range source.Len() {
+			if err := decodeValue(source.Index(i).Interface(), slice.Index(i), depth+1); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+		rvalue.Set(slice)
+	case reflect.Array:
+		if source.Kind() != reflect.Slice || source.Len() > rtype.Len() {
+			return mismatch
+		}
+		rvalue.SetZero()
+		for i := range source.Len() {
+			if err := decodeValue(source.Index(i).Interface(), rvalue.Index(i), depth+1); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	case reflect.Map:
+		entries, ok := source.Interface().(iter.Seq2[Any, Any])
+		if !ok {
+			return mismatch
+		}
+		result := reflect.MakeMap(rtype)
+		for key, value := range entries {
+			k := reflect.New(rtype.Key()).Elem()
+			if err := decodeValue(key, k, depth+1); err != nil {
+				return err
+			}
+			v := reflect.New(rtype.Elem()).Elem()
+			if err := decodeValue(value, v, depth+1); err != nil {
+				return fmt.Errorf("%v: %w", key, err)
+			}
+			result.SetMapIndex(k, v)
+		}
+		rvalue.Set(result)
+	case reflect.Struct:
+		entries, ok := source.Interface().(iter.Seq2[Any, Any])
+		if !ok {
+			return mismatch
+		}
+		fields := make(map[string][]int)
+		for _, field := range structFields(rtype) {
+			fields[field.name] = field.index
+		}
+		for key, value := range entries {
+			name := key.String()
+			index, ok := fields[name]
+			if !ok {
+				continue
+			}
+			field, err := rvalue.FieldByIndexErr(index)
+			if err != nil {
+				return err
+			}
+			if err := decodeValue(value, field, depth+1); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	default:
+		return mismatch
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package variant_test
+
+import (
+	"bytes"
+	"testing"
+
+	"graphics.gd/variant"
+	"graphics.gd/variant/Array"
+	"graphics.gd/variant/Dictionary"
+	"graphics.gd/variant/Vector2"
+)
+
+type SaveItem struct {
+	Name  string `gd:"name"`
+	Count int    `gd:"count"`
+}
+
+type SaveBase struct {
+	Version int `gd:"version"`
+}
+
+type SaveGame struct {
+	SaveBase
+
+	Player    string             `gd:"player"`
+	Position  Vector2.XY         `gd:"position"`
+	Items     []SaveItem         `gd:"items"`
+	Scores    []int              `gd:"scores"`
+	Flags     map[string]bool    `gd:"flags"`
+	Waypoints []Vector2.XY       `gd:"waypoints"`
+	Nested    map[string][]int32 `gd:"nested"`
+	Best      *SaveItem          `gd:"best"`
+	Secret    string             `gd:"-"`
+	Untagged  float64
+	private   int
+}
+
+func TestEncodeDecode(t *testing.T) {
+	save := SaveGame{
+		SaveBase:  SaveBase{Version: 2},
+		Player:    "gopher",
+		Position:  Vector2.New(1, 2),
+		Items:     []SaveItem{{"sword", 1}, {"potion", 3}},
+		Scores:    []int{10, 20},
+		Flags:     map[string]bool{"b": true, "a": false},
+		Waypoints: []Vector2.XY{Vector2.New(3, 4)},
+		Nested:    map[string][]int32{"x": {1, 2}},
+		Best:      &SaveItem{"shield", 1},
+		Secret:    "hidden",
+		Untagged:  1.5,
+		private:   1,
+	}
+	encoded, err := variant.Encode(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dictionary, ok := encoded.Interface().(Dictionary.Any)
+	if !ok {
+		t.Fatalf("expected a dictionary, got %T", encoded.Interface())
+	}
+	var names []string
+	for _, key := range dictionary.Keys() {
+		names = append(names, key.String())
+	}
+	if got := names; len(got) != 10 || got[0] != "version" || got[1] != "player" || got[9] != "Untagged" {
+		t.Fatalf("unexpected keys %v", got)
+	}
+	values := dictionary.Values()
+	if _, ok := values[3].Interface().(Array.Contains[Dictionary.Any]); !ok {
+		t.Fatalf("expected a typed array of items, got %T", values[3].Interface())
+	}
+	if _, ok := values[4].Interface().([]int64); !ok {
+		t.Fatalf("expected a packed array of scores, got %T", values[4].Interface())
+	}
+	var decoded SaveGame
+	if err := variant.Decode(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	save.Secret, save.private = "", 0
+	if decoded.Version != 2 || decoded.Player != "gopher" || decoded.Position != save.Position ||
+		len(decoded.Items) != 2 || decoded.Items[1] != save.Items[1] || decoded.Scores[1] != 20 ||
+		!decoded.Flags["b"] || len(decoded.Flags) != 2 || decoded.Waypoints[0] != save.Waypoints[0] ||
+		decoded.Nested["x"][1] != 2 || *decoded.Best != *save.Best || decoded.Secret != "" || decoded.Untagged != 1.5 {
+		t.Fatalf("unexpected decoded value %+v", decoded)
+	}
+	// the wire encoding of the struct is the same as the encoding of the dictionary.
+	a, err := variant.Marshal(save)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := variant.Marshal(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Fatal("expected the struct to be marshaled as a dictionary")
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var small struct {
+		Value int8 `gd:"value"`
+	}
+	encoded, _ := variant.Encode(map[string]int{"value": 300})
+	if err := variant.Decode(encoded, &small); err == nil {
+		t.Fatal("expected an overflow error")
+	}
+	if err := variant.Decode(variant.New("text"), &small); err == nil {
+		t.Fatal("expected an error decoding a string into a struct")
+	}
+	if err := variant.Decode(encoded, small); err == nil {
+		t.Fatal("expected an error for a non-pointer target")
+	}
+}
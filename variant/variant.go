@@ -308,6 +308,8 @@ func (a Any) Type() Type { //gd:typeof
 		return TypeQuaternion
 	case AABB.PositionSize:
 		return TypeAABB
+	case EncodedObject:
+		return TypeObject
 	default:
 		switch rtype.Kind() {
 		case reflect.Slice: